@@ -21,53 +21,118 @@ It automatically detects various project types including:
   - Python (pyproject.toml, setup.py, requirements.txt)
   - JavaScript/TypeScript (package.json)
   - Java (pom.xml, build.gradle)
+  - Kotlin (build.gradle.kts or build.gradle applying a Kotlin plugin)
   - .NET (*.csproj, *.sln)
+  - SQL Server database projects (*.sqlproj)
   - Rust (Cargo.toml)
   - Dart (pubspec.yaml)
   - C/C++ (CMakeLists.txt, Makefile)
+  - Haskell (*.cabal, package.yaml, stack.yaml)
+  - PHP (composer.json)
+  - Nim (*.nimble)
+  - R (DESCRIPTION)
+  - Swift (Package.swift, Podfile)
+  - Clojure (project.clj, deps.edn)
+  - OCaml (dune-project, *.opam)
 
 Usage:
   1. repo-ctr init              - Create a projects.yaml template
   2. repo-ctr identify .        - Auto-discover projects
   3. repo-ctr stats             - Show LOC statistics
 
-If projects.yaml exists, running 'repo-ctr' without arguments shows stats.`,
+If projects.yaml exists, running 'repo-ctr' without arguments shows stats.
+Otherwise it discovers projects and shows their stats without saving them;
+add --emit-projects to also write them to projects.yaml for editing.`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		switch {
+		case quiet:
+			cli.SetVerbosity(cli.VerbosityQuiet)
+		case verbose:
+			cli.SetVerbosity(cli.VerbosityVerbose)
+		}
+		cli.SetFollowSymlinks(followSymlinks)
+
+		if asciiOnly {
+			var err error
+			if restoreOutput, err = cli.EnableASCIIOnly(); err != nil {
+				return err
+			}
+		}
+
+		// Install an update staged by 'repo-ctr update --staged' before
+		// running the requested command, once --quiet and --ascii-only apply
+		finalizeStagedUpdate(cmd)
+		return nil
+	},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// If projects.yaml exists, run stats by default
 		if _, err := os.Stat(projectsFileName); err == nil {
-			return cli.RunStats(projectsFileName, false, "", "", false)
+			return cli.RunStats(projectsFileName, cli.StatsOptions{})
 		}
 
 		// Auto-discover projects and show stats
-		fmt.Println("No projects.yaml found. Auto-discovering projects...")
-		if err := cli.RunIdentify([]string{"."}, projectsFileName); err != nil {
-			return err
-		}
+		return cli.RunAutoStats(projectsFileName, emitProjects)
+	},
+}
 
-		// Check if any projects were discovered (projects.yaml should exist now)
-		if _, err := os.Stat(projectsFileName); err != nil {
-			fmt.Println("\nNo projects discovered. Use 'repo-ctr identify <path>' to scan a specific directory.")
-			return nil
-		}
+// emitProjects writes auto-discovered projects to projects.yaml.
+var emitProjects bool
 
-		fmt.Println()
-		return cli.RunStats(projectsFileName, false, "", "", false)
-	},
+// asciiOnly restricts every command's output to ASCII.
+var asciiOnly bool
+
+// quiet suppresses informational messages; verbose adds diagnostics.
+var quiet, verbose bool
+
+// followSymlinks walks symbolic links to directories in every command.
+var followSymlinks bool
+
+// restoreOutput undoes --ascii-only once the command has run.
+var restoreOutput = func() {}
+
+// finalizeStagedUpdate installs a staged update, if any, before cmd runs and
+// reports it on stderr unless --quiet is set. Failures are warnings only.
+func finalizeStagedUpdate(cmd *cobra.Command) {
+	if installed, err := cli.FinalizeStagedUpdate(cmd); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	} else if installed && !quiet {
+		fmt.Fprintln(os.Stderr, "Installed the staged repo-ctr update; it takes effect from the next run.")
+	}
 }
 
 // Execute runs the root command.
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	err := rootCmd.Execute()
+	restoreOutput()
+	if err != nil {
 		os.Exit(1)
 	}
 }
 
 func init() {
+	rootCmd.PersistentFlags().BoolVar(&asciiOnly, "ascii-only", false, "Replace emoji, box-drawing separators, and tree connectors with ASCII in all output")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "Suppress informational messages such as progress and discovery banners")
+	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Log each detected manifest and skipped directory to stderr")
+	rootCmd.MarkFlagsMutuallyExclusive("quiet", "verbose")
+	rootCmd.PersistentFlags().BoolVar(&followSymlinks, "follow-symlinks", false, "Descend into symbolic links to directories during discovery and counting (cycles are skipped)")
+	rootCmd.Flags().BoolVar(&emitProjects, "emit-projects", false, "Write auto-discovered projects to projects.yaml when it does not exist")
+
 	// Add subcommands
 	rootCmd.AddCommand(cli.NewInitCmd())
 	rootCmd.AddCommand(cli.NewIdentifyCmd())
+	rootCmd.AddCommand(cli.NewDetectCmd())
 	rootCmd.AddCommand(cli.NewStatsCmd())
+	rootCmd.AddCommand(cli.NewLanguagesCmd())
+	rootCmd.AddCommand(cli.NewMainCmd())
+	rootCmd.AddCommand(cli.NewWatchCmd())
+	rootCmd.AddCommand(cli.NewFingerprintCmd())
+	rootCmd.AddCommand(cli.NewDiffCmd())
+	rootCmd.AddCommand(cli.NewHistoryCmd())
 	rootCmd.AddCommand(cli.NewConfigCmd())
+	rootCmd.AddCommand(cli.NewInitConfigFromGitignoreCmd())
+	rootCmd.AddCommand(cli.NewDoctorCmd())
+	rootCmd.AddCommand(cli.NewExplainCmd())
 	rootCmd.AddCommand(cli.NewVersionCmd())
 	rootCmd.AddCommand(cli.NewUpdateCmd())
+	rootCmd.AddCommand(cli.NewDetectDirCmd())
 }
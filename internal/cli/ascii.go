@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"unicode/utf8"
+
+	"repoctr/internal/emoji"
+	"repoctr/pkg/models"
+)
+
+// asciiReplacements maps the non-ASCII runes repo-ctr prints to ASCII.
+// Box-drawing separators become "-", tree connectors "|", "`" and "--"
+// (so "├── " reads "|-- "), and emoji become bracketed tags.
+var asciiReplacements = buildASCIIReplacements()
+
+func buildASCIIReplacements() map[rune]string {
+	replacements := map[rune]string{
+		'─': "-", '━': "-", '═': "-",
+		'│': "|", '┃': "|", '├': "|", '┝': "|", '┣': "|",
+		'└': "`", '┗': "`", '┌': "+", '┐': "+", '┘': "+", '┬': "+", '┴': "+", '┼': "+",
+		'—': "-", '–': "-", '…': "...", '“': `"`, '”': `"`, '‘': "'", '’': "'",
+		'📊': "[stats]", '🌐': "[lang]", '📁': "[project]", '📏': "[long]",
+		'📄': "[file]", '📈': "[history]",
+		'✓': "[ok]", '✔': "[ok]", '✗': "[x]", '✘': "[x]", '⚠': "[!]",
+		// Variation selectors only choose how the preceding emoji is drawn
+		'\uFE0E': "", '\uFE0F': "",
+	}
+
+	// Runtime emoji become the runtime name, e.g. "[Go]"
+	for _, rt := range models.AllRuntimeTypes {
+		r, _ := utf8.DecodeRuneInString(emoji.Map(rt))
+		replacements[r] = "[" + string(rt) + "]"
+	}
+	r, _ := utf8.DecodeRuneInString(emoji.Map(""))
+	replacements[r] = "[project]"
+
+	return replacements
+}
+
+// asciiRune returns the ASCII replacement for r. Runes without a known
+// replacement, including invalid UTF-8, become "?".
+func asciiRune(r rune) string {
+	if r < utf8.RuneSelf {
+		return string(r)
+	}
+	if s, ok := asciiReplacements[r]; ok {
+		return s
+	}
+	return "?"
+}
+
+// copyASCII copies src to dst, replacing non-ASCII runes as it goes. Output
+// is flushed whenever src has no more data buffered, so interactive output
+// such as watch mode is not held back.
+func copyASCII(dst io.Writer, src io.Reader) error {
+	in := bufio.NewReader(src)
+	out := bufio.NewWriter(dst)
+	for {
+		r, _, err := in.ReadRune()
+		if err != nil {
+			if flushErr := out.Flush(); flushErr != nil {
+				return flushErr
+			}
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		if _, err := out.WriteString(asciiRune(r)); err != nil {
+			return err
+		}
+		if in.Buffered() == 0 {
+			if err := out.Flush(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// EnableASCIIOnly routes os.Stdout and os.Stderr through a filter that
+// replaces every non-ASCII rune (emoji, box-drawing separators, tree
+// connectors) with ASCII, for log viewers that mangle anything else. Call
+// the returned function before exiting to restore both streams and wait
+// for the filtered output to be written.
+func EnableASCIIOnly() (restore func(), err error) {
+	restoreStdout, err := filterASCII(&os.Stdout)
+	if err != nil {
+		return nil, err
+	}
+	restoreStderr, err := filterASCII(&os.Stderr)
+	if err != nil {
+		restoreStdout()
+		return nil, err
+	}
+	return func() {
+		restoreStderr()
+		restoreStdout()
+	}, nil
+}
+
+// filterASCII replaces *stream with a pipe whose contents copyASCII writes
+// to the original file.
+func filterASCII(stream **os.File) (restore func(), err error) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+
+	original := *stream
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if copyASCII(original, r) != nil {
+			// Keep draining so writers never block on a full pipe
+			io.Copy(io.Discard, r)
+		}
+		r.Close()
+	}()
+
+	*stream = w
+	return func() {
+		*stream = original
+		w.Close()
+		<-done
+	}, nil
+}
@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEnableASCIIOnly(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "go.mod", "module example.com/app\n\ngo 1.22\n")
+	writeTestFile(t, dir, "main.go", "package main\n\nfunc main() {}\n")
+	writeTestFile(t, dir, "web/package.json", `{"name": "web"}`)
+	writeTestFile(t, dir, "web/index.js", "console.log('hi')\n")
+
+	out := captureStdout(t, func() {
+		restore, err := EnableASCIIOnly()
+		if err != nil {
+			t.Fatalf("EnableASCIIOnly: %v", err)
+		}
+		defer restore()
+
+		if err := RunAutoStats(filepath.Join(dir, projectsFileName), false); err != nil {
+			t.Errorf("RunAutoStats: %v", err)
+		}
+		fmt.Fprintln(os.Stdout, "├── app — ⚙️ héllo")
+		fmt.Fprintln(os.Stdout, "└── web")
+	})
+
+	for i, b := range out {
+		if b > 0x7F {
+			t.Fatalf("byte %#x at offset %d is not ASCII:\n%s", b, i, out)
+		}
+	}
+
+	report := string(out)
+	for _, want := range []string{"[project] app [Go]", "GRAND TOTALS", strings.Repeat("-", 60), "|-- app - [C/C++] h?llo\n", "`-- web\n"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("output does not contain %q:\n%s", want, report)
+		}
+	}
+}
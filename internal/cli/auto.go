@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+
+	"repoctr/pkg/models"
+)
+
+// RunAutoStats discovers the projects in the directory of projectsFile and
+// shows their statistics, as the root command does when projectsFile does
+// not exist. The discovered projects are only counted in memory unless
+// emitProjects is set, in which case they are also written to projectsFile
+// so they can be edited and reused by later runs.
+func RunAutoStats(projectsFile string, emitProjects bool) error {
+	dir := filepath.Dir(projectsFile)
+
+	infof(os.Stdout, "No %s found. Auto-discovering projects...\n", filepath.Base(projectsFile))
+
+	if emitProjects {
+		if err := RunIdentify([]string{dir}, projectsFile, IdentifyOptions{}); err != nil {
+			return err
+		}
+
+		// Nothing is written when no projects are discovered
+		if _, err := os.Stat(projectsFile); err != nil {
+			infof(os.Stdout, "\nNo projects discovered. Use 'repo-ctr identify <path>' to scan a specific directory.\n")
+			return nil
+		}
+
+		infof(os.Stdout, "\n")
+		return RunStats(projectsFile, StatsOptions{})
+	}
+
+	projects, err := identifyProjects([]string{dir}, projectsFile, IdentifyOptions{}, os.Stdout)
+	if err != nil {
+		return err
+	}
+	if projects == nil {
+		infof(os.Stdout, "\nNo projects discovered. Use 'repo-ctr identify <path>' to scan a specific directory.\n")
+		return nil
+	}
+
+	rootDir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+
+	infof(os.Stdout, "\nCounting %d discovered project(s); use --emit-projects to save them to %s.\n\n",
+		countProjects(projects), projectsFile)
+	return runProjectStats(&models.ProjectsConfig{Projects: projects}, rootDir, projectsFile, StatsOptions{})
+}
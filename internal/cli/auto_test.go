@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunAutoStats_EmitProjects(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "go.mod", "module example.com/app\n\ngo 1.22\n")
+	writeTestFile(t, dir, "main.go", "package main\n\nfunc main() {}\n")
+	projectsFile := filepath.Join(dir, projectsFileName)
+
+	// Without the flag the discovered projects are counted but not saved
+	out := captureStdout(t, func() {
+		if err := RunAutoStats(projectsFile, false); err != nil {
+			t.Fatalf("RunAutoStats: %v", err)
+		}
+	})
+	if !strings.Contains(string(out), "--emit-projects") {
+		t.Errorf("output does not mention --emit-projects:\n%s", out)
+	}
+	if _, err := os.Stat(projectsFile); !os.IsNotExist(err) {
+		t.Fatalf("expected no %s without --emit-projects", projectsFileName)
+	}
+
+	captureStdout(t, func() {
+		if err := RunAutoStats(projectsFile, true); err != nil {
+			t.Fatalf("RunAutoStats: %v", err)
+		}
+	})
+
+	config, _, err := loadProjectsFile(projectsFile)
+	if err != nil {
+		t.Fatalf("expected %s to be written: %v", projectsFileName, err)
+	}
+	if len(config.Projects) != 1 || config.Projects[0].ManifestFile != "go.mod" {
+		t.Errorf("projects = %+v, want one go.mod project", config.Projects)
+	}
+}
+
+func TestRunAutoStats_Quiet(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "go.mod", "module example.com/app\n\ngo 1.22\n")
+	writeTestFile(t, dir, "main.go", "package main\n\nfunc main() {}\n")
+	projectsFile := filepath.Join(dir, projectsFileName)
+
+	out := captureStdout(t, func() {
+		if err := RunAutoStats(projectsFile, false); err != nil {
+			t.Fatalf("RunAutoStats: %v", err)
+		}
+	})
+	if !strings.Contains(string(out), "Auto-discovering projects") {
+		t.Errorf("output does not contain the discovery banner:\n%s", out)
+	}
+
+	SetVerbosity(VerbosityQuiet)
+	t.Cleanup(func() { SetVerbosity(VerbosityNormal) })
+
+	out = captureStdout(t, func() {
+		if err := RunAutoStats(projectsFile, false); err != nil {
+			t.Fatalf("RunAutoStats: %v", err)
+		}
+	})
+	for _, banner := range []string{"Auto-discovering projects", "Scanning", "Counting 1 discovered project"} {
+		if strings.Contains(string(out), banner) {
+			t.Errorf("quiet output contains %q:\n%s", banner, out)
+		}
+	}
+	if !strings.Contains(string(out), "main.go") && !strings.Contains(string(out), "app") {
+		t.Errorf("quiet output lost the stats report:\n%s", out)
+	}
+}
@@ -7,6 +7,8 @@ import (
 
 	"github.com/spf13/cobra"
 	"repoctr/internal/config"
+	"repoctr/internal/detector"
+	"repoctr/internal/discovery"
 	"repoctr/pkg/models"
 )
 
@@ -27,43 +29,73 @@ func NewConfigCmd() *cobra.Command {
 	return cmd
 }
 
+// configInitOptions controls the template written by 'config init'.
+type configInitOptions struct {
+	// Minimal writes an empty but valid config without commented examples.
+	Minimal bool
+	// WithDetected runs discovery and adds an empty project override for
+	// each discovered project path.
+	WithDetected bool
+}
+
 // newConfigInitCmd creates the 'config init' subcommand.
 func newConfigInitCmd() *cobra.Command {
+	var opts configInitOptions
+
 	cmd := &cobra.Command{
 		Use:   "init",
 		Short: "Initialize a .repoctrconfig.yaml template",
-		Long:  "Creates a .repoctrconfig.yaml template file in the current directory.",
-		RunE:  runConfigInit,
+		Long: `Creates a .repoctrconfig.yaml template file in the current directory.
+
+Use --minimal for an empty config without examples, and --with-detected to
+pre-populate project-overrides with a stub for every discovered project.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			rootDir, _ := filepath.Abs(".")
+			return runConfigInit(rootDir, opts)
+		},
 	}
 
+	cmd.Flags().BoolVar(&opts.Minimal, "minimal", false, "Write an empty config without commented examples")
+	cmd.Flags().BoolVar(&opts.WithDetected, "with-detected", false, "Add a project-overrides stub for each discovered project")
+
 	return cmd
 }
 
-func runConfigInit(cmd *cobra.Command, args []string) error {
-	rootDir, _ := filepath.Abs(".")
-
+func runConfigInit(rootDir string, opts configInitOptions) error {
 	// Check if config already exists
 	configPath := config.ConfigPath(rootDir)
 	if _, err := os.Stat(configPath); err == nil {
 		return fmt.Errorf("%s already exists. Use 'repo-ctr config show' to view it", filepath.Base(configPath))
 	}
 
-	// Create template config
-	templateConfig := &models.RepoCtrConfig{
-		GlobalExcludes: []string{
+	templateConfig := &models.RepoCtrConfig{}
+	if !opts.Minimal {
+		// Create template config
+		templateConfig.GlobalExcludes = []string{
 			"# Examples of global exclusions applied to all projects:",
 			"# **/*.test.js",
 			"# **/__mocks__/**",
 			"# **/generated/**",
-		},
-		ProjectOverrides: map[string]models.ProjectOverride{
-			"lib": {
-				ExcludePatterns: []string{
-					"# examples/**",
-					"# test_data/**",
+			"# /apps/legacy/vendored   (leading / matches from the repo root only)",
+		}
+		if !opts.WithDetected {
+			templateConfig.ProjectOverrides = map[string]models.ProjectOverride{
+				"lib": {
+					ExcludePatterns: []string{
+						"# examples/**",
+						"# test_data/**",
+					},
 				},
-			},
-		},
+			}
+		}
+	}
+
+	if opts.WithDetected {
+		overrides, err := detectedProjectOverrides(rootDir)
+		if err != nil {
+			return err
+		}
+		templateConfig.ProjectOverrides = overrides
 	}
 
 	// Save config
@@ -74,6 +106,9 @@ func runConfigInit(cmd *cobra.Command, args []string) error {
 	absPath := config.ConfigPath(rootDir)
 	absPath, _ = filepath.Abs(absPath)
 	fmt.Printf("Created %s\n", absPath)
+	if opts.WithDetected {
+		fmt.Printf("Added overrides for %d discovered project(s)\n", len(templateConfig.ProjectOverrides))
+	}
 	fmt.Println("\nNext steps:")
 	fmt.Println("  1. Edit .repoctrconfig.yaml to add your exclusion patterns")
 	fmt.Println("  2. Run 'repo-ctr stats' to apply the exclusions")
@@ -82,18 +117,42 @@ func runConfigInit(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// detectedProjectOverrides discovers projects under rootDir and returns an
+// empty override for each project path, keyed the way MergeProjects looks
+// them up.
+func detectedProjectOverrides(rootDir string) (map[string]models.ProjectOverride, error) {
+	walker, err := discovery.NewWalker(rootDir, detector.NewRegistry())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create walker for %s: %w", rootDir, err)
+	}
+
+	projects, err := walker.Discover()
+	if err != nil {
+		return nil, fmt.Errorf("discovery failed for %s: %w", rootDir, err)
+	}
+
+	overrides := make(map[string]models.ProjectOverride, len(projects))
+	for _, p := range projects {
+		overrides[p.Path] = models.ProjectOverride{}
+	}
+
+	return overrides, nil
+}
+
 // newConfigAddExcludeCmd creates the 'config add-exclude' subcommand.
 func newConfigAddExcludeCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "add-exclude <pattern>",
 		Short: "Add a global exclusion pattern",
 		Long: `Add a gitignore-style pattern to global exclusions.
-The pattern will be applied to all projects.
+The pattern will be applied to all projects. A leading "/" anchors the
+pattern to the repository root; without it a name matches at any depth.
 
 Examples:
   repo-ctr config add-exclude "**/*.test.js"
   repo-ctr config add-exclude "node_modules/**"
-  repo-ctr config add-exclude "__pycache__"`,
+  repo-ctr config add-exclude "__pycache__"
+  repo-ctr config add-exclude "/apps/legacy/vendored"`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runConfigAddExclude(args[0])
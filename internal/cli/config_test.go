@@ -0,0 +1,165 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"repoctr/internal/config"
+	"repoctr/internal/stats"
+	"repoctr/pkg/models"
+)
+
+// writeTestFile creates a file (and its parent directories) under root.
+func writeTestFile(t *testing.T, root, rel, content string) {
+	t.Helper()
+	full := filepath.Join(root, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+		t.Fatalf("write %s: %v", rel, err)
+	}
+}
+
+func TestConfigInit_WithDetected(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/root\n\ngo 1.22\n")
+	writeTestFile(t, root, "services/api/go.mod", "module example.com/api\n\ngo 1.22\n")
+	writeTestFile(t, root, "web/package.json", `{"name": "web"}`)
+
+	if err := runConfigInit(root, configInitOptions{WithDetected: true}); err != nil {
+		t.Fatalf("runConfigInit: %v", err)
+	}
+
+	cfg, err := config.LoadConfig(root)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+
+	for _, path := range []string{".", filepath.Join("services", "api"), "web"} {
+		if _, ok := cfg.ProjectOverrides[path]; !ok {
+			t.Errorf("missing override for %q; got %v", path, cfg.ProjectOverrides)
+		}
+	}
+	if len(cfg.ProjectOverrides) != 3 {
+		t.Errorf("got %d overrides, want 3: %v", len(cfg.ProjectOverrides), cfg.ProjectOverrides)
+	}
+	if _, ok := cfg.ProjectOverrides["lib"]; ok {
+		t.Error("example 'lib' override should not be written with --with-detected")
+	}
+}
+
+func TestConfigInit_Minimal(t *testing.T) {
+	root := t.TempDir()
+
+	if err := runConfigInit(root, configInitOptions{Minimal: true}); err != nil {
+		t.Fatalf("runConfigInit: %v", err)
+	}
+
+	data, err := os.ReadFile(config.ConfigPath(root))
+	if err != nil {
+		t.Fatalf("read config: %v", err)
+	}
+	if strings.Contains(string(data), "Examples") || strings.Contains(string(data), "lib") {
+		t.Errorf("minimal config should not contain examples:\n%s", data)
+	}
+
+	cfg, err := config.LoadConfig(root)
+	if err != nil {
+		t.Fatalf("minimal config should be valid: %v", err)
+	}
+	if len(cfg.GlobalExcludes) != 0 || len(cfg.ProjectOverrides) != 0 {
+		t.Errorf("expected empty config, got %+v", cfg)
+	}
+}
+
+func TestConfigInit_ExistingConfig(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, ".repoctrconfig.yaml", "global-excludes: []\n")
+
+	if err := runConfigInit(root, configInitOptions{}); err == nil {
+		t.Error("expected error when config already exists")
+	}
+}
+
+func TestInitConfigFromGitignore(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, ".gitignore", `# Build output
+out/
+/coverage
+docs/build/
+*.min.js
+*_gen.go
+
+# Not counted anyway
+*.log
+.env
+*.pyc
+.DS_Store
+
+# Already ignored by default
+node_modules/
+build
+!keep.min.js
+`)
+	writeTestFile(t, root, ".repoctrconfig.yaml", "global-excludes:\n  - \"*.min.js\"\n")
+
+	var out bytes.Buffer
+	if err := runInitConfigFromGitignore(root, true, &out); err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if !strings.Contains(out.String(), "+ out/") {
+		t.Errorf("dry run output does not list out/:\n%s", out.String())
+	}
+	cfg, err := config.LoadConfig(root)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if len(cfg.GlobalExcludes) != 1 {
+		t.Errorf("dry run changed the config: %v", cfg.GlobalExcludes)
+	}
+
+	out.Reset()
+	if err := runInitConfigFromGitignore(root, false, &out); err != nil {
+		t.Fatalf("runInitConfigFromGitignore: %v", err)
+	}
+	cfg, err = config.LoadConfig(root)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+
+	want := []string{"*.min.js", "out/", "/coverage", "docs/build/", "*_gen.go", "!keep.min.js"}
+	if !slices.Equal(cfg.GlobalExcludes, want) {
+		t.Errorf("global-excludes = %q, want %q", cfg.GlobalExcludes, want)
+	}
+
+	// A file .gitignore re-includes is still counted under the imported excludes
+	writeTestFile(t, root, "web/main.js", "main()\n")
+	writeTestFile(t, root, "web/app.min.js", "minified()\n")
+	writeTestFile(t, root, "web/keep.min.js", "vendored()\n")
+	counter, err := stats.NewCounter(root)
+	if err != nil {
+		t.Fatalf("NewCounter: %v", err)
+	}
+	projectStats, err := counter.CountProject(&models.Project{
+		Name:        "web",
+		Path:        "web",
+		Runtime:     models.Runtime{Type: models.RuntimeJavaScript},
+		SourcePaths: []string{"."},
+	})
+	if err != nil {
+		t.Fatalf("CountProject: %v", err)
+	}
+	var counted []string
+	for _, f := range projectStats.AllFiles {
+		counted = append(counted, filepath.Base(f.Path))
+	}
+	slices.Sort(counted)
+	if want := []string{"keep.min.js", "main.js"}; !slices.Equal(counted, want) {
+		t.Errorf("counted %q, want %q", counted, want)
+	}
+}
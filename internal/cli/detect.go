@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"repoctr/internal/detector"
+	"repoctr/internal/discovery"
+	"repoctr/internal/emoji"
+)
+
+// NewDetectCmd creates the detect command.
+func NewDetectCmd() *cobra.Command {
+	var jsonOut bool
+
+	cmd := &cobra.Command{
+		Use:   "detect [path]",
+		Short: "Classify a single directory by its manifests",
+		Long: `Runs project detection on the manifests in a single directory (default: the
+current directory) and prints the detected runtime, name, and version.
+
+Unlike 'identify', subdirectories are not scanned, projects.yaml is not
+written, and no statistics are computed.
+
+Examples:
+  repo-ctr detect
+  repo-ctr detect services/api --json`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir := "."
+			if len(args) > 0 {
+				dir = args[0]
+			}
+			return RunDetect(dir, jsonOut, os.Stdout)
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output in JSON format")
+
+	return cmd
+}
+
+// RunDetect detects the projects described by the manifests directly in dir
+// and writes them to w, as JSON if jsonOut is set.
+func RunDetect(dir string, jsonOut bool, w io.Writer) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("cannot access %s: %w", dir, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+
+	walker, err := discovery.NewWalker(dir, detector.NewRegistry())
+	if err != nil {
+		return fmt.Errorf("failed to create walker for %s: %w", dir, err)
+	}
+	walker.SetLogf(verboseLogf())
+	walker.SetFollowSymlinks(followSymlinks)
+
+	projects, err := walker.DiscoverDir()
+	if err != nil {
+		return fmt.Errorf("detection failed for %s: %w", dir, err)
+	}
+
+	if jsonOut {
+		detected := make([]DetectedProject, 0, len(projects))
+		for _, p := range projects {
+			detected = append(detected, DetectedProject{
+				Name:         p.Name,
+				Path:         p.Path,
+				Runtime:      string(p.Runtime.Type),
+				Version:      p.Runtime.Version,
+				ManifestFile: p.ManifestFile,
+			})
+		}
+
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		encoder.SetEscapeHTML(false)
+		return encoder.Encode(detected)
+	}
+
+	if len(projects) == 0 {
+		fmt.Fprintf(w, "No projects detected in %s\n", dir)
+		return nil
+	}
+
+	for _, p := range projects {
+		label := string(p.Runtime.Type)
+		if label == "" {
+			label = "unknown"
+		}
+		if p.Runtime.Version != "" {
+			label += " " + p.Runtime.Version
+		}
+		fmt.Fprintf(w, "%s %s (%s) — %s\n", emoji.Map(p.Runtime.Type), p.Name, label, p.ManifestFile)
+	}
+
+	return nil
+}
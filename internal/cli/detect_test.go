@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestRunDetect_GoDirectory(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module github.com/example/platform\n\ngo 1.22\n")
+	// Nested manifests are not part of the directory's classification
+	writeTestFile(t, root, "web/package.json", `{"name": "web"}`)
+
+	var buf bytes.Buffer
+	if err := RunDetect(root, true, &buf); err != nil {
+		t.Fatalf("RunDetect: %v", err)
+	}
+
+	var detected []DetectedProject
+	if err := json.Unmarshal(buf.Bytes(), &detected); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, buf.String())
+	}
+	if len(detected) != 1 {
+		t.Fatalf("expected 1 project, got %d: %+v", len(detected), detected)
+	}
+	if detected[0].Runtime != "Go" {
+		t.Errorf("runtime = %q, want %q", detected[0].Runtime, "Go")
+	}
+	if detected[0].Name != "platform" {
+		t.Errorf("name = %q, want %q", detected[0].Name, "platform")
+	}
+	if detected[0].Version != "1.22" {
+		t.Errorf("version = %q, want %q", detected[0].Version, "1.22")
+	}
+}
+
+func TestRunDetect_HumanOutput(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module github.com/example/platform\n\ngo 1.22\n")
+
+	var buf bytes.Buffer
+	if err := RunDetect(root, false, &buf); err != nil {
+		t.Fatalf("RunDetect: %v", err)
+	}
+
+	if out := buf.String(); !strings.Contains(out, "platform (Go 1.22)") {
+		t.Errorf("output missing runtime and name:\n%s", out)
+	}
+}
+
+func TestRunDetect_EmptyDirectory(t *testing.T) {
+	var buf bytes.Buffer
+	if err := RunDetect(t.TempDir(), true, &buf); err != nil {
+		t.Fatalf("RunDetect: %v", err)
+	}
+	if got := strings.TrimSpace(buf.String()); got != "[]" {
+		t.Errorf("output = %q, want []", got)
+	}
+}
@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"repoctr/internal/detector"
+	"repoctr/internal/discovery"
+)
+
+// DetectedProject is the stable, flat representation of a detected project
+// used by 'detect --json' and the __detect-dir golden tests.
+type DetectedProject struct {
+	Name         string `json:"name"`
+	Path         string `json:"path"`
+	Runtime      string `json:"runtime"`
+	Version      string `json:"version,omitempty"`
+	ManifestFile string `json:"manifest_file"`
+}
+
+// NewDetectDirCmd creates the hidden __detect-dir command.
+func NewDetectDirCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:    "__detect-dir <dir>",
+		Short:  "Print detected projects as stable JSON (for golden tests)",
+		Hidden: true,
+		Args:   cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunDetectDir(args[0], os.Stdout)
+		},
+	}
+}
+
+// RunDetectDir walks dir and writes the detected projects to w as JSON.
+// Projects are flat, sorted by path then manifest, and use forward slashes
+// so the output is identical across platforms.
+func RunDetectDir(dir string, w io.Writer) error {
+	walker, err := discovery.NewWalker(dir, detector.NewRegistry())
+	if err != nil {
+		return fmt.Errorf("failed to create walker for %s: %w", dir, err)
+	}
+	walker.SetLogf(verboseLogf())
+	walker.SetFollowSymlinks(followSymlinks)
+
+	projects, err := walker.Discover()
+	if err != nil {
+		return fmt.Errorf("discovery failed for %s: %w", dir, err)
+	}
+
+	detected := make([]DetectedProject, 0, len(projects))
+	for _, p := range projects {
+		detected = append(detected, DetectedProject{
+			Name:         p.Name,
+			Path:         filepath.ToSlash(p.Path),
+			Runtime:      string(p.Runtime.Type),
+			Version:      p.Runtime.Version,
+			ManifestFile: p.ManifestFile,
+		})
+	}
+
+	sort.Slice(detected, func(i, j int) bool {
+		if detected[i].Path != detected[j].Path {
+			return detected[i].Path < detected[j].Path
+		}
+		return detected[i].ManifestFile < detected[j].ManifestFile
+	})
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(detected)
+}
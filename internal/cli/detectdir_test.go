@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var updateGolden = flag.Bool("update", false, "update golden files")
+
+// TestDetectDir_Golden runs __detect-dir over each fixture directory in
+// testdata/detect and compares the output with its .golden.json file.
+// Run with -update to regenerate the golden files.
+func TestDetectDir_Golden(t *testing.T) {
+	fixtures, err := os.ReadDir(filepath.Join("testdata", "detect"))
+	if err != nil {
+		t.Fatalf("failed to read fixtures: %v", err)
+	}
+
+	for _, fixture := range fixtures {
+		if !fixture.IsDir() {
+			continue
+		}
+
+		t.Run(fixture.Name(), func(t *testing.T) {
+			dir := filepath.Join("testdata", "detect", fixture.Name())
+			goldenPath := dir + ".golden.json"
+
+			var buf bytes.Buffer
+			if err := RunDetectDir(dir, &buf); err != nil {
+				t.Fatalf("RunDetectDir: %v", err)
+			}
+
+			if *updateGolden {
+				if err := os.WriteFile(goldenPath, buf.Bytes(), 0644); err != nil {
+					t.Fatalf("failed to update golden file: %v", err)
+				}
+			}
+
+			want, err := os.ReadFile(goldenPath)
+			if err != nil {
+				t.Fatalf("failed to read golden file (run with -update to create): %v", err)
+			}
+
+			if !bytes.Equal(bytes.ReplaceAll(want, []byte("\r\n"), []byte("\n")), buf.Bytes()) {
+				t.Errorf("output mismatch for %s\n--- got ---\n%s\n--- want ---\n%s", fixture.Name(), buf.String(), want)
+			}
+		})
+	}
+}
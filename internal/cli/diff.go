@@ -0,0 +1,241 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// Project diff statuses.
+const (
+	DiffAdded     = "added"
+	DiffRemoved   = "removed"
+	DiffChanged   = "changed"
+	DiffUnchanged = "unchanged"
+)
+
+// NewDiffCmd creates the diff command.
+func NewDiffCmd() *cobra.Command {
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "diff <old-stats> <new-stats>",
+		Short: "Compare two stats snapshots",
+		Long: `Compares two machine-readable stats snapshots (from 'repo-ctr stats --json'
+or '--yaml') and prints per-project and total deltas in files, lines, and
+code lines. Projects present in only one snapshot are listed as added or
+removed.
+
+Examples:
+  repo-ctr stats --json > before.json
+  # ... make changes ...
+  repo-ctr stats --json > after.json
+  repo-ctr diff before.json after.json
+  repo-ctr diff before.json after.json --format json`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunDiff(args[0], args[1], format)
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "text", "Output format: text or json")
+
+	return cmd
+}
+
+// DiffOutput represents the machine-readable diff between two snapshots.
+type DiffOutput struct {
+	Projects []ProjectDiffOutput `json:"projects"`
+	Totals   StatsDelta          `json:"totals"`
+}
+
+// ProjectDiffOutput represents the change in a single project.
+type ProjectDiffOutput struct {
+	Name    string     `json:"name"`
+	Path    string     `json:"path"`
+	Runtime string     `json:"runtime,omitempty"`
+	Status  string     `json:"status"`
+	Delta   StatsDelta `json:"delta"`
+}
+
+// StatsDelta holds the difference (new - old) in counted totals.
+type StatsDelta struct {
+	Files      int `json:"files"`
+	TotalLines int `json:"total_lines"`
+	CodeLines  int `json:"code_lines"`
+}
+
+// IsZero reports whether nothing changed.
+func (d StatsDelta) IsZero() bool {
+	return d == StatsDelta{}
+}
+
+// RunDiff loads two stats snapshots and prints their differences.
+func RunDiff(oldFile, newFile, format string) error {
+	if format != "text" && format != "json" {
+		return fmt.Errorf("unknown format: %s (expected text or json)", format)
+	}
+
+	oldStats, err := loadStatsSnapshot(oldFile)
+	if err != nil {
+		return err
+	}
+	newStats, err := loadStatsSnapshot(newFile)
+	if err != nil {
+		return err
+	}
+
+	diff := computeStatsDiff(oldStats, newStats)
+
+	if format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(diff)
+	}
+
+	writeDiff(os.Stdout, diff)
+	return nil
+}
+
+// loadStatsSnapshot reads a stats snapshot written by 'stats --json' or
+// 'stats --yaml'. JSON is valid YAML, so a single decoder handles both.
+func loadStatsSnapshot(file string) (*StatsOutput, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", file, err)
+	}
+
+	var output StatsOutput
+	if err := yaml.Unmarshal(data, &output); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+	}
+
+	return &output, nil
+}
+
+// computeStatsDiff compares two snapshots project by project. Projects are
+// matched by path and name; the hierarchy is flattened so a project that
+// moved under a new parent still matches. Order follows the new snapshot,
+// with removed projects appended in their original order.
+func computeStatsDiff(oldStats, newStats *StatsOutput) DiffOutput {
+	oldProjects := flattenProjectOutputs(oldStats.Projects)
+	newProjects := flattenProjectOutputs(newStats.Projects)
+
+	oldByKey := make(map[string]ProjectStatsOutput, len(oldProjects))
+	for _, p := range oldProjects {
+		oldByKey[projectDiffKey(p)] = p
+	}
+
+	diff := DiffOutput{
+		Projects: []ProjectDiffOutput{},
+		Totals: StatsDelta{
+			Files:      newStats.Totals.Files - oldStats.Totals.Files,
+			TotalLines: newStats.Totals.TotalLines - oldStats.Totals.TotalLines,
+			CodeLines:  newStats.Totals.CodeLines - oldStats.Totals.CodeLines,
+		},
+	}
+
+	seen := make(map[string]bool, len(newProjects))
+	for _, p := range newProjects {
+		key := projectDiffKey(p)
+		seen[key] = true
+
+		old, existed := oldByKey[key]
+		entry := ProjectDiffOutput{
+			Name:    p.Name,
+			Path:    p.Path,
+			Runtime: p.Runtime,
+			Delta:   subtractProjectStats(p, old),
+		}
+		switch {
+		case !existed:
+			entry.Status = DiffAdded
+		case entry.Delta.IsZero():
+			entry.Status = DiffUnchanged
+		default:
+			entry.Status = DiffChanged
+		}
+		diff.Projects = append(diff.Projects, entry)
+	}
+
+	for _, p := range oldProjects {
+		if seen[projectDiffKey(p)] {
+			continue
+		}
+		diff.Projects = append(diff.Projects, ProjectDiffOutput{
+			Name:    p.Name,
+			Path:    p.Path,
+			Runtime: p.Runtime,
+			Status:  DiffRemoved,
+			Delta:   subtractProjectStats(ProjectStatsOutput{}, p),
+		})
+	}
+
+	return diff
+}
+
+func subtractProjectStats(newP, oldP ProjectStatsOutput) StatsDelta {
+	return StatsDelta{
+		Files:      newP.Files - oldP.Files,
+		TotalLines: newP.TotalLines - oldP.TotalLines,
+		CodeLines:  newP.CodeLines - oldP.CodeLines,
+	}
+}
+
+func projectDiffKey(p ProjectStatsOutput) string {
+	return p.Path + "\x00" + p.Name
+}
+
+// flattenProjectOutputs returns projects in depth-first order.
+func flattenProjectOutputs(projects []ProjectStatsOutput) []ProjectStatsOutput {
+	var result []ProjectStatsOutput
+	for _, p := range projects {
+		result = append(result, p)
+		result = append(result, flattenProjectOutputs(p.Children)...)
+	}
+	return result
+}
+
+// writeDiff prints a human-readable diff. Unchanged projects are omitted.
+func writeDiff(w io.Writer, diff DiffOutput) {
+	separator := strings.Repeat("─", 60)
+
+	fmt.Fprintln(w, separator)
+	fmt.Fprintf(w, "\n📊 LOC DIFF\n")
+	fmt.Fprintln(w, separator)
+
+	changed := 0
+	for _, p := range diff.Projects {
+		var marker string
+		switch p.Status {
+		case DiffAdded:
+			marker = "+"
+		case DiffRemoved:
+			marker = "-"
+		case DiffChanged:
+			marker = "~"
+		default:
+			continue
+		}
+		changed++
+
+		fmt.Fprintf(w, " %s %s (%s) [%s]\n", marker, p.Name, p.Path, p.Status)
+		fmt.Fprintf(w, "     %s\n", formatDelta(p.Delta))
+	}
+
+	if changed == 0 {
+		fmt.Fprintln(w, "   No project changes")
+	}
+
+	fmt.Fprintln(w, separator)
+	fmt.Fprintf(w, "   Total: %s\n", formatDelta(diff.Totals))
+}
+
+func formatDelta(d StatsDelta) string {
+	return fmt.Sprintf("files %+d, lines %+d, code %+d", d.Files, d.TotalLines, d.CodeLines)
+}
@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestComputeStatsDiff(t *testing.T) {
+	oldStats, err := loadStatsSnapshot(filepath.Join("testdata", "diff", "old.json"))
+	if err != nil {
+		t.Fatalf("load old: %v", err)
+	}
+	newStats, err := loadStatsSnapshot(filepath.Join("testdata", "diff", "new.yaml"))
+	if err != nil {
+		t.Fatalf("load new: %v", err)
+	}
+
+	diff := computeStatsDiff(oldStats, newStats)
+
+	want := []ProjectDiffOutput{
+		{Name: "api", Path: "services/api", Runtime: "Go", Status: DiffChanged, Delta: StatsDelta{Files: 2, TotalLines: 150, CodeLines: 130}},
+		{Name: "worker", Path: "services/api/worker", Runtime: "Go", Status: DiffUnchanged},
+		{Name: "web", Path: "web", Runtime: "TypeScript", Status: DiffAdded, Delta: StatsDelta{Files: 8, TotalLines: 600, CodeLines: 500}},
+		{Name: "legacy", Path: "legacy", Runtime: "Python", Status: DiffRemoved, Delta: StatsDelta{Files: -5, TotalLines: -300, CodeLines: -250}},
+	}
+
+	if len(diff.Projects) != len(want) {
+		t.Fatalf("got %d projects, want %d: %+v", len(diff.Projects), len(want), diff.Projects)
+	}
+	for i := range want {
+		if diff.Projects[i] != want[i] {
+			t.Errorf("project %d = %+v, want %+v", i, diff.Projects[i], want[i])
+		}
+	}
+
+	wantTotals := StatsDelta{Files: 5, TotalLines: 450, CodeLines: 380}
+	if diff.Totals != wantTotals {
+		t.Errorf("totals = %+v, want %+v", diff.Totals, wantTotals)
+	}
+}
+
+func TestWriteDiff(t *testing.T) {
+	diff := DiffOutput{
+		Projects: []ProjectDiffOutput{
+			{Name: "api", Path: "api", Status: DiffChanged, Delta: StatsDelta{Files: 1, TotalLines: 10, CodeLines: 8}},
+			{Name: "lib", Path: "lib", Status: DiffUnchanged},
+			{Name: "web", Path: "web", Status: DiffAdded, Delta: StatsDelta{Files: 3, TotalLines: 30, CodeLines: 25}},
+			{Name: "old", Path: "old", Status: DiffRemoved, Delta: StatsDelta{Files: -2, TotalLines: -20, CodeLines: -15}},
+		},
+		Totals: StatsDelta{Files: 2, TotalLines: 20, CodeLines: 18},
+	}
+
+	var buf bytes.Buffer
+	writeDiff(&buf, diff)
+	out := buf.String()
+
+	for _, want := range []string{
+		"~ api (api) [changed]",
+		"+ web (web) [added]",
+		"- old (old) [removed]",
+		"files -2, lines -20, code -15",
+		"Total: files +2, lines +20, code +18",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "lib") {
+		t.Errorf("unchanged project should be omitted:\n%s", out)
+	}
+}
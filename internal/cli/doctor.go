@@ -0,0 +1,177 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"repoctr/internal/config"
+	"repoctr/internal/ignore"
+	"repoctr/pkg/models"
+)
+
+// NewDoctorCmd creates the doctor command.
+func NewDoctorCmd() *cobra.Command {
+	var checkConfig bool
+
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check the repository setup for common mistakes",
+		Long: `Checks the repository setup for common mistakes and suggests fixes.
+
+With --config (the default when no check is selected), .repoctrconfig.yaml
+is linted for:
+  - exclude patterns that can never match, such as Windows backslashes,
+    absolute paths, and invalid globs
+  - exclude patterns that repeat a default ignore (e.g. "node_modules")
+    or another global exclude
+  - project-overrides keys for project paths that do not exist
+
+"name:" override keys are not checked. The command exits with status 1 when
+a problem is found.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			rootDir, _ := filepath.Abs(".")
+			// --config is the only check so far, so it also runs by default
+			return runDoctorConfig(rootDir, os.Stdout)
+		},
+	}
+
+	cmd.Flags().BoolVar(&checkConfig, "config", false, "Lint .repoctrconfig.yaml")
+
+	return cmd
+}
+
+// configProblem is a mistake found in .repoctrconfig.yaml.
+type configProblem struct {
+	// Where names the config entry, e.g. "global-excludes".
+	Where string
+	// Value is the offending pattern or key.
+	Value string
+	// Message explains the problem and suggests a fix. It reads as a
+	// sentence following Value, e.g. "is ignored by default; remove it".
+	Message string
+}
+
+func runDoctorConfig(rootDir string, w io.Writer) error {
+	configPath := config.ConfigPath(rootDir)
+	if _, err := os.Stat(configPath); err != nil {
+		fmt.Fprintf(w, "No %s found. Run 'repo-ctr config init' to create one.\n", filepath.Base(configPath))
+		return nil
+	}
+
+	cfg, err := config.LoadConfig(rootDir)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	problems := lintConfig(rootDir, cfg)
+	if len(problems) == 0 {
+		fmt.Fprintf(w, "%s: no problems found\n", filepath.Base(configPath))
+		return nil
+	}
+
+	fmt.Fprintf(w, "%s:\n", filepath.Base(configPath))
+	for _, p := range problems {
+		fmt.Fprintf(w, "  - %s: %q %s\n", p.Where, p.Value, p.Message)
+	}
+	return fmt.Errorf("found %d problem(s) in %s", len(problems), filepath.Base(configPath))
+}
+
+// lintConfig returns the problems in cfg, the config of the repository at
+// rootDir: global excludes first, then overrides in key order.
+func lintConfig(rootDir string, cfg *models.RepoCtrConfig) []configProblem {
+	var problems []configProblem
+
+	seen := make(map[string]bool)
+	for _, pattern := range cfg.GlobalExcludes {
+		if seen[pattern] {
+			problems = append(problems, configProblem{"global-excludes", pattern, "is listed more than once; remove the duplicate"})
+			continue
+		}
+		seen[pattern] = true
+		if msg := lintExcludePattern(rootDir, pattern); msg != "" {
+			problems = append(problems, configProblem{"global-excludes", pattern, msg})
+		}
+	}
+
+	keys := make([]string, 0, len(cfg.ProjectOverrides))
+	for key := range cfg.ProjectOverrides {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if msg := lintOverrideKey(rootDir, key); msg != "" {
+			problems = append(problems, configProblem{"project-overrides", key, msg})
+		}
+		where := fmt.Sprintf("project-overrides[%q].exclude-patterns", key)
+		for _, pattern := range cfg.ProjectOverrides[key].ExcludePatterns {
+			if msg := lintExcludePattern(rootDir, pattern); msg != "" {
+				problems = append(problems, configProblem{where, pattern, msg})
+			}
+		}
+	}
+
+	return problems
+}
+
+// lintExcludePattern returns the problem with an exclude pattern, or "".
+// Commented-out entries, as written by 'config init', are skipped.
+func lintExcludePattern(rootDir, pattern string) string {
+	if strings.HasPrefix(pattern, "#") {
+		return ""
+	}
+
+	// A leading "/" anchors a pattern to the root, so an absolute path
+	// inside the repository is mistaken for a path below the root
+	root := filepath.ToSlash(rootDir)
+	if rel, ok := strings.CutPrefix(strings.TrimPrefix(pattern, "!"), root+"/"); ok {
+		return fmt.Sprintf("is an absolute path, which never matches; use %q", "/"+rel)
+	}
+
+	if err := ignore.ValidatePattern(pattern); err != nil {
+		return err.Error()
+	}
+	if ignore.IsDefaultPattern(pattern) {
+		return "is ignored by default; remove it"
+	}
+	return ""
+}
+
+// lintOverrideKey returns the problem with a project-overrides key, or "".
+// Path keys must name an existing directory and glob keys must match one.
+func lintOverrideKey(rootDir, key string) string {
+	if strings.HasPrefix(key, config.NameOverridePrefix) {
+		return ""
+	}
+	if strings.Contains(key, `\`) {
+		return fmt.Sprintf("uses Windows backslashes, which never match a project path; use %q", strings.ReplaceAll(key, `\`, "/"))
+	}
+	if filepath.IsAbs(key) {
+		return "is an absolute path; use the project path relative to the repository root"
+	}
+
+	if strings.ContainsAny(key, "*?[") {
+		matches, err := filepath.Glob(filepath.Join(rootDir, filepath.FromSlash(key)))
+		if err != nil {
+			return fmt.Sprintf("is not a valid glob: %v", err)
+		}
+		for _, match := range matches {
+			if info, err := os.Stat(match); err == nil && info.IsDir() {
+				return ""
+			}
+		}
+		return "matches no directory; fix or remove the override (see 'repo-ctr identify' for project paths)"
+	}
+
+	info, err := os.Stat(filepath.Join(rootDir, filepath.FromSlash(key)))
+	if err != nil || !info.IsDir() {
+		return "is not a directory in the repository; fix or remove the override (see 'repo-ctr identify' for project paths)"
+	}
+	return ""
+}
@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRunDoctorConfig(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "services/api/main.go", "package main\n")
+	writeTestFile(t, dir, ".repoctrconfig.yaml", `global-excludes:
+  - 'build\out'
+  - "**/*.snap"
+  - node_modules/
+  - "# **/generated/**"
+project-overrides:
+  services/api:
+    exclude-patterns:
+      - fixtures/**
+  services/old-billing:
+    exclude-patterns:
+      - "*.sql"
+  "packages/*":
+    source-paths:
+      - src
+  name:web:
+    source-paths:
+      - app
+`)
+
+	var out bytes.Buffer
+	err := runDoctorConfig(dir, &out)
+	if err == nil {
+		t.Fatalf("runDoctorConfig returned no error for a config with problems:\n%s", out.String())
+	}
+
+	report := out.String()
+	for _, want := range []string{
+		`global-excludes: "build\\out" uses Windows backslashes, which never match; use "build/out"`,
+		`global-excludes: "node_modules/" is ignored by default`,
+		`project-overrides: "services/old-billing" is not a directory in the repository`,
+		`project-overrides: "packages/*" matches no directory`,
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report does not contain %q:\n%s", want, report)
+		}
+	}
+	for _, unwanted := range []string{"**/*.snap", "# **/generated/**", `"services/api"`, "name:web"} {
+		if strings.Contains(report, unwanted) {
+			t.Errorf("report flags %q, which is fine:\n%s", unwanted, report)
+		}
+	}
+	if !strings.Contains(err.Error(), "4 problem(s)") {
+		t.Errorf("error = %v, want 4 problems", err)
+	}
+}
+
+func TestRunDoctorConfig_Clean(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "lib/lib.go", "package lib\n")
+	writeTestFile(t, dir, ".repoctrconfig.yaml", "global-excludes:\n  - \"**/*.snap\"\nproject-overrides:\n  lib:\n    exclude-patterns:\n      - testdata/**\n")
+
+	var out bytes.Buffer
+	if err := runDoctorConfig(dir, &out); err != nil {
+		t.Fatalf("runDoctorConfig: %v\n%s", err, out.String())
+	}
+	if !strings.Contains(out.String(), "no problems found") {
+		t.Errorf("output = %q, want no problems", out.String())
+	}
+}
@@ -0,0 +1,190 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"repoctr/internal/stats"
+	"repoctr/pkg/models"
+	"repoctr/pkg/repoctr"
+)
+
+// NewExplainCmd creates the explain command.
+func NewExplainCmd() *cobra.Command {
+	var inputFile string
+	var opts StatsOptions
+	var maxFileSize string
+
+	cmd := &cobra.Command{
+		Use:   "explain <path>",
+		Short: "Explain why a file or directory is or isn't counted",
+		Long: `Explains how 'repo-ctr stats' treats a file or directory: which project it
+belongs to, whether it is inside the project's source paths, whether its
+extension is counted for the project's runtime, and which ignore rule, if
+any, excludes it. Ignore rules are reported with where they are defined: the
+default ignores, a .gitignore or .repoctrignore line, the global-excludes of
+.repoctrconfig.yaml, a project's exclude-patterns, or a command-line flag.
+
+Projects are read from projects.yaml, or discovered when it does not exist.
+Files that look binary, or are larger than --max-file-size, are not counted
+either. Pass the same --exclude, --include-ext, --exclude-generated-dirs,
+--include-notebooks, and --max-file-size flags as to 'repo-ctr stats' to
+explain that run.
+
+Examples:
+  repo-ctr explain web/dist/app.js
+  repo-ctr explain services/api --exclude 'testdata/'`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if maxFileSize != "" {
+				size, err := parseByteSize(maxFileSize)
+				if err != nil {
+					return fmt.Errorf("invalid --max-file-size: %w", err)
+				}
+				opts.MaxFileSize = size
+			}
+			return RunExplain(inputFile, args[0], opts, os.Stdout)
+		},
+	}
+
+	cmd.Flags().StringVarP(&inputFile, "file", "f", projectsFileName, "Projects configuration file")
+	cmd.Flags().StringArrayVar(&opts.Excludes, "exclude", nil, "Exclude files matching a gitignore-style pattern (repeatable)")
+	cmd.Flags().StringArrayVar(&opts.IncludeExtensions, "include-ext", nil, "Also count files with this extension in every project, e.g. .tmpl (repeatable)")
+	cmd.Flags().BoolVar(&opts.ExcludeGeneratedDirs, "exclude-generated-dirs", false, "Skip gen/, generated/, __generated__/, migrations/ and *.pb.go files")
+	cmd.Flags().BoolVar(&opts.IncludeNotebooks, "include-notebooks", false, "Count the code cells of Jupyter notebooks (.ipynb) in Python projects")
+	cmd.Flags().StringVar(&maxFileSize, "max-file-size", "", "Skip files larger than this size in bytes, with optional K/M/G suffix (default: unlimited)")
+
+	return cmd
+}
+
+// RunExplain writes to w how the projects of inputFile, or those discovered
+// in its directory when it does not exist, treat target.
+func RunExplain(inputFile, target string, opts StatsOptions, w io.Writer) error {
+	absTarget, err := filepath.Abs(target)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(absTarget); err != nil {
+		return fmt.Errorf("cannot explain %s: %w", target, err)
+	}
+
+	var projects []*models.Project
+	var rootDir string
+	if _, err := os.Stat(inputFile); err == nil {
+		config, dir, err := loadProjectsFile(inputFile)
+		if err != nil {
+			return err
+		}
+		projects, rootDir = config.Projects, dir
+	} else {
+		if rootDir, err = filepath.Abs(filepath.Dir(inputFile)); err != nil {
+			return err
+		}
+		if projects, err = repoctr.Discover(rootDir); err != nil {
+			return err
+		}
+	}
+
+	relTarget, err := filepath.Rel(rootDir, absTarget)
+	if err != nil || relTarget == ".." || strings.HasPrefix(relTarget, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("%s is outside the repository root %s", target, rootDir)
+	}
+	relTarget = filepath.ToSlash(relTarget)
+	fmt.Fprintf(w, "%s\n", relTarget)
+
+	project := findProjectByPath(projects, rootDir, absTarget)
+	if project == nil {
+		fmt.Fprintf(w, "  Project:   none; the path is not inside any project\n")
+		fmt.Fprintf(w, "  Result:    not counted\n")
+		return nil
+	}
+
+	counter, err := stats.NewCounterWithOptions(rootDir, stats.Options{
+		Excludes:             opts.Excludes,
+		IncludeExtensions:    opts.IncludeExtensions,
+		ExcludeGeneratedDirs: opts.ExcludeGeneratedDirs,
+		IncludeNotebooks:     opts.IncludeNotebooks,
+		MaxFileSize:          opts.MaxFileSize,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create stats counter: %w", err)
+	}
+	e, err := counter.Explain(project, absTarget)
+	if err != nil {
+		return err
+	}
+
+	printExplanation(w, project, relTarget, e)
+	return nil
+}
+
+// findProjectByPath returns the deepest project in the tree whose
+// directory contains absPath, or nil.
+func findProjectByPath(projects []*models.Project, rootDir, absPath string) *models.Project {
+	for _, p := range projects {
+		rel, err := filepath.Rel(filepath.Join(rootDir, p.Path), absPath)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		if child := findProjectByPath(p.Children, rootDir, absPath); child != nil {
+			return child
+		}
+		return p
+	}
+	return nil
+}
+
+// printExplanation writes one line per check of e, then the result.
+func printExplanation(w io.Writer, project *models.Project, relTarget string, e *stats.Explanation) {
+	fmt.Fprintf(w, "  Project:   %s (%s) at %s\n", project.Name, project.Runtime.Type, filepath.ToSlash(project.Path))
+
+	if e.InSourcePaths {
+		fmt.Fprintf(w, "  Source:    inside the source paths\n")
+	} else {
+		fmt.Fprintf(w, "  Source:    outside the source paths (%s)\n", strings.Join(project.SourcePaths, ", "))
+	}
+
+	if !e.IsDir {
+		ext := filepath.Ext(relTarget)
+		switch {
+		case e.SourceExtension:
+			fmt.Fprintf(w, "  Extension: %q is counted for %s\n", ext, project.Runtime.Type)
+		case ext == "":
+			fmt.Fprintf(w, "  Extension: none; files without an extension are not counted\n")
+		default:
+			fmt.Fprintf(w, "  Extension: %q is not counted for %s; add it with --include-ext or extra-extensions\n", ext, project.Runtime.Type)
+		}
+	}
+
+	if !e.IsDir {
+		switch {
+		case e.OverMaxFileSize:
+			fmt.Fprintf(w, "  Content:   over the maximum file size; raise or drop --max-file-size to count it\n")
+		case e.Binary:
+			fmt.Fprintf(w, "  Content:   binary file; binary files are not counted\n")
+		default:
+			fmt.Fprintf(w, "  Content:   text\n")
+		}
+	}
+
+	switch {
+	case e.SrcIgnorePath != "":
+		fmt.Fprintf(w, "  Ignored:   by src-ignore-paths entry %q of project %s\n", e.SrcIgnorePath, project.Name)
+	case e.IgnoredBy != nil && e.IgnoredBy.Path != relTarget:
+		fmt.Fprintf(w, "  Ignored:   by %q (%s), which excludes the directory %s\n", e.IgnoredBy.Pattern, e.IgnoredBy.Source, e.IgnoredBy.Path)
+	case e.IgnoredBy != nil:
+		fmt.Fprintf(w, "  Ignored:   by %q (%s)\n", e.IgnoredBy.Pattern, e.IgnoredBy.Source)
+	default:
+		fmt.Fprintf(w, "  Ignored:   no\n")
+	}
+
+	if e.Counted() {
+		fmt.Fprintf(w, "  Result:    counted\n")
+	} else {
+		fmt.Fprintf(w, "  Result:    not counted\n")
+	}
+}
@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunExplain(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "go.mod", "module example.com/app\n\ngo 1.22\n")
+	writeTestFile(t, dir, "main.go", "package main\n")
+	writeTestFile(t, dir, "internal/mock/mock.go", "package mock\n")
+	writeTestFile(t, dir, "README.md", "# app\n")
+	writeTestFile(t, dir, "blob.go", "package main\x00\x01")
+	writeTestFile(t, dir, "big.go", "package main\n\nvar table = []int{"+strings.Repeat("0, ", 1024)+"}\n")
+	writeTestFile(t, dir, ".repoctrconfig.yaml", "global-excludes:\n  - \"**/mock/**\"\n")
+	projectsFile := filepath.Join(dir, projectsFileName)
+
+	tests := []struct {
+		target string
+		opts   StatsOptions
+		want   []string
+	}{
+		{"internal/mock/mock.go", StatsOptions{}, []string{
+			"  Project:   app (Go) at .",
+			`  Ignored:   by "**/mock/**" (global-excludes in .repoctrconfig.yaml)`,
+			"  Result:    not counted",
+		}},
+		{"README.md", StatsOptions{}, []string{
+			`  Extension: ".md" is not counted for Go`,
+			"  Ignored:   no",
+			"  Result:    not counted",
+		}},
+		{"main.go", StatsOptions{}, []string{
+			`  Extension: ".go" is counted for Go`,
+			"  Content:   text",
+			"  Result:    counted",
+		}},
+		{"blob.go", StatsOptions{}, []string{
+			"  Content:   binary file",
+			"  Ignored:   no",
+			"  Result:    not counted",
+		}},
+		{"big.go", StatsOptions{MaxFileSize: 1024}, []string{
+			"  Content:   over the maximum file size",
+			"  Result:    not counted",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := RunExplain(projectsFile, filepath.Join(dir, tt.target), tt.opts, &out); err != nil {
+				t.Fatalf("RunExplain: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("output does not contain %q:\n%s", want, out.String())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"repoctr/internal/hashing"
+	"repoctr/internal/stats"
+	"repoctr/pkg/models"
+)
+
+// NewFingerprintCmd creates the fingerprint command.
+func NewFingerprintCmd() *cobra.Command {
+	var inputFile string
+	var jsonOut bool
+	var hashAlgo string
+
+	cmd := &cobra.Command{
+		Use:   "fingerprint",
+		Short: "Compute a reproducible hash of the repository's counted code",
+		Long: `Computes a single digest over every file counted by 'repo-ctr stats'.
+Each file contributes its relative path and a hash of its content, so the
+digest changes only when counted code changes. Useful for change detection
+in external systems.
+
+Digests use SHA-256 by default. --hash-algo selects sha1 or xxhash instead;
+xxhash is much faster on large trees but is not a cryptographic hash.
+Digests computed with different algorithms are not comparable.
+
+Use --json to include per-project sub-fingerprints.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			algo, err := hashing.Parse(hashAlgo)
+			if err != nil {
+				return fmt.Errorf("invalid --hash-algo: %w", err)
+			}
+			return RunFingerprint(inputFile, jsonOut, algo)
+		},
+	}
+
+	cmd.Flags().StringVarP(&inputFile, "file", "f", projectsFileName, "Projects configuration file")
+	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output in JSON format with per-project fingerprints")
+	cmd.Flags().StringVar(&hashAlgo, "hash-algo", string(hashing.Default), "Content hash algorithm: sha256, sha1, or xxhash")
+
+	return cmd
+}
+
+// FingerprintOutput represents the machine-readable fingerprint output.
+type FingerprintOutput struct {
+	Digest   string                     `json:"digest"`
+	Files    int                        `json:"files"`
+	Projects []ProjectFingerprintOutput `json:"projects"`
+}
+
+// ProjectFingerprintOutput represents the fingerprint of a single project.
+type ProjectFingerprintOutput struct {
+	Name     string                     `json:"name"`
+	Path     string                     `json:"path"`
+	Digest   string                     `json:"digest"`
+	Files    int                        `json:"files"`
+	Children []ProjectFingerprintOutput `json:"children,omitempty"`
+}
+
+// RunFingerprint computes and prints the fingerprint of the projects in
+// inputFile, hashing file contents with algo.
+func RunFingerprint(inputFile string, jsonOut bool, algo hashing.Algorithm) error {
+	config, rootDir, err := loadProjectsFile(inputFile)
+	if err != nil {
+		return err
+	}
+
+	counter, err := stats.NewCounterWithOptions(rootDir, stats.Options{HashAlgorithm: algo, FollowSymlinks: followSymlinks})
+	if err != nil {
+		return fmt.Errorf("failed to create stats counter: %w", err)
+	}
+
+	projectStats, err := counter.CountHierarchy(context.Background(), config.Projects, stats.HierarchyOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to calculate statistics: %w", err)
+	}
+
+	fp, err := counter.Fingerprint(projectStats)
+	if err != nil {
+		return fmt.Errorf("failed to compute fingerprint: %w", err)
+	}
+
+	if !jsonOut {
+		fmt.Println(fp.Digest)
+		return nil
+	}
+
+	output := FingerprintOutput{
+		Digest:   fp.Digest,
+		Files:    fp.Files,
+		Projects: convertProjectFingerprints(fp.Projects),
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(output)
+}
+
+func convertProjectFingerprints(fps []*models.ProjectFingerprint) []ProjectFingerprintOutput {
+	result := make([]ProjectFingerprintOutput, 0, len(fps))
+	for _, fp := range fps {
+		result = append(result, ProjectFingerprintOutput{
+			Name:     fp.Project.Name,
+			Path:     fp.Project.Path,
+			Digest:   fp.Digest,
+			Files:    fp.Files,
+			Children: convertProjectFingerprints(fp.Children),
+		})
+	}
+	return result
+}
@@ -0,0 +1,154 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"repoctr/internal/config"
+	"repoctr/internal/ignore"
+	"repoctr/internal/stats"
+)
+
+// NewInitConfigFromGitignoreCmd creates the init-config-from-gitignore command.
+func NewInitConfigFromGitignoreCmd() *cobra.Command {
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "init-config-from-gitignore",
+		Short: "Add .gitignore patterns to .repoctrconfig.yaml global excludes",
+		Long: `Reads .gitignore in the current directory and adds the patterns that can
+affect line counts to global-excludes in .repoctrconfig.yaml, creating the
+file if needed.
+
+A pattern is kept when it names a directory (e.g. "out/", "coverage") or
+matches files with a source extension (e.g. "*.min.js", "*_gen.go").
+Negations (e.g. "!keep.min.js") that follow a kept pattern are kept too, so
+files .gitignore re-includes are still counted. Comments, other negations,
+patterns for files that are never counted (e.g. "*.log", ".env"),
+directories ignored by default, and patterns already in global-excludes are
+skipped.
+
+Use --dry-run to review the selected patterns without writing the config.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			rootDir, _ := filepath.Abs(".")
+			return runInitConfigFromGitignore(rootDir, dryRun, os.Stdout)
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the patterns that would be added without writing the config")
+
+	return cmd
+}
+
+func runInitConfigFromGitignore(rootDir string, dryRun bool, w io.Writer) error {
+	file, err := os.Open(filepath.Join(rootDir, ".gitignore"))
+	if err != nil {
+		return fmt.Errorf("failed to read .gitignore: %w", err)
+	}
+	defer file.Close()
+
+	selected, skipped, err := selectGitignoreExcludes(file)
+	if err != nil {
+		return fmt.Errorf("failed to read .gitignore: %w", err)
+	}
+
+	cfg, err := config.LoadConfig(rootDir)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	var added []string
+	for _, pattern := range selected {
+		if !slices.Contains(cfg.GlobalExcludes, pattern) && !slices.Contains(added, pattern) {
+			added = append(added, pattern)
+		}
+	}
+
+	if len(added) == 0 {
+		fmt.Fprintln(w, "No new .gitignore patterns to add to global-excludes")
+		return nil
+	}
+
+	fmt.Fprintf(w, "Global excludes from .gitignore (%d skipped as irrelevant to line counts):\n", skipped)
+	for _, pattern := range added {
+		fmt.Fprintf(w, "  + %s\n", pattern)
+	}
+
+	if dryRun {
+		fmt.Fprintf(w, "\nDry run: %s not changed\n", filepath.Base(config.ConfigPath(rootDir)))
+		return nil
+	}
+
+	cfg.GlobalExcludes = append(cfg.GlobalExcludes, added...)
+	if err := config.SaveConfig(rootDir, cfg); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	fmt.Fprintf(w, "\nAdded %d pattern(s) to %s\n", len(added), config.ConfigPath(rootDir))
+	return nil
+}
+
+// selectGitignoreExcludes returns the .gitignore patterns in r that can
+// change line counts, in file order, and the number of patterns skipped.
+// A negation is kept when it follows a selected pattern, which it may
+// refine: global-excludes outrank .gitignore, so without it the files
+// .gitignore re-includes would become excluded.
+func selectGitignoreExcludes(r io.Reader) ([]string, int, error) {
+	var selected []string
+	skipped := 0
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		pattern := strings.TrimSpace(scanner.Text())
+		if pattern == "" || strings.HasPrefix(pattern, "#") {
+			continue
+		}
+		if strings.HasPrefix(pattern, "!") && len(selected) > 0 || isCountRelevantPattern(pattern) {
+			selected = append(selected, pattern)
+		} else {
+			skipped++
+		}
+	}
+	return selected, skipped, scanner.Err()
+}
+
+// isCountRelevantPattern reports whether excluding the gitignore pattern
+// could change line counts: it names a directory, which may hold sources,
+// or its last segment matches a source file extension. Negations are not,
+// since on their own they exclude nothing, and neither are directories the
+// ignore matcher already skips by default.
+func isCountRelevantPattern(pattern string) bool {
+	if strings.HasPrefix(pattern, "!") {
+		return false
+	}
+
+	name := path.Base(strings.TrimSuffix(strings.TrimPrefix(pattern, "/"), "/"))
+	if slices.Contains(ignore.DefaultIgnorePatterns, name) && !strings.Contains(strings.Trim(pattern, "/"), "/") {
+		return false
+	}
+
+	if strings.HasSuffix(pattern, "/") {
+		return true
+	}
+
+	// A bare "*" is the start of a whitelist (e.g. "*" then "!src/"), which
+	// only works together with its negations
+	if strings.Trim(name, "*") == "" {
+		return false
+	}
+
+	ext := path.Ext(name)
+	if ext == "" {
+		// Bare names such as "out" or "coverage" are usually directories
+		return !strings.HasPrefix(name, ".")
+	}
+	return ext != name && stats.IsSourceExtension(ext)
+}
@@ -0,0 +1,145 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"repoctr/internal/history"
+)
+
+// NewHistoryCmd creates the history command.
+func NewHistoryCmd() *cobra.Command {
+	var inputFile string
+	var projectName string
+	var byYear bool
+	var jsonOut bool
+
+	cmd := &cobra.Command{
+		Use:   "history [directory]",
+		Short: "Show how the repository's code grew over time",
+		Long: `Reads the git history of a repository and reports how its code grew.
+
+--by-year attributes the lines added and deleted by every commit to the
+calendar year it was authored, from 'git log --numstat', giving a growth
+timeline with a running total of net lines. Binary files are ignored.
+
+Use --project to limit the history to one project's path from
+projects.yaml, and --json for output suitable for charting.
+
+Examples:
+  repo-ctr history --by-year
+  repo-ctr history --by-year --project api --json`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !byYear {
+				return errors.New("no history report selected; use --by-year")
+			}
+			dir := "."
+			if len(args) > 0 {
+				dir = args[0]
+			}
+			return RunHistory(dir, inputFile, projectName, jsonOut, os.Stdout)
+		},
+	}
+
+	cmd.Flags().StringVarP(&inputFile, "file", "f", projectsFileName, "Projects configuration file, used with --project")
+	cmd.Flags().StringVarP(&projectName, "project", "p", "", "Limit the history to a project from projects.yaml")
+	cmd.Flags().BoolVar(&byYear, "by-year", false, "Attribute net added lines to calendar years")
+	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output in JSON format")
+
+	return cmd
+}
+
+// HistoryOutput represents the machine-readable yearly history.
+type HistoryOutput struct {
+	Project string              `json:"project,omitempty"`
+	Path    string              `json:"path,omitempty"`
+	Years   []HistoryYearOutput `json:"years"`
+}
+
+// HistoryYearOutput represents the line changes of one calendar year.
+type HistoryYearOutput struct {
+	Year    int `json:"year"`
+	Commits int `json:"commits"`
+	Added   int `json:"added"`
+	Deleted int `json:"deleted"`
+	Net     int `json:"net"`
+	Total   int `json:"total"`
+}
+
+// RunHistory writes the yearly growth of the git repository in dir to w.
+// If projectName is set, dir is ignored and the history is limited to that
+// project's path, resolved against the directory containing inputFile.
+func RunHistory(dir, inputFile, projectName string, jsonOut bool, w io.Writer) error {
+	output := HistoryOutput{Project: projectName}
+
+	var paths []string
+	if projectName != "" {
+		config, rootDir, err := loadProjectsFile(inputFile)
+		if err != nil {
+			return err
+		}
+		project := findProjectByName(config.Projects, projectName)
+		if project == nil {
+			return fmt.Errorf("project %q not found in %s", projectName, inputFile)
+		}
+		dir = rootDir
+		output.Path = filepath.ToSlash(project.Path)
+		paths = []string{project.Path}
+	}
+
+	years, err := history.ByYear(dir, paths)
+	if err != nil {
+		return err
+	}
+
+	output.Years = make([]HistoryYearOutput, 0, len(years))
+	for _, y := range years {
+		output.Years = append(output.Years, HistoryYearOutput{
+			Year:    y.Year,
+			Commits: y.Commits,
+			Added:   y.Added,
+			Deleted: y.Deleted,
+			Net:     y.Net(),
+			Total:   y.Total,
+		})
+	}
+
+	if jsonOut {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(output)
+	}
+
+	writeHistory(w, output)
+	return nil
+}
+
+// writeHistory prints a human-readable yearly history.
+func writeHistory(w io.Writer, output HistoryOutput) {
+	separator := strings.Repeat("─", 60)
+
+	fmt.Fprintln(w, separator)
+	if output.Project != "" {
+		fmt.Fprintf(w, "\n📈 HISTORY BY YEAR: %s (%s)\n", output.Project, output.Path)
+	} else {
+		fmt.Fprintf(w, "\n📈 HISTORY BY YEAR\n")
+	}
+	fmt.Fprintln(w, separator)
+
+	if len(output.Years) == 0 {
+		fmt.Fprintln(w, "   No commits")
+		return
+	}
+
+	fmt.Fprintf(w, "   %-6s %8s %10s %10s %10s %10s\n", "Year", "Commits", "Added", "Deleted", "Net", "Total")
+	for _, y := range output.Years {
+		fmt.Fprintf(w, "   %-6d %8d %10d %10d %+10d %10d\n", y.Year, y.Commits, y.Added, y.Deleted, y.Net, y.Total)
+	}
+}
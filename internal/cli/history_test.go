@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"repoctr/internal/history"
+)
+
+func TestRunHistory_ProjectByYear(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "projects.yaml", `projects:
+  - name: api
+    path: services/api
+    runtime:
+      type: Go
+`)
+
+	restore := history.GitLog
+	defer func() { history.GitLog = restore }()
+
+	var gotDir string
+	var gotArgs []string
+	history.GitLog = func(dir string, args ...string) ([]byte, error) {
+		gotDir, gotArgs = dir, args
+		return []byte("commit 2024-02-01T10:00:00Z\n40\t10\tservices/api/main.go\n\n" +
+			"commit 2023-05-01T10:00:00Z\n20\t0\tservices/api/main.go\n"), nil
+	}
+
+	var out bytes.Buffer
+	if err := RunHistory(".", filepath.Join(root, "projects.yaml"), "api", true, &out); err != nil {
+		t.Fatalf("RunHistory: %v", err)
+	}
+
+	if gotDir != root {
+		t.Errorf("git ran in %q, want %q", gotDir, root)
+	}
+	if !slices.Contains(gotArgs, "services/api") {
+		t.Errorf("git args %v do not limit the log to services/api", gotArgs)
+	}
+
+	var output HistoryOutput
+	if err := json.Unmarshal(out.Bytes(), &output); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, out.String())
+	}
+	want := []HistoryYearOutput{
+		{Year: 2023, Commits: 1, Added: 20, Deleted: 0, Net: 20, Total: 20},
+		{Year: 2024, Commits: 1, Added: 40, Deleted: 10, Net: 30, Total: 50},
+	}
+	if output.Path != "services/api" || !slices.Equal(output.Years, want) {
+		t.Errorf("output = %+v, want path services/api and years %+v", output, want)
+	}
+}
+
+func TestRunHistory_UnknownProject(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "projects.yaml", "projects: []\n")
+
+	var out bytes.Buffer
+	if err := RunHistory(".", filepath.Join(root, "projects.yaml"), "missing", false, &out); err == nil {
+		t.Error("expected an error for an unknown project")
+	}
+}
@@ -1,45 +1,190 @@
 package cli
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 	"repoctr/internal/config"
 	"repoctr/internal/detector"
 	"repoctr/internal/discovery"
+	"repoctr/internal/ignore"
+	"repoctr/internal/stats"
 	"repoctr/pkg/models"
 )
 
+// IdentifyOptions controls project discovery.
+type IdentifyOptions struct {
+	// FailOnEmpty returns an error when no projects are discovered.
+	FailOnEmpty bool
+	// Verbose prints non-fatal detection warnings, such as manifests that
+	// could only be partially parsed.
+	Verbose bool
+	// Strict returns an error when any detection warning is reported.
+	Strict bool
+	// Format is the output encoding: "yaml" (default) or "json".
+	Format string
+	// Stdout writes the discovered projects to stdout instead of the output
+	// file. Progress messages go to stderr so stdout stays parseable.
+	Stdout bool
+	// ExcludeEmpty drops discovered projects without any source files, such
+	// as freshly scaffolded ones that only have a manifest. By default they
+	// are recorded like any other project.
+	ExcludeEmpty bool
+	// MaxDepth stops discovery from descending more than this many
+	// directory levels below each scanned path. Zero means unlimited.
+	MaxDepth int
+	// PathIncludes, when set, limits discovery to manifests matching one of
+	// these gitignore-style patterns, relative to each scanned path.
+	PathIncludes []string
+	// PathExcludes skips directories and manifests matching any of these
+	// patterns, on top of the ignore rules.
+	PathExcludes []string
+}
+
 // NewIdentifyCmd creates the identify command.
 func NewIdentifyCmd() *cobra.Command {
 	var outputFile string
+	var includeEmpty bool
+	var opts IdentifyOptions
 
 	cmd := &cobra.Command{
 		Use:   "identify [paths...]",
 		Short: "Discover projects in the specified paths",
 		Long: `Recursively scans the specified directories to discover projects.
 Detects projects based on manifest files (go.mod, package.json, etc.).
-Builds a hierarchical project tree and outputs to projects.yaml.`,
+Builds a hierarchical project tree and outputs to projects.yaml.
+
+Use --fail-on-empty to exit with status 1 when no projects are found.
+Use the global --verbose flag to print detection warnings (e.g. a malformed
+.csproj whose version could not be read) and log each detected manifest and
+skipped directory, or --strict to fail when any warnings are reported.
+
+Projects with a manifest but no source files yet are recorded by default
+(--include-empty); use --exclude-empty to leave them out of projects.yaml.
+
+Use --max-depth N to only look for manifests in each path and the first N
+levels of subdirectories below it, which speeds up scanning large trees.
+Use --path-include and --path-exclude (repeatable, gitignore-style) to scan
+only matching subtrees:
+  repo-ctr identify . --path-include "services/**" --path-exclude "**/testdata/**"
+
+Use --stdout to print the discovered projects instead of writing a file,
+and --format json for JSON output:
+  repo-ctr identify . --stdout --format json | jq '.projects[].name'`,
 		Args: cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return RunIdentify(args, outputFile)
+			if opts.MaxDepth < 0 {
+				return fmt.Errorf("--max-depth must not be negative")
+			}
+			opts.Verbose = verbosity == VerbosityVerbose
+			if cmd.Flags().Changed("include-empty") {
+				opts.ExcludeEmpty = !includeEmpty
+			}
+			return RunIdentify(args, outputFile, opts)
 		},
 	}
 
 	cmd.Flags().StringVarP(&outputFile, "output", "o", projectsFileName, "Output file path")
+	cmd.Flags().BoolVar(&opts.FailOnEmpty, "fail-on-empty", false, "Exit with an error if no projects are discovered")
+	cmd.Flags().BoolVar(&opts.Strict, "strict", false, "Exit with an error if any detection warnings are reported")
+	cmd.Flags().StringVar(&opts.Format, "format", "yaml", "Output format: yaml or json")
+	cmd.Flags().BoolVar(&opts.Stdout, "stdout", false, "Print discovered projects to stdout instead of writing the output file")
+	cmd.Flags().BoolVar(&includeEmpty, "include-empty", true, "Record projects that have a manifest but no source files")
+	cmd.Flags().BoolVar(&opts.ExcludeEmpty, "exclude-empty", false, "Leave out projects that have a manifest but no source files")
+	cmd.MarkFlagsMutuallyExclusive("include-empty", "exclude-empty")
+	cmd.Flags().StringArrayVar(&opts.PathIncludes, "path-include", nil, "Only discover manifests matching this gitignore-style pattern, e.g. 'services/**' (repeatable)")
+	cmd.Flags().StringArrayVar(&opts.PathExcludes, "path-exclude", nil, "Do not descend into or discover manifests matching this pattern (repeatable)")
+	cmd.Flags().IntVar(&opts.MaxDepth, "max-depth", 0, "Descend at most N directory levels below each path (default: unlimited)")
 
 	return cmd
 }
 
 // RunIdentify discovers projects in the given paths and writes to outputFile.
-func RunIdentify(paths []string, outputFile string) error {
+func RunIdentify(paths []string, outputFile string, opts IdentifyOptions) error {
+	if opts.Format != "" && opts.Format != "yaml" && opts.Format != "json" {
+		return fmt.Errorf("unknown format: %s (expected yaml or json)", opts.Format)
+	}
+
+	// Keep stdout clean for the projects when printing them there
+	var status io.Writer = os.Stdout
+	if opts.Stdout {
+		status = os.Stderr
+	}
+
+	mergedProjects, err := identifyProjects(paths, outputFile, opts, status)
+	if err != nil || mergedProjects == nil {
+		return err
+	}
+
+	// Create config
+	projectsConfig := models.ProjectsConfig{
+		Projects: mergedProjects,
+	}
+
+	if opts.Stdout {
+		return writeProjectsConfig(os.Stdout, projectsConfig, opts.Format)
+	}
+
+	var content string
+	if opts.Format == "json" {
+		data, err := json.MarshalIndent(projectsConfig, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal projects: %w", err)
+		}
+		content = string(data) + "\n"
+	} else {
+		// Marshal to YAML, keeping the comments users added to the
+		// previous version of the file
+		existing, _ := os.ReadFile(outputFile)
+		data, err := marshalKeepingComments(projectsConfig, existing)
+		if err != nil {
+			return fmt.Errorf("failed to marshal projects: %w", err)
+		}
+
+		// Add header comment
+		header := fmt.Sprintf(`# projects.yaml - Repository project configuration
+# Generated by repo-ctr identify
+# Total projects discovered: %d
+
+`, countProjects(mergedProjects))
+		content = header + string(data)
+	}
+
+	// Write file
+	if err := os.WriteFile(outputFile, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", outputFile, err)
+	}
+
+	absOutput, _ := filepath.Abs(outputFile)
+	infof(os.Stdout, "\nWrote %d project(s) to %s\n", countProjects(mergedProjects), absOutput)
+	printProjectSummary(mergedProjects, 0)
+
+	return nil
+}
+
+// identifyProjects discovers the projects in paths and merges them with
+// those already in outputFile, if it exists. Progress goes to status. It
+// returns nil if no projects are discovered.
+func identifyProjects(paths []string, outputFile string, opts IdentifyOptions, status io.Writer) ([]*models.Project, error) {
 	registry := detector.NewRegistry()
 	builder := discovery.NewHierarchyBuilder()
 
+	var filter *ignore.PathFilter
+	if len(opts.PathIncludes) > 0 || len(opts.PathExcludes) > 0 {
+		var err error
+		if filter, err = ignore.NewPathFilter(opts.PathIncludes, opts.PathExcludes); err != nil {
+			return nil, err
+		}
+	}
+
 	var allProjects []*models.Project
+	var warnings []detector.Warning
 
 	// Process each input path
 	for _, path := range paths {
@@ -61,13 +206,17 @@ func RunIdentify(paths []string, outputFile string) error {
 			continue
 		}
 
-		fmt.Printf("Scanning %s...\n", absPath)
+		infof(status, "Scanning %s...\n", absPath)
 
 		walker, err := discovery.NewWalker(absPath, registry)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: failed to create walker for %s: %v\n", path, err)
 			continue
 		}
+		walker.SetLogf(verboseLogf())
+		walker.SetFollowSymlinks(followSymlinks)
+		walker.SetMaxDepth(opts.MaxDepth)
+		walker.SetPathFilter(filter)
 
 		projects, err := walker.Discover()
 		if err != nil {
@@ -75,13 +224,37 @@ func RunIdentify(paths []string, outputFile string) error {
 			continue
 		}
 
+		found := len(projects)
+		if opts.ExcludeEmpty {
+			projects, err = dropEmptyProjects(absPath, projects)
+			if err != nil {
+				return nil, err
+			}
+		}
+
 		allProjects = append(allProjects, projects...)
-		fmt.Printf("  Found %d project(s)\n", len(projects))
+		warnings = append(warnings, walker.Warnings()...)
+		infof(status, "  Found %d project(s)\n", found)
+		if skipped := found - len(projects); skipped > 0 {
+			infof(status, "  Skipped %d project(s) without source files\n", skipped)
+		}
+	}
+
+	if opts.Verbose || opts.Strict {
+		for _, w := range warnings {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+		}
+	}
+	if opts.Strict && len(warnings) > 0 {
+		return nil, fmt.Errorf("%d detection warning(s) reported", len(warnings))
 	}
 
 	if len(allProjects) == 0 {
-		fmt.Println("No projects discovered.")
-		return nil
+		if opts.FailOnEmpty {
+			return nil, fmt.Errorf("no projects discovered in %s", strings.Join(paths, ", "))
+		}
+		infof(status, "No projects discovered.\n")
+		return nil, nil
 	}
 
 	// Build hierarchy
@@ -112,37 +285,46 @@ func RunIdentify(paths []string, outputFile string) error {
 	}
 
 	// Merge projects (non-destructive)
-	mergedProjects := config.MergeProjects(hierarchy, existingProjects, cfg)
-
-	// Create config
-	projectsConfig := models.ProjectsConfig{
-		Projects: mergedProjects,
+	merged, overrideWarnings := config.MergeProjects(hierarchy, existingProjects, cfg)
+	for _, w := range overrideWarnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
 	}
+	return merged, nil
+}
 
-	// Marshal to YAML
-	data, err := yaml.Marshal(projectsConfig)
+// dropEmptyProjects returns the projects, whose paths are relative to root,
+// that contain at least one source file for their runtime.
+func dropEmptyProjects(root string, projects []*models.Project) ([]*models.Project, error) {
+	counter, err := stats.NewCounterWithOptions(root, stats.Options{FollowSymlinks: followSymlinks})
 	if err != nil {
-		return fmt.Errorf("failed to marshal projects: %w", err)
+		return nil, fmt.Errorf("failed to create stats counter: %w", err)
 	}
 
-	// Add header comment
-	header := fmt.Sprintf(`# projects.yaml - Repository project configuration
-# Generated by repo-ctr identify
-# Total projects discovered: %d
-
-`, countProjects(mergedProjects))
-	content := header + string(data)
-
-	// Write file
-	if err := os.WriteFile(outputFile, []byte(content), 0644); err != nil {
-		return fmt.Errorf("failed to write %s: %w", outputFile, err)
+	var kept []*models.Project
+	for _, project := range projects {
+		projectStats, err := counter.CountProject(project)
+		if err != nil || projectStats.TotalFiles == 0 {
+			continue
+		}
+		kept = append(kept, project)
 	}
+	return kept, nil
+}
 
-	absOutput, _ := filepath.Abs(outputFile)
-	fmt.Printf("\nWrote %d project(s) to %s\n", countProjects(mergedProjects), absOutput)
-	printProjectSummary(mergedProjects, 0)
+// writeProjectsConfig encodes the projects config to w as YAML or JSON.
+func writeProjectsConfig(w io.Writer, projectsConfig models.ProjectsConfig, format string) error {
+	if format == "json" {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(projectsConfig)
+	}
 
-	return nil
+	data, err := yaml.Marshal(projectsConfig)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
 }
 
 func countProjects(projects []*models.Project) int {
@@ -153,6 +335,8 @@ func countProjects(projects []*models.Project) int {
 	return count
 }
 
+// printProjectSummary lists projects as an indented tree unless --quiet is
+// set.
 func printProjectSummary(projects []*models.Project, depth int) {
 	indent := ""
 	for i := 0; i < depth; i++ {
@@ -160,11 +344,17 @@ func printProjectSummary(projects []*models.Project, depth int) {
 	}
 
 	for _, p := range projects {
-		version := ""
+		runtime := string(p.Runtime.Type)
+		if runtime == "" {
+			runtime = "unknown"
+		}
 		if p.Runtime.Version != "" {
-			version = " " + p.Runtime.Version
+			runtime += " " + p.Runtime.Version
+		}
+		if p.Framework != "" {
+			runtime += ", " + p.Framework
 		}
-		fmt.Printf("%s  - %s (%s%s)\n", indent, p.Name, p.Runtime.Type, version)
+		infof(os.Stdout, "%s  - %s (%s)\n", indent, p.Name, runtime)
 		printProjectSummary(p.Children, depth+1)
 	}
 }
@@ -0,0 +1,236 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"repoctr/pkg/models"
+)
+
+func TestRunIdentify_FailOnEmpty(t *testing.T) {
+	dir := t.TempDir()
+	outputFile := filepath.Join(dir, projectsFileName)
+
+	err := RunIdentify([]string{dir}, outputFile, IdentifyOptions{FailOnEmpty: true})
+	if err == nil {
+		t.Fatal("expected error for empty directory with --fail-on-empty")
+	}
+	if !strings.Contains(err.Error(), "no projects discovered") {
+		t.Errorf("unexpected error message: %v", err)
+	}
+
+	if _, err := os.Stat(outputFile); !os.IsNotExist(err) {
+		t.Errorf("expected no %s to be written", projectsFileName)
+	}
+}
+
+func TestRunIdentify_EmptyWithoutFlag(t *testing.T) {
+	dir := t.TempDir()
+
+	if err := RunIdentify([]string{dir}, filepath.Join(dir, projectsFileName), IdentifyOptions{}); err != nil {
+		t.Errorf("expected no error by default, got %v", err)
+	}
+}
+
+func TestRunIdentify_Quiet(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "go.mod", "module example.com/app\n\ngo 1.22\n")
+	outputFile := filepath.Join(dir, projectsFileName)
+
+	SetVerbosity(VerbosityQuiet)
+	t.Cleanup(func() { SetVerbosity(VerbosityNormal) })
+
+	out := captureStdout(t, func() {
+		if err := RunIdentify([]string{dir}, outputFile, IdentifyOptions{}); err != nil {
+			t.Fatalf("RunIdentify: %v", err)
+		}
+	})
+	if len(out) != 0 {
+		t.Errorf("quiet identify printed:\n%s", out)
+	}
+	if _, err := os.Stat(outputFile); err != nil {
+		t.Errorf("expected %s to be written: %v", projectsFileName, err)
+	}
+}
+
+func TestProjectsConfig_JSONRoundTrip(t *testing.T) {
+	original := models.ProjectsConfig{
+		Projects: []*models.Project{
+			{
+				Name:            "api",
+				Path:            "services/api",
+				Runtime:         models.Runtime{Type: models.RuntimeGo, Version: "1.22"},
+				ManifestFile:    "go.mod",
+				SourcePaths:     []string{"."},
+				ExcludePatterns: []string{"**/mocks/**"},
+				Children: []*models.Project{
+					{
+						Name:           "web",
+						Path:           "services/api/web",
+						Runtime:        models.Runtime{Type: models.RuntimeTypeScript},
+						ManifestFile:   "package.json",
+						SourcePaths:    []string{"src", "lib", "."},
+						SrcIgnorePaths: []string{"node_modules"},
+					},
+				},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := writeProjectsConfig(&buf, original, "json"); err != nil {
+		t.Fatalf("writeProjectsConfig: %v", err)
+	}
+
+	for _, key := range []string{`"manifest-file": "go.mod"`, `"source-paths"`, `"type": "Go"`, `"children"`} {
+		if !strings.Contains(buf.String(), key) {
+			t.Errorf("JSON output missing %s:\n%s", key, buf.String())
+		}
+	}
+
+	var decoded models.ProjectsConfig
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("json.Unmarshal: %v", err)
+	}
+	if !reflect.DeepEqual(original, decoded) {
+		t.Errorf("round trip mismatch:\n got %+v\nwant %+v", decoded, original)
+	}
+}
+
+func TestRunIdentify_StdoutJSON(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "go.mod", "module example.com/app\n\ngo 1.22\n")
+	outputFile := filepath.Join(dir, projectsFileName)
+
+	out := captureStdout(t, func() {
+		if err := RunIdentify([]string{dir}, outputFile, IdentifyOptions{Format: "json", Stdout: true}); err != nil {
+			t.Fatalf("RunIdentify: %v", err)
+		}
+	})
+
+	var cfg models.ProjectsConfig
+	if err := json.Unmarshal(out, &cfg); err != nil {
+		t.Fatalf("stdout is not valid JSON: %v\n%s", err, out)
+	}
+	if len(cfg.Projects) != 1 || cfg.Projects[0].ManifestFile != "go.mod" {
+		t.Errorf("projects = %+v, want one go.mod project", cfg.Projects)
+	}
+
+	if _, err := os.Stat(outputFile); !os.IsNotExist(err) {
+		t.Errorf("expected %s not to be written with --stdout", projectsFileName)
+	}
+}
+
+func TestRunIdentify_EmptyProjects(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "api/go.mod", "module example.com/api\n\ngo 1.22\n")
+	writeTestFile(t, dir, "api/main.go", "package main\n\nfunc main() {}\n")
+	// Freshly scaffolded: a manifest but no sources yet
+	writeTestFile(t, dir, "web/package.json", `{"name": "web", "version": "0.1.0"}`)
+
+	identify := func(opts IdentifyOptions) []string {
+		t.Helper()
+		opts.Format = "json"
+		opts.Stdout = true
+		out := captureStdout(t, func() {
+			if err := RunIdentify([]string{dir}, filepath.Join(dir, projectsFileName), opts); err != nil {
+				t.Fatalf("RunIdentify: %v", err)
+			}
+		})
+		var cfg models.ProjectsConfig
+		if err := json.Unmarshal(out, &cfg); err != nil {
+			t.Fatalf("stdout is not valid JSON: %v\n%s", err, out)
+		}
+		var names []string
+		for _, p := range cfg.Projects {
+			names = append(names, p.Name)
+		}
+		return names
+	}
+
+	if names := identify(IdentifyOptions{}); !reflect.DeepEqual(names, []string{"api", "web"}) {
+		t.Errorf("default projects = %v, want [api web]", names)
+	}
+	if names := identify(IdentifyOptions{ExcludeEmpty: true}); !reflect.DeepEqual(names, []string{"api"}) {
+		t.Errorf("projects with ExcludeEmpty = %v, want [api]", names)
+	}
+}
+
+func TestRunIdentify_KeepsComments(t *testing.T) {
+	dir := t.TempDir()
+	outputFile := filepath.Join(dir, projectsFileName)
+	writeTestFile(t, dir, "api/go.mod", "module example.com/api\n\ngo 1.22\n")
+	writeTestFile(t, dir, "api/main.go", "package main\n\nfunc main() {}\n")
+
+	identify := func() string {
+		t.Helper()
+		captureStdout(t, func() {
+			if err := RunIdentify([]string{dir}, outputFile, IdentifyOptions{}); err != nil {
+				t.Fatalf("RunIdentify: %v", err)
+			}
+		})
+		data, err := os.ReadFile(outputFile)
+		if err != nil {
+			t.Fatalf("read %s: %v", projectsFileName, err)
+		}
+		return string(data)
+	}
+
+	// Annotate the generated file by hand
+	content := identify()
+	annotated := strings.Replace(content, "    - name: api\n", "    # Owned by the platform team\n    - name: api\n", 1)
+	annotated = strings.Replace(annotated, "      path: api\n", "      path: api # do not move\n", 1)
+	if annotated == content || strings.Count(annotated, "#") != strings.Count(content, "#")+2 {
+		t.Fatalf("unexpected layout, could not annotate:\n%s", content)
+	}
+	if err := os.WriteFile(outputFile, []byte(annotated), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// A new project is merged in without losing the annotations
+	writeTestFile(t, dir, "web/package.json", `{"name": "web", "version": "0.1.0"}`)
+	content = identify()
+
+	for _, want := range []string{
+		"    # Owned by the platform team\n    - name: api\n",
+		"      path: api # do not move\n",
+		"name: web\n",
+		"# Total projects discovered: 2\n",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("re-identified %s is missing %q:\n%s", projectsFileName, want, content)
+		}
+	}
+	if strings.Contains(content, "discovered: 1") {
+		t.Errorf("stale header kept:\n%s", content)
+	}
+}
+
+// captureStdout runs fn and returns everything it wrote to os.Stdout.
+func captureStdout(t *testing.T, fn func()) []byte {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe: %v", err)
+	}
+	orig := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = orig }()
+
+	done := make(chan []byte)
+	go func() {
+		data, _ := io.ReadAll(r)
+		done <- data
+	}()
+
+	fn()
+	w.Close()
+	return <-done
+}
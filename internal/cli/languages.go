@@ -0,0 +1,149 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"repoctr/internal/stats"
+	"repoctr/pkg/models"
+	pkgstats "repoctr/pkg/stats"
+)
+
+// NewLanguagesCmd creates the languages command.
+func NewLanguagesCmd() *cobra.Command {
+	var inputFile string
+	var badge bool
+
+	cmd := &cobra.Command{
+		Use:   "languages",
+		Short: "Show code totals per language",
+		Long: `Reads projects.yaml and prints code totals grouped by language (runtime
+type) across the whole project hierarchy, largest first.
+
+Use --badge to print a shields.io endpoint payload naming the dominant
+language and its share of code lines. Host the JSON anywhere public and
+point https://img.shields.io/endpoint?url=... at it for a README badge.
+
+Examples:
+  repo-ctr languages
+  repo-ctr languages --badge > badge.json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunLanguages(inputFile, badge, os.Stdout)
+		},
+	}
+
+	cmd.Flags().StringVarP(&inputFile, "file", "f", projectsFileName, "Projects configuration file")
+	cmd.Flags().BoolVar(&badge, "badge", false, "Output a shields.io endpoint badge for the dominant language")
+
+	return cmd
+}
+
+// LanguageBadge is a shields.io endpoint payload.
+// See https://shields.io/badges/endpoint-badge.
+type LanguageBadge struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	Label         string `json:"label"`
+	Message       string `json:"message"`
+	Color         string `json:"color"`
+}
+
+// RunLanguages counts the projects in inputFile and writes the per-language
+// totals to w, or the dominant-language badge if badge is set.
+func RunLanguages(inputFile string, badge bool, w io.Writer) error {
+	config, rootDir, err := loadProjectsFile(inputFile)
+	if err != nil {
+		return err
+	}
+
+	result, err := pkgstats.Compute(rootDir, config.Projects, pkgstats.Options{FollowSymlinks: followSymlinks})
+	if err != nil {
+		return err
+	}
+
+	if badge {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(buildLanguageBadge(result.ByLanguage))
+	}
+
+	stats.NewReporter(w).ReportLanguages(result.ByLanguage)
+	return nil
+}
+
+// buildLanguageBadge summarizes the language with the most code lines and
+// its share of all code lines. langs must be ordered largest first, as
+// returned by stats.AggregateByLanguage.
+func buildLanguageBadge(langs []*models.LanguageStats) LanguageBadge {
+	badge := LanguageBadge{
+		SchemaVersion: 1,
+		Label:         "language",
+		Message:       "none",
+		Color:         "lightgrey",
+	}
+
+	total := 0
+	for _, l := range langs {
+		total += l.CodeLines
+	}
+	if len(langs) == 0 || total == 0 {
+		return badge
+	}
+
+	top := langs[0]
+	name := string(top.Runtime)
+	if name == "" {
+		name = "Other"
+	}
+	badge.Message = fmt.Sprintf("%s %.1f%%", name, float64(top.CodeLines)*100/float64(total))
+	badge.Color = badgeColor(top.Runtime)
+	return badge
+}
+
+// badgeColor returns the badge color of a runtime type, following the
+// language colors GitHub uses.
+func badgeColor(rt models.RuntimeType) string {
+	switch rt {
+	case models.RuntimeGo:
+		return "00ADD8"
+	case models.RuntimePython:
+		return "3572A5"
+	case models.RuntimeJava:
+		return "b07219"
+	case models.RuntimeTypeScript:
+		return "3178c6"
+	case models.RuntimeJavaScript:
+		return "f1e05a"
+	case models.RuntimeDart:
+		return "00B4AB"
+	case models.RuntimeDotNet:
+		return "178600"
+	case models.RuntimeRust:
+		return "dea584"
+	case models.RuntimeCpp:
+		return "f34b7d"
+	case models.RuntimeHaskell:
+		return "5e5086"
+	case models.RuntimePHP:
+		return "4F5D95"
+	case models.RuntimeSQL:
+		return "e38c00"
+	case models.RuntimeNim:
+		return "ffc200"
+	case models.RuntimeR:
+		return "198ce7"
+	case models.RuntimeSwift:
+		return "F05138"
+	case models.RuntimeClojure:
+		return "db5855"
+	case models.RuntimeOCaml:
+		return "ef7a08"
+	case models.RuntimeKotlin:
+		return "A97BFF"
+	default:
+		return "lightgrey"
+	}
+}
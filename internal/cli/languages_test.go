@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"repoctr/pkg/models"
+)
+
+func TestBuildLanguageBadge(t *testing.T) {
+	langs := []*models.LanguageStats{
+		{Runtime: models.RuntimeGo, CodeLines: 750},
+		{Runtime: models.RuntimeTypeScript, CodeLines: 200},
+		{Runtime: models.RuntimePython, CodeLines: 50},
+	}
+
+	var out bytes.Buffer
+	if err := json.NewEncoder(&out).Encode(buildLanguageBadge(langs)); err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(out.Bytes(), &payload); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	// shields.io requires schemaVersion 1 plus a label and a message
+	want := map[string]any{
+		"schemaVersion": float64(1),
+		"label":         "language",
+		"message":       "Go 75.0%",
+		"color":         "00ADD8",
+	}
+	for key, value := range want {
+		if payload[key] != value {
+			t.Errorf("%s = %v, want %v", key, payload[key], value)
+		}
+	}
+
+	// The color follows the top language
+	langs[0], langs[1] = langs[1], langs[0]
+	langs[0].CodeLines, langs[1].CodeLines = 750, 200
+	if badge := buildLanguageBadge(langs); badge.Color != badgeColor(models.RuntimeTypeScript) || badge.Message != "TypeScript 75.0%" {
+		t.Errorf("badge = %+v, want TypeScript color and message", badge)
+	}
+}
+
+func TestBuildLanguageBadge_NoCode(t *testing.T) {
+	badge := buildLanguageBadge(nil)
+	if badge.SchemaVersion != 1 || badge.Message != "none" || badge.Color != "lightgrey" {
+		t.Errorf("badge = %+v, want schemaVersion 1, message none, color lightgrey", badge)
+	}
+}
@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// Verbosity controls how much repo-ctr prints besides its results.
+type Verbosity int
+
+const (
+	// VerbosityNormal prints informational messages such as
+	// "Scanning <dir>...".
+	VerbosityNormal Verbosity = iota
+	// VerbosityQuiet prints results, warnings, and errors only.
+	VerbosityQuiet
+	// VerbosityVerbose also logs every detected manifest and skipped
+	// directory to stderr.
+	VerbosityVerbose
+)
+
+// verbosity is set once from the global --quiet and --verbose flags.
+var verbosity = VerbosityNormal
+
+// SetVerbosity sets the verbosity of every command.
+func SetVerbosity(v Verbosity) {
+	verbosity = v
+}
+
+// infof prints an informational message to w unless --quiet is set.
+func infof(w io.Writer, format string, args ...any) {
+	if verbosity == VerbosityQuiet {
+		return
+	}
+	fmt.Fprintf(w, format, args...)
+}
+
+// verboseLogf returns a logger for discovery.Walker.SetLogf and
+// stats.Options.Logf that writes one line per message to stderr, or nil
+// unless --verbose is set.
+func verboseLogf() func(format string, args ...any) {
+	if verbosity != VerbosityVerbose {
+		return nil
+	}
+	return func(format string, args ...any) {
+		fmt.Fprintf(os.Stderr, format+"\n", args...)
+	}
+}
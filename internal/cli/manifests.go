@@ -0,0 +1,179 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+	"repoctr/internal/detector"
+	"repoctr/internal/discovery"
+	"repoctr/internal/emoji"
+	"repoctr/internal/stats"
+	"repoctr/pkg/models"
+)
+
+// ManifestStatsOutput represents the machine-readable output of
+// 'stats --stats-of-manifest'.
+type ManifestStatsOutput struct {
+	XMLName   xml.Name                `xml:"manifests" json:"-" yaml:"-"`
+	Manifests []ManifestOutput        `yaml:"manifests" json:"manifests" xml:"manifest"`
+	ByRuntime []ManifestRuntimeOutput `yaml:"by_runtime" json:"by_runtime" xml:"by_runtime>runtime"`
+}
+
+// ManifestOutput represents a single manifest file.
+type ManifestOutput struct {
+	Path            string `yaml:"path" json:"path" xml:"path"`
+	Project         string `yaml:"project" json:"project" xml:"project"`
+	Runtime         string `yaml:"runtime" json:"runtime" xml:"runtime"`
+	Lines           int    `yaml:"lines" json:"lines" xml:"lines"`
+	DependencyCount int    `yaml:"dependency_count" json:"dependency_count" xml:"dependency_count"`
+}
+
+// ManifestRuntimeOutput represents the manifest totals of a runtime type.
+type ManifestRuntimeOutput struct {
+	Runtime         string `yaml:"runtime" json:"runtime" xml:"name"`
+	Manifests       int    `yaml:"manifests" json:"manifests" xml:"manifests"`
+	Lines           int    `yaml:"lines" json:"lines" xml:"lines"`
+	DependencyCount int    `yaml:"dependency_count" json:"dependency_count" xml:"dependency_count"`
+}
+
+// runManifestStats lists every manifest discovered under rootDir with its
+// line and dependency counts, followed by per-runtime totals. Source files
+// are not counted.
+func runManifestStats(rootDir string, opts StatsOptions, w io.Writer) error {
+	walker, err := discovery.NewWalker(rootDir, detector.NewRegistry())
+	if err != nil {
+		return fmt.Errorf("failed to create walker for %s: %w", rootDir, err)
+	}
+	walker.SetLogf(verboseLogf())
+	walker.SetFollowSymlinks(followSymlinks)
+
+	manifests, err := walker.DiscoverManifests()
+	if err != nil {
+		return fmt.Errorf("discovery failed for %s: %w", rootDir, err)
+	}
+
+	counter, err := stats.NewCounter(rootDir)
+	if err != nil {
+		return fmt.Errorf("failed to create stats counter: %w", err)
+	}
+
+	output := ManifestStatsOutput{
+		Manifests: make([]ManifestOutput, 0, len(manifests)),
+		ByRuntime: []ManifestRuntimeOutput{},
+	}
+	for _, m := range manifests {
+		fileStats, err := counter.CountFile(filepath.Join(rootDir, m.Path))
+		if err != nil {
+			return fmt.Errorf("failed to count %s: %w", m.Path, err)
+		}
+		output.Manifests = append(output.Manifests, ManifestOutput{
+			Path:            filepath.ToSlash(m.Path),
+			Project:         m.Project.Name,
+			Runtime:         string(m.Project.Runtime.Type),
+			Lines:           fileStats.Lines,
+			DependencyCount: m.Project.DependencyCount,
+		})
+	}
+	sort.Slice(output.Manifests, func(i, j int) bool {
+		return output.Manifests[i].Path < output.Manifests[j].Path
+	})
+	output.ByRuntime = aggregateManifests(output.Manifests)
+
+	switch determineFormat(opts.Machine, opts.Format) {
+	case FormatYAML:
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		return encoder.Encode(output)
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(output)
+	case FormatXML:
+		fmt.Fprintln(w, `<?xml version="1.0" encoding="UTF-8"?>`)
+		encoder := xml.NewEncoder(w)
+		encoder.Indent("", "  ")
+		if err := encoder.Encode(output); err != nil {
+			return err
+		}
+		fmt.Fprintln(w)
+		return nil
+	case FormatCSV:
+		return writeManifestsCSV(w, output.Manifests)
+	case FormatCSVLanguages, FormatCSVFiles, FormatMarkdown:
+		return fmt.Errorf("%s output is not supported with --stats-of-manifest", determineFormat(opts.Machine, opts.Format))
+	}
+
+	writeManifestReport(w, output)
+	return nil
+}
+
+// aggregateManifests sums manifests per runtime, ordered by manifest count
+// (descending), then by runtime name.
+func aggregateManifests(manifests []ManifestOutput) []ManifestRuntimeOutput {
+	byRuntime := make(map[string]*ManifestRuntimeOutput)
+	for _, m := range manifests {
+		totals, ok := byRuntime[m.Runtime]
+		if !ok {
+			totals = &ManifestRuntimeOutput{Runtime: m.Runtime}
+			byRuntime[m.Runtime] = totals
+		}
+		totals.Manifests++
+		totals.Lines += m.Lines
+		totals.DependencyCount += m.DependencyCount
+	}
+
+	result := make([]ManifestRuntimeOutput, 0, len(byRuntime))
+	for _, totals := range byRuntime {
+		result = append(result, *totals)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Manifests != result[j].Manifests {
+			return result[i].Manifests > result[j].Manifests
+		}
+		return result[i].Runtime < result[j].Runtime
+	})
+	return result
+}
+
+func writeManifestReport(w io.Writer, output ManifestStatsOutput) {
+	if len(output.Manifests) == 0 {
+		fmt.Fprintln(w, "No manifests found")
+		return
+	}
+
+	fmt.Fprintf(w, "📄 MANIFESTS\n")
+	for _, m := range output.Manifests {
+		fmt.Fprintf(w, "   %s %6d lines %5d deps   %s\n",
+			emoji.Map(models.RuntimeType(m.Runtime)), m.Lines, m.DependencyCount, m.Path)
+	}
+
+	fmt.Fprintf(w, "\n🌐 BY RUNTIME\n")
+	for _, r := range output.ByRuntime {
+		fmt.Fprintf(w, "   %s %-12s %4d manifests %7d lines %6d deps\n",
+			emoji.Map(models.RuntimeType(r.Runtime)), r.Runtime, r.Manifests, r.Lines, r.DependencyCount)
+	}
+}
+
+func writeManifestsCSV(w io.Writer, manifests []ManifestOutput) error {
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write([]string{"path", "project", "runtime", "lines", "dependency_count"}); err != nil {
+		return err
+	}
+	for _, m := range manifests {
+		row := []string{m.Path, m.Project, m.Runtime, strconv.Itoa(m.Lines), strconv.Itoa(m.DependencyCount)}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestRunManifestStats(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/api\n\ngo 1.22\n\nrequire github.com/spf13/cobra v1.8.0\n")
+	writeTestFile(t, root, "web/package.json", "{\n  \"name\": \"web\",\n  \"dependencies\": {\"react\": \"^18.0.0\", \"vite\": \"^5.0.0\"}\n}\n")
+	writeTestFile(t, root, "tools/requirements.txt", "requests==2.31.0\n")
+	writeTestFile(t, root, "tools/requirements-dev.txt", "pytest==8.0.0\nruff==0.4.0\n")
+	writeTestFile(t, root, "main.go", "package main\n")
+
+	var out bytes.Buffer
+	if err := runManifestStats(root, StatsOptions{Format: "json"}, &out); err != nil {
+		t.Fatalf("runManifestStats: %v", err)
+	}
+
+	var output ManifestStatsOutput
+	if err := json.Unmarshal(out.Bytes(), &output); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, out.String())
+	}
+
+	want := []ManifestOutput{
+		{Path: "go.mod", Project: "api", Runtime: "Go", Lines: 5, DependencyCount: 1},
+		{Path: "tools/requirements-dev.txt", Project: "tools", Runtime: "Python", Lines: 2, DependencyCount: 2},
+		{Path: "tools/requirements.txt", Project: "tools", Runtime: "Python", Lines: 1, DependencyCount: 1},
+		{Path: "web/package.json", Project: "web", Runtime: "JavaScript", Lines: 4, DependencyCount: 2},
+	}
+	if len(output.Manifests) != len(want) {
+		t.Fatalf("got %d manifests, want %d: %+v", len(output.Manifests), len(want), output.Manifests)
+	}
+	for i, m := range output.Manifests {
+		if m != want[i] {
+			t.Errorf("manifest %d = %+v, want %+v", i, m, want[i])
+		}
+	}
+
+	if len(output.ByRuntime) != 3 {
+		t.Fatalf("got %d runtimes, want 3: %+v", len(output.ByRuntime), output.ByRuntime)
+	}
+	python := output.ByRuntime[0]
+	if python.Runtime != "Python" || python.Manifests != 2 || python.Lines != 3 || python.DependencyCount != 3 {
+		t.Errorf("Python totals = %+v, want 2 manifests, 3 lines, 3 deps", python)
+	}
+}
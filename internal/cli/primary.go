@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"repoctr/internal/stats"
+	"repoctr/pkg/models"
+	pkgstats "repoctr/pkg/stats"
+)
+
+// NewMainCmd creates the main command.
+func NewMainCmd() *cobra.Command {
+	var inputFile string
+	var jsonOut bool
+
+	cmd := &cobra.Command{
+		Use:   "main",
+		Short: "Show the primary project of the repository",
+		Long: `Reads projects.yaml, counts every project, and prints a summary of the
+repository's primary project: the root-most project, preferring the one
+with the most code lines among projects at the same depth.
+
+Examples:
+  repo-ctr main
+  repo-ctr main --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunMain(inputFile, jsonOut, os.Stdout)
+		},
+	}
+
+	cmd.Flags().StringVarP(&inputFile, "file", "f", projectsFileName, "Projects configuration file")
+	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output in JSON format")
+
+	return cmd
+}
+
+// RunMain counts the projects in inputFile and writes a summary of the
+// primary project to w.
+func RunMain(inputFile string, jsonOut bool, w io.Writer) error {
+	config, rootDir, err := loadProjectsFile(inputFile)
+	if err != nil {
+		return err
+	}
+
+	result, err := pkgstats.Compute(rootDir, config.Projects, pkgstats.Options{FollowSymlinks: followSymlinks})
+	if err != nil {
+		return err
+	}
+
+	primary := selectPrimary(result.Projects)
+	if primary == nil {
+		return fmt.Errorf("no projects found in %s", inputFile)
+	}
+
+	// Summarize the primary project on its own, without its children
+	summary := *primary
+	summary.Children = nil
+
+	if jsonOut {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(convertProjectStats([]*models.ProjectStats{&summary}, rootDir)[0])
+	}
+
+	stats.NewReporter(w).Report([]*models.ProjectStats{&summary})
+	return nil
+}
+
+// selectPrimary returns the project in the hierarchy with the shallowest
+// path, breaking ties by the most code lines and then by path. It returns
+// nil if there are no projects.
+func selectPrimary(projectStats []*models.ProjectStats) *models.ProjectStats {
+	var primary *models.ProjectStats
+
+	var visit func([]*models.ProjectStats)
+	visit = func(list []*models.ProjectStats) {
+		for _, s := range list {
+			if primary == nil || morePrimary(s, primary) {
+				primary = s
+			}
+			visit(s.Children)
+		}
+	}
+
+	visit(projectStats)
+	return primary
+}
+
+// morePrimary reports whether a is a better primary project than b.
+func morePrimary(a, b *models.ProjectStats) bool {
+	if da, db := pathDepth(a.Project.Path), pathDepth(b.Project.Path); da != db {
+		return da < db
+	}
+	if a.CodeLines != b.CodeLines {
+		return a.CodeLines > b.CodeLines
+	}
+	return a.Project.Path < b.Project.Path
+}
+
+// pathDepth returns the number of directories in a project path; the
+// repository root "." has depth 0.
+func pathDepth(path string) int {
+	path = filepath.ToSlash(filepath.Clean(path))
+	if path == "." {
+		return 0
+	}
+	return strings.Count(path, "/") + 1
+}
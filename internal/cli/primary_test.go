@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunMain(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "projects.yaml", `projects:
+  - name: docs
+    path: docs
+    runtime:
+      type: Python
+    source-paths: ["."]
+  - name: api
+    path: services/api
+    runtime:
+      type: Go
+    source-paths: ["."]
+  - name: web
+    path: web
+    runtime:
+      type: JavaScript
+    source-paths: ["."]
+    children:
+      - name: widgets
+        path: web/widgets
+        runtime:
+          type: JavaScript
+        source-paths: ["."]
+`)
+	writeTestFile(t, root, "docs/conf.py", "project = 'docs'\n")
+	writeTestFile(t, root, "web/index.js", "import './widgets/button.js';\nconsole.log('ready');\n")
+	// The deepest project has the most code but is not root-most
+	writeTestFile(t, root, "services/api/main.go", "package main\n\nfunc main() {\n\tprintln(1)\n\tprintln(2)\n\tprintln(3)\n}\n")
+
+	var out bytes.Buffer
+	if err := RunMain(filepath.Join(root, "projects.yaml"), true, &out); err != nil {
+		t.Fatalf("RunMain: %v", err)
+	}
+
+	var primary ProjectStatsOutput
+	if err := json.Unmarshal(out.Bytes(), &primary); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, out.String())
+	}
+
+	// docs and web are both top-level; web has more code lines
+	if primary.Name != "web" || primary.Path != "web" {
+		t.Errorf("primary = %s (%s), want web", primary.Name, primary.Path)
+	}
+	if primary.CodeLines != 2 {
+		t.Errorf("code lines = %d, want 2", primary.CodeLines)
+	}
+	if len(primary.Children) != 0 {
+		t.Errorf("summary lists %d children, want none", len(primary.Children))
+	}
+}
+
+func TestPathDepth(t *testing.T) {
+	tests := map[string]int{
+		".":            0,
+		"":             0,
+		"web":          1,
+		"services/api": 2,
+		"./web/":       1,
+	}
+	for path, want := range tests {
+		if got := pathDepth(path); got != want {
+			t.Errorf("pathDepth(%q) = %d, want %d", path, got, want)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"repoctr/pkg/models"
+)
+
+// progressInterval is the minimum time between progress updates.
+const progressInterval = 200 * time.Millisecond
+
+// progressReporter renders a single, rewritten status line with the number
+// of files counted so far and the elapsed time.
+type progressReporter struct {
+	w     io.Writer
+	now   func() time.Time
+	start time.Time
+	// last is when the status line was last written; zero before the first
+	// update.
+	last  time.Time
+	files int
+}
+
+func newProgressReporter(w io.Writer) *progressReporter {
+	p := &progressReporter{w: w, now: time.Now}
+	p.start = p.now()
+	return p
+}
+
+// fileCounted records a counted file and redraws the status line, at most
+// once per progressInterval. It has the signature of stats.Options.OnFile.
+func (p *progressReporter) fileCounted(*models.FileStats) {
+	p.files++
+
+	now := p.now()
+	if now.Sub(p.start) < progressInterval || now.Sub(p.last) < progressInterval {
+		return
+	}
+	p.last = now
+	fmt.Fprintf(p.w, "\rCounting... %d files, %s elapsed", p.files, now.Sub(p.start).Round(100*time.Millisecond))
+}
+
+// finish clears the status line, if one was written, so the report starts
+// on a clean line.
+func (p *progressReporter) finish() {
+	if !p.last.IsZero() {
+		fmt.Fprint(p.w, "\r\033[K")
+	}
+}
+
+// stderrIsTerminal reports whether stderr is a terminal, where a rewritten
+// status line renders cleanly instead of filling a log.
+var stderrIsTerminal = func() bool {
+	info, err := os.Stderr.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProgressReporter_Throttles(t *testing.T) {
+	var out bytes.Buffer
+	clock := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	p := newProgressReporter(&out)
+	p.now = func() time.Time { return clock }
+	p.start = clock
+
+	// Fast runs print nothing
+	for i := 0; i < 50; i++ {
+		p.fileCounted(nil)
+	}
+	if out.Len() != 0 {
+		t.Fatalf("progress written before %v: %q", progressInterval, out.String())
+	}
+
+	clock = clock.Add(progressInterval)
+	p.fileCounted(nil)
+	p.fileCounted(nil)
+	clock = clock.Add(progressInterval / 2)
+	p.fileCounted(nil)
+	if got := strings.Count(out.String(), "\r"); got != 1 {
+		t.Errorf("got %d updates within one interval, want 1: %q", got, out.String())
+	}
+	if !strings.Contains(out.String(), "51 files") {
+		t.Errorf("update = %q, want 51 files", out.String())
+	}
+
+	clock = clock.Add(progressInterval)
+	p.fileCounted(nil)
+	if !strings.Contains(out.String(), "\rCounting... 54 files, 500ms elapsed") {
+		t.Errorf("second update missing: %q", out.String())
+	}
+
+	p.finish()
+	if !strings.HasSuffix(out.String(), "\r\033[K") {
+		t.Errorf("finish did not clear the status line: %q", out.String())
+	}
+}
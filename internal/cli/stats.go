@@ -1,18 +1,28 @@
 package cli
 
 import (
+	"bufio"
+	"context"
 	"encoding/csv"
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
+	"repoctr/internal/hashing"
 	"repoctr/internal/stats"
+	"repoctr/internal/version"
 	"repoctr/pkg/models"
+	pkgstats "repoctr/pkg/stats"
 )
 
 // OutputFormat represents the machine-readable output format.
@@ -23,15 +33,136 @@ const (
 	FormatJSON OutputFormat = "json"
 	FormatXML  OutputFormat = "xml"
 	FormatCSV  OutputFormat = "csv"
+
+	// FormatCSVLanguages outputs per-language totals as CSV.
+	FormatCSVLanguages OutputFormat = "csv-languages"
+
+	// FormatCSVFiles outputs one CSV row per counted file.
+	FormatCSVFiles OutputFormat = "csv-files"
+
+	// FormatMarkdown outputs a Markdown table of projects, e.g. for PR
+	// comments posted by CI.
+	FormatMarkdown OutputFormat = "markdown"
+)
+
+// OutputLayout selects how machine-readable output represents the project
+// hierarchy.
+type OutputLayout string
+
+const (
+	// LayoutDefault nests children in YAML, JSON, XML, and Markdown output
+	// and flattens them in CSV.
+	LayoutDefault OutputLayout = ""
+
+	// LayoutNest keeps children under their parent. CSV rows gain depth and
+	// parent columns so the hierarchy can be rebuilt.
+	LayoutNest OutputLayout = "nest"
+
+	// LayoutFlatten lists every project at the top level, without children;
+	// each entry's path identifies it.
+	LayoutFlatten OutputLayout = "flatten"
 )
 
+// StatsOptions controls how statistics are calculated and reported.
+type StatsOptions struct {
+	// Machine selects machine-readable output (YAML unless Format is set).
+	Machine bool
+	// Format is an explicit output format: yaml, json, xml, csv,
+	// csv-languages, csv-files, markdown.
+	Format string
+	// ProjectName limits output to a single project.
+	ProjectName string
+	// AllFiles lists every file instead of the top 5.
+	AllFiles bool
+	// CountTestDirsSeparately reports test directories apart from other folders.
+	CountTestDirsSeparately bool
+	// Excludes are ad-hoc exclusion patterns combined with configured excludes.
+	Excludes []string
+	// IncludeExtensions are extra file extensions counted in every project.
+	IncludeExtensions []string
+	// ExcludeGeneratedDirs skips conventional generated-code directories.
+	ExcludeGeneratedDirs bool
+	// ExcludeGenerated omits files with a generated-code header from totals.
+	ExcludeGenerated bool
+	// SeparateStructuralLines counts brace/paren/semicolon-only lines as
+	// structural lines instead of code.
+	SeparateStructuralLines bool
+	// SeparateDataLines counts the interior lines of multi-line string
+	// literals as data lines instead of code.
+	SeparateDataLines bool
+	// IncludeNotebooks counts the code cells of Jupyter notebooks in
+	// Python projects as notebook lines.
+	IncludeNotebooks bool
+	// MaxLineLength, when positive, counts lines longer than this many
+	// characters as long lines.
+	MaxLineLength int
+	// LongLines lists the files with the most long lines. It implies a
+	// MaxLineLength of defaultMaxLineLength when none is set.
+	LongLines bool
+	// BlankRuns counts and reports runs of two or more consecutive blank
+	// lines.
+	BlankRuns bool
+	// MaxFileSize skips files larger than this many bytes. Zero means
+	// unlimited.
+	MaxFileSize int64
+	// CacheFile names a file caching per-file counts between runs.
+	CacheFile string
+	// HashAlgorithm selects the content hash used to detect changes in
+	// watch mode.
+	HashAlgorithm hashing.Algorithm
+	// WatchInterval re-scans on this interval and redraws when the
+	// fingerprint of counted code changes. Zero disables watching.
+	WatchInterval time.Duration
+	// ManifestsOnly lists every discovered manifest with its line and
+	// dependency counts instead of counting source files.
+	ManifestsOnly bool
+	// NormalizePaths prints every path with forward slashes, so output is
+	// identical on Windows and Unix. The stats command enables it for
+	// machine-readable formats unless --normalize-paths=false is given.
+	NormalizePaths bool
+	// PathsFrom names a file listing paths to count directly ("-" for stdin),
+	// bypassing projects.yaml and discovery.
+	PathsFrom string
+	// Runtimes limits counting to projects of these runtimes. Empty means
+	// every runtime.
+	Runtimes []models.RuntimeType
+	// ExcludeRuntimes skips projects of these runtimes.
+	ExcludeRuntimes []models.RuntimeType
+	// Layout selects nested or flat projects in machine-readable output.
+	Layout OutputLayout
+	// CSVTotals appends a grand totals row to CSV output.
+	CSVTotals bool
+	// NoProgress hides the files-counted status line that is otherwise
+	// shown on stderr while counting for a human-readable report, when
+	// stderr is a terminal.
+	NoProgress bool
+	// GroupThreshold collapses projects with fewer code lines than this
+	// into one aggregate entry in the human-readable report. Zero disables
+	// grouping.
+	GroupThreshold int
+	// ProjectJobs is the number of sibling projects counted concurrently.
+	// Zero counts one project at a time.
+	ProjectJobs int
+}
+
+// defaultMaxLineLength is the line length used by --max-line-length-report
+// without a value and by --long-lines.
+const defaultMaxLineLength = 120
+
+// longLinesReportLimit is the number of files listed by --long-lines.
+const longLinesReportLimit = 10
+
 // NewStatsCmd creates the stats command.
 func NewStatsCmd() *cobra.Command {
 	var inputFile string
-	var machine bool
-	var yamlOut, jsonOut, xmlOut, csvOut bool
-	var projectName string
-	var allFiles bool
+	var opts StatsOptions
+	var yamlOut, jsonOut, xmlOut, csvOut, csvLanguagesOut, csvFilesOut bool
+	var maxFileSize string
+	var hashAlgo string
+	var runtimes, excludeRuntimes []string
+	var countStringsAsCode bool
+	var progress bool
+	var flatten, nest bool
 
 	cmd := &cobra.Command{
 		Use:   "stats",
@@ -41,105 +172,462 @@ Shows total files, folders, lines, code lines, blank lines, and file sizes.
 Displays the top 5 largest files per project by default.
 
 Use --machine to output in machine-readable format (default: yaml).
-Supported formats: --yaml, --json, --xml, --csv
+Supported formats: --yaml, --json, --xml, --csv, --csv-languages, --csv-files,
+or --format with one of those names or markdown (a table for PR comments).
+
+Totals are also grouped by language (runtime type) across the hierarchy.
+
+--only (or --runtime) and --skip (or --exclude-runtime) filter projects by
+runtime before counting. A project that is filtered out is never counted,
+even if some of its children match; the matching children take its place
+in the hierarchy instead.
+
+--paths-from counts the listed files as given, so it cannot be combined with
+options that select projects or files (-p, --exclude, --include-ext, --only,
+--skip) or with --cache.
 
 Examples:
   repo-ctr stats                 # All projects
   repo-ctr stats -p myproject    # Single project
   repo-ctr stats -a              # All projects with all files listed
-  repo-ctr stats -p lib -a       # Single project with all files`,
+  repo-ctr stats -p lib -a       # Single project with all files
+  repo-ctr stats --exclude "**/testdata/**" --exclude "*.gen.go"
+  repo-ctr stats --only go,rust       # Only Go and Rust projects
+  repo-ctr stats --skip node,ts
+  git diff --name-only main | repo-ctr stats --paths-from -
+  repo-ctr stats --watch-interval 2s   # Redraw when counted code changes
+  repo-ctr stats --long-lines          # Files with the most lines over 120 characters
+  repo-ctr stats --max-line-length-report=100 --long-lines
+  repo-ctr stats --blank-runs          # Runs of 2+ consecutive blank lines
+  repo-ctr stats --max-file-size 512K  # Skip minified bundles and other huge files
+  repo-ctr stats --cache .repoctr-cache.json   # Reuse counts of unchanged files (e.g. in CI)
+  repo-ctr stats --project-jobs 4      # Count four sibling projects at a time
+  repo-ctr stats --stats-of-manifest --json    # Manifests with line and dependency counts
+  repo-ctr stats --format markdown > loc.md    # Markdown table for a PR comment`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			format := ""
 			if yamlOut {
-				format = "yaml"
+				opts.Format = "yaml"
 			} else if jsonOut {
-				format = "json"
+				opts.Format = "json"
 			} else if xmlOut {
-				format = "xml"
+				opts.Format = "xml"
 			} else if csvOut {
-				format = "csv"
+				opts.Format = "csv"
+			} else if csvLanguagesOut {
+				opts.Format = "csv-languages"
+			} else if csvFilesOut {
+				opts.Format = "csv-files"
+			} else if opts.Format != "" && determineFormat(false, opts.Format) == "" {
+				return fmt.Errorf("unknown format: %s (expected yaml, json, xml, csv, csv-languages, csv-files, or markdown)", opts.Format)
+			}
+			if maxFileSize != "" {
+				size, err := parseByteSize(maxFileSize)
+				if err != nil {
+					return fmt.Errorf("invalid --max-file-size: %w", err)
+				}
+				opts.MaxFileSize = size
+			}
+			algo, err := hashing.Parse(hashAlgo)
+			if err != nil {
+				return fmt.Errorf("invalid --hash-algo: %w", err)
+			}
+			opts.HashAlgorithm = algo
+			opts.SeparateDataLines = !countStringsAsCode
+			opts.NoProgress = !progress
+			if opts.Runtimes, err = parseRuntimeTypes(runtimes); err != nil {
+				return fmt.Errorf("invalid --only: %w", err)
+			}
+			if opts.ExcludeRuntimes, err = parseRuntimeTypes(excludeRuntimes); err != nil {
+				return fmt.Errorf("invalid --skip: %w", err)
+			}
+			if opts.ProjectJobs < 0 {
+				return fmt.Errorf("invalid --project-jobs: %d (expected 0 or more)", opts.ProjectJobs)
 			}
-			return RunStats(inputFile, machine, format, projectName, allFiles)
+			if flatten {
+				opts.Layout = LayoutFlatten
+			} else if nest {
+				opts.Layout = LayoutNest
+			}
+			if !cmd.Flags().Changed("normalize-paths") {
+				opts.NormalizePaths = determineFormat(opts.Machine, opts.Format) != ""
+			}
+			return RunStats(inputFile, opts)
 		},
 	}
 
 	cmd.Flags().StringVarP(&inputFile, "file", "f", projectsFileName, "Projects configuration file")
-	cmd.Flags().BoolVarP(&machine, "machine", "m", false, "Output in machine-readable format (default: yaml)")
+	cmd.Flags().BoolVarP(&opts.Machine, "machine", "m", false, "Output in machine-readable format (default: yaml)")
 	cmd.Flags().BoolVar(&yamlOut, "yaml", false, "Output in YAML format")
 	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output in JSON format")
 	cmd.Flags().BoolVar(&xmlOut, "xml", false, "Output in XML format")
 	cmd.Flags().BoolVar(&csvOut, "csv", false, "Output in CSV format")
-	cmd.Flags().StringVarP(&projectName, "project", "p", "", "Show stats for a single project by name")
-	cmd.Flags().BoolVarP(&allFiles, "all-files", "a", false, "List all files instead of top 5")
+	cmd.Flags().BoolVar(&csvLanguagesOut, "csv-languages", false, "Output per-language totals in CSV format")
+	cmd.Flags().BoolVar(&csvFilesOut, "csv-files", false, "Output one CSV row per counted file")
+	cmd.Flags().StringVar(&opts.Format, "format", "", "Output format: yaml, json, xml, csv, csv-languages, csv-files, or markdown")
+	cmd.Flags().BoolVar(&opts.NormalizePaths, "normalize-paths", false, "Print paths with forward slashes on every platform (default: on for machine-readable formats)")
+	cmd.Flags().BoolVar(&flatten, "flatten", false, "List every project at the top level without children in machine-readable output")
+	cmd.Flags().BoolVar(&nest, "nest", false, "Keep children under their parent in machine-readable output; CSV gains depth and parent columns")
+	cmd.MarkFlagsMutuallyExclusive("flatten", "nest")
+	cmd.Flags().BoolVar(&opts.CSVTotals, "csv-totals", false, "Append a TOTAL row with the grand totals to CSV output")
+	cmd.Flags().StringVarP(&opts.ProjectName, "project", "p", "", "Show stats for a single project by name")
+	cmd.Flags().BoolVarP(&opts.AllFiles, "all-files", "a", false, "List all files instead of top 5")
+	cmd.Flags().BoolVar(&progress, "progress", true, "Show the number of files counted so far on stderr (terminal and human-readable output only)")
+	cmd.Flags().IntVar(&opts.GroupThreshold, "group-threshold", 0, "Collapse projects with fewer than N code lines into one aggregate entry in the report")
+	cmd.Flags().StringVar(&opts.PathsFrom, "paths-from", "", "Count exactly the files listed in FILE, one per line ('-' for stdin)")
+	cmd.Flags().BoolVar(&opts.ManifestsOnly, "stats-of-manifest", false, "List every manifest under the projects file's directory with line and dependency counts, instead of counting sources")
+	cmd.Flags().DurationVar(&opts.WatchInterval, "watch-interval", 0, "Poll for changes on this interval (e.g. 2s) and redraw when counted code changes")
+	cmd.Flags().StringArrayVar(&opts.Excludes, "exclude", nil, "Exclude files matching a gitignore-style pattern (repeatable)")
+	cmd.Flags().StringArrayVar(&opts.IncludeExtensions, "include-ext", nil, "Also count files with this extension in every project, e.g. .tmpl (repeatable)")
+	cmd.Flags().StringSliceVar(&runtimes, "only", nil, "Only count projects of these runtimes, by name or alias (e.g. go, golang, ts, node, csharp, c++)")
+	cmd.Flags().StringSliceVar(&runtimes, "runtime", nil, "Same as --only")
+	cmd.Flags().StringSliceVar(&excludeRuntimes, "skip", nil, "Skip projects of these runtimes, by name or alias")
+	cmd.Flags().StringSliceVar(&excludeRuntimes, "exclude-runtime", nil, "Same as --skip")
+	cmd.MarkFlagsMutuallyExclusive("only", "runtime")
+	cmd.MarkFlagsMutuallyExclusive("skip", "exclude-runtime")
+	cmd.Flags().BoolVar(&opts.ExcludeGeneratedDirs, "exclude-generated-dirs", false, "Skip gen/, generated/, __generated__/, migrations/ and *.pb.go files")
+	cmd.Flags().BoolVar(&opts.ExcludeGenerated, "exclude-generated", false, "Omit files with a generated-code header (e.g. '// Code generated ... DO NOT EDIT.') from totals")
+	cmd.Flags().BoolVar(&opts.SeparateStructuralLines, "separate-structural-lines", false, "Count lines of only braces, parentheses, and semicolons as structural instead of code")
+	cmd.Flags().BoolVar(&opts.IncludeNotebooks, "include-notebooks", false, "Count the code cells of Jupyter notebooks (.ipynb) in Python projects as notebook lines")
+	cmd.Flags().BoolVar(&countStringsAsCode, "count-strings-as-code", true, "Count lines inside multi-line strings (Go raw strings, Python triple-quoted strings) as code; false counts them as data lines")
+	cmd.Flags().IntVar(&opts.MaxLineLength, "max-line-length-report", 0, "Count lines longer than N characters as long lines")
+	cmd.Flags().Lookup("max-line-length-report").NoOptDefVal = strconv.Itoa(defaultMaxLineLength)
+	cmd.Flags().BoolVar(&opts.LongLines, "long-lines", false, "List the files with the most long lines")
+	cmd.Flags().BoolVar(&opts.BlankRuns, "blank-runs", false, "Count runs of 2+ consecutive blank lines per file and project")
+	cmd.Flags().StringVar(&maxFileSize, "max-file-size", "", "Skip files larger than this size in bytes, with optional K/M/G suffix (default: unlimited)")
+	cmd.Flags().IntVar(&opts.ProjectJobs, "project-jobs", 0, "Count up to N sibling projects concurrently, each with its own file workers (default: one at a time)")
+	cmd.Flags().StringVar(&opts.CacheFile, "cache", "", "Cache per-file counts in FILE, keyed by git blob SHA (size and mtime outside git), to speed up repeated runs")
+	cmd.Flags().StringVar(&hashAlgo, "hash-algo", string(hashing.Default), "Content hash for change detection in watch mode: sha256, sha1, or xxhash")
+	cmd.Flags().BoolVar(&opts.CountTestDirsSeparately, "count-test-dirs-separately", false, "Count test/tests/__tests__/spec directories apart from other folders")
 
 	return cmd
 }
 
 // RunStats executes the stats command logic (exported for use by root command).
-func RunStats(inputFile string, machine bool, format string, projectName string, allFiles bool) error {
-	// Read projects.yaml
-	data, err := os.ReadFile(inputFile)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return fmt.Errorf("%s not found. Run 'repo-ctr init' or 'repo-ctr identify .' first", inputFile)
-		}
-		return fmt.Errorf("failed to read %s: %w", inputFile, err)
+func RunStats(inputFile string, opts StatsOptions) error {
+	if opts.LongLines && opts.MaxLineLength <= 0 {
+		opts.MaxLineLength = defaultMaxLineLength
 	}
 
-	var config models.ProjectsConfig
-	if err := yaml.Unmarshal(data, &config); err != nil {
-		return fmt.Errorf("failed to parse %s: %w", inputFile, err)
+	if opts.PathsFrom != "" {
+		if flag := pathsFromConflict(opts); flag != "" {
+			return fmt.Errorf("--paths-from cannot be combined with %s: listed files are counted as given", flag)
+		}
+		return runStatsForPaths(opts)
 	}
 
-	if len(config.Projects) == 0 {
-		fmt.Println("No projects found in", inputFile)
-		return nil
+	if opts.ManifestsOnly {
+		return runManifestStats(filepath.Dir(inputFile), opts, os.Stdout)
 	}
 
-	// Get the directory containing projects.yaml as root
-	rootDir, err := filepath.Abs(filepath.Dir(inputFile))
+	config, rootDir, err := loadProjectsFile(inputFile)
 	if err != nil {
-		rootDir = "."
+		return err
 	}
 
-	// Create counter
-	counter, err := stats.NewCounter(rootDir)
-	if err != nil {
-		return fmt.Errorf("failed to create stats counter: %w", err)
+	return runProjectStats(config, rootDir, inputFile, opts)
+}
+
+// runProjectStats counts and renders the projects in config, whose paths are
+// relative to rootDir. inputFile names their source in messages.
+func runProjectStats(config *models.ProjectsConfig, rootDir, inputFile string, opts StatsOptions) error {
+	if len(config.Projects) == 0 {
+		fmt.Println("No projects found in", inputFile)
+		return nil
 	}
 
 	// Filter projects if --project is specified
 	var projectsToProcess []*models.Project
-	if projectName != "" {
-		found := findProjectByName(config.Projects, projectName)
+	if opts.ProjectName != "" {
+		found := findProjectByName(config.Projects, opts.ProjectName)
 		if found == nil {
-			return fmt.Errorf("project '%s' not found", projectName)
+			return fmt.Errorf("project '%s' not found", opts.ProjectName)
 		}
 		projectsToProcess = []*models.Project{found}
 	} else {
 		projectsToProcess = config.Projects
 	}
 
-	// Calculate stats for projects
-	projectStats, err := counter.CountHierarchy(projectsToProcess)
+	if len(opts.Runtimes) > 0 || len(opts.ExcludeRuntimes) > 0 {
+		projectsToProcess = filterProjectsByRuntime(projectsToProcess, opts.Runtimes, opts.ExcludeRuntimes)
+		if len(projectsToProcess) == 0 {
+			fmt.Println("No projects match the runtime filter")
+			return nil
+		}
+	}
+
+	if opts.WatchInterval > 0 {
+		counter, err := stats.NewCounterWithOptions(rootDir, stats.Options{
+			CountTestDirsSeparately: opts.CountTestDirsSeparately,
+			Excludes:                opts.Excludes,
+			IncludeExtensions:       opts.IncludeExtensions,
+			ExcludeGeneratedDirs:    opts.ExcludeGeneratedDirs,
+			ExcludeGenerated:        opts.ExcludeGenerated,
+			SeparateStructuralLines: opts.SeparateStructuralLines,
+			SeparateDataLines:       opts.SeparateDataLines,
+			IncludeNotebooks:        opts.IncludeNotebooks,
+			MaxLineLength:           opts.MaxLineLength,
+			CountBlankRuns:          opts.BlankRuns,
+			MaxFileSize:             opts.MaxFileSize,
+			FollowSymlinks:          followSymlinks,
+			HashAlgorithm:           opts.HashAlgorithm,
+			Logf:                    verboseLogf(),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create stats counter: %w", err)
+		}
+		return watchStats(counter, rootDir, projectsToProcess, opts)
+	}
+
+	// Calculate stats for projects. Ctrl-C stops counting but still shows
+	// the projects counted so far.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	// Machine-readable output is usually piped, so it gets no progress
+	var onFile func(*models.FileStats)
+	var progress *progressReporter
+	if !opts.NoProgress && verbosity != VerbosityQuiet && determineFormat(opts.Machine, opts.Format) == "" && stderrIsTerminal() {
+		progress = newProgressReporter(os.Stderr)
+		onFile = progress.fileCounted
+	}
+
+	result, err := pkgstats.ComputeContext(ctx, rootDir, projectsToProcess, pkgstats.Options{
+		CountTestDirsSeparately: opts.CountTestDirsSeparately,
+		Excludes:                opts.Excludes,
+		IncludeExtensions:       opts.IncludeExtensions,
+		ExcludeGeneratedDirs:    opts.ExcludeGeneratedDirs,
+		ExcludeGenerated:        opts.ExcludeGenerated,
+		SeparateStructuralLines: opts.SeparateStructuralLines,
+		SeparateDataLines:       opts.SeparateDataLines,
+		IncludeNotebooks:        opts.IncludeNotebooks,
+		MaxLineLength:           opts.MaxLineLength,
+		CountBlankRuns:          opts.BlankRuns,
+		MaxFileSize:             opts.MaxFileSize,
+		FollowSymlinks:          followSymlinks,
+		CacheFile:               opts.CacheFile,
+		ProjectJobs:             opts.ProjectJobs,
+		OnFile:                  onFile,
+		Logf:                    verboseLogf(),
+	})
+	if progress != nil {
+		progress.finish()
+	}
 	if err != nil {
-		return fmt.Errorf("failed to calculate statistics: %w", err)
+		if ctx.Err() == nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Interrupted: showing %d of %d projects counted so far\n",
+			pkgstats.Sum(result.Projects).Projects, countProjects(projectsToProcess))
+		if renderErr := renderStats(result.Projects, rootDir, opts); renderErr != nil {
+			return renderErr
+		}
+		return err
 	}
 
+	return renderStats(result.Projects, rootDir, opts)
+}
+
+// renderStats writes project statistics to stdout in the requested format.
+// File paths in the long-lines report are shown relative to rootDir.
+func renderStats(projectStats []*models.ProjectStats, rootDir string, opts StatsOptions) error {
 	// Determine output format
-	outputFormat := determineFormat(machine, format)
+	outputFormat := determineFormat(opts.Machine, opts.Format)
 
+	if outputFormat == FormatCSVFiles {
+		return outputFilesCSV(os.Stdout, projectStats, rootDir, opts.NormalizePaths)
+	}
 	if outputFormat != "" {
-		return outputMachineReadable(projectStats, outputFormat)
+		return outputMachineReadable(projectStats, rootDir, outputFormat, opts.NormalizePaths, opts.Layout, opts.CSVTotals)
 	}
 
 	// Human-readable output
 	reporter := stats.NewReporter(os.Stdout)
-	reporter.ReportWithOptions(projectStats, allFiles)
+	reporter.SetNormalizePaths(opts.NormalizePaths)
+	reporter.SetGroupThreshold(opts.GroupThreshold)
+	reporter.ReportWithOptions(projectStats, opts.AllFiles)
+	if opts.LongLines {
+		reporter.ReportLongLines(projectStats, rootDir, opts.MaxLineLength, longLinesReportLimit)
+	}
 
 	return nil
 }
 
+// runStatsForPaths counts the files listed in opts.PathsFrom as a single
+// synthetic project, without reading projects.yaml.
+func runStatsForPaths(opts StatsOptions) error {
+	paths, err := readPathList(opts.PathsFrom)
+	if err != nil {
+		return err
+	}
+
+	counter, err := stats.NewCounterWithOptions(".", stats.Options{
+		ExcludeGenerated:        opts.ExcludeGenerated,
+		SeparateStructuralLines: opts.SeparateStructuralLines,
+		SeparateDataLines:       opts.SeparateDataLines,
+		IncludeNotebooks:        opts.IncludeNotebooks,
+		MaxLineLength:           opts.MaxLineLength,
+		CountBlankRuns:          opts.BlankRuns,
+		MaxFileSize:             opts.MaxFileSize,
+		Logf:                    verboseLogf(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create stats counter: %w", err)
+	}
+
+	project := &models.Project{
+		Name: "paths-from",
+		Path: ".",
+	}
+	projectStats := []*models.ProjectStats{counter.CountPaths(project, paths)}
+
+	return renderStats(projectStats, ".", opts)
+}
+
+// pathsFromConflict returns the flag of an option that --paths-from would
+// ignore, since listed files bypass projects, ignore rules, and the cache,
+// or "" if there is none.
+func pathsFromConflict(opts StatsOptions) string {
+	switch {
+	case opts.ProjectName != "":
+		return "--project"
+	case len(opts.Excludes) > 0:
+		return "--exclude"
+	case opts.ExcludeGeneratedDirs:
+		return "--exclude-generated-dirs"
+	case len(opts.IncludeExtensions) > 0:
+		return "--include-ext"
+	case len(opts.Runtimes) > 0:
+		return "--only"
+	case len(opts.ExcludeRuntimes) > 0:
+		return "--skip"
+	case opts.CacheFile != "":
+		return "--cache"
+	case opts.ProjectJobs > 0:
+		return "--project-jobs"
+	case opts.ManifestsOnly:
+		return "--stats-of-manifest"
+	case opts.WatchInterval > 0:
+		return "--watch-interval"
+	}
+	return ""
+}
+
+// readPathList reads one path per line from file ("-" for stdin).
+// Blank lines and lines starting with "#" are skipped.
+func readPathList(file string) ([]string, error) {
+	var r io.Reader = os.Stdin
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	var paths []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		paths = append(paths, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", file, err)
+	}
+
+	return paths, nil
+}
+
+// parseByteSize parses a size in bytes with an optional K, M, or G suffix
+// (powers of 1024, case-insensitive, optionally followed by "B"),
+// e.g. "500000", "512K", "2MB".
+func parseByteSize(s string) (int64, error) {
+	value := strings.ToUpper(strings.TrimSpace(s))
+	value = strings.TrimSuffix(value, "B")
+
+	multiplier := int64(1)
+	switch {
+	case strings.HasSuffix(value, "K"):
+		multiplier = 1 << 10
+	case strings.HasSuffix(value, "M"):
+		multiplier = 1 << 20
+	case strings.HasSuffix(value, "G"):
+		multiplier = 1 << 30
+	}
+	if multiplier > 1 {
+		value = value[:len(value)-1]
+	}
+
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%q is not a size like 500000, 512K, or 2M", s)
+	}
+	return n * multiplier, nil
+}
+
+// loadProjectsFile reads and parses a projects.yaml file. It also returns the
+// directory containing the file, which is the root for project paths.
+func loadProjectsFile(inputFile string) (*models.ProjectsConfig, string, error) {
+	data, err := os.ReadFile(inputFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, "", fmt.Errorf("%s not found. Run 'repo-ctr init' or 'repo-ctr identify .' first", inputFile)
+		}
+		return nil, "", fmt.Errorf("failed to read %s: %w", inputFile, err)
+	}
+
+	var config models.ProjectsConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, "", fmt.Errorf("failed to parse %s: %w", inputFile, err)
+	}
+
+	// Get the directory containing projects.yaml as root
+	rootDir, err := filepath.Abs(filepath.Dir(inputFile))
+	if err != nil {
+		rootDir = "."
+	}
+
+	return &config, rootDir, nil
+}
+
+// parseRuntimeTypes resolves runtime names and aliases given on the command
+// line.
+func parseRuntimeTypes(names []string) ([]models.RuntimeType, error) {
+	var types []models.RuntimeType
+	for _, name := range names {
+		rt, err := models.ParseRuntimeType(name)
+		if err != nil {
+			return nil, err
+		}
+		types = append(types, rt)
+	}
+	return types, nil
+}
+
+// filterProjectsByRuntime returns the projects whose runtime is in include
+// (any runtime when include is empty) and not in exclude. The children of a
+// dropped project take its place, so matching projects below a filtered-out
+// parent are still counted. The input projects are not modified.
+func filterProjectsByRuntime(projects []*models.Project, include, exclude []models.RuntimeType) []*models.Project {
+	var result []*models.Project
+	for _, p := range projects {
+		children := filterProjectsByRuntime(p.Children, include, exclude)
+
+		if (len(include) > 0 && !slices.Contains(include, p.Runtime.Type)) || slices.Contains(exclude, p.Runtime.Type) {
+			result = append(result, children...)
+			continue
+		}
+
+		kept := *p
+		kept.Children = children
+		result = append(result, &kept)
+	}
+	return result
+}
+
 // findProjectByName searches for a project by name in the project tree.
 func findProjectByName(projects []*models.Project, name string) *models.Project {
 	for _, p := range projects {
@@ -164,6 +652,12 @@ func determineFormat(machine bool, format string) OutputFormat {
 		return FormatXML
 	case "csv":
 		return FormatCSV
+	case "csv-languages":
+		return FormatCSVLanguages
+	case "csv-files":
+		return FormatCSVFiles
+	case "markdown":
+		return FormatMarkdown
 	}
 
 	// If --machine flag is set without format, default to YAML
@@ -174,27 +668,65 @@ func determineFormat(machine bool, format string) OutputFormat {
 	return ""
 }
 
-// StatsOutput represents the machine-readable stats output.
+// clock returns the current time. Tests replace it for deterministic output.
+var clock = time.Now
+
+// StatsOutput represents the machine-readable stats output. GeneratedAt
+// (RFC 3339, UTC) and ToolVersion record when and by which repo-ctr version
+// the statistics were produced.
 type StatsOutput struct {
-	XMLName  xml.Name             `xml:"statistics" json:"-" yaml:"-"`
-	Projects []ProjectStatsOutput `yaml:"projects" json:"projects" xml:"project"`
-	Totals   TotalsOutput         `yaml:"totals" json:"totals" xml:"totals"`
+	XMLName     xml.Name               `xml:"statistics" json:"-" yaml:"-"`
+	GeneratedAt string                 `yaml:"generated_at" json:"generated_at" xml:"generated_at"`
+	ToolVersion string                 `yaml:"tool_version" json:"tool_version" xml:"tool_version"`
+	Projects    []ProjectStatsOutput   `yaml:"projects" json:"projects" xml:"project"`
+	Totals      TotalsOutput           `yaml:"totals" json:"totals" xml:"totals"`
+	ByLanguage  []LanguageTotalsOutput `yaml:"by_language" json:"by_language" xml:"by_language>language"`
 }
 
 // ProjectStatsOutput represents stats for a single project.
 type ProjectStatsOutput struct {
-	Name         string               `yaml:"name" json:"name" xml:"name"`
-	Path         string               `yaml:"path" json:"path" xml:"path"`
-	Runtime      string               `yaml:"runtime" json:"runtime" xml:"runtime"`
-	Version      string               `yaml:"version,omitempty" json:"version,omitempty" xml:"version,omitempty"`
-	Files        int                  `yaml:"files" json:"files" xml:"files"`
-	Folders      int                  `yaml:"folders" json:"folders" xml:"folders"`
-	TotalLines   int                  `yaml:"total_lines" json:"total_lines" xml:"total_lines"`
-	CodeLines    int                  `yaml:"code_lines" json:"code_lines" xml:"code_lines"`
-	BlankLines   int                  `yaml:"blank_lines" json:"blank_lines" xml:"blank_lines"`
-	SizeBytes    int64                `yaml:"size_bytes" json:"size_bytes" xml:"size_bytes"`
-	LargestFiles []FileStatsOutput    `yaml:"largest_files,omitempty" json:"largest_files,omitempty" xml:"largest_file,omitempty"`
-	Children     []ProjectStatsOutput `yaml:"children,omitempty" json:"children,omitempty" xml:"child,omitempty"`
+	Name            string               `yaml:"name" json:"name" xml:"name"`
+	Path            string               `yaml:"path" json:"path" xml:"path"`
+	Runtime         string               `yaml:"runtime" json:"runtime" xml:"runtime"`
+	Version         string               `yaml:"version,omitempty" json:"version,omitempty" xml:"version,omitempty"`
+	DependencyCount int                  `yaml:"dependency_count,omitempty" json:"dependency_count,omitempty" xml:"dependency_count,omitempty"`
+	PackageManager  string               `yaml:"package_manager,omitempty" json:"package_manager,omitempty" xml:"package_manager,omitempty"`
+	Framework       string               `yaml:"framework,omitempty" json:"framework,omitempty" xml:"framework,omitempty"`
+	Files           int                  `yaml:"files" json:"files" xml:"files"`
+	Folders         int                  `yaml:"folders" json:"folders" xml:"folders"`
+	TestFolders     int                  `yaml:"test_folders,omitempty" json:"test_folders,omitempty" xml:"test_folders,omitempty"`
+	TotalLines      int                  `yaml:"total_lines" json:"total_lines" xml:"total_lines"`
+	CodeLines       int                  `yaml:"code_lines" json:"code_lines" xml:"code_lines"`
+	BlankLines      int                  `yaml:"blank_lines" json:"blank_lines" xml:"blank_lines"`
+	StructuralLines int                  `yaml:"structural_lines,omitempty" json:"structural_lines,omitempty" xml:"structural_lines,omitempty"`
+	DataLines       int                  `yaml:"data_lines,omitempty" json:"data_lines,omitempty" xml:"data_lines,omitempty"`
+	NotebookLines   int                  `yaml:"notebook_lines,omitempty" json:"notebook_lines,omitempty" xml:"notebook_lines,omitempty"`
+	LongLines       int                  `yaml:"long_lines,omitempty" json:"long_lines,omitempty" xml:"long_lines,omitempty"`
+	BlankRuns       int                  `yaml:"blank_runs,omitempty" json:"blank_runs,omitempty" xml:"blank_runs,omitempty"`
+	SizeBytes       int64                `yaml:"size_bytes" json:"size_bytes" xml:"size_bytes"`
+	GeneratedFiles  int                  `yaml:"generated_files,omitempty" json:"generated_files,omitempty" xml:"generated_files,omitempty"`
+	GeneratedLines  int                  `yaml:"generated_lines,omitempty" json:"generated_lines,omitempty" xml:"generated_lines,omitempty"`
+	SkippedFiles    []SkippedFileOutput  `yaml:"skipped_files,omitempty" json:"skipped_files,omitempty" xml:"skipped_file,omitempty"`
+	BinaryFiles     int                  `yaml:"binary_files,omitempty" json:"binary_files,omitempty" xml:"binary_files,omitempty"`
+	Languages       []SubLanguageOutput  `yaml:"languages,omitempty" json:"languages,omitempty" xml:"languages>language,omitempty"`
+	LargestFiles    []FileStatsOutput    `yaml:"largest_files,omitempty" json:"largest_files,omitempty" xml:"largest_file,omitempty"`
+	Children        []ProjectStatsOutput `yaml:"children,omitempty" json:"children,omitempty" xml:"child,omitempty"`
+}
+
+// SubLanguageOutput represents stats for one language within a project.
+type SubLanguageOutput struct {
+	Language   string `yaml:"language" json:"language" xml:"name"`
+	Files      int    `yaml:"files" json:"files" xml:"files"`
+	TotalLines int    `yaml:"total_lines" json:"total_lines" xml:"total_lines"`
+	CodeLines  int    `yaml:"code_lines" json:"code_lines" xml:"code_lines"`
+	BlankLines int    `yaml:"blank_lines" json:"blank_lines" xml:"blank_lines"`
+}
+
+// SkippedFileOutput represents a file skipped for exceeding the maximum
+// file size.
+type SkippedFileOutput struct {
+	Path      string `yaml:"path" json:"path" xml:"path"`
+	SizeBytes int64  `yaml:"size_bytes" json:"size_bytes" xml:"size_bytes"`
 }
 
 // FileStatsOutput represents stats for a single file.
@@ -205,16 +737,43 @@ type FileStatsOutput struct {
 
 // TotalsOutput represents the grand totals.
 type TotalsOutput struct {
-	Files      int   `yaml:"files" json:"files" xml:"files"`
-	Folders    int   `yaml:"folders" json:"folders" xml:"folders"`
-	TotalLines int   `yaml:"total_lines" json:"total_lines" xml:"total_lines"`
-	CodeLines  int   `yaml:"code_lines" json:"code_lines" xml:"code_lines"`
-	BlankLines int   `yaml:"blank_lines" json:"blank_lines" xml:"blank_lines"`
-	SizeBytes  int64 `yaml:"size_bytes" json:"size_bytes" xml:"size_bytes"`
+	Files           int   `yaml:"files" json:"files" xml:"files"`
+	Folders         int   `yaml:"folders" json:"folders" xml:"folders"`
+	TestFolders     int   `yaml:"test_folders,omitempty" json:"test_folders,omitempty" xml:"test_folders,omitempty"`
+	TotalLines      int   `yaml:"total_lines" json:"total_lines" xml:"total_lines"`
+	CodeLines       int   `yaml:"code_lines" json:"code_lines" xml:"code_lines"`
+	BlankLines      int   `yaml:"blank_lines" json:"blank_lines" xml:"blank_lines"`
+	StructuralLines int   `yaml:"structural_lines,omitempty" json:"structural_lines,omitempty" xml:"structural_lines,omitempty"`
+	DataLines       int   `yaml:"data_lines,omitempty" json:"data_lines,omitempty" xml:"data_lines,omitempty"`
+	NotebookLines   int   `yaml:"notebook_lines,omitempty" json:"notebook_lines,omitempty" xml:"notebook_lines,omitempty"`
+	LongLines       int   `yaml:"long_lines,omitempty" json:"long_lines,omitempty" xml:"long_lines,omitempty"`
+	BlankRuns       int   `yaml:"blank_runs,omitempty" json:"blank_runs,omitempty" xml:"blank_runs,omitempty"`
+	SizeBytes       int64 `yaml:"size_bytes" json:"size_bytes" xml:"size_bytes"`
+	GeneratedFiles  int   `yaml:"generated_files,omitempty" json:"generated_files,omitempty" xml:"generated_files,omitempty"`
+	GeneratedLines  int   `yaml:"generated_lines,omitempty" json:"generated_lines,omitempty" xml:"generated_lines,omitempty"`
+	SkippedFiles    int   `yaml:"skipped_file_count,omitempty" json:"skipped_file_count,omitempty" xml:"skipped_file_count,omitempty"`
+	BinaryFiles     int   `yaml:"binary_files,omitempty" json:"binary_files,omitempty" xml:"binary_files,omitempty"`
 }
 
-func outputMachineReadable(projectStats []*models.ProjectStats, format OutputFormat) error {
-	output := buildStatsOutput(projectStats)
+// LanguageTotalsOutput represents totals for a single runtime type.
+type LanguageTotalsOutput struct {
+	Runtime    string `yaml:"runtime" json:"runtime" xml:"runtime"`
+	Projects   int    `yaml:"projects" json:"projects" xml:"projects"`
+	Files      int    `yaml:"files" json:"files" xml:"files"`
+	TotalLines int    `yaml:"total_lines" json:"total_lines" xml:"total_lines"`
+	CodeLines  int    `yaml:"code_lines" json:"code_lines" xml:"code_lines"`
+	BlankLines int    `yaml:"blank_lines" json:"blank_lines" xml:"blank_lines"`
+	SizeBytes  int64  `yaml:"size_bytes" json:"size_bytes" xml:"size_bytes"`
+}
+
+func outputMachineReadable(projectStats []*models.ProjectStats, rootDir string, format OutputFormat, normalizePaths bool, layout OutputLayout, csvTotals bool) error {
+	output := buildStatsOutput(projectStats, rootDir)
+	if normalizePaths {
+		normalizeOutputPaths(output.Projects)
+	}
+	if layout == LayoutFlatten {
+		output.Projects = flattenProjects(output.Projects)
+	}
 
 	switch format {
 	case FormatYAML:
@@ -224,46 +783,127 @@ func outputMachineReadable(projectStats []*models.ProjectStats, format OutputFor
 	case FormatXML:
 		return outputXML(output)
 	case FormatCSV:
-		return outputCSV(projectStats)
+		return outputCSV(os.Stdout, projectStats, normalizePaths, layout == LayoutNest, csvTotals)
+	case FormatCSVLanguages:
+		return outputLanguagesCSV(output.ByLanguage)
+	case FormatMarkdown:
+		return outputMarkdown(os.Stdout, output)
 	}
 
 	return fmt.Errorf("unknown format: %s", format)
 }
 
-func buildStatsOutput(projectStats []*models.ProjectStats) StatsOutput {
+// normalizeOutputPaths rewrites the paths of projects and their children
+// with forward slashes.
+func normalizeOutputPaths(projects []ProjectStatsOutput) {
+	for i := range projects {
+		p := &projects[i]
+		p.Path = stats.NormalizePath(p.Path)
+		for j := range p.SkippedFiles {
+			p.SkippedFiles[j].Path = stats.NormalizePath(p.SkippedFiles[j].Path)
+		}
+		for j := range p.LargestFiles {
+			p.LargestFiles[j].Path = stats.NormalizePath(p.LargestFiles[j].Path)
+		}
+		normalizeOutputPaths(p.Children)
+	}
+}
+
+// outputPath returns path as it should be emitted.
+func outputPath(path string, normalize bool) string {
+	if normalize {
+		return stats.NormalizePath(path)
+	}
+	return path
+}
+
+// buildStatsOutput converts counted projects, whose paths are relative to
+// rootDir, to the machine-readable output.
+func buildStatsOutput(projectStats []*models.ProjectStats, rootDir string) StatsOutput {
 	output := StatsOutput{
-		Projects: convertProjectStats(projectStats),
-		Totals:   calculateTotals(projectStats),
+		GeneratedAt: clock().UTC().Format(time.RFC3339),
+		ToolVersion: version.Version,
+		Projects:    convertProjectStats(projectStats, rootDir),
+		Totals:      calculateTotals(projectStats),
+		ByLanguage:  convertLanguageStats(stats.AggregateByLanguage(projectStats)),
 	}
 	return output
 }
 
-func convertProjectStats(stats []*models.ProjectStats) []ProjectStatsOutput {
+func convertLanguageStats(langs []*models.LanguageStats) []LanguageTotalsOutput {
+	result := make([]LanguageTotalsOutput, 0, len(langs))
+	for _, l := range langs {
+		result = append(result, LanguageTotalsOutput{
+			Runtime:    string(l.Runtime),
+			Projects:   l.Projects,
+			Files:      l.TotalFiles,
+			TotalLines: l.TotalLines,
+			CodeLines:  l.CodeLines,
+			BlankLines: l.BlankLines,
+			SizeBytes:  l.TotalSize,
+		})
+	}
+	return result
+}
+
+// convertProjectStats converts counted projects to their output form. File
+// paths are emitted relative to their project, found below rootDir.
+func convertProjectStats(stats []*models.ProjectStats, rootDir string) []ProjectStatsOutput {
 	var result []ProjectStatsOutput
 
 	for _, s := range stats {
 		p := ProjectStatsOutput{
-			Name:       s.Project.Name,
-			Path:       s.Project.Path,
-			Runtime:    string(s.Project.Runtime.Type),
-			Version:    s.Project.Runtime.Version,
-			Files:      s.TotalFiles,
-			Folders:    s.TotalFolders,
-			TotalLines: s.TotalLines,
-			CodeLines:  s.CodeLines,
-			BlankLines: s.BlankLines,
-			SizeBytes:  s.TotalSize,
+			Name:            s.Project.Name,
+			Path:            s.Project.Path,
+			Runtime:         string(s.Project.Runtime.Type),
+			Version:         s.Project.Runtime.Version,
+			DependencyCount: s.Project.DependencyCount,
+			PackageManager:  s.Project.PackageManager,
+			Framework:       s.Project.Framework,
+			Files:           s.TotalFiles,
+			Folders:         s.TotalFolders,
+			TestFolders:     s.TestFolders,
+			TotalLines:      s.TotalLines,
+			CodeLines:       s.CodeLines,
+			BlankLines:      s.BlankLines,
+			SizeBytes:       s.TotalSize,
+			StructuralLines: s.StructuralLines,
+			DataLines:       s.DataLines,
+			NotebookLines:   s.NotebookLines,
+			LongLines:       s.LongLines,
+			BlankRuns:       s.BlankRuns,
+			GeneratedFiles:  s.GeneratedFiles,
+			GeneratedLines:  s.GeneratedLines,
+			BinaryFiles:     s.BinaryFiles,
+		}
+
+		for _, l := range s.SubLanguages {
+			p.Languages = append(p.Languages, SubLanguageOutput{
+				Language:   l.Language,
+				Files:      l.TotalFiles,
+				TotalLines: l.TotalLines,
+				CodeLines:  l.CodeLines,
+				BlankLines: l.BlankLines,
+			})
+		}
+
+		projectDir := filepath.Join(rootDir, s.Project.Path)
+		for _, f := range s.SkippedFiles {
+			p.SkippedFiles = append(p.SkippedFiles, SkippedFileOutput{
+				Path:      projectRelPath(projectDir, f.Path),
+				SizeBytes: f.Size,
+			})
 		}
 
 		for _, f := range s.LargestFiles {
 			p.LargestFiles = append(p.LargestFiles, FileStatsOutput{
-				Path:  filepath.Base(f.Path),
+				Path:  projectRelPath(projectDir, f.Path),
 				Lines: f.Lines,
 			})
 		}
 
 		if len(s.Children) > 0 {
-			p.Children = convertProjectStats(s.Children)
+			p.Children = convertProjectStats(s.Children, rootDir)
 		}
 
 		result = append(result, p)
@@ -272,24 +912,36 @@ func convertProjectStats(stats []*models.ProjectStats) []ProjectStatsOutput {
 	return result
 }
 
-func calculateTotals(stats []*models.ProjectStats) TotalsOutput {
-	totals := TotalsOutput{}
-
-	var aggregate func([]*models.ProjectStats)
-	aggregate = func(list []*models.ProjectStats) {
-		for _, s := range list {
-			totals.Files += s.TotalFiles
-			totals.Folders += s.TotalFolders
-			totals.TotalLines += s.TotalLines
-			totals.CodeLines += s.CodeLines
-			totals.BlankLines += s.BlankLines
-			totals.SizeBytes += s.TotalSize
-			aggregate(s.Children)
-		}
+// projectRelPath returns the path of a counted file relative to projectDir,
+// or path itself if it does not lie below it.
+func projectRelPath(projectDir, path string) string {
+	relPath, err := filepath.Rel(projectDir, path)
+	if err != nil || relPath == ".." || strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+		return path
 	}
+	return relPath
+}
 
-	aggregate(stats)
-	return totals
+func calculateTotals(stats []*models.ProjectStats) TotalsOutput {
+	totals := pkgstats.Sum(stats)
+	return TotalsOutput{
+		Files:           totals.Files,
+		Folders:         totals.Folders,
+		TestFolders:     totals.TestFolders,
+		TotalLines:      totals.TotalLines,
+		CodeLines:       totals.CodeLines,
+		BlankLines:      totals.BlankLines,
+		StructuralLines: totals.StructuralLines,
+		DataLines:       totals.DataLines,
+		NotebookLines:   totals.NotebookLines,
+		LongLines:       totals.LongLines,
+		BlankRuns:       totals.BlankRuns,
+		SizeBytes:       totals.Size,
+		GeneratedFiles:  totals.GeneratedFiles,
+		GeneratedLines:  totals.GeneratedLines,
+		SkippedFiles:    totals.SkippedFiles,
+		BinaryFiles:     totals.BinaryFiles,
+	}
 }
 
 func outputYAML(output StatsOutput) error {
@@ -315,22 +967,33 @@ func outputXML(output StatsOutput) error {
 	return nil
 }
 
-func outputCSV(projectStats []*models.ProjectStats) error {
-	writer := csv.NewWriter(os.Stdout)
+// csvTotalsName is the name of the grand totals row that --csv-totals adds
+// to CSV output. The row's path is empty, which no project's is.
+const csvTotalsName = "TOTAL"
+
+// outputCSV writes one row per project in the hierarchy. With nest, rows
+// gain depth and parent columns; with totals, a final csvTotalsName row
+// holds the grand totals reported by the other formats.
+func outputCSV(w io.Writer, projectStats []*models.ProjectStats, normalizePaths, nest, totals bool) error {
+	writer := csv.NewWriter(w)
 	defer writer.Flush()
 
 	// Write header
 	header := []string{"name", "path", "runtime", "version", "files", "folders", "total_lines", "code_lines", "blank_lines", "size_bytes"}
+	if nest {
+		header = append(header, "depth", "parent")
+	}
 	if err := writer.Write(header); err != nil {
 		return err
 	}
 
 	// Flatten and write all projects
-	var writeProject func(*models.ProjectStats)
-	writeProject = func(s *models.ProjectStats) {
+	var writeProject func(s *models.ProjectStats, depth int, parent string)
+	writeProject = func(s *models.ProjectStats, depth int, parent string) {
+		path := outputPath(s.Project.Path, normalizePaths)
 		row := []string{
 			s.Project.Name,
-			s.Project.Path,
+			path,
 			string(s.Project.Runtime.Type),
 			s.Project.Runtime.Version,
 			strconv.Itoa(s.TotalFiles),
@@ -340,15 +1003,154 @@ func outputCSV(projectStats []*models.ProjectStats) error {
 			strconv.Itoa(s.BlankLines),
 			strconv.FormatInt(s.TotalSize, 10),
 		}
+		if nest {
+			row = append(row, strconv.Itoa(depth), parent)
+		}
+		writer.Write(row)
+
+		for _, child := range s.Children {
+			writeProject(child, depth+1, path)
+		}
+	}
+
+	for _, s := range projectStats {
+		writeProject(s, 0, "")
+	}
+
+	if totals {
+		t := calculateTotals(projectStats)
+		row := []string{
+			csvTotalsName,
+			"",
+			"",
+			"",
+			strconv.Itoa(t.Files),
+			strconv.Itoa(t.Folders),
+			strconv.Itoa(t.TotalLines),
+			strconv.Itoa(t.CodeLines),
+			strconv.Itoa(t.BlankLines),
+			strconv.FormatInt(t.SizeBytes, 10),
+		}
+		if nest {
+			row = append(row, "", "")
+		}
 		writer.Write(row)
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// flattenProjects lists every project of the hierarchy in depth-first
+// order, each without its children.
+func flattenProjects(projects []ProjectStatsOutput) []ProjectStatsOutput {
+	flat := flattenProjectOutputs(projects)
+	for i := range flat {
+		flat[i].Children = nil
+	}
+	return flat
+}
+
+// outputFilesCSV writes one row per file counted for each project in the
+// hierarchy, with paths relative to rootDir. A file counted by both a
+// project and its parent appears once for each, matching the file totals.
+func outputFilesCSV(w io.Writer, projectStats []*models.ProjectStats, rootDir string, normalizePaths bool) error {
+	writer := csv.NewWriter(w)
+
+	header := []string{"path", "project", "runtime", "lines", "code_lines", "blank_lines", "size_bytes"}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	var writeProject func(*models.ProjectStats) error
+	writeProject = func(s *models.ProjectStats) error {
+		for _, f := range s.AllFiles {
+			path := f.Path
+			if relPath, err := filepath.Rel(rootDir, f.Path); err == nil {
+				path = relPath
+			}
+			row := []string{
+				outputPath(path, normalizePaths),
+				s.Project.Name,
+				string(s.Project.Runtime.Type),
+				strconv.Itoa(f.Lines),
+				strconv.Itoa(f.CodeLines),
+				strconv.Itoa(f.BlankLines),
+				strconv.FormatInt(f.Size, 10),
+			}
+			if err := writer.Write(row); err != nil {
+				return err
+			}
+		}
 
 		for _, child := range s.Children {
-			writeProject(child)
+			if err := writeProject(child); err != nil {
+				return err
+			}
 		}
+		return nil
 	}
 
 	for _, s := range projectStats {
-		writeProject(s)
+		if err := writeProject(s); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// outputMarkdown writes a Markdown table with one row per project in the
+// hierarchy, followed by a totals row. Child projects are indented under
+// their parent.
+func outputMarkdown(w io.Writer, output StatsOutput) error {
+	fmt.Fprintln(w, "| Project | Runtime | Files | Code Lines | Size |")
+	fmt.Fprintln(w, "|---|---|---:|---:|---:|")
+
+	var writeProjects func([]ProjectStatsOutput, int)
+	writeProjects = func(projects []ProjectStatsOutput, depth int) {
+		for _, p := range projects {
+			name := strings.Repeat("&nbsp;&nbsp;", depth) + markdownEscape(p.Name)
+			fmt.Fprintf(w, "| %s | %s | %d | %d | %s |\n",
+				name, markdownEscape(p.Runtime), p.Files, p.CodeLines, stats.FormatSize(p.SizeBytes))
+			writeProjects(p.Children, depth+1)
+		}
+	}
+	writeProjects(output.Projects, 0)
+
+	fmt.Fprintf(w, "| **Total** | | **%d** | **%d** | **%s** |\n",
+		output.Totals.Files, output.Totals.CodeLines, stats.FormatSize(output.Totals.SizeBytes))
+	return nil
+}
+
+// markdownEscape escapes characters that would break a Markdown table cell.
+func markdownEscape(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+func outputLanguagesCSV(langs []LanguageTotalsOutput) error {
+	writer := csv.NewWriter(os.Stdout)
+	defer writer.Flush()
+
+	header := []string{"runtime", "projects", "files", "total_lines", "code_lines", "blank_lines", "size_bytes"}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, l := range langs {
+		row := []string{
+			l.Runtime,
+			strconv.Itoa(l.Projects),
+			strconv.Itoa(l.Files),
+			strconv.Itoa(l.TotalLines),
+			strconv.Itoa(l.CodeLines),
+			strconv.Itoa(l.BlankLines),
+			strconv.FormatInt(l.SizeBytes, 10),
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
 	}
 
 	return nil
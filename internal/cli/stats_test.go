@@ -0,0 +1,543 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"repoctr/internal/stats"
+	"repoctr/internal/version"
+	"repoctr/pkg/models"
+	pkgstats "repoctr/pkg/stats"
+)
+
+func TestRunStats_PathsFromConflicts(t *testing.T) {
+	tests := []struct {
+		flag string
+		opts StatsOptions
+	}{
+		{"--project", StatsOptions{ProjectName: "api"}},
+		{"--exclude", StatsOptions{Excludes: []string{"*.gen.go"}}},
+		{"--include-ext", StatsOptions{IncludeExtensions: []string{".tmpl"}}},
+		{"--only", StatsOptions{Runtimes: []models.RuntimeType{models.RuntimeGo}}},
+		{"--skip", StatsOptions{ExcludeRuntimes: []models.RuntimeType{models.RuntimeGo}}},
+		{"--exclude-generated-dirs", StatsOptions{ExcludeGeneratedDirs: true}},
+		{"--cache", StatsOptions{CacheFile: "cache.json"}},
+		{"--project-jobs", StatsOptions{ProjectJobs: 4}},
+		{"--stats-of-manifest", StatsOptions{ManifestsOnly: true}},
+		{"--watch-interval", StatsOptions{WatchInterval: 2 * time.Second}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.flag, func(t *testing.T) {
+			tt.opts.PathsFrom = filepath.Join(t.TempDir(), "files.txt")
+			err := RunStats(projectsFileName, tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.flag) {
+				t.Errorf("RunStats error = %v, want one naming %s", err, tt.flag)
+			}
+		})
+	}
+}
+
+func TestReadPathList(t *testing.T) {
+	dir := t.TempDir()
+	listFile := filepath.Join(dir, "files.txt")
+
+	content := "# changed files\nsrc/a.go\n\n  src/b.go  \n"
+	if err := os.WriteFile(listFile, []byte(content), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	paths, err := readPathList(listFile)
+	if err != nil {
+		t.Fatalf("readPathList: %v", err)
+	}
+
+	want := []string{"src/a.go", "src/b.go"}
+	if len(paths) != len(want) {
+		t.Fatalf("paths = %v, want %v", paths, want)
+	}
+	for i := range want {
+		if paths[i] != want[i] {
+			t.Errorf("paths[%d] = %q, want %q", i, paths[i], want[i])
+		}
+	}
+}
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int64
+		wantErr bool
+	}{
+		{"500000", 500000, false},
+		{"512K", 512 << 10, false},
+		{"512kb", 512 << 10, false},
+		{"2M", 2 << 20, false},
+		{"1G", 1 << 30, false},
+		{"", 0, true},
+		{"10X", 0, true},
+		{"-1", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseByteSize(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseByteSize(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseByteSize(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestOutputFilesCSV(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "main.go", "package main\n\nfunc main() {}\n")
+	writeTestFile(t, root, "util.go", "package main\n")
+	writeTestFile(t, root, "web/index.js", "// entry\nconsole.log('hi');\n\n")
+
+	projects := []*models.Project{
+		{Name: "app", Path: ".", Runtime: models.Runtime{Type: models.RuntimeGo}, SourcePaths: []string{"."}},
+		{Name: "web", Path: "web", Runtime: models.Runtime{Type: models.RuntimeJavaScript}, SourcePaths: []string{"."}},
+	}
+	counter, err := stats.NewCounter(root)
+	if err != nil {
+		t.Fatalf("NewCounter: %v", err)
+	}
+	projectStats, err := counter.CountHierarchy(context.Background(), projects, stats.HierarchyOptions{})
+	if err != nil {
+		t.Fatalf("CountHierarchy: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := outputFilesCSV(&out, projectStats, root, true); err != nil {
+		t.Fatalf("outputFilesCSV: %v", err)
+	}
+
+	records, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatalf("parse CSV: %v\n%s", err, out.String())
+	}
+
+	wantHeader := []string{"path", "project", "runtime", "lines", "code_lines", "blank_lines", "size_bytes"}
+	if len(records) == 0 || !slices.Equal(records[0], wantHeader) {
+		t.Fatalf("header = %v, want %v", records, wantHeader)
+	}
+	if files := pkgstats.Sum(projectStats).Files; len(records)-1 != files {
+		t.Errorf("got %d rows, want one per file (%d)", len(records)-1, files)
+	}
+
+	want := map[string][]string{
+		"main.go":      {"main.go", "app", "Go", "3", "2", "1", "29"},
+		"util.go":      {"util.go", "app", "Go", "1", "1", "0", "13"},
+		"web/index.js": {"web/index.js", "web", "JavaScript", "3", "2", "1", "29"},
+	}
+	for _, record := range records[1:] {
+		if w, ok := want[record[0]]; !ok || !slices.Equal(record, w) {
+			t.Errorf("row = %v, want %v", record, w)
+		}
+	}
+}
+
+func TestOutputMarkdown(t *testing.T) {
+	projectStats := []*models.ProjectStats{
+		{
+			Project:    &models.Project{Name: "api", Path: "api", Runtime: models.Runtime{Type: models.RuntimeGo}},
+			TotalFiles: 12,
+			CodeLines:  1500,
+			TotalSize:  2048,
+			Children: []*models.ProjectStats{
+				{
+					Project:    &models.Project{Name: "proto|gen", Path: "api/proto", Runtime: models.Runtime{Type: models.RuntimeGo}},
+					TotalFiles: 3,
+					CodeLines:  200,
+					TotalSize:  512,
+				},
+			},
+		},
+		{
+			Project:    &models.Project{Name: "web", Path: "web", Runtime: models.Runtime{Type: models.RuntimeTypeScript}},
+			TotalFiles: 8,
+			CodeLines:  900,
+			TotalSize:  4096,
+		},
+	}
+
+	var out bytes.Buffer
+	if err := outputMarkdown(&out, buildStatsOutput(projectStats, "")); err != nil {
+		t.Fatalf("outputMarkdown: %v", err)
+	}
+
+	want := []string{
+		"| Project | Runtime | Files | Code Lines | Size |",
+		"|---|---|---:|---:|---:|",
+		"| api | Go | 12 | 1500 | 2.0 KB |",
+		`| &nbsp;&nbsp;proto\|gen | Go | 3 | 200 | 512 B |`,
+		"| web | TypeScript | 8 | 900 | 4.0 KB |",
+		"| **Total** | | **23** | **2600** | **6.5 KB** |",
+	}
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if !slices.Equal(lines, want) {
+		t.Errorf("markdown =\n%s\nwant\n%s", out.String(), strings.Join(want, "\n"))
+	}
+}
+
+func TestRenderStats_NormalizePaths(t *testing.T) {
+	// Paths as the counter produces them on Windows
+	rootDir := "/repo"
+	projectStats := []*models.ProjectStats{
+		{
+			Project:    &models.Project{Name: "api", Path: `services\api`, Runtime: models.Runtime{Type: models.RuntimeGo}},
+			TotalFiles: 1,
+			AllFiles: []models.FileStats{
+				{Path: `/repo/services\api\cmd\main.go`, Lines: 10, LongLines: 1},
+			},
+			LargestFiles: []models.FileStats{
+				{Path: `/repo/services\api\cmd\main.go`, Lines: 10},
+			},
+			SkippedFiles: []models.SkippedFile{
+				{Path: `/repo/services\api\assets\bundle.js`, Size: 4096},
+			},
+		},
+	}
+
+	formats := []string{"", "yaml", "json", "xml", "csv", "csv-files"}
+	for _, format := range formats {
+		opts := StatsOptions{Format: format, NormalizePaths: true, LongLines: true, MaxLineLength: 80}
+		out := captureStdout(t, func() {
+			if err := renderStats(projectStats, rootDir, opts); err != nil {
+				t.Errorf("renderStats(%q): %v", format, err)
+			}
+		})
+		if strings.Contains(string(out), `\`) {
+			t.Errorf("format %q emitted a backslash:\n%s", format, out)
+		}
+		if !strings.Contains(string(out), "services/api") {
+			t.Errorf("format %q did not emit the normalized project path:\n%s", format, out)
+		}
+	}
+
+	// Without normalization, paths are emitted as counted
+	out := captureStdout(t, func() {
+		if err := renderStats(projectStats, rootDir, StatsOptions{Format: "csv"}); err != nil {
+			t.Errorf("renderStats: %v", err)
+		}
+	})
+	if !strings.Contains(string(out), `services\api`) {
+		t.Errorf("expected native path without --normalize-paths:\n%s", out)
+	}
+}
+
+func TestBuildStatsOutput_FilePathsRelativeToProject(t *testing.T) {
+	rootDir := "repo"
+	projectStats := []*models.ProjectStats{
+		{
+			Project: &models.Project{Name: "api", Path: filepath.Join("services", "api")},
+			LargestFiles: []models.FileStats{
+				{Path: filepath.Join(rootDir, "services", "api", "cmd", "main.go"), Lines: 10},
+			},
+			SkippedFiles: []models.SkippedFile{
+				{Path: filepath.Join(rootDir, "services", "api", "assets", "bundle.js"), Size: 4096},
+			},
+		},
+	}
+
+	output := buildStatsOutput(projectStats, rootDir)
+	normalizeOutputPaths(output.Projects)
+
+	p := output.Projects[0]
+	if got := p.LargestFiles[0].Path; got != "cmd/main.go" {
+		t.Errorf("largest file path = %q, want cmd/main.go", got)
+	}
+	if got := p.SkippedFiles[0].Path; got != "assets/bundle.js" {
+		t.Errorf("skipped file path = %q, want assets/bundle.js", got)
+	}
+}
+
+func TestBuildStatsOutput_Provenance(t *testing.T) {
+	generatedAt := time.Date(2024, 3, 1, 14, 30, 0, 0, time.FixedZone("CET", 3600))
+	restore := clock
+	clock = func() time.Time { return generatedAt }
+	defer func() { clock = restore }()
+
+	projectStats := []*models.ProjectStats{
+		{Project: &models.Project{Name: "api", Path: "api", Runtime: models.Runtime{Type: models.RuntimeGo}}},
+	}
+
+	data, err := json.Marshal(buildStatsOutput(projectStats, ""))
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var output StatsOutput
+	if err := json.Unmarshal(data, &output); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	if output.GeneratedAt != "2024-03-01T13:30:00Z" {
+		t.Errorf("generated_at = %q, want the clock time in UTC", output.GeneratedAt)
+	}
+	parsed, err := time.Parse(time.RFC3339, output.GeneratedAt)
+	if err != nil || !parsed.Equal(generatedAt) {
+		t.Errorf("generated_at parses to %v (%v), want %v", parsed, err, generatedAt)
+	}
+	if output.ToolVersion == "" || output.ToolVersion != version.Version {
+		t.Errorf("tool_version = %q, want %q", output.ToolVersion, version.Version)
+	}
+}
+
+func TestFilterProjectsByRuntime(t *testing.T) {
+	projects := []*models.Project{
+		{
+			Name:    "web",
+			Runtime: models.Runtime{Type: models.RuntimeJavaScript},
+			Children: []*models.Project{
+				{Name: "api", Runtime: models.Runtime{Type: models.RuntimeGo}},
+				{Name: "ui", Runtime: models.Runtime{Type: models.RuntimeTypeScript}},
+			},
+		},
+		{Name: "tool", Runtime: models.Runtime{Type: models.RuntimeGo}},
+	}
+
+	names := func(list []*models.Project) []string {
+		var result []string
+		for _, p := range list {
+			result = append(result, p.Name)
+			for _, c := range p.Children {
+				result = append(result, p.Name+"/"+c.Name)
+			}
+		}
+		return result
+	}
+
+	include, err := parseRuntimeTypes([]string{"golang"})
+	if err != nil {
+		t.Fatalf("parseRuntimeTypes: %v", err)
+	}
+	// api is promoted in place of its JavaScript parent
+	if got, want := names(filterProjectsByRuntime(projects, include, nil)), []string{"api", "tool"}; !slices.Equal(got, want) {
+		t.Errorf("--runtime golang = %v, want %v", got, want)
+	}
+
+	exclude, err := parseRuntimeTypes([]string{"ts", "Go"})
+	if err != nil {
+		t.Fatalf("parseRuntimeTypes: %v", err)
+	}
+	if got, want := names(filterProjectsByRuntime(projects, nil, exclude)), []string{"web"}; !slices.Equal(got, want) {
+		t.Errorf("--exclude-runtime ts,Go = %v, want %v", got, want)
+	}
+
+	if len(projects[0].Children) != 2 {
+		t.Error("filtering modified the input projects")
+	}
+
+	if _, err := parseRuntimeTypes([]string{"cobol"}); err == nil {
+		t.Error("expected an error for an unknown runtime")
+	}
+}
+
+func TestStatsCmd_OnlyAndSkip(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "projects.yaml", `projects:
+  - name: api
+    path: api
+    runtime:
+      type: Go
+    source-paths: [.]
+  - name: engine
+    path: engine
+    runtime:
+      type: Rust
+    source-paths: [.]
+  - name: web
+    path: web
+    runtime:
+      type: JavaScript
+    source-paths: [.]
+    children:
+      - name: wasm
+        path: web/wasm
+        runtime:
+          type: Rust
+        source-paths: [.]
+`)
+	writeTestFile(t, root, "api/main.go", "package main\n")
+	writeTestFile(t, root, "engine/src/lib.rs", "pub fn run() {}\n")
+	writeTestFile(t, root, "web/index.js", "console.log(1)\n")
+	writeTestFile(t, root, "web/wasm/lib.rs", "pub fn add() {}\n")
+
+	run := func(args ...string) []string {
+		t.Helper()
+		cmd := NewStatsCmd()
+		cmd.SetArgs(append([]string{"-f", filepath.Join(root, "projects.yaml"), "--json"}, args...))
+
+		var err error
+		out := captureStdout(t, func() { err = cmd.Execute() })
+		if err != nil {
+			t.Fatalf("stats %v: %v", args, err)
+		}
+
+		var output StatsOutput
+		if err := json.Unmarshal(out, &output); err != nil {
+			t.Fatalf("unmarshal: %v\n%s", err, out)
+		}
+		var names []string
+		var collect func([]ProjectStatsOutput)
+		collect = func(list []ProjectStatsOutput) {
+			for _, p := range list {
+				names = append(names, p.Name)
+				collect(p.Children)
+			}
+		}
+		collect(output.Projects)
+		return names
+	}
+
+	// The JavaScript parent is not counted; its Rust child takes its place
+	if got, want := run("--only", "go,rust"), []string{"api", "engine", "wasm"}; !slices.Equal(got, want) {
+		t.Errorf("--only go,rust = %v, want %v", got, want)
+	}
+	if got, want := run("--skip", "rs"), []string{"api", "web"}; !slices.Equal(got, want) {
+		t.Errorf("--skip rs = %v, want %v", got, want)
+	}
+}
+
+// layoutTestStats returns a parent project with one child.
+func layoutTestStats() []*models.ProjectStats {
+	child := &models.ProjectStats{
+		Project:    &models.Project{Name: "web", Path: "app/web", Runtime: models.Runtime{Type: models.RuntimeTypeScript}},
+		TotalFiles: 2,
+		CodeLines:  20,
+	}
+	return []*models.ProjectStats{{
+		Project:    &models.Project{Name: "app", Path: "app", Runtime: models.Runtime{Type: models.RuntimeGo}},
+		TotalFiles: 3,
+		CodeLines:  30,
+		Children:   []*models.ProjectStats{child},
+	}}
+}
+
+func TestOutputMachineReadable_FlattenJSON(t *testing.T) {
+	out := captureStdout(t, func() {
+		if err := outputMachineReadable(layoutTestStats(), "", FormatJSON, true, LayoutFlatten, false); err != nil {
+			t.Fatalf("outputMachineReadable: %v", err)
+		}
+	})
+
+	var output struct {
+		Projects []map[string]any `json:"projects"`
+	}
+	if err := json.Unmarshal(out, &output); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, out)
+	}
+	if len(output.Projects) != 2 {
+		t.Fatalf("got %d projects, want parent and child at the top level", len(output.Projects))
+	}
+	for i, want := range []string{"app", "app/web"} {
+		p := output.Projects[i]
+		if p["path"] != want {
+			t.Errorf("projects[%d].path = %v, want %s", i, p["path"], want)
+		}
+		if _, ok := p["children"]; ok {
+			t.Errorf("projects[%d] has a children key with --flatten", i)
+		}
+	}
+
+	// The default layout nests the child
+	out = captureStdout(t, func() {
+		if err := outputMachineReadable(layoutTestStats(), "", FormatJSON, true, LayoutDefault, false); err != nil {
+			t.Fatalf("outputMachineReadable: %v", err)
+		}
+	})
+	if err := json.Unmarshal(out, &output); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, out)
+	}
+	if len(output.Projects) != 1 || output.Projects[0]["children"] == nil {
+		t.Errorf("default layout = %v, want one project with children", output.Projects)
+	}
+}
+
+func TestOutputCSV_Totals(t *testing.T) {
+	projectStats := layoutTestStats()
+	projectStats = append(projectStats, &models.ProjectStats{
+		Project:    &models.Project{Name: "tool", Path: "tool", Runtime: models.Runtime{Type: models.RuntimeGo}},
+		TotalFiles: 1,
+		TotalLines: 12,
+		CodeLines:  10,
+		BlankLines: 2,
+		TotalSize:  300,
+	})
+
+	var out bytes.Buffer
+	if err := outputCSV(&out, projectStats, true, false, true); err != nil {
+		t.Fatalf("outputCSV: %v", err)
+	}
+
+	records, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatalf("parse CSV: %v", err)
+	}
+	if len(records) != 5 {
+		t.Fatalf("got %d records, want header, 3 projects, and totals", len(records))
+	}
+
+	totals := calculateTotals(projectStats)
+	want := []string{
+		"TOTAL", "", "", "",
+		strconv.Itoa(totals.Files),
+		strconv.Itoa(totals.Folders),
+		strconv.Itoa(totals.TotalLines),
+		strconv.Itoa(totals.CodeLines),
+		strconv.Itoa(totals.BlankLines),
+		strconv.FormatInt(totals.SizeBytes, 10),
+	}
+	if last := records[len(records)-1]; !slices.Equal(last, want) {
+		t.Errorf("last row = %v, want %v", last, want)
+	}
+	if totals.Files != 6 || totals.CodeLines != 60 {
+		t.Errorf("totals = %+v, want 6 files and 60 code lines", totals)
+	}
+}
+
+func TestOutputCSV_Nest(t *testing.T) {
+	var out bytes.Buffer
+	if err := outputCSV(&out, layoutTestStats(), true, true, false); err != nil {
+		t.Fatalf("outputCSV: %v", err)
+	}
+
+	records, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatalf("parse CSV: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("got %d records, want header and 2 rows", len(records))
+	}
+	header := records[0]
+	if !slices.Equal(header[len(header)-2:], []string{"depth", "parent"}) {
+		t.Errorf("header = %v, want trailing depth and parent columns", header)
+	}
+	if got := records[1][len(header)-2:]; !slices.Equal(got, []string{"0", ""}) {
+		t.Errorf("parent row nesting = %v, want [0 \"\"]", got)
+	}
+	if got := records[2][len(header)-2:]; !slices.Equal(got, []string{"1", "app"}) {
+		t.Errorf("child row nesting = %v, want [1 app]", got)
+	}
+
+	// Without --nest the columns are unchanged
+	out.Reset()
+	if err := outputCSV(&out, layoutTestStats(), true, false, false); err != nil {
+		t.Fatalf("outputCSV: %v", err)
+	}
+	if strings.Contains(out.String(), "depth") {
+		t.Errorf("flat CSV has nesting columns:\n%s", out.String())
+	}
+}
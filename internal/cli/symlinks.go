@@ -0,0 +1,10 @@
+package cli
+
+// followSymlinks is set once from the global --follow-symlinks flag.
+var followSymlinks bool
+
+// SetFollowSymlinks makes discovery and counting in every command descend
+// into symbolic links to directories.
+func SetFollowSymlinks(follow bool) {
+	followSymlinks = follow
+}
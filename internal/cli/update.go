@@ -5,15 +5,19 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"runtime"
 	"sort"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -25,13 +29,88 @@ var httpClient = &http.Client{
 	Timeout: 60 * time.Second,
 }
 
-// allowedDownloadHosts contains the valid hosts for binary downloads.
+// maxAttempts is how many times a request is tried before a transient
+// failure is reported.
+const maxAttempts = 3
+
+// retryDelay is the wait before the first retry; it doubles after each.
+var retryDelay = time.Second
+
+// allowedDownloadHosts contains the valid hosts for binary downloads from GitHub.
 var allowedDownloadHosts = []string{
 	"https://github.com/",
 	"https://objects.githubusercontent.com/",
 }
 
-// githubRelease represents a GitHub release from the API.
+// Supported release providers.
+const (
+	providerGitHub = "github"
+	providerGitLab = "gitlab"
+	providerGitea  = "gitea"
+)
+
+// releaseSource describes where releases are listed and which hosts
+// binaries and checksums may be downloaded from.
+type releaseSource struct {
+	Provider     string
+	ReleasesURL  string
+	AllowedHosts []string
+}
+
+// currentReleaseSource returns the release source configured at build time,
+// with REPOCTR_RELEASE_PROVIDER and REPOCTR_RELEASE_URL taking precedence.
+func currentReleaseSource() (releaseSource, error) {
+	provider := version.ReleaseProvider
+	if env := os.Getenv("REPOCTR_RELEASE_PROVIDER"); env != "" {
+		provider = env
+	}
+	baseURL := version.ReleaseBaseURL
+	if env := os.Getenv("REPOCTR_RELEASE_URL"); env != "" {
+		baseURL = env
+	}
+	return newReleaseSource(provider, baseURL, version.GitHubOwner, version.GitHubRepo)
+}
+
+// newReleaseSource builds the release source for provider. GitLab and Gitea
+// require the https base URL of the instance, which also becomes the only
+// allowed download host.
+func newReleaseSource(provider, baseURL, owner, repo string) (releaseSource, error) {
+	provider = strings.ToLower(strings.TrimSpace(provider))
+	if provider == "" || provider == providerGitHub {
+		return releaseSource{
+			Provider:     providerGitHub,
+			ReleasesURL:  fmt.Sprintf("https://api.github.com/repos/%s/%s/releases", owner, repo),
+			AllowedHosts: allowedDownloadHosts,
+		}, nil
+	}
+
+	if provider != providerGitLab && provider != providerGitea {
+		return releaseSource{}, fmt.Errorf("unknown release provider %q (expected github, gitlab, or gitea)", provider)
+	}
+
+	baseURL = strings.TrimSuffix(strings.TrimSpace(baseURL), "/")
+	if baseURL == "" {
+		return releaseSource{}, fmt.Errorf("release provider %s requires a base URL (set REPOCTR_RELEASE_URL)", provider)
+	}
+	if !strings.HasPrefix(baseURL, "https://") {
+		return releaseSource{}, fmt.Errorf("release base URL must use https: %s", baseURL)
+	}
+
+	source := releaseSource{
+		Provider:     provider,
+		AllowedHosts: []string{baseURL + "/"},
+	}
+	if provider == providerGitLab {
+		source.ReleasesURL = fmt.Sprintf("%s/api/v4/projects/%s/releases", baseURL, url.PathEscape(owner+"/"+repo))
+	} else {
+		source.ReleasesURL = fmt.Sprintf("%s/api/v1/repos/%s/%s/releases", baseURL, owner, repo)
+	}
+
+	return source, nil
+}
+
+// githubRelease represents a GitHub release from the API. Gitea uses the
+// same shape, and GitLab releases are converted to it.
 type githubRelease struct {
 	TagName     string        `json:"tag_name"`
 	Name        string        `json:"name"`
@@ -48,42 +127,147 @@ type githubAsset struct {
 	BrowserDownloadURL string `json:"browser_download_url"`
 }
 
+// gitlabRelease represents a release from the GitLab API.
+type gitlabRelease struct {
+	TagName         string `json:"tag_name"`
+	Name            string `json:"name"`
+	Description     string `json:"description"`
+	ReleasedAt      string `json:"released_at"`
+	UpcomingRelease bool   `json:"upcoming_release"`
+	Assets          struct {
+		Links []gitlabAssetLink `json:"links"`
+	} `json:"assets"`
+}
+
+// gitlabAssetLink represents a release asset link in the GitLab API.
+type gitlabAssetLink struct {
+	Name           string `json:"name"`
+	URL            string `json:"url"`
+	DirectAssetURL string `json:"direct_asset_url"`
+}
+
+// toGitHubRelease converts a GitLab release to the common release shape.
+func (r gitlabRelease) toGitHubRelease() githubRelease {
+	release := githubRelease{
+		TagName:     r.TagName,
+		Name:        r.Name,
+		Body:        r.Description,
+		Prerelease:  r.UpcomingRelease,
+		PublishedAt: r.ReleasedAt,
+	}
+	for _, link := range r.Assets.Links {
+		downloadURL := link.DirectAssetURL
+		if downloadURL == "" {
+			downloadURL = link.URL
+		}
+		release.Assets = append(release.Assets, githubAsset{
+			Name:               link.Name,
+			BrowserDownloadURL: downloadURL,
+		})
+	}
+	return release
+}
+
+// updateOptions controls the update command.
+type updateOptions struct {
+	// Force reinstalls the latest release even if already up to date.
+	Force bool
+	// CheckOnly reports available updates without installing.
+	CheckOnly bool
+	// SkipChecksum skips SHA256 verification of the download.
+	SkipChecksum bool
+	// AssumeYes installs without prompting for confirmation.
+	AssumeYes bool
+	// Prerelease also considers pre-releases as upgrade candidates.
+	Prerelease bool
+	// Staged downloads the new binary next to the current one and swaps it
+	// in the next time repo-ctr starts, for platforms that lock running
+	// executables.
+	Staged bool
+	// DryRun selects the release and asset and fetches the checksum, then
+	// prints what would be installed where without downloading the binary
+	// or changing any file.
+	DryRun bool
+	// Version installs this release tag (e.g. "v1.2.3") instead of the
+	// latest, to pin or roll back.
+	Version string
+}
+
+// stdin is the source of interactive confirmations.
+var stdin io.Reader = os.Stdin
+
+// stdinIsInteractive reports whether stdin is a terminal.
+var stdinIsInteractive = func() bool {
+	info, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
 // NewUpdateCmd creates the update command.
 func NewUpdateCmd() *cobra.Command {
-	var forceUpdate bool
-	var checkOnly bool
-	var skipChecksum bool
+	var opts updateOptions
 
 	cmd := &cobra.Command{
 		Use:   "update",
 		Short: "Check for updates and upgrade repo-ctr to the latest version",
-		Long: `Checks GitHub for new releases and displays release notes for all
-versions since your current version. If updates are available, prompts
-to download and install the latest version.
+		Long: `Checks for new releases and displays release notes for all versions
+since your current version. If updates are available, prompts to download
+and install the latest version.
+
+Releases come from GitHub by default. Set GITHUB_TOKEN (or
+REPOCTR_GITHUB_TOKEN) to authenticate GitHub API requests and avoid the
+unauthenticated rate limit, e.g. on shared CI runners. Self-hosted installs
+can use GitLab or Gitea by setting REPOCTR_RELEASE_PROVIDER (gitlab or
+gitea) and REPOCTR_RELEASE_URL (e.g. https://gitlab.example.com).
 
 Use --check to only check for updates without installing.
 Use --force to update even if already on the latest version.
+Use --yes to install without prompting (required when stdin is not a terminal).
+Use --pre to include pre-releases (drafts are never installed).
+Use --staged to download the new binary next to the current one and
+install it the next time repo-ctr runs, where replacing a running
+executable fails.
+Use --dry-run to show the release, asset, checksum, and install path that
+would be used, without downloading the binary or changing any file.
+Use --version to install a specific release, e.g. to pin or roll back:
+  repo-ctr update --version v1.2.3
 Use --skip-checksum to skip SHA256 verification (not recommended).`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runUpdate(forceUpdate, checkOnly, skipChecksum)
+			return runUpdate(opts)
 		},
 	}
 
-	cmd.Flags().BoolVarP(&forceUpdate, "force", "f", false, "Force update even if already on latest version")
-	cmd.Flags().BoolVarP(&checkOnly, "check", "c", false, "Only check for updates, don't install")
-	cmd.Flags().BoolVar(&skipChecksum, "skip-checksum", false, "Skip SHA256 checksum verification (not recommended)")
+	cmd.Flags().BoolVarP(&opts.Force, "force", "f", false, "Force update even if already on latest version")
+	cmd.Flags().BoolVarP(&opts.CheckOnly, "check", "c", false, "Only check for updates, don't install")
+	cmd.Flags().BoolVar(&opts.SkipChecksum, "skip-checksum", false, "Skip SHA256 checksum verification (not recommended)")
+	cmd.Flags().BoolVar(&opts.Prerelease, "pre", false, "Include pre-releases when looking for updates")
+	cmd.Flags().BoolVar(&opts.Staged, "staged", false, "Stage the new binary and install it the next time repo-ctr runs")
+	cmd.Flags().StringVar(&opts.Version, "version", "", "Install this release tag (e.g. v1.2.3) instead of the latest")
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "Show what would be downloaded and where it would be installed, without changing any file")
+	cmd.Flags().BoolVarP(&opts.AssumeYes, "yes", "y", false, "Install without prompting for confirmation")
+	cmd.Flags().BoolVar(&opts.AssumeYes, "assume-yes", false, "Alias for --yes")
+	cmd.Flags().MarkHidden("assume-yes")
+	cmd.MarkFlagsMutuallyExclusive("check", "dry-run")
+	cmd.MarkFlagsMutuallyExclusive("check", "version")
 
 	return cmd
 }
 
-func runUpdate(forceUpdate, checkOnly, skipChecksum bool) error {
+func runUpdate(opts updateOptions) error {
 	currentVersion := version.Version
 
+	source, err := currentReleaseSource()
+	if err != nil {
+		return err
+	}
+
 	fmt.Printf("Current version: %s\n", currentVersion)
 	fmt.Println("Checking for updates...")
 
-	// Fetch releases from GitHub
-	releases, err := fetchReleases()
+	// Fetch releases from the configured provider
+	releases, err := fetchReleases(source)
 	if err != nil {
 		return fmt.Errorf("failed to check for updates: %w", err)
 	}
@@ -93,16 +277,19 @@ func runUpdate(forceUpdate, checkOnly, skipChecksum bool) error {
 		return nil
 	}
 
-	// Filter to stable releases only (no drafts or prereleases)
-	var stableReleases []githubRelease
-	for _, r := range releases {
-		if !r.Draft && !r.Prerelease {
-			stableReleases = append(stableReleases, r)
-		}
+	if opts.Version != "" {
+		return runUpdateToVersion(releases, currentVersion, opts, source)
 	}
 
+	// Filter to stable releases only (no drafts, and no prereleases unless --pre)
+	stableReleases := filterReleases(releases, opts.Prerelease)
+
 	if len(stableReleases) == 0 {
-		fmt.Println("No stable releases found.")
+		if opts.Prerelease {
+			fmt.Println("No releases found.")
+		} else {
+			fmt.Println("No stable releases found.")
+		}
 		return nil
 	}
 
@@ -115,13 +302,17 @@ func runUpdate(forceUpdate, checkOnly, skipChecksum bool) error {
 	// Find releases newer than current version
 	newerReleases := findNewerReleases(stableReleases, currentVersion)
 
-	if len(newerReleases) == 0 && !forceUpdate {
+	if len(newerReleases) == 0 && !opts.Force {
 		fmt.Printf("\nYou are already on the latest version (%s).\n", latestVersion)
 		return nil
 	}
 
 	if len(newerReleases) > 0 {
-		fmt.Printf("\nNew version available: %s\n", latestVersion)
+		if latestRelease.Prerelease {
+			fmt.Printf("\nNew pre-release available: %s\n", latestVersion)
+		} else {
+			fmt.Printf("\nNew version available: %s\n", latestVersion)
+		}
 		fmt.Println("\n" + strings.Repeat("=", 60))
 		fmt.Println("RELEASE NOTES")
 		fmt.Println(strings.Repeat("=", 60))
@@ -132,60 +323,188 @@ func runUpdate(forceUpdate, checkOnly, skipChecksum bool) error {
 			displayReleaseNotes(r)
 		}
 		fmt.Println(strings.Repeat("=", 60))
-	} else if forceUpdate {
+	} else if opts.Force {
 		fmt.Printf("\nForce updating to %s...\n", latestVersion)
 	}
 
-	if checkOnly {
+	if opts.CheckOnly {
 		if len(newerReleases) > 0 {
-			fmt.Printf("\nRun 'repo-ctr update' to install version %s.\n", latestVersion)
+			command := "repo-ctr update"
+			if opts.Prerelease {
+				command += " --pre"
+			}
+			fmt.Printf("\nRun '%s' to install version %s.\n", command, latestVersion)
 		}
 		return nil
 	}
 
+	return installRelease(latestRelease, opts, source)
+}
+
+// runUpdateToVersion installs the release tagged opts.Version, which may be
+// older than the current version. Pre-releases can be chosen by tag; drafts
+// never are.
+func runUpdateToVersion(releases []githubRelease, currentVersion string, opts updateOptions, source releaseSource) error {
+	release := findReleaseByTag(releases, opts.Version)
+	if release == nil {
+		return fmt.Errorf("release %s not found", opts.Version)
+	}
+
+	if compareVersions(release.TagName, currentVersion) == 0 && !opts.Force {
+		fmt.Printf("\nYou are already on %s. Use --force to reinstall it.\n", release.TagName)
+		return nil
+	}
+
+	fmt.Printf("\nSelected release: %s\n", release.TagName)
+	displayReleaseNotes(*release)
+	return installRelease(*release, opts, source)
+}
+
+// findReleaseByTag returns the release tagged tag, with or without a "v"
+// prefix, ignoring drafts. It returns nil if there is none.
+func findReleaseByTag(releases []githubRelease, tag string) *githubRelease {
+	want := strings.TrimPrefix(strings.TrimSpace(tag), "v")
+	for i, r := range releases {
+		if !r.Draft && strings.TrimPrefix(r.TagName, "v") == want {
+			return &releases[i]
+		}
+	}
+	return nil
+}
+
+// installRelease downloads and installs the binary of release for this
+// platform after confirmation, or only prints the plan with --dry-run.
+func installRelease(release githubRelease, opts updateOptions, source releaseSource) error {
 	// Find the appropriate asset for this OS/arch
-	asset := findAssetForPlatform(latestRelease.Assets)
+	asset := findAssetForPlatform(release.Assets)
 	if asset == nil {
-		return fmt.Errorf("no binary available for %s/%s", runtime.GOOS, runtime.GOARCH)
+		return fmt.Errorf("release %s has no binary for %s/%s", release.TagName, runtime.GOOS, runtime.GOARCH)
 	}
 
 	// Find the checksum file
-	checksumAsset := findChecksumAsset(latestRelease.Assets)
+	checksumAsset := findChecksumAsset(release.Assets, asset.Name)
+
+	if opts.DryRun {
+		return printUpdatePlan(release.TagName, asset, checksumAsset, opts, source.AllowedHosts)
+	}
 
 	// Prompt for confirmation
-	if !promptConfirm(fmt.Sprintf("Update to %s?", latestVersion)) {
+	if !confirm(fmt.Sprintf("Update to %s?", release.TagName), opts.AssumeYes) {
 		fmt.Println("Update cancelled.")
 		return nil
 	}
 
 	// Download and install
 	fmt.Printf("\nDownloading %s...\n", asset.Name)
-	if err := downloadAndInstall(asset, checksumAsset, skipChecksum); err != nil {
+	if err := downloadAndInstall(asset, checksumAsset, opts.SkipChecksum, opts.Staged, source.AllowedHosts); err != nil {
 		return fmt.Errorf("failed to update: %w", err)
 	}
 
-	fmt.Printf("\nSuccessfully updated to %s!\n", latestVersion)
+	if opts.Staged {
+		fmt.Printf("\nStaged %s; it will be installed the next time repo-ctr runs.\n", release.TagName)
+		return nil
+	}
+	fmt.Printf("\nSuccessfully updated to %s!\n", release.TagName)
 	return nil
 }
 
-func fetchReleases() ([]githubRelease, error) {
-	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/releases", version.GitHubOwner, version.GitHubRepo)
+// printUpdatePlan prints what installing asset would do: the download URL,
+// the expected checksum, and the path that would be replaced or staged. It
+// validates the URLs and fetches the checksum like downloadAndInstall, but
+// never downloads the binary or touches the filesystem.
+func printUpdatePlan(latestVersion string, asset, checksumAsset *githubAsset, opts updateOptions, allowedHosts []string) error {
+	if !isAllowedDownloadURL(asset.BrowserDownloadURL, allowedHosts) {
+		return fmt.Errorf("invalid download URL: must be from %s", strings.Join(allowedHosts, " or "))
+	}
 
-	req, err := http.NewRequest("GET", url, nil)
+	execPath, err := executablePath()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("\nDry run: no files will be changed.")
+	fmt.Printf("  Release:  %s\n", latestVersion)
+	fmt.Printf("  Asset:    %s\n", asset.Name)
+	fmt.Printf("  URL:      %s\n", asset.BrowserDownloadURL)
+
+	switch {
+	case opts.SkipChecksum:
+		fmt.Println("  Checksum: skipped (--skip-checksum)")
+	case checksumAsset == nil:
+		return fmt.Errorf("checksum verification would fail: no checksum file available (use --skip-checksum to proceed anyway)")
+	default:
+		if !isAllowedDownloadURL(checksumAsset.BrowserDownloadURL, allowedHosts) {
+			return fmt.Errorf("invalid checksum URL: must be from %s", strings.Join(allowedHosts, " or "))
+		}
+		expectedChecksum, err := fetchExpectedChecksum(checksumAsset.BrowserDownloadURL, asset.Name)
+		if err != nil {
+			return fmt.Errorf("checksum verification would fail: %w", err)
+		}
+		fmt.Printf("  Checksum: %s (from %s)\n", expectedChecksum, checksumAsset.Name)
+	}
+
+	if opts.Staged {
+		fmt.Printf("  Target:   %s (staged; installed over %s on the next run)\n", stagedBinaryPath(execPath), execPath)
+	} else {
+		fmt.Printf("  Target:   %s\n", execPath)
+	}
+	return nil
+}
+
+// filterReleases drops drafts and, unless includePrerelease is set,
+// pre-releases.
+func filterReleases(releases []githubRelease, includePrerelease bool) []githubRelease {
+	var filtered []githubRelease
+	for _, r := range releases {
+		if r.Draft || (r.Prerelease && !includePrerelease) {
+			continue
+		}
+		filtered = append(filtered, r)
+	}
+	return filtered
+}
+
+func fetchReleases(source releaseSource) ([]githubRelease, error) {
+	req, err := http.NewRequest("GET", source.ReleasesURL, nil)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	if source.Provider == providerGitHub {
+		req.Header.Set("Accept", "application/vnd.github.v3+json")
+		// Unauthenticated requests share a low rate limit per IP, which
+		// shared CI runners exhaust quickly
+		if token := githubToken(); token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+	} else {
+		req.Header.Set("Accept", "application/json")
+	}
 	req.Header.Set("User-Agent", "repo-ctr/"+version.Version)
 
-	resp, err := httpClient.Do(req)
+	resp, err := doWithRetry(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("GitHub API returned status %d", resp.StatusCode)
+		if remaining := resp.Header.Get("X-RateLimit-Remaining"); resp.StatusCode == http.StatusForbidden && remaining != "" {
+			return nil, fmt.Errorf("%s API returned status %d (rate limit remaining: %s); set GITHUB_TOKEN to authenticate",
+				source.Provider, resp.StatusCode, remaining)
+		}
+		return nil, fmt.Errorf("%s API returned status %d", source.Provider, resp.StatusCode)
+	}
+
+	if source.Provider == providerGitLab {
+		var gitlabReleases []gitlabRelease
+		if err := json.NewDecoder(resp.Body).Decode(&gitlabReleases); err != nil {
+			return nil, err
+		}
+		releases := make([]githubRelease, 0, len(gitlabReleases))
+		for _, r := range gitlabReleases {
+			releases = append(releases, r.toGitHubRelease())
+		}
+		return releases, nil
 	}
 
 	var releases []githubRelease
@@ -196,6 +515,57 @@ func fetchReleases() ([]githubRelease, error) {
 	return releases, nil
 }
 
+// getWithRetry is doWithRetry for a plain GET of url.
+func getWithRetry(url string) (*http.Response, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return doWithRetry(req)
+}
+
+// doWithRetry sends req, which must have no body, with httpClient. Transient
+// failures (timeouts, connection resets, and 5xx responses) are retried up
+// to maxAttempts in total with exponential backoff; the last failure is
+// returned as is. Other responses, such as 404, are returned at once.
+func doWithRetry(req *http.Request) (*http.Response, error) {
+	delay := retryDelay
+	for attempt := 1; ; attempt++ {
+		resp, err := httpClient.Do(req)
+		if attempt == maxAttempts || !isTransientFailure(resp, err) {
+			return resp, err
+		}
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+// isTransientFailure reports whether a request that returned resp and err
+// may succeed when retried.
+func isTransientFailure(resp *http.Response, err error) bool {
+	if err != nil {
+		var netErr net.Error
+		return errors.As(err, &netErr) && netErr.Timeout() ||
+			errors.Is(err, syscall.ECONNRESET) ||
+			errors.Is(err, io.ErrUnexpectedEOF) ||
+			errors.Is(err, io.EOF)
+	}
+	return resp.StatusCode >= 500
+}
+
+// githubToken returns the GitHub API token from REPOCTR_GITHUB_TOKEN or,
+// failing that, GITHUB_TOKEN, or "" if neither is set.
+func githubToken() string {
+	if token := strings.TrimSpace(os.Getenv("REPOCTR_GITHUB_TOKEN")); token != "" {
+		return token
+	}
+	return strings.TrimSpace(os.Getenv("GITHUB_TOKEN"))
+}
+
 // sortReleasesByVersion sorts releases by semantic version (newest first).
 func sortReleasesByVersion(releases []githubRelease) {
 	sort.Slice(releases, func(i, j int) bool {
@@ -214,19 +584,16 @@ func findNewerReleases(releases []githubRelease, currentVersion string) []github
 	return newer
 }
 
-// compareVersions compares two version strings.
+// compareVersions compares two version strings using semantic version
+// precedence: a pre-release ("1.0.0-beta") is lower than the release it
+// precedes, and build metadata ("+build.5") is ignored.
 // Returns: 1 if v1 > v2, -1 if v1 < v2, 0 if equal.
 func compareVersions(v1, v2 string) int {
-	// Strip 'v' prefix if present
-	v1 = strings.TrimPrefix(v1, "v")
-	v2 = strings.TrimPrefix(v2, "v")
-
-	// Split by '.' and also handle prerelease suffixes like "-beta"
-	v1 = strings.Split(v1, "-")[0] // Remove any prerelease suffix
-	v2 = strings.Split(v2, "-")[0]
+	core1, pre1 := splitVersion(v1)
+	core2, pre2 := splitVersion(v2)
 
-	parts1 := strings.Split(v1, ".")
-	parts2 := strings.Split(v2, ".")
+	parts1 := strings.Split(core1, ".")
+	parts2 := strings.Split(core2, ".")
 
 	// Compare each part
 	maxLen := len(parts1)
@@ -251,14 +618,86 @@ func compareVersions(v1, v2 string) int {
 		}
 	}
 
+	return comparePrerelease(pre1, pre2)
+}
+
+// splitVersion strips a "v" prefix and build metadata and splits a version
+// into its core ("1.2.0") and pre-release ("beta.1") parts.
+func splitVersion(v string) (core, prerelease string) {
+	v = strings.TrimPrefix(v, "v")
+	if i := strings.Index(v, "+"); i >= 0 {
+		v = v[:i]
+	}
+	if i := strings.Index(v, "-"); i >= 0 {
+		return v[:i], v[i+1:]
+	}
+	return v, ""
+}
+
+// comparePrerelease compares pre-release strings by semver rules. An empty
+// pre-release (a final release) ranks above any pre-release. Dot-separated
+// identifiers are compared in order: numeric ones numerically, others
+// lexically, with numeric identifiers ranking below alphanumeric ones and a
+// shorter list ranking below a longer one it prefixes.
+func comparePrerelease(p1, p2 string) int {
+	if p1 == p2 {
+		return 0
+	}
+	if p1 == "" {
+		return 1
+	}
+	if p2 == "" {
+		return -1
+	}
+
+	ids1 := strings.Split(p1, ".")
+	ids2 := strings.Split(p2, ".")
+
+	for i := 0; i < len(ids1) && i < len(ids2); i++ {
+		if c := comparePrereleaseIdentifier(ids1[i], ids2[i]); c != 0 {
+			return c
+		}
+	}
+
+	switch {
+	case len(ids1) > len(ids2):
+		return 1
+	case len(ids1) < len(ids2):
+		return -1
+	}
 	return 0
 }
 
+func comparePrereleaseIdentifier(id1, id2 string) int {
+	n1, err1 := strconv.Atoi(id1)
+	n2, err2 := strconv.Atoi(id2)
+
+	switch {
+	case err1 == nil && err2 == nil:
+		switch {
+		case n1 > n2:
+			return 1
+		case n1 < n2:
+			return -1
+		}
+		return 0
+	case err1 == nil:
+		return -1 // numeric < alphanumeric
+	case err2 == nil:
+		return 1
+	}
+
+	return strings.Compare(id1, id2)
+}
+
 func displayReleaseNotes(r githubRelease) {
 	fmt.Printf("\n## %s", r.TagName)
 	if r.Name != "" && r.Name != r.TagName {
 		fmt.Printf(" - %s", r.Name)
 	}
+	if r.Prerelease {
+		fmt.Print(" [PRE-RELEASE]")
+	}
 	fmt.Println()
 
 	if r.PublishedAt != "" {
@@ -295,17 +734,38 @@ func findAssetForPlatform(assets []githubAsset) *githubAsset {
 	return nil
 }
 
-func findChecksumAsset(assets []githubAsset) *githubAsset {
+// findChecksumAsset returns the aggregate checksums.sha256 asset, falling
+// back to a per-asset "<assetName>.sha256" sidecar.
+func findChecksumAsset(assets []githubAsset, assetName string) *githubAsset {
 	for _, a := range assets {
 		if a.Name == "checksums.sha256" {
 			return &a
 		}
 	}
+	for _, a := range assets {
+		if a.Name == assetName+".sha256" {
+			return &a
+		}
+	}
 	return nil
 }
 
+// confirm asks the user to confirm an action. With assumeYes it returns
+// true without reading stdin. When stdin is not a terminal it returns false
+// rather than blocking, so scripts must pass --yes explicitly.
+func confirm(message string, assumeYes bool) bool {
+	if assumeYes {
+		return true
+	}
+	if !stdinIsInteractive() {
+		fmt.Printf("%s [y/N]: stdin is not interactive; rerun with --yes to proceed\n", message)
+		return false
+	}
+	return promptConfirm(message)
+}
+
 func promptConfirm(message string) bool {
-	reader := bufio.NewReader(os.Stdin)
+	reader := bufio.NewReader(stdin)
 	fmt.Printf("%s [y/N]: ", message)
 
 	response, err := reader.ReadString('\n')
@@ -318,31 +778,26 @@ func promptConfirm(message string) bool {
 }
 
 // isAllowedDownloadURL validates that the URL is from an allowed host.
-func isAllowedDownloadURL(url string) bool {
-	for _, host := range allowedDownloadHosts {
-		if strings.HasPrefix(url, host) {
+func isAllowedDownloadURL(downloadURL string, allowedHosts []string) bool {
+	for _, host := range allowedHosts {
+		if strings.HasPrefix(downloadURL, host) {
 			return true
 		}
 	}
 	return false
 }
 
-func downloadAndInstall(asset, checksumAsset *githubAsset, skipChecksum bool) error {
+// downloadAndInstall downloads and verifies asset, then replaces the running
+// executable with it, or stages it for the next start if staged is set.
+func downloadAndInstall(asset, checksumAsset *githubAsset, skipChecksum, staged bool, allowedHosts []string) error {
 	// Validate download URL
-	if !isAllowedDownloadURL(asset.BrowserDownloadURL) {
-		return fmt.Errorf("invalid download URL: must be from github.com or objects.githubusercontent.com")
+	if !isAllowedDownloadURL(asset.BrowserDownloadURL, allowedHosts) {
+		return fmt.Errorf("invalid download URL: must be from %s", strings.Join(allowedHosts, " or "))
 	}
 
-	// Get current executable path
-	execPath, err := os.Executable()
+	execPath, err := executablePath()
 	if err != nil {
-		return fmt.Errorf("cannot determine executable path: %w", err)
-	}
-
-	// Resolve symlinks to get the real path
-	execPath, err = filepath.EvalSymlinks(execPath)
-	if err != nil {
-		return fmt.Errorf("cannot resolve executable path: %w", err)
+		return err
 	}
 
 	// Download to a temporary file
@@ -361,7 +816,7 @@ func downloadAndInstall(asset, checksumAsset *githubAsset, skipChecksum bool) er
 	}()
 
 	// Download the new binary
-	resp, err := httpClient.Get(asset.BrowserDownloadURL)
+	resp, err := getWithRetry(asset.BrowserDownloadURL)
 	if err != nil {
 		return fmt.Errorf("download failed: %w", err)
 	}
@@ -393,8 +848,8 @@ func downloadAndInstall(asset, checksumAsset *githubAsset, skipChecksum bool) er
 			return fmt.Errorf("checksum verification failed: no checksum file available")
 		}
 
-		if !isAllowedDownloadURL(checksumAsset.BrowserDownloadURL) {
-			return fmt.Errorf("invalid checksum URL: must be from github.com or objects.githubusercontent.com")
+		if !isAllowedDownloadURL(checksumAsset.BrowserDownloadURL, allowedHosts) {
+			return fmt.Errorf("invalid checksum URL: must be from %s", strings.Join(allowedHosts, " or "))
 		}
 
 		expectedChecksum, err := fetchExpectedChecksum(checksumAsset.BrowserDownloadURL, asset.Name)
@@ -415,7 +870,32 @@ func downloadAndInstall(asset, checksumAsset *githubAsset, skipChecksum bool) er
 		return fmt.Errorf("failed to set permissions: %w", err)
 	}
 
-	// Atomic replace: rename temp file to actual executable
+	if staged {
+		return stageUpdate(tmpPath, execPath, downloadedChecksum)
+	}
+
+	return replaceExecutable(tmpPath, execPath)
+}
+
+// executablePath returns the path of the running executable with symlinks
+// resolved, which is what an update replaces.
+var executablePath = func() (string, error) {
+	execPath, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("cannot determine executable path: %w", err)
+	}
+
+	// Resolve symlinks to get the real path
+	execPath, err = filepath.EvalSymlinks(execPath)
+	if err != nil {
+		return "", fmt.Errorf("cannot resolve executable path: %w", err)
+	}
+	return execPath, nil
+}
+
+// replaceExecutable renames the binary at newPath over execPath.
+func replaceExecutable(newPath, execPath string) error {
+	// Atomic replace: rename the new binary to the actual executable
 	// On Windows, we need to rename the old file first
 	if runtime.GOOS == "windows" {
 		oldPath := execPath + ".old"
@@ -423,7 +903,7 @@ func downloadAndInstall(asset, checksumAsset *githubAsset, skipChecksum bool) er
 		if err := os.Rename(execPath, oldPath); err != nil {
 			return fmt.Errorf("failed to backup old binary: %w", err)
 		}
-		if err := os.Rename(tmpPath, execPath); err != nil {
+		if err := os.Rename(newPath, execPath); err != nil {
 			// Try to restore old binary
 			if restoreErr := os.Rename(oldPath, execPath); restoreErr != nil {
 				return fmt.Errorf("failed to install new binary: %w (rollback also failed: %v)", err, restoreErr)
@@ -434,7 +914,7 @@ func downloadAndInstall(asset, checksumAsset *githubAsset, skipChecksum bool) er
 		os.Remove(oldPath)
 	} else {
 		// On Unix, rename is atomic
-		if err := os.Rename(tmpPath, execPath); err != nil {
+		if err := os.Rename(newPath, execPath); err != nil {
 			return fmt.Errorf("failed to install new binary: %w", err)
 		}
 	}
@@ -442,9 +922,120 @@ func downloadAndInstall(asset, checksumAsset *githubAsset, skipChecksum bool) er
 	return nil
 }
 
+// stagedUpdate is the marker written next to the executable by a staged
+// update. The staged binary is only installed if it still has this checksum.
+type stagedUpdate struct {
+	SHA256 string `json:"sha256"`
+}
+
+// stagedBinaryPath returns where a staged update of execPath is stored.
+func stagedBinaryPath(execPath string) string {
+	return execPath + ".new"
+}
+
+// stagedMarkerPath returns the marker announcing a staged update of execPath.
+func stagedMarkerPath(execPath string) string {
+	return execPath + ".update.json"
+}
+
+// stageUpdate moves the verified binary at tmpPath next to execPath and
+// writes the marker that FinalizeStagedUpdate looks for. The marker is
+// written last, so its presence means the staged binary is complete.
+func stageUpdate(tmpPath, execPath, checksum string) error {
+	stagedPath := stagedBinaryPath(execPath)
+	if err := os.Rename(tmpPath, stagedPath); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to stage new binary: %w", err)
+	}
+
+	data, err := json.Marshal(stagedUpdate{SHA256: checksum})
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(stagedMarkerPath(execPath), data, 0644); err != nil {
+		os.Remove(stagedPath)
+		return fmt.Errorf("failed to write update marker: %w", err)
+	}
+	return nil
+}
+
+// FinalizeStagedUpdate installs an update staged by 'update --staged', if
+// any, by swapping the staged binary over the running executable before cmd
+// runs. It reports whether an update was installed. The new binary takes
+// effect from the next run; the current process keeps running the old code.
+// Nothing is installed before 'update' itself, whose --dry-run and --check
+// must not change any file and which otherwise installs its own binary.
+func FinalizeStagedUpdate(cmd *cobra.Command) (bool, error) {
+	if cmd.Name() == "update" {
+		return false, nil
+	}
+	execPath, err := executablePath()
+	if err != nil {
+		return false, nil
+	}
+	return finalizeStagedUpdate(execPath)
+}
+
+// finalizeStagedUpdate swaps the update staged for execPath into place.
+// A staged binary whose checksum no longer matches the marker is discarded.
+func finalizeStagedUpdate(execPath string) (bool, error) {
+	markerPath := stagedMarkerPath(execPath)
+	data, err := os.ReadFile(markerPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to read update marker: %w", err)
+	}
+
+	stagedPath := stagedBinaryPath(execPath)
+	discard := func() {
+		os.Remove(stagedPath)
+		os.Remove(markerPath)
+	}
+
+	var marker stagedUpdate
+	if err := json.Unmarshal(data, &marker); err != nil || marker.SHA256 == "" {
+		discard()
+		return false, fmt.Errorf("discarded staged update: invalid marker %s", markerPath)
+	}
+
+	checksum, err := fileSHA256(stagedPath)
+	if err != nil {
+		discard()
+		return false, fmt.Errorf("discarded staged update: %w", err)
+	}
+	if checksum != marker.SHA256 {
+		discard()
+		return false, fmt.Errorf("discarded staged update: checksum mismatch: expected %s, got %s", marker.SHA256, checksum)
+	}
+
+	if err := replaceExecutable(stagedPath, execPath); err != nil {
+		// Keep the staged files so the swap is retried on the next run
+		return false, err
+	}
+	os.Remove(markerPath)
+	return true, nil
+}
+
+// fileSHA256 returns the hex SHA-256 checksum of the file at path.
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
 // fetchExpectedChecksum downloads the checksum file and extracts the checksum for the given asset.
 func fetchExpectedChecksum(checksumURL, assetName string) (string, error) {
-	resp, err := httpClient.Get(checksumURL)
+	resp, err := getWithRetry(checksumURL)
 	if err != nil {
 		return "", fmt.Errorf("failed to download checksum file: %w", err)
 	}
@@ -460,23 +1051,42 @@ func fetchExpectedChecksum(checksumURL, assetName string) (string, error) {
 		return "", fmt.Errorf("failed to read checksum file: %w", err)
 	}
 
-	// Parse checksum file (format: "checksum  filename" per line)
-	lines := strings.Split(string(body), "\n")
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
+	return parseChecksum(string(body), assetName)
+}
+
+// parseChecksum extracts the SHA-256 checksum for assetName from the content
+// of a checksum file. Both the multi-entry format ("checksum  filename" per
+// line, as written by sha256sum) and a sidecar holding a single bare
+// checksum are accepted.
+func parseChecksum(content, assetName string) (string, error) {
+	// Split by whitespace (checksum files use two spaces or tab)
+	var entries [][]string
+	for _, line := range strings.Split(content, "\n") {
+		if parts := strings.Fields(line); len(parts) > 0 {
+			entries = append(entries, parts)
 		}
-		// Split by whitespace (checksum files use two spaces or tab)
-		parts := strings.Fields(line)
-		if len(parts) >= 2 {
-			checksum := parts[0]
-			filename := parts[len(parts)-1]
-			if filename == assetName {
-				return strings.ToLower(checksum), nil
+	}
+
+	checksum := ""
+	if len(entries) == 1 && len(entries[0]) == 1 {
+		// Bare checksum in a per-asset sidecar; in a multi-entry file a bare
+		// token could belong to any asset
+		checksum = entries[0][0]
+	} else {
+		for _, parts := range entries {
+			// sha256sum marks binary-mode entries with a leading '*'
+			if len(parts) > 1 && strings.TrimPrefix(parts[len(parts)-1], "*") == assetName {
+				checksum = parts[0]
+				break
 			}
 		}
 	}
+	if checksum == "" {
+		return "", fmt.Errorf("checksum not found for %s", assetName)
+	}
 
-	return "", fmt.Errorf("checksum not found for %s", assetName)
+	if decoded, err := hex.DecodeString(checksum); err != nil || len(decoded) != sha256.Size {
+		return "", fmt.Errorf("invalid checksum %q for %s: want %d hex characters", checksum, assetName, 2*sha256.Size)
+	}
+	return strings.ToLower(checksum), nil
 }
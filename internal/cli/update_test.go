@@ -0,0 +1,737 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"repoctr/internal/version"
+)
+
+const testChecksum = "3a7bd3e2360a3d29eea436fcfb7e44c735d117c42d1c1835420b6b9942dd4f1b"
+
+func TestFindChecksumAsset(t *testing.T) {
+	binary := "repo-ctr-linux-amd64"
+
+	tests := []struct {
+		name   string
+		assets []string
+		want   string
+	}{
+		{"aggregate", []string{binary, "checksums.sha256"}, "checksums.sha256"},
+		{"sidecar", []string{binary, binary + ".sha256", "repo-ctr-darwin-arm64.sha256"}, binary + ".sha256"},
+		{"aggregate preferred", []string{binary + ".sha256", "checksums.sha256"}, "checksums.sha256"},
+		{"other sidecar only", []string{binary, "repo-ctr-darwin-arm64.sha256"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var assets []githubAsset
+			for _, name := range tt.assets {
+				assets = append(assets, githubAsset{Name: name})
+			}
+
+			got := findChecksumAsset(assets, binary)
+			if tt.want == "" {
+				if got != nil {
+					t.Errorf("findChecksumAsset() = %q, want nil", got.Name)
+				}
+				return
+			}
+			if got == nil || got.Name != tt.want {
+				t.Errorf("findChecksumAsset() = %v, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchExpectedChecksum(t *testing.T) {
+	binary := "repo-ctr-linux-amd64"
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"aggregate", fmt.Sprintf("ffff  repo-ctr-darwin-arm64\n%s  %s\n", testChecksum, binary)},
+		{"aggregate binary mode", fmt.Sprintf("%s *%s\n", testChecksum, binary)},
+		{"sidecar bare checksum", testChecksum + "\n"},
+		{"sidecar uppercase", "3A7BD3E2360A3D29EEA436FCFB7E44C735D117C42D1C1835420B6B9942DD4F1B"},
+		{"sidecar with filename", fmt.Sprintf("%s  %s\n", testChecksum, binary)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, tt.body)
+			}))
+			defer server.Close()
+
+			got, err := fetchExpectedChecksum(server.URL+"/checksum", binary)
+			if err != nil {
+				t.Fatalf("fetchExpectedChecksum: %v", err)
+			}
+			if got != testChecksum {
+				t.Errorf("checksum = %q, want %q", got, testChecksum)
+			}
+		})
+	}
+}
+
+func TestParseChecksum_NotFound(t *testing.T) {
+	if _, err := parseChecksum("ffff  other-binary\n", "repo-ctr-linux-amd64"); err == nil {
+		t.Error("expected error when asset is missing from aggregate file")
+	}
+}
+
+func TestParseChecksum_Invalid(t *testing.T) {
+	binary := "repo-ctr-linux-amd64"
+	tests := []struct {
+		name    string
+		content string
+	}{
+		// A bare token only stands for the asset in a single-entry sidecar
+		{"bare token among entries", fmt.Sprintf("%s\n%s  repo-ctr-darwin-arm64\n", testChecksum, testChecksum)},
+		{"too short", "ffff  " + binary + "\n"},
+		{"not hex", strings.Repeat("zz", 32) + "  " + binary + "\n"},
+		{"bare too long", testChecksum + "00\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := parseChecksum(tt.content, binary); err == nil {
+				t.Errorf("parseChecksum = %q, want an error", got)
+			}
+		})
+	}
+}
+
+func TestDoWithRetry(t *testing.T) {
+	origDelay := retryDelay
+	retryDelay = time.Millisecond
+	t.Cleanup(func() { retryDelay = origDelay })
+
+	tests := []struct {
+		name     string
+		statuses []int
+		want     int
+		requests int
+	}{
+		{"succeeds after two failures", []int{http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusOK}, http.StatusOK, 3},
+		{"gives up after max attempts", []int{500, 500, 500, 500}, 500, maxAttempts},
+		{"no retry on not found", []int{http.StatusNotFound, http.StatusOK}, http.StatusNotFound, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.statuses[requests])
+				requests++
+			}))
+			defer server.Close()
+
+			resp, err := getWithRetry(server.URL)
+			if err != nil {
+				t.Fatalf("getWithRetry: %v", err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.want {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.want)
+			}
+			if requests != tt.requests {
+				t.Errorf("requests = %d, want %d", requests, tt.requests)
+			}
+		})
+	}
+}
+
+func TestDoWithRetry_ConnectionReset(t *testing.T) {
+	origDelay := retryDelay
+	retryDelay = time.Millisecond
+	t.Cleanup(func() { retryDelay = origDelay })
+
+	// The first two connections are closed without a response
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests <= 2 {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				conn.Close()
+			}
+			return
+		}
+		fmt.Fprint(w, "ok")
+	}))
+	defer server.Close()
+
+	resp, err := getWithRetry(server.URL)
+	if err != nil {
+		t.Fatalf("getWithRetry: %v", err)
+	}
+	defer resp.Body.Close()
+	if body, _ := io.ReadAll(resp.Body); string(body) != "ok" || requests != 3 {
+		t.Errorf("body = %q after %d requests, want \"ok\" after 3", body, requests)
+	}
+}
+
+func TestFetchReleases_GitHubToken(t *testing.T) {
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		fmt.Fprint(w, `[]`)
+	}))
+	defer server.Close()
+
+	source := releaseSource{Provider: providerGitHub, ReleasesURL: server.URL}
+
+	tests := []struct {
+		name          string
+		githubToken   string
+		repoctrToken  string
+		authorization string
+	}{
+		{"no token", "", "", ""},
+		{"GITHUB_TOKEN", "gh-token", "", "Bearer gh-token"},
+		{"REPOCTR_GITHUB_TOKEN wins", "gh-token", "repoctr-token", "Bearer repoctr-token"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("GITHUB_TOKEN", tt.githubToken)
+			t.Setenv("REPOCTR_GITHUB_TOKEN", tt.repoctrToken)
+
+			if _, err := fetchReleases(source); err != nil {
+				t.Fatalf("fetchReleases: %v", err)
+			}
+			if authorization != tt.authorization {
+				t.Errorf("Authorization = %q, want %q", authorization, tt.authorization)
+			}
+		})
+	}
+
+	// The token is only sent to GitHub
+	t.Setenv("GITHUB_TOKEN", "gh-token")
+	if _, err := fetchReleases(releaseSource{Provider: providerGitea, ReleasesURL: server.URL}); err != nil {
+		t.Fatalf("fetchReleases: %v", err)
+	}
+	if authorization != "" {
+		t.Errorf("Authorization = %q sent to Gitea, want none", authorization)
+	}
+}
+
+func TestFetchReleases_RateLimited(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	_, err := fetchReleases(releaseSource{Provider: providerGitHub, ReleasesURL: server.URL})
+	if err == nil || !strings.Contains(err.Error(), "rate limit remaining: 0") {
+		t.Errorf("fetchReleases error = %v, want the remaining rate limit", err)
+	}
+}
+
+func TestFetchReleases_GitLab(t *testing.T) {
+	var requestedPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestedPath = r.URL.EscapedPath()
+		fmt.Fprint(w, `[
+  {
+    "tag_name": "v1.3.0",
+    "name": "1.3.0",
+    "description": "Bug fixes",
+    "released_at": "2025-03-01T10:00:00Z",
+    "upcoming_release": false,
+    "assets": {
+      "links": [
+        {"name": "repo-ctr-linux-amd64", "url": "https://gitlab.example.com/x", "direct_asset_url": "https://gitlab.example.com/group/repoctr/-/releases/v1.3.0/downloads/repo-ctr-linux-amd64"},
+        {"name": "checksums.sha256", "url": "https://gitlab.example.com/group/repoctr/-/releases/v1.3.0/downloads/checksums.sha256"}
+      ]
+    }
+  }
+]`)
+	}))
+	defer server.Close()
+
+	source := releaseSource{
+		Provider:    providerGitLab,
+		ReleasesURL: server.URL + "/api/v4/projects/group%2Frepoctr/releases",
+	}
+
+	releases, err := fetchReleases(source)
+	if err != nil {
+		t.Fatalf("fetchReleases: %v", err)
+	}
+
+	if requestedPath != "/api/v4/projects/group%2Frepoctr/releases" {
+		t.Errorf("requested path = %q", requestedPath)
+	}
+	if len(releases) != 1 {
+		t.Fatalf("got %d releases, want 1", len(releases))
+	}
+
+	r := releases[0]
+	if r.TagName != "v1.3.0" || r.Body != "Bug fixes" || r.PublishedAt != "2025-03-01T10:00:00Z" {
+		t.Errorf("release = %+v", r)
+	}
+	if len(r.Assets) != 2 {
+		t.Fatalf("got %d assets, want 2", len(r.Assets))
+	}
+	if want := "https://gitlab.example.com/group/repoctr/-/releases/v1.3.0/downloads/repo-ctr-linux-amd64"; r.Assets[0].BrowserDownloadURL != want {
+		t.Errorf("asset URL = %q, want direct asset URL %q", r.Assets[0].BrowserDownloadURL, want)
+	}
+	if want := "https://gitlab.example.com/group/repoctr/-/releases/v1.3.0/downloads/checksums.sha256"; r.Assets[1].BrowserDownloadURL != want {
+		t.Errorf("asset URL = %q, want link URL %q", r.Assets[1].BrowserDownloadURL, want)
+	}
+}
+
+func TestNewReleaseSource(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider string
+		baseURL  string
+		wantURL  string
+		wantHost string
+		wantErr  bool
+	}{
+		{"default github", "", "", "https://api.github.com/repos/acme/tool/releases", "https://github.com/", false},
+		{"gitlab", "gitlab", "https://gitlab.example.com/", "https://gitlab.example.com/api/v4/projects/acme%2Ftool/releases", "https://gitlab.example.com/", false},
+		{"gitea", "Gitea", "https://git.example.com", "https://git.example.com/api/v1/repos/acme/tool/releases", "https://git.example.com/", false},
+		{"gitlab without url", "gitlab", "", "", "", true},
+		{"gitea over http", "gitea", "http://git.example.com", "", "", true},
+		{"unknown provider", "bitbucket", "https://bitbucket.org", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source, err := newReleaseSource(tt.provider, tt.baseURL, "acme", "tool")
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got %+v", source)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("newReleaseSource: %v", err)
+			}
+			if source.ReleasesURL != tt.wantURL {
+				t.Errorf("ReleasesURL = %q, want %q", source.ReleasesURL, tt.wantURL)
+			}
+			if source.AllowedHosts[0] != tt.wantHost {
+				t.Errorf("AllowedHosts = %v, want %q first", source.AllowedHosts, tt.wantHost)
+			}
+		})
+	}
+}
+
+func TestIsAllowedDownloadURL(t *testing.T) {
+	hosts := []string{"https://gitlab.example.com/"}
+
+	if !isAllowedDownloadURL("https://gitlab.example.com/group/repoctr/-/releases/v1/downloads/bin", hosts) {
+		t.Error("expected URL on configured host to be allowed")
+	}
+	if isAllowedDownloadURL("https://gitlab.example.com.evil.io/bin", hosts) {
+		t.Error("expected look-alike host to be rejected")
+	}
+	if isAllowedDownloadURL("https://github.com/acme/tool/releases/download/v1/bin", hosts) {
+		t.Error("expected GitHub URL to be rejected for a GitLab source")
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		v1, v2 string
+		want   int
+	}{
+		{"1.0.0", "1.0.0", 0},
+		{"v1.2.0", "1.2.0", 0},
+		{"1.10.0", "1.9.0", 1},
+		{"1.0", "1.0.1", -1},
+
+		// Pre-release precedence
+		{"1.0.0-alpha", "1.0.0", -1},
+		{"v1.2.0", "v1.2.0-beta", 1},
+		{"1.0.0-alpha.1", "1.0.0-alpha.2", -1},
+		{"1.0.0-alpha.10", "1.0.0-alpha.2", 1},
+		{"1.0.0-alpha", "1.0.0-alpha.1", -1},
+		{"1.0.0-alpha.1", "1.0.0-alpha.beta", -1},
+		{"1.0.0-alpha.beta", "1.0.0-beta", -1},
+		{"1.0.0-beta.11", "1.0.0-rc.1", -1},
+		{"1.0.0-rc.1", "1.0.0-rc.1", 0},
+		{"1.0.0-rc.1", "0.9.0", 1},
+
+		// Build metadata is ignored
+		{"1.0.0+build.5", "1.0.0", 0},
+		{"1.0.0-rc.1+build.5", "1.0.0", -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.v1+" vs "+tt.v2, func(t *testing.T) {
+			if got := compareVersions(tt.v1, tt.v2); got != tt.want {
+				t.Errorf("compareVersions(%q, %q) = %d, want %d", tt.v1, tt.v2, got, tt.want)
+			}
+			if got := compareVersions(tt.v2, tt.v1); got != -tt.want {
+				t.Errorf("compareVersions(%q, %q) = %d, want %d", tt.v2, tt.v1, got, -tt.want)
+			}
+		})
+	}
+}
+
+func TestFindNewerReleases_FromPrerelease(t *testing.T) {
+	releases := []githubRelease{
+		{TagName: "v1.1.0"},
+		{TagName: "v1.2.0"},
+		{TagName: "v1.2.0-beta"},
+	}
+	sortReleasesByVersion(releases)
+
+	if releases[0].TagName != "v1.2.0" || releases[1].TagName != "v1.2.0-beta" {
+		t.Errorf("sorted = %v, want v1.2.0 before v1.2.0-beta", releases)
+	}
+
+	newer := findNewerReleases(releases, "v1.2.0-beta")
+	if len(newer) != 1 || newer[0].TagName != "v1.2.0" {
+		t.Errorf("findNewerReleases = %v, want [v1.2.0]", newer)
+	}
+}
+
+// failingReader fails the test if anything tries to read from it.
+type failingReader struct{ t *testing.T }
+
+func (r failingReader) Read(p []byte) (int, error) {
+	r.t.Error("stdin should not be read")
+	return 0, io.EOF
+}
+
+// withStdin replaces the confirmation input for the duration of a test.
+func withStdin(t *testing.T, r io.Reader, interactive bool) {
+	t.Helper()
+	origStdin, origInteractive := stdin, stdinIsInteractive
+	stdin = r
+	stdinIsInteractive = func() bool { return interactive }
+	t.Cleanup(func() {
+		stdin, stdinIsInteractive = origStdin, origInteractive
+	})
+}
+
+func TestConfirm_AssumeYesSkipsStdin(t *testing.T) {
+	withStdin(t, failingReader{t}, true)
+
+	if !confirm("Update to v2.0.0?", true) {
+		t.Error("expected confirm to proceed with --yes")
+	}
+}
+
+func TestConfirm_NonInteractiveDoesNotBlock(t *testing.T) {
+	withStdin(t, failingReader{t}, false)
+
+	if confirm("Update to v2.0.0?", false) {
+		t.Error("expected confirm to decline when stdin is not interactive")
+	}
+}
+
+func TestConfirm_Prompt(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"y\n", true},
+		{"YES\n", true},
+		{"n\n", false},
+		{"\n", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.TrimSpace(tt.input), func(t *testing.T) {
+			withStdin(t, strings.NewReader(tt.input), true)
+			if got := confirm("Update?", false); got != tt.want {
+				t.Errorf("confirm(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterReleases(t *testing.T) {
+	releases := []githubRelease{
+		{TagName: "v1.0.0"},
+		{TagName: "v1.1.0-rc.1", Prerelease: true},
+		{TagName: "v1.1.0", Draft: true},
+		{TagName: "v1.2.0-beta", Prerelease: true, Draft: true},
+	}
+
+	tags := func(rs []githubRelease) []string {
+		var out []string
+		for _, r := range rs {
+			out = append(out, r.TagName)
+		}
+		return out
+	}
+
+	stable := tags(filterReleases(releases, false))
+	if strings.Join(stable, ",") != "v1.0.0" {
+		t.Errorf("stable = %v, want [v1.0.0]", stable)
+	}
+
+	withPre := tags(filterReleases(releases, true))
+	if strings.Join(withPre, ",") != "v1.0.0,v1.1.0-rc.1" {
+		t.Errorf("with --pre = %v, want [v1.0.0 v1.1.0-rc.1]", withPre)
+	}
+}
+
+func TestFinalizeStagedUpdate(t *testing.T) {
+	dir := t.TempDir()
+	execPath := filepath.Join(dir, "repo-ctr")
+	writeTestFile(t, dir, "repo-ctr", "old binary")
+
+	// Nothing staged
+	if installed, err := finalizeStagedUpdate(execPath); installed || err != nil {
+		t.Fatalf("finalize without staged update = %v, %v; want false, nil", installed, err)
+	}
+
+	writeTestFile(t, dir, "repo-ctr-update-download", "new binary")
+	checksum, err := fileSHA256(filepath.Join(dir, "repo-ctr-update-download"))
+	if err != nil {
+		t.Fatalf("fileSHA256: %v", err)
+	}
+	if err := stageUpdate(filepath.Join(dir, "repo-ctr-update-download"), execPath, checksum); err != nil {
+		t.Fatalf("stageUpdate: %v", err)
+	}
+
+	installed, err := finalizeStagedUpdate(execPath)
+	if err != nil || !installed {
+		t.Fatalf("finalize = %v, %v; want true, nil", installed, err)
+	}
+	if content, _ := os.ReadFile(execPath); string(content) != "new binary" {
+		t.Errorf("executable = %q, want the staged binary", content)
+	}
+	for _, path := range []string{stagedBinaryPath(execPath), stagedMarkerPath(execPath)} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("%s still exists after finalizing", filepath.Base(path))
+		}
+	}
+}
+
+func TestFinalizeStagedUpdate_SkipsUpdateCommand(t *testing.T) {
+	dir := t.TempDir()
+	execPath := filepath.Join(dir, "repo-ctr")
+	writeTestFile(t, dir, "repo-ctr", "old binary")
+	writeTestFile(t, dir, "download", "new binary")
+
+	origExecutablePath := executablePath
+	executablePath = func() (string, error) { return execPath, nil }
+	t.Cleanup(func() { executablePath = origExecutablePath })
+
+	checksum, err := fileSHA256(filepath.Join(dir, "download"))
+	if err != nil {
+		t.Fatalf("fileSHA256: %v", err)
+	}
+	if err := stageUpdate(filepath.Join(dir, "download"), execPath, checksum); err != nil {
+		t.Fatalf("stageUpdate: %v", err)
+	}
+
+	// A dry run leaves the staged binary alone
+	cmd := NewUpdateCmd()
+	if err := cmd.ParseFlags([]string{"--dry-run"}); err != nil {
+		t.Fatalf("ParseFlags: %v", err)
+	}
+	if installed, err := FinalizeStagedUpdate(cmd); installed || err != nil {
+		t.Fatalf("finalize before update --dry-run = %v, %v; want false, nil", installed, err)
+	}
+	if content, _ := os.ReadFile(execPath); string(content) != "old binary" {
+		t.Errorf("executable = %q, want it untouched", content)
+	}
+	for _, path := range []string{stagedBinaryPath(execPath), stagedMarkerPath(execPath)} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("%s was touched by a dry run: %v", filepath.Base(path), err)
+		}
+	}
+
+	// Any other command installs it
+	if installed, err := FinalizeStagedUpdate(NewVersionCmd()); !installed || err != nil {
+		t.Fatalf("finalize before version = %v, %v; want true, nil", installed, err)
+	}
+	if content, _ := os.ReadFile(execPath); string(content) != "new binary" {
+		t.Errorf("executable = %q, want the staged binary", content)
+	}
+}
+
+func TestFinalizeStagedUpdate_DiscardsTamperedBinary(t *testing.T) {
+	dir := t.TempDir()
+	execPath := filepath.Join(dir, "repo-ctr")
+	writeTestFile(t, dir, "repo-ctr", "old binary")
+	writeTestFile(t, dir, "download", "new binary")
+
+	if err := stageUpdate(filepath.Join(dir, "download"), execPath, testChecksum); err != nil {
+		t.Fatalf("stageUpdate: %v", err)
+	}
+
+	installed, err := finalizeStagedUpdate(execPath)
+	if installed || err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("finalize = %v, %v; want a checksum mismatch", installed, err)
+	}
+	if content, _ := os.ReadFile(execPath); string(content) != "old binary" {
+		t.Errorf("executable = %q, want it untouched", content)
+	}
+	for _, path := range []string{stagedBinaryPath(execPath), stagedMarkerPath(execPath)} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("%s was not discarded", filepath.Base(path))
+		}
+	}
+}
+
+// startReleaseServer serves tags as Gitea releases with a binary for this
+// platform and a checksums file, and points the update command at it.
+// Executables are "installed" into the returned directory. downloaded
+// reports whether a binary was requested.
+func startReleaseServer(t *testing.T, tags ...string) (dir, binary string, downloaded *bool) {
+	t.Helper()
+
+	binary = fmt.Sprintf("repo-ctr-%s-%s", runtime.GOOS, runtime.GOARCH)
+	if runtime.GOOS == "windows" {
+		binary += ".exe"
+	}
+
+	downloaded = new(bool)
+	var server *httptest.Server
+	server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/v1/repos/"+version.GitHubOwner+"/"+version.GitHubRepo+"/releases":
+			var releases []githubRelease
+			for _, tag := range tags {
+				releases = append(releases, githubRelease{
+					TagName: tag,
+					Assets: []githubAsset{
+						{Name: binary, BrowserDownloadURL: server.URL + "/download/" + tag + "/" + binary},
+						{Name: "checksums.sha256", BrowserDownloadURL: server.URL + "/download/" + tag + "/checksums.sha256"},
+					},
+				})
+			}
+			json.NewEncoder(w).Encode(releases)
+		case path.Base(r.URL.Path) == "checksums.sha256":
+			fmt.Fprintf(w, "%s  %s\n", testChecksum, binary)
+		default:
+			*downloaded = true
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	t.Setenv("REPOCTR_RELEASE_PROVIDER", providerGitea)
+	t.Setenv("REPOCTR_RELEASE_URL", server.URL)
+	origClient := httpClient
+	httpClient = server.Client()
+	t.Cleanup(func() { httpClient = origClient })
+
+	dir = t.TempDir()
+	writeTestFile(t, dir, "repo-ctr", "old binary")
+	origExecutablePath := executablePath
+	executablePath = func() (string, error) { return filepath.Join(dir, "repo-ctr"), nil }
+	t.Cleanup(func() { executablePath = origExecutablePath })
+
+	return dir, binary, downloaded
+}
+
+func TestRunUpdate_DryRun(t *testing.T) {
+	dir, binary, downloaded := startReleaseServer(t, "v9.0.0")
+	execPath := filepath.Join(dir, "repo-ctr")
+
+	out := captureStdout(t, func() {
+		if err := runUpdate(updateOptions{DryRun: true}); err != nil {
+			t.Errorf("runUpdate: %v", err)
+		}
+	})
+
+	for _, want := range []string{
+		"Dry run: no files will be changed.",
+		"  Asset:    " + binary,
+		"  Checksum: " + testChecksum + " (from checksums.sha256)",
+		"  Target:   " + execPath,
+	} {
+		if !strings.Contains(string(out), want+"\n") {
+			t.Errorf("output does not contain %q:\n%s", want, out)
+		}
+	}
+	if *downloaded {
+		t.Error("dry run downloaded the binary")
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("dry run changed the install directory: %v", entries)
+	}
+	if content, _ := os.ReadFile(execPath); string(content) != "old binary" {
+		t.Errorf("executable = %q, want it unchanged", content)
+	}
+}
+
+func TestRunUpdate_Version(t *testing.T) {
+	_, binary, _ := startReleaseServer(t, "v1.3.0", "v1.2.3", "v1.2.0")
+
+	out := captureStdout(t, func() {
+		if err := runUpdate(updateOptions{Version: "1.2.3", DryRun: true}); err != nil {
+			t.Errorf("runUpdate: %v", err)
+		}
+	})
+	for _, want := range []string{
+		"  Release:  v1.2.3",
+		"/download/v1.2.3/" + binary,
+	} {
+		if !strings.Contains(string(out), want+"\n") {
+			t.Errorf("output does not contain %q:\n%s", want, out)
+		}
+	}
+
+	captureStdout(t, func() {
+		err := runUpdate(updateOptions{Version: "v1.1.0", DryRun: true})
+		if err == nil || !strings.Contains(err.Error(), "release v1.1.0 not found") {
+			t.Errorf("runUpdate with an unknown version = %v, want a not found error", err)
+		}
+	})
+}
+
+func TestFindReleaseByTag(t *testing.T) {
+	releases := []githubRelease{
+		{TagName: "v1.3.0-beta.1", Prerelease: true},
+		{TagName: "v1.2.3"},
+		{TagName: "1.2.0"},
+		{TagName: "v1.4.0", Draft: true},
+	}
+
+	tests := []struct {
+		tag  string
+		want string
+	}{
+		{"v1.2.3", "v1.2.3"},
+		{"1.2.3", "v1.2.3"},
+		{"v1.2.0", "1.2.0"},
+		{"v1.3.0-beta.1", "v1.3.0-beta.1"},
+		{"v1.4.0", ""},
+		{"v1.2", ""},
+	}
+
+	for _, tt := range tests {
+		got := findReleaseByTag(releases, tt.tag)
+		switch {
+		case tt.want == "" && got != nil:
+			t.Errorf("findReleaseByTag(%q) = %s, want none", tt.tag, got.TagName)
+		case tt.want != "" && (got == nil || got.TagName != tt.want):
+			t.Errorf("findReleaseByTag(%q) = %v, want %s", tt.tag, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,300 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+	"repoctr/internal/emoji"
+	"repoctr/internal/stats"
+	"repoctr/pkg/models"
+	pkgstats "repoctr/pkg/stats"
+)
+
+// clearScreen moves the cursor home and clears the terminal.
+const clearScreen = "\033[H\033[2J"
+
+// WatchOptions controls the watch command.
+type WatchOptions struct {
+	// ProjectName limits watching to a single project.
+	ProjectName string
+	// Interval is how often the source paths are polled. Changes made
+	// within one interval are coalesced into a single recount.
+	Interval time.Duration
+	// Format is the summary format: text, json, or yaml.
+	Format string
+	// Excludes are ad-hoc exclusion patterns combined with configured excludes.
+	Excludes []string
+}
+
+// defaultWatchInterval is the polling interval used by the watch command.
+const defaultWatchInterval = 2 * time.Second
+
+// NewWatchCmd creates the watch command.
+func NewWatchCmd() *cobra.Command {
+	var inputFile string
+	var opts WatchOptions
+
+	cmd := &cobra.Command{
+		Use:   "watch",
+		Short: "Recount statistics whenever counted code changes",
+		Long: `Reads projects.yaml, counts every project, and prints a compact summary.
+The projects' source paths are then polled every --interval and a new
+summary is printed whenever counted code changes, which makes it usable as
+a live dashboard during refactors.
+
+Polling does not rely on filesystem notifications, so it also works on
+network filesystems and inside containers. Changes made within one
+interval produce a single recount.
+
+Use --format json or --format yaml to emit one machine-readable summary
+per change (JSON summaries are one object per line; YAML summaries are
+separate documents).
+
+Examples:
+  repo-ctr watch
+  repo-ctr watch -p api --interval 500ms
+  repo-ctr watch --format json | jq .totals.code_lines`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunWatch(inputFile, opts, os.Stdout)
+		},
+	}
+
+	cmd.Flags().StringVarP(&inputFile, "file", "f", projectsFileName, "Projects configuration file")
+	cmd.Flags().StringVarP(&opts.ProjectName, "project", "p", "", "Watch a single project by name")
+	cmd.Flags().DurationVar(&opts.Interval, "interval", defaultWatchInterval, "Polling interval; changes within one interval trigger a single recount")
+	cmd.Flags().StringVar(&opts.Format, "format", "text", "Summary format: text, json, or yaml")
+	cmd.Flags().StringArrayVar(&opts.Excludes, "exclude", nil, "Exclude files matching a gitignore-style pattern (repeatable)")
+
+	return cmd
+}
+
+// WatchSummary is the machine-readable summary printed on each change.
+type WatchSummary struct {
+	Time     string                `yaml:"time" json:"time"`
+	Totals   WatchTotals           `yaml:"totals" json:"totals"`
+	Projects []WatchProjectSummary `yaml:"projects" json:"projects"`
+}
+
+// WatchTotals holds the grand totals of a watch summary.
+type WatchTotals struct {
+	Projects   int `yaml:"projects" json:"projects"`
+	Files      int `yaml:"files" json:"files"`
+	TotalLines int `yaml:"total_lines" json:"total_lines"`
+	CodeLines  int `yaml:"code_lines" json:"code_lines"`
+}
+
+// WatchProjectSummary holds the compact statistics of a single project.
+type WatchProjectSummary struct {
+	Name       string                `yaml:"name" json:"name"`
+	Path       string                `yaml:"path" json:"path"`
+	Runtime    string                `yaml:"runtime,omitempty" json:"runtime,omitempty"`
+	Files      int                   `yaml:"files" json:"files"`
+	TotalLines int                   `yaml:"total_lines" json:"total_lines"`
+	CodeLines  int                   `yaml:"code_lines" json:"code_lines"`
+	Children   []WatchProjectSummary `yaml:"children,omitempty" json:"children,omitempty"`
+}
+
+// RunWatch counts the projects in inputFile and writes a summary to w
+// whenever counted code changes. Runs until the process is interrupted.
+func RunWatch(inputFile string, opts WatchOptions, w io.Writer) error {
+	switch opts.Format {
+	case "text", "json", "yaml":
+	default:
+		return fmt.Errorf("unknown format: %s (expected text, json, or yaml)", opts.Format)
+	}
+	if opts.Interval <= 0 {
+		return fmt.Errorf("--interval must be positive")
+	}
+
+	config, rootDir, err := loadProjectsFile(inputFile)
+	if err != nil {
+		return err
+	}
+
+	projects := config.Projects
+	if opts.ProjectName != "" {
+		found := findProjectByName(config.Projects, opts.ProjectName)
+		if found == nil {
+			return fmt.Errorf("project '%s' not found", opts.ProjectName)
+		}
+		projects = []*models.Project{found}
+	}
+	if len(projects) == 0 {
+		return fmt.Errorf("no projects found in %s", inputFile)
+	}
+
+	counter, err := stats.NewCounterWithOptions(rootDir, stats.Options{Excludes: opts.Excludes, FollowSymlinks: followSymlinks})
+	if err != nil {
+		return fmt.Errorf("failed to create stats counter: %w", err)
+	}
+
+	if opts.Format == "text" {
+		fmt.Fprintf(os.Stderr, "Watching %d project(s) every %s. Press Ctrl+C to stop.\n", len(projects), opts.Interval)
+	}
+
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+
+	return watchProjects(counter, projects, ticker.C, opts.Format, w)
+}
+
+// watchProjects recounts projects on every tick and writes a summary in
+// format to w for the initial count and after each change.
+func watchProjects(counter *stats.Counter, projects []*models.Project, ticks <-chan time.Time, format string, w io.Writer) error {
+	var latest []*models.ProjectStats
+	fingerprint := recountFingerprint(counter, projects, &latest)
+
+	onChange := func() error {
+		return writeWatchSummary(w, buildWatchSummary(latest, time.Now()), format)
+	}
+
+	return pollForChanges(ticks, fingerprint, onChange)
+}
+
+func buildWatchSummary(projectStats []*models.ProjectStats, now time.Time) WatchSummary {
+	totals := pkgstats.Sum(projectStats)
+	return WatchSummary{
+		Time: now.Format(time.RFC3339),
+		Totals: WatchTotals{
+			Projects:   totals.Projects,
+			Files:      totals.Files,
+			TotalLines: totals.TotalLines,
+			CodeLines:  totals.CodeLines,
+		},
+		Projects: convertWatchProjects(projectStats),
+	}
+}
+
+func convertWatchProjects(projectStats []*models.ProjectStats) []WatchProjectSummary {
+	result := make([]WatchProjectSummary, 0, len(projectStats))
+	for _, s := range projectStats {
+		result = append(result, WatchProjectSummary{
+			Name:       s.Project.Name,
+			Path:       s.Project.Path,
+			Runtime:    string(s.Project.Runtime.Type),
+			Files:      s.TotalFiles,
+			TotalLines: s.TotalLines,
+			CodeLines:  s.CodeLines,
+			Children:   convertWatchProjects(s.Children),
+		})
+	}
+	return result
+}
+
+// writeWatchSummary writes one summary to w. JSON summaries are written on a
+// single line and YAML summaries as separate documents so a stream of them
+// stays parseable.
+func writeWatchSummary(w io.Writer, summary WatchSummary, format string) error {
+	switch format {
+	case "json":
+		return json.NewEncoder(w).Encode(summary)
+	case "yaml":
+		data, err := yaml.Marshal(summary)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "---\n%s", data)
+		return err
+	}
+
+	t := summary.Totals
+	fmt.Fprintf(w, "[%s] %d projects, %d files, %d lines, %d code\n",
+		summary.Time, t.Projects, t.Files, t.TotalLines, t.CodeLines)
+
+	var write func([]WatchProjectSummary, int)
+	write = func(list []WatchProjectSummary, depth int) {
+		for _, p := range list {
+			indent := strings.Repeat("  ", depth)
+			fmt.Fprintf(w, "   %s%s %s: %d files, %d lines, %d code\n",
+				indent, emoji.Map(models.RuntimeType(p.Runtime)), p.Name, p.Files, p.TotalLines, p.CodeLines)
+			write(p.Children, depth+1)
+		}
+	}
+	write(summary.Projects, 0)
+	return nil
+}
+
+// watchStats polls the projects every opts.WatchInterval and redraws the
+// statistics whenever the fingerprint of the counted code changes. It does
+// not rely on filesystem notifications, so it works on network filesystems
+// and in containers. Runs until the process is interrupted.
+func watchStats(counter *stats.Counter, rootDir string, projects []*models.Project, opts StatsOptions) error {
+	var latest []*models.ProjectStats
+	fingerprint := recountFingerprint(counter, projects, &latest)
+
+	redraw := func() error {
+		if determineFormat(opts.Machine, opts.Format) == "" {
+			fmt.Fprint(os.Stdout, clearScreen)
+		}
+		if err := renderStats(latest, rootDir, opts); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "\nWatching every %s (updated %s). Press Ctrl+C to stop.\n",
+			opts.WatchInterval, time.Now().Format("15:04:05"))
+		return nil
+	}
+
+	ticker := time.NewTicker(opts.WatchInterval)
+	defer ticker.Stop()
+
+	return pollForChanges(ticker.C, fingerprint, redraw)
+}
+
+// recountFingerprint returns a fingerprint function for pollForChanges that
+// recounts projects and stores the fresh statistics in *latest.
+func recountFingerprint(counter *stats.Counter, projects []*models.Project, latest *[]*models.ProjectStats) func() (string, error) {
+	return func() (string, error) {
+		projectStats, err := counter.CountHierarchy(context.Background(), projects, stats.HierarchyOptions{})
+		if err != nil {
+			return "", fmt.Errorf("failed to calculate statistics: %w", err)
+		}
+		fp, err := counter.Fingerprint(projectStats)
+		if err != nil {
+			return "", fmt.Errorf("failed to compute fingerprint: %w", err)
+		}
+		*latest = projectStats
+		return fp.Digest, nil
+	}
+}
+
+// pollForChanges computes a fingerprint immediately and then on every tick,
+// calling onChange for the first result and whenever the fingerprint differs
+// from the previous one. Only a failure of the initial fingerprint is fatal;
+// one on a later tick, such as a file vanishing during an editor's atomic
+// save, is reported on stderr and retried on the next tick. It returns when
+// ticks is closed or when onChange fails.
+func pollForChanges(ticks <-chan time.Time, fingerprint func() (string, error), onChange func() error) error {
+	digest, err := fingerprint()
+	if err != nil {
+		return err
+	}
+	if err := onChange(); err != nil {
+		return err
+	}
+	previous := digest
+
+	for range ticks {
+		digest, err := fingerprint()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v (retrying on the next tick)\n", err)
+			continue
+		}
+		if digest == previous {
+			continue
+		}
+		previous = digest
+		if err := onChange(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
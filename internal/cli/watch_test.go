@@ -0,0 +1,199 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"repoctr/internal/emoji"
+	"repoctr/internal/stats"
+	"repoctr/pkg/models"
+)
+
+func TestPollForChanges(t *testing.T) {
+	digests := []string{"a", "a", "b", "b", "b", "c", "a"}
+	polls := 0
+	fingerprint := func() (string, error) {
+		d := digests[polls]
+		polls++
+		return d, nil
+	}
+
+	redraws := 0
+	onChange := func() error {
+		redraws++
+		return nil
+	}
+
+	// One initial poll plus one per tick
+	ticks := make(chan time.Time, len(digests)-1)
+	for i := 0; i < len(digests)-1; i++ {
+		ticks <- time.Time{}
+	}
+	close(ticks)
+
+	if err := pollForChanges(ticks, fingerprint, onChange); err != nil {
+		t.Fatalf("pollForChanges: %v", err)
+	}
+
+	if polls != len(digests) {
+		t.Errorf("polls = %d, want %d", polls, len(digests))
+	}
+	// Initial draw, then a->b, b->c, c->a
+	if redraws != 4 {
+		t.Errorf("redraws = %d, want 4", redraws)
+	}
+}
+
+func TestPollForChanges_FingerprintError(t *testing.T) {
+	wantErr := errors.New("boom")
+	ticks := make(chan time.Time)
+	close(ticks)
+
+	err := pollForChanges(ticks, func() (string, error) { return "", wantErr }, func() error {
+		t.Error("onChange should not be called")
+		return nil
+	})
+	if !errors.Is(err, wantErr) {
+		t.Errorf("err = %v, want %v", err, wantErr)
+	}
+}
+
+func TestPollForChanges_TransientError(t *testing.T) {
+	results := []struct {
+		digest string
+		err    error
+	}{
+		{"a", nil},
+		{"", errors.New("file vanished")},
+		{"a", nil},
+		{"b", nil},
+	}
+	polls := 0
+	fingerprint := func() (string, error) {
+		r := results[polls]
+		polls++
+		return r.digest, r.err
+	}
+
+	redraws := 0
+	onChange := func() error {
+		redraws++
+		return nil
+	}
+
+	ticks := make(chan time.Time, len(results)-1)
+	for i := 0; i < len(results)-1; i++ {
+		ticks <- time.Time{}
+	}
+	close(ticks)
+
+	if err := pollForChanges(ticks, fingerprint, onChange); err != nil {
+		t.Fatalf("pollForChanges: %v, want a failed tick to be retried", err)
+	}
+	if polls != len(results) {
+		t.Errorf("polls = %d, want %d", polls, len(results))
+	}
+	// Initial draw and a->b; the failed tick keeps "a" as the previous digest
+	if redraws != 2 {
+		t.Errorf("redraws = %d, want 2", redraws)
+	}
+}
+
+// notifyWriter buffers output and signals each write.
+type notifyWriter struct {
+	buf     bytes.Buffer
+	written chan struct{}
+}
+
+func (w *notifyWriter) Write(p []byte) (int, error) {
+	n, err := w.buf.Write(p)
+	w.written <- struct{}{}
+	return n, err
+}
+
+func TestWatchProjects_RecountsOnChange(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "main.go", "package main\n")
+
+	counter, err := stats.NewCounter(root)
+	if err != nil {
+		t.Fatalf("NewCounter: %v", err)
+	}
+	projects := []*models.Project{{
+		Name:        "app",
+		Path:        ".",
+		Runtime:     models.Runtime{Type: models.RuntimeGo},
+		SourcePaths: []string{"."},
+	}}
+
+	// Edit the file only after the initial summary is written, while the
+	// watcher is idle waiting for the next tick. Ticks are unbuffered, so the
+	// final send completes only after the recount triggered by the first.
+	out := &notifyWriter{written: make(chan struct{}, 10)}
+	ticks := make(chan time.Time)
+	go func() {
+		<-out.written
+		writeTestFile(t, root, "main.go", "package main\n\nfunc main() {}\n")
+		ticks <- time.Time{}
+		ticks <- time.Time{}
+		close(ticks)
+	}()
+
+	if err := watchProjects(counter, projects, ticks, "json", out); err != nil {
+		t.Fatalf("watchProjects: %v", err)
+	}
+
+	var summaries []WatchSummary
+	decoder := json.NewDecoder(&out.buf)
+	for decoder.More() {
+		var s WatchSummary
+		if err := decoder.Decode(&s); err != nil {
+			t.Fatalf("decode summary: %v", err)
+		}
+		summaries = append(summaries, s)
+	}
+
+	// The initial count plus one recount for the edit
+	if len(summaries) != 2 {
+		t.Fatalf("got %d summaries, want 2", len(summaries))
+	}
+	if got := summaries[0].Totals.TotalLines; got != 1 {
+		t.Errorf("initial TotalLines = %d, want 1", got)
+	}
+	if got := summaries[1].Totals.TotalLines; got != 3 {
+		t.Errorf("recounted TotalLines = %d, want 3", got)
+	}
+	if len(summaries[1].Projects) != 1 || summaries[1].Projects[0].Name != "app" {
+		t.Errorf("unexpected projects in summary: %+v", summaries[1].Projects)
+	}
+}
+
+func TestWriteWatchSummary_Text(t *testing.T) {
+	summary := WatchSummary{
+		Time:   "2024-01-02T15:04:05Z",
+		Totals: WatchTotals{Projects: 2, Files: 3, TotalLines: 30, CodeLines: 20},
+		Projects: []WatchProjectSummary{{
+			Name: "app", Path: ".", Runtime: "Go", Files: 3, TotalLines: 30, CodeLines: 20,
+			Children: []WatchProjectSummary{{Name: "svc", Path: "svc", Runtime: "Go", Files: 1, TotalLines: 10, CodeLines: 8}},
+		}},
+	}
+
+	var out bytes.Buffer
+	if err := writeWatchSummary(&out, summary, "text"); err != nil {
+		t.Fatalf("writeWatchSummary: %v", err)
+	}
+
+	for _, want := range []string{
+		"[2024-01-02T15:04:05Z] 2 projects, 3 files, 30 lines, 20 code\n",
+		"app: 3 files, 30 lines, 20 code\n",
+		"     " + emoji.Map(models.RuntimeGo) + " svc: 1 files, 10 lines, 8 code\n",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"gopkg.in/yaml.v3"
+)
+
+// marshalKeepingComments marshals v to YAML like yaml.Marshal, carrying over
+// the comments of existing, a previous version of the same document, so
+// annotations users add to projects.yaml survive a re-identify. Comments
+// follow the node they are attached to: mapping entries are matched by key,
+// projects by path and runtime, and other list items by value. The
+// document's own header comment is not carried over, since callers write a
+// fresh one. Comments on nodes that no longer exist are dropped.
+func marshalKeepingComments(v any, existing []byte) ([]byte, error) {
+	var node yaml.Node
+	if err := node.Encode(v); err != nil {
+		return nil, err
+	}
+
+	var previous yaml.Node
+	if err := yaml.Unmarshal(existing, &previous); err == nil {
+		if previous.Kind == yaml.DocumentNode && len(previous.Content) == 1 {
+			copyComments(&node, previous.Content[0])
+		}
+	}
+
+	return yaml.Marshal(&node)
+}
+
+// copyComments copies the comments of src and its descendants onto the
+// matching nodes of dst.
+func copyComments(dst, src *yaml.Node) {
+	if dst.Kind != src.Kind {
+		return
+	}
+	dst.HeadComment = src.HeadComment
+	dst.LineComment = src.LineComment
+	dst.FootComment = src.FootComment
+
+	switch dst.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(dst.Content); i += 2 {
+			key := dst.Content[i]
+			if j := mappingKeyIndex(src, key.Value); j >= 0 {
+				copyComments(key, src.Content[j])
+				copyComments(dst.Content[i+1], src.Content[j+1])
+			}
+		}
+	case yaml.SequenceNode:
+		used := make([]bool, len(src.Content))
+		for _, item := range dst.Content {
+			identity := sequenceItemIdentity(item)
+			for j, candidate := range src.Content {
+				if !used[j] && candidate.Kind == item.Kind && sequenceItemIdentity(candidate) == identity {
+					used[j] = true
+					copyComments(item, candidate)
+					break
+				}
+			}
+		}
+	}
+}
+
+// sequenceItemIdentity returns what identifies a list item across saves: a
+// project's path and runtime, or a scalar's value.
+func sequenceItemIdentity(item *yaml.Node) string {
+	switch item.Kind {
+	case yaml.ScalarNode:
+		return item.Value
+	case yaml.MappingNode:
+		identity := mappingValue(item, "path")
+		if runtime := mappingChild(item, "runtime"); runtime != nil {
+			identity += "\x00" + mappingValue(runtime, "type")
+		}
+		return identity
+	}
+	return ""
+}
+
+// mappingKeyIndex returns the index in mapping.Content of the key node
+// named key, or -1.
+func mappingKeyIndex(mapping *yaml.Node, key string) int {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// mappingChild returns the value node of key in mapping, or nil.
+func mappingChild(mapping *yaml.Node, key string) *yaml.Node {
+	if i := mappingKeyIndex(mapping, key); i >= 0 {
+		return mapping.Content[i+1]
+	}
+	return nil
+}
+
+// mappingValue returns the scalar value of key in mapping, or "".
+func mappingValue(mapping *yaml.Node, key string) string {
+	if child := mappingChild(mapping, key); child != nil && child.Kind == yaml.ScalarNode {
+		return child.Value
+	}
+	return ""
+}
@@ -1,69 +1,125 @@
 package config
 
 import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
 	"repoctr/pkg/models"
 )
 
+// NameOverridePrefix marks a project-overrides key that selects a project by
+// name instead of by path, e.g. "name:my-service".
+const NameOverridePrefix = "name:"
+
 // MergeProjects combines discovered projects with existing projects and applies
 // overrides from the configuration. It performs a non-destructive merge that
 // preserves user customizations while updating auto-detected fields.
+// It also returns warnings about ambiguous overrides.
 func MergeProjects(
 	discovered []*models.Project,
 	existing []*models.Project,
 	cfg *models.RepoCtrConfig,
-) []*models.Project {
+) ([]*models.Project, []string) {
 	// Build a map of existing projects by path for fast lookup
 	existingMap := buildProjectMap(existing)
 
+	// Pair discovered projects with existing ones: first by path and
+	// runtime, then by path alone so a runtime change (e.g. JavaScript to
+	// TypeScript) keeps user customizations
+	matches := make([]*models.Project, len(discovered))
+	for i, discoveredProj := range discovered {
+		matches[i] = takeExistingProject(existingMap, discoveredProj.Path, func(p *models.Project) bool {
+			return p.Runtime.Type == discoveredProj.Runtime.Type
+		})
+	}
+	for i, discoveredProj := range discovered {
+		if matches[i] == nil {
+			matches[i] = takeExistingProject(existingMap, discoveredProj.Path, func(*models.Project) bool {
+				return true
+			})
+		}
+	}
+
 	var result []*models.Project
+	var warnings []string
 
 	// Process discovered projects
-	for _, discoveredProj := range discovered {
+	for i, discoveredProj := range discovered {
 		// Check if this project already exists
-		if existingProj, found := existingMap[discoveredProj.Path]; found {
+		if existingProj := matches[i]; existingProj != nil {
 			// Merge discovered into existing
 			merged := mergeProject(existingProj, discoveredProj)
-			applyConfigOverrides(merged, cfg)
+			warnings = append(warnings, applyConfigOverrides(merged, cfg)...)
 			result = append(result, merged)
-			delete(existingMap, discoveredProj.Path)
 		} else {
 			// New project - just apply config overrides
-			applyConfigOverrides(discoveredProj, cfg)
+			warnings = append(warnings, applyConfigOverrides(discoveredProj, cfg)...)
 			result = append(result, discoveredProj)
 		}
 	}
 
 	// Add any existing projects that weren't re-discovered
 	// (but still apply config overrides)
-	for _, existingProj := range existingMap {
-		applyConfigOverrides(existingProj, cfg)
-		result = append(result, existingProj)
+	for _, existingProjs := range existingMap {
+		for _, existingProj := range existingProjs {
+			warnings = append(warnings, applyConfigOverrides(existingProj, cfg)...)
+			result = append(result, existingProj)
+		}
 	}
 
-	return result
+	return result, warnings
 }
 
 // buildProjectMap creates a map of projects by their path for quick lookup.
-func buildProjectMap(projects []*models.Project) map[string]*models.Project {
-	m := make(map[string]*models.Project)
+// A directory can hold several projects of different runtimes (e.g. PHP and
+// JavaScript side by side), so each path maps to a list.
+func buildProjectMap(projects []*models.Project) map[string][]*models.Project {
+	m := make(map[string][]*models.Project)
 	for _, p := range projects {
-		m[p.Path] = p
+		m[p.Path] = append(m[p.Path], p)
 	}
 	return m
 }
 
+// takeExistingProject removes and returns the first project at path that
+// satisfies match, or nil if there is none.
+func takeExistingProject(existingMap map[string][]*models.Project, path string, match func(*models.Project) bool) *models.Project {
+	candidates := existingMap[path]
+	for i, p := range candidates {
+		if !match(p) {
+			continue
+		}
+		remaining := append(candidates[:i:i], candidates[i+1:]...)
+		if len(remaining) == 0 {
+			delete(existingMap, path)
+		} else {
+			existingMap[path] = remaining
+		}
+		return p
+	}
+	return nil
+}
+
 // mergeProject merges discovered project info into an existing project,
 // preserving user-customized fields while updating auto-detected ones.
 func mergeProject(existing, discovered *models.Project) *models.Project {
 	result := &models.Project{
 		// Keep existing values where user might have customized
-		Name:           discovered.Name, // Use discovered name
-		Path:           existing.Path,   // Path is the primary key
-		Runtime:        discovered.Runtime,
-		ManifestFile:   discovered.ManifestFile,
-		SourcePaths:    discovered.SourcePaths,
+		Name:            discovered.Name, // Use discovered name
+		Path:            existing.Path,   // Path is the primary key
+		Runtime:         discovered.Runtime,
+		ManifestFile:    discovered.ManifestFile,
+		SourcePaths:     discovered.SourcePaths,
+		DependencyCount: discovered.DependencyCount,
+		PackageManager:  discovered.PackageManager,
+		Version:         discovered.Version,
+		Framework:       discovered.Framework,
 		ExcludePatterns: existing.ExcludePatterns, // Preserve user excludes
-		Children:       discovered.Children,       // Use discovered hierarchy
+		ExtraExtensions: existing.ExtraExtensions, // Preserve user extensions
+		Children:        discovered.Children,      // Use discovered hierarchy
 	}
 
 	// For src-ignore-paths, if user has set them, keep them; otherwise use discovered
@@ -77,14 +133,31 @@ func mergeProject(existing, discovered *models.Project) *models.Project {
 }
 
 // applyConfigOverrides applies configuration overrides from .repoctrconfig.yaml
-// to a project.
-func applyConfigOverrides(project *models.Project, cfg *models.RepoCtrConfig) {
+// to a project. Overrides are keyed by project path or by "name:" plus the
+// project name; when both match, the path override wins and a warning is
+// returned. Path keys may be globs such as "packages/*", which are only used
+// when no exact key matches.
+func applyConfigOverrides(project *models.Project, cfg *models.RepoCtrConfig) []string {
 	if cfg == nil || cfg.ProjectOverrides == nil {
-		return
+		return nil
+	}
+
+	var warnings []string
+	override, found := cfg.ProjectOverrides[project.Path]
+	nameKey := NameOverridePrefix + project.Name
+	if byName, nameFound := cfg.ProjectOverrides[nameKey]; nameFound {
+		if found {
+			warnings = append(warnings, fmt.Sprintf("project %s (%s) matches overrides %q and %q; using the path override",
+				project.Name, project.Path, project.Path, nameKey))
+		} else {
+			override, found = byName, true
+		}
+	}
+	if !found {
+		override, found = globOverride(project.Path, cfg.ProjectOverrides)
 	}
 
-	// Check for project-specific overrides by path
-	if override, found := cfg.ProjectOverrides[project.Path]; found {
+	if found {
 		// Apply exclude patterns override if provided
 		if len(override.ExcludePatterns) > 0 {
 			project.ExcludePatterns = override.ExcludePatterns
@@ -99,5 +172,52 @@ func applyConfigOverrides(project *models.Project, cfg *models.RepoCtrConfig) {
 		if len(override.SourcePaths) > 0 {
 			project.SourcePaths = override.SourcePaths
 		}
+
+		// Apply extra-extensions override if provided
+		if len(override.ExtraExtensions) > 0 {
+			project.ExtraExtensions = override.ExtraExtensions
+		}
+	}
+
+	return warnings
+}
+
+// globOverride merges the overrides whose keys are glob patterns matching
+// projectPath. Matching keys are merged in sorted order, appending their
+// patterns and dropping duplicates, so the result does not depend on map
+// iteration order.
+func globOverride(projectPath string, overrides map[string]models.ProjectOverride) (models.ProjectOverride, bool) {
+	var keys []string
+	for key := range overrides {
+		if strings.HasPrefix(key, NameOverridePrefix) || !strings.ContainsAny(key, "*?[") {
+			continue
+		}
+		if matched, err := filepath.Match(filepath.FromSlash(key), projectPath); err == nil && matched {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return models.ProjectOverride{}, false
+	}
+	sort.Strings(keys)
+
+	var merged models.ProjectOverride
+	for _, key := range keys {
+		o := overrides[key]
+		merged.ExcludePatterns = appendUnique(merged.ExcludePatterns, o.ExcludePatterns)
+		merged.SrcIgnorePaths = appendUnique(merged.SrcIgnorePaths, o.SrcIgnorePaths)
+		merged.SourcePaths = appendUnique(merged.SourcePaths, o.SourcePaths)
+		merged.ExtraExtensions = appendUnique(merged.ExtraExtensions, o.ExtraExtensions)
+	}
+	return merged, true
+}
+
+// appendUnique appends the values not already in list.
+func appendUnique(list, values []string) []string {
+	for _, v := range values {
+		if !slices.Contains(list, v) {
+			list = append(list, v)
+		}
 	}
+	return list
 }
@@ -0,0 +1,137 @@
+package config
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"repoctr/pkg/models"
+)
+
+func TestMergeProjects_SamePathDifferentRuntimes(t *testing.T) {
+	existing := []*models.Project{
+		{
+			Name:            "shop-assets",
+			Path:            ".",
+			Runtime:         models.Runtime{Type: models.RuntimeJavaScript},
+			ExcludePatterns: []string{"public/build/**"},
+		},
+	}
+	discovered := []*models.Project{
+		{Name: "shop", Path: ".", Runtime: models.Runtime{Type: models.RuntimePHP}},
+		{Name: "shop-assets", Path: ".", Runtime: models.Runtime{Type: models.RuntimeJavaScript}},
+	}
+
+	result, _ := MergeProjects(discovered, existing, nil)
+	if len(result) != 2 {
+		t.Fatalf("expected 2 projects, got %d", len(result))
+	}
+
+	php, js := result[0], result[1]
+	if php.Runtime.Type != models.RuntimePHP || len(php.ExcludePatterns) != 0 {
+		t.Errorf("PHP project = %+v, want no inherited excludes", php)
+	}
+	if js.Runtime.Type != models.RuntimeJavaScript || len(js.ExcludePatterns) != 1 {
+		t.Errorf("JavaScript project = %+v, want preserved excludes", js)
+	}
+}
+
+func TestMergeProjects_RuntimeChangeKeepsCustomizations(t *testing.T) {
+	existing := []*models.Project{
+		{
+			Name:            "web",
+			Path:            "web",
+			Runtime:         models.Runtime{Type: models.RuntimeJavaScript},
+			ExcludePatterns: []string{"fixtures/**"},
+		},
+	}
+	discovered := []*models.Project{
+		{Name: "web", Path: "web", Runtime: models.Runtime{Type: models.RuntimeTypeScript}},
+	}
+
+	result, _ := MergeProjects(discovered, existing, nil)
+	if len(result) != 1 {
+		t.Fatalf("expected 1 project, got %d", len(result))
+	}
+	if result[0].Runtime.Type != models.RuntimeTypeScript {
+		t.Errorf("runtime = %q, want %q", result[0].Runtime.Type, models.RuntimeTypeScript)
+	}
+	if len(result[0].ExcludePatterns) != 1 {
+		t.Errorf("exclude patterns = %v, want preserved", result[0].ExcludePatterns)
+	}
+}
+
+func TestMergeProjects_OverridesByNameAndPath(t *testing.T) {
+	discovered := []*models.Project{
+		{Name: "billing", Path: "services/billing", SourcePaths: []string{"."}},
+		{Name: "web", Path: "apps/web", SourcePaths: []string{"."}},
+		{Name: "docs", Path: "docs", SourcePaths: []string{"."}},
+	}
+	cfg := &models.RepoCtrConfig{
+		ProjectOverrides: map[string]models.ProjectOverride{
+			// Path only
+			"services/billing": {SourcePaths: []string{"cmd", "internal"}, ExtraExtensions: []string{".tmpl"}},
+			// Name only
+			"name:web": {SourcePaths: []string{"src"}},
+			// Both: the path override wins
+			"docs":      {SourcePaths: []string{"content"}},
+			"name:docs": {SourcePaths: []string{"site"}},
+		},
+	}
+
+	result, warnings := MergeProjects(discovered, nil, cfg)
+
+	want := map[string][]string{
+		"billing": {"cmd", "internal"},
+		"web":     {"src"},
+		"docs":    {"content"},
+	}
+	for _, p := range result {
+		if !slices.Equal(p.SourcePaths, want[p.Name]) {
+			t.Errorf("%s source paths = %v, want %v", p.Name, p.SourcePaths, want[p.Name])
+		}
+		if p.Name == "billing" && !slices.Equal(p.ExtraExtensions, []string{".tmpl"}) {
+			t.Errorf("billing extra extensions = %v, want [.tmpl]", p.ExtraExtensions)
+		}
+	}
+
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "name:docs") {
+		t.Errorf("warnings = %q, want one about the docs project", warnings)
+	}
+}
+
+func TestMergeProjects_GlobOverrides(t *testing.T) {
+	discovered := []*models.Project{
+		{Name: "ui", Path: filepath.Join("packages", "ui"), SourcePaths: []string{"."}},
+		{Name: "utils", Path: filepath.Join("packages", "utils"), SourcePaths: []string{"."}},
+		{Name: "core", Path: filepath.Join("packages", "core"), SourcePaths: []string{"."}},
+		{Name: "web", Path: filepath.Join("apps", "web"), SourcePaths: []string{"."}},
+	}
+	cfg := &models.RepoCtrConfig{
+		ProjectOverrides: map[string]models.ProjectOverride{
+			"packages/*":  {ExcludePatterns: []string{"**/*.stories.tsx", "**/*.snap"}},
+			"packages/u*": {ExcludePatterns: []string{"**/*.snap", "**/fixtures/**"}},
+			// An exact key takes precedence over the globs
+			"packages/core": {ExcludePatterns: []string{"generated/**"}},
+		},
+	}
+
+	result, warnings := MergeProjects(discovered, nil, cfg)
+	if len(warnings) != 0 {
+		t.Errorf("unexpected warnings: %q", warnings)
+	}
+
+	// Globs merge in sorted key order without duplicates
+	want := map[string][]string{
+		"ui":    {"**/*.stories.tsx", "**/*.snap", "**/fixtures/**"},
+		"utils": {"**/*.stories.tsx", "**/*.snap", "**/fixtures/**"},
+		"core":  {"generated/**"},
+		"web":   nil,
+	}
+	for _, p := range result {
+		if !slices.Equal(p.ExcludePatterns, want[p.Name]) {
+			t.Errorf("%s exclude patterns = %v, want %v", p.Name, p.ExcludePatterns, want[p.Name])
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package detector
+
+import (
+	"path/filepath"
+	"regexp"
+
+	"repoctr/pkg/models"
+)
+
+type clojureDetector struct{}
+
+func NewClojureDetector() Detector {
+	return &clojureDetector{}
+}
+
+func (d *clojureDetector) Name() string {
+	return "Clojure"
+}
+
+func (d *clojureDetector) RuntimeType() models.RuntimeType {
+	return models.RuntimeClojure
+}
+
+func (d *clojureDetector) ManifestFiles() []string {
+	return []string{"project.clj", "deps.edn"}
+}
+
+var (
+	// defprojectRe matches the form that opens a Leiningen project.clj,
+	// e.g. (defproject my-app "0.1.0-SNAPSHOT".
+	defprojectRe = regexp.MustCompile(`\(\s*defproject\s+([^\s"()]+)(?:\s+"([^"]*)")?`)
+
+	// leinClojureRe and depsClojureRe match the Clojure dependency of
+	// project.clj ([org.clojure/clojure "1.11.1"]) and deps.edn
+	// (org.clojure/clojure {:mvn/version "1.11.1"}).
+	leinClojureRe = regexp.MustCompile(`\[\s*org\.clojure/clojure\s+"([^"]+)"`)
+	depsClojureRe = regexp.MustCompile(`org\.clojure/clojure\s*\{[^}]*:mvn/version\s+"([^"]+)"`)
+)
+
+func (d *clojureDetector) Detect(manifestPath string, content []byte) (*models.Project, error) {
+	contentStr := string(content)
+
+	switch filepath.Base(manifestPath) {
+	case "project.clj":
+		matches := defprojectRe.FindStringSubmatch(contentStr)
+		if matches == nil {
+			return nil, nil
+		}
+		project := d.createProject(manifestPath, matches[1], clojureVersion(leinClojureRe, contentStr))
+		project.Version = matches[2]
+		project.PackageManager = "leiningen"
+		return project, nil
+	case "deps.edn":
+		// deps.edn has no project name, so the directory names it
+		project := d.createProject(manifestPath, "", clojureVersion(depsClojureRe, contentStr))
+		project.PackageManager = "clojure-cli"
+		return project, nil
+	}
+	return nil, nil
+}
+
+// clojureVersion returns the Clojure version the manifest depends on, or ""
+// when it relies on the tool's default.
+func clojureVersion(re *regexp.Regexp, content string) string {
+	if matches := re.FindStringSubmatch(content); len(matches) > 1 {
+		return matches[1]
+	}
+	return ""
+}
+
+func (d *clojureDetector) createProject(manifestPath, name, version string) *models.Project {
+	dir := filepath.Dir(manifestPath)
+	if name == "" {
+		name = filepath.Base(dir)
+	}
+
+	return &models.Project{
+		Name:           name,
+		Path:           dir,
+		Runtime:        models.Runtime{Type: models.RuntimeClojure, Version: version},
+		ManifestFile:   filepath.Base(manifestPath),
+		SourcePaths:    []string{"src", "."},
+		SrcIgnorePaths: []string{".cpcache", ".shadow-cljs"},
+	}
+}
@@ -1,9 +1,11 @@
 package detector
 
 import (
+	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
+	"sync"
 
 	"repoctr/pkg/models"
 )
@@ -23,7 +25,7 @@ func (d *cppDetector) RuntimeType() models.RuntimeType {
 }
 
 func (d *cppDetector) ManifestFiles() []string {
-	return []string{"CMakeLists.txt", "Makefile", "meson.build", "*.vcxproj"}
+	return []string{"CMakeLists.txt", "meson.build", "*.vcxproj", "Makefile"}
 }
 
 func (d *cppDetector) Detect(manifestPath string, content []byte) (*models.Project, error) {
@@ -78,55 +80,95 @@ func (d *cppDetector) detectCMake(manifestPath string, content []byte) (*models.
 	return d.createProject(manifestPath, name, version), nil
 }
 
-func (d *cppDetector) detectMakefile(manifestPath string, content []byte) (*models.Project, error) {
-	contentStr := string(content)
-
-	// Check for C/C++ specific indicators in Makefile
-	// Must have C/C++ compiler variable references ($(CC), $(CXX)) or direct compiler calls
-	isCppMakefile := false
-
-	// Check for C/C++ compiler variable references - strong indicator
-	if strings.Contains(contentStr, "$(CC)") || strings.Contains(contentStr, "$(CXX)") {
-		isCppMakefile = true
+// makefileSiblingManifests returns the file name patterns of manifests that
+// describe a directory better than a Makefile next to them, which then only
+// drives that build: every other manifest a built-in detector handles.
+var makefileSiblingManifests = sync.OnceValue(func() []string {
+	var patterns []string
+	for _, d := range builtinDetectors() {
+		for _, pattern := range d.ManifestFiles() {
+			// Patterns with a directory, such as requirements/*.txt,
+			// describe the parent of their directory
+			if pattern != "Makefile" && !strings.Contains(pattern, "/") {
+				patterns = append(patterns, pattern)
+			}
+		}
 	}
+	return patterns
+})
+
+var (
+	// makeCompilerRe matches references to a C/C++ compiler: the implicit
+	// $(CC)/$(CXX) variables, flag variables, or direct compiler calls.
+	makeCompilerRe = regexp.MustCompile(`\$[({](CC|CXX)[)}]|\b(CFLAGS|CXXFLAGS|CPPFLAGS)\s*[:+?]?=|\b(gcc|g\+\+|clang|clang\+\+)\s+.*-[co]\b`)
+	// makeCSourceRe matches C/C++ sources or object files, e.g. main.c,
+	// src/*.cpp, or a %.o pattern rule.
+	makeCSourceRe = regexp.MustCompile(`[\w*%)]\.(c|cc|cpp|cxx|o)\b`)
+	// makeStdRe matches a C or C++ language standard flag.
+	makeStdRe = regexp.MustCompile(`-std=(?:c|gnu)(\+\+)?(\d+)`)
+)
 
-	// Check for direct compiler calls with compile flags
-	compilerRe := regexp.MustCompile(`\b(gcc|g\+\+|clang|clang\+\+)\s+.*-[co]\b`)
-	if compilerRe.MatchString(contentStr) {
-		isCppMakefile = true
-	}
+func (d *cppDetector) detectMakefile(manifestPath string, content []byte) (*models.Project, error) {
+	dir := filepath.Dir(manifestPath)
 
-	// Check for C/C++ standard flags - definitive indicator
-	if strings.Contains(contentStr, "-std=c") {
-		isCppMakefile = true
+	// A Makefile next to another manifest is a task runner for that project
+	if hasSiblingManifest(dir) {
+		return nil, nil
 	}
 
-	// Check for common C/C++ build variables with assignments (more specific)
-	cBuildVarsRe := regexp.MustCompile(`\b(CFLAGS|CXXFLAGS|CPPFLAGS)\s*[:+]?=`)
-	if cBuildVarsRe.MatchString(contentStr) {
-		isCppMakefile = true
-	}
+	contentStr := string(content)
 
-	if !isCppMakefile {
-		return nil, nil
+	// A standard flag is definitive; compiler references only count when
+	// the Makefile also builds C/C++ sources or objects, since Makefiles of
+	// other stacks may set CFLAGS for native extensions
+	std := makeStdRe.FindStringSubmatch(contentStr)
+	if std == nil && !(makeCompilerRe.MatchString(contentStr) && makeCSourceRe.MatchString(contentStr)) {
+		return d.createMakefileProject(manifestPath), nil
 	}
 
-	// Try to extract C++ standard from flags
 	version := ""
-	stdRe := regexp.MustCompile(`-std=c\+\+(\d+)`)
-	if matches := stdRe.FindStringSubmatch(contentStr); len(matches) > 1 {
-		version = "C++" + matches[1]
+	if std != nil {
+		if std[1] != "" {
+			version = "C++" + std[2]
+		} else {
+			version = "C" + std[2]
+		}
 	}
 
-	// Also check for C standard
-	if version == "" {
-		cStdRe := regexp.MustCompile(`-std=c(\d+)`)
-		if matches := cStdRe.FindStringSubmatch(contentStr); len(matches) > 1 {
-			version = "C" + matches[1]
+	return d.createProject(manifestPath, "", version), nil
+}
+
+// hasSiblingManifest reports whether dir contains one of
+// makefileSiblingManifests.
+func hasSiblingManifest(dir string) bool {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		for _, pattern := range makefileSiblingManifests() {
+			if matched, _ := filepath.Match(pattern, entry.Name()); matched {
+				return true
+			}
 		}
 	}
+	return false
+}
 
-	return d.createProject(manifestPath, "", version), nil
+// createMakefileProject creates a low-confidence project for a Makefile
+// whose language cannot be told. It has no runtime, so no files are counted
+// for it until the runtime is set in projects.yaml.
+func (d *cppDetector) createMakefileProject(manifestPath string) *models.Project {
+	dir := filepath.Dir(manifestPath)
+	return &models.Project{
+		Name:         filepath.Base(dir),
+		Path:         dir,
+		ManifestFile: filepath.Base(manifestPath),
+		SourcePaths:  []string{"."},
+	}
 }
 
 func (d *cppDetector) detectMeson(manifestPath string, content []byte) (*models.Project, error) {
@@ -144,7 +186,64 @@ func (d *cppDetector) detectMeson(manifestPath string, content []byte) (*models.
 		name = matches[1]
 	}
 
-	return d.createProject(manifestPath, name, ""), nil
+	// If languages are declared, at least one must be C or C++
+	if languages := mesonLanguages(contentStr); len(languages) > 0 {
+		isCpp := false
+		for _, lang := range languages {
+			if lang == "c" || lang == "cpp" {
+				isCpp = true
+				break
+			}
+		}
+		if !isCpp {
+			return nil, nil
+		}
+	}
+
+	// Extract the standard from default_options, preferring C++ over C
+	version := ""
+	cppStdRe := regexp.MustCompile(`cpp_std\s*=\s*(?:c|gnu)\+\+(\d+)`)
+	if matches := cppStdRe.FindStringSubmatch(contentStr); len(matches) > 1 {
+		version = "C++" + matches[1]
+	}
+
+	if version == "" {
+		cStdRe := regexp.MustCompile(`c_std\s*=\s*(?:c|gnu)(\d+)`)
+		if matches := cStdRe.FindStringSubmatch(contentStr); len(matches) > 1 {
+			version = "C" + matches[1]
+		}
+	}
+
+	return d.createProject(manifestPath, name, version), nil
+}
+
+// mesonLanguages returns the languages declared as positional arguments of
+// a meson project() call, e.g. project('foo', 'c', 'cpp') or
+// project('foo', ['c', 'cpp'], version: '1.0') -> [c cpp].
+func mesonLanguages(content string) []string {
+	start := strings.Index(content, "project(")
+	if start < 0 {
+		return nil
+	}
+	args := content[start+len("project("):]
+
+	// Positional arguments end at the first keyword argument or the closing paren
+	if end := regexp.MustCompile(`\)|\b\w+\s*:`).FindStringIndex(args); end != nil {
+		args = args[:end[0]]
+	}
+
+	stringRe := regexp.MustCompile(`'([^']*)'`)
+	matches := stringRe.FindAllStringSubmatch(args, -1)
+	if len(matches) < 2 {
+		return nil
+	}
+
+	// The first positional string is the project name
+	var languages []string
+	for _, m := range matches[1:] {
+		languages = append(languages, strings.ToLower(m[1]))
+	}
+	return languages
 }
 
 func (d *cppDetector) detectVcxproj(manifestPath string, content []byte) (*models.Project, error) {
@@ -43,7 +43,9 @@ func (d *dartDetector) Detect(manifestPath string, content []byte) (*models.Proj
 		sdkVersion = cleanDartVersion(pubspec.Environment.SDK)
 	}
 
-	return d.createProject(manifestPath, pubspec.Name, sdkVersion), nil
+	project := d.createProject(manifestPath, pubspec.Name, sdkVersion)
+	project.DependencyCount = len(pubspec.Dependencies) + len(pubspec.DevDependencies)
+	return project, nil
 }
 
 // pubspecYaml represents the structure of a pubspec.yaml file.
@@ -53,6 +55,8 @@ type pubspecYaml struct {
 		SDK     string `yaml:"sdk"`
 		Flutter string `yaml:"flutter"`
 	} `yaml:"environment"`
+	Dependencies    map[string]yaml.Node `yaml:"dependencies"`
+	DevDependencies map[string]yaml.Node `yaml:"dev_dependencies"`
 }
 
 func (d *dartDetector) createProject(manifestPath, name, version string) *models.Project {
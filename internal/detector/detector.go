@@ -1,6 +1,13 @@
 package detector
 
 import (
+	"fmt"
+	"math"
+	"path"
+	"path/filepath"
+	"regexp"
+
+	"repoctr/internal/textenc"
 	"repoctr/pkg/models"
 )
 
@@ -12,7 +19,10 @@ type Detector interface {
 	// RuntimeType returns the runtime type this detector handles.
 	RuntimeType() models.RuntimeType
 
-	// ManifestFiles returns the list of manifest file patterns to look for.
+	// ManifestFiles returns the list of manifest file patterns to look for,
+	// most informative first. When several manifests describe the same
+	// directory, the one matching the earliest pattern is used (see
+	// Registry.ManifestPriority).
 	ManifestFiles() []string
 
 	// Detect checks if a manifest file represents a project and extracts info.
@@ -20,6 +30,28 @@ type Detector interface {
 	Detect(manifestPath string, content []byte) (*models.Project, error)
 }
 
+// WarningDetector is implemented by detectors that can report non-fatal
+// problems, such as a manifest that only partially parsed, alongside the
+// detected project.
+type WarningDetector interface {
+	Detector
+
+	// DetectWithWarnings behaves like Detect and also returns warnings
+	// describing anything that degraded the result.
+	DetectWithWarnings(manifestPath string, content []byte) (*models.Project, []string, error)
+}
+
+// Warning is a non-fatal problem reported while detecting a project.
+type Warning struct {
+	ManifestPath string
+	Detector     string
+	Message      string
+}
+
+func (w Warning) String() string {
+	return fmt.Sprintf("%s: %s (%s)", w.ManifestPath, w.Message, w.Detector)
+}
+
 // Registry holds all registered detectors.
 type Registry struct {
 	detectors []Detector
@@ -27,17 +59,28 @@ type Registry struct {
 
 // NewRegistry creates a new detector registry with all built-in detectors.
 func NewRegistry() *Registry {
-	return &Registry{
-		detectors: []Detector{
-			NewDotNetDetector(),
-			NewPythonDetector(),
-			NewGoDetector(),
-			NewJavaDetector(),
-			NewJavaScriptDetector(),
-			NewDartDetector(),
-			NewCppDetector(),
-			NewRustDetector(),
-		},
+	return &Registry{detectors: builtinDetectors()}
+}
+
+// builtinDetectors returns every built-in detector in the order they are
+// tried.
+func builtinDetectors() []Detector {
+	return []Detector{
+		NewDotNetDetector(),
+		NewPythonDetector(),
+		NewGoDetector(),
+		NewJavaDetector(),
+		NewJavaScriptDetector(),
+		NewDartDetector(),
+		NewCppDetector(),
+		NewRustDetector(),
+		NewHaskellDetector(),
+		NewPHPDetector(),
+		NewNimDetector(),
+		NewRDetector(),
+		NewSwiftDetector(),
+		NewClojureDetector(),
+		NewOCamlDetector(),
 	}
 }
 
@@ -55,16 +98,78 @@ func (r *Registry) GetManifestPatterns() []string {
 	return patterns
 }
 
+// ManifestPriority ranks a manifest among those of its detector: the index
+// of the first ManifestFiles pattern it matches, so lower is more
+// informative. manifest is relative to the project directory, e.g.
+// "pyproject.toml" or "requirements/base.txt". Manifests that match no
+// pattern rank after all others.
+func (r *Registry) ManifestPriority(manifest string) int {
+	manifest = filepath.ToSlash(manifest)
+	for _, d := range r.detectors {
+		for i, pattern := range d.ManifestFiles() {
+			if matched, _ := path.Match(pattern, manifest); matched {
+				return i
+			}
+		}
+	}
+	return math.MaxInt
+}
+
 // DetectProject tries all detectors for a given manifest file.
+// Byte order marks are handled before the content reaches any detector.
 func (r *Registry) DetectProject(manifestPath string, content []byte) (*models.Project, error) {
+	project, _, err := r.DetectProjectWithWarnings(manifestPath, content)
+	return project, err
+}
+
+// DetectProjectWithWarnings is like DetectProject but also returns the
+// warnings reported by the detectors that were consulted.
+func (r *Registry) DetectProjectWithWarnings(manifestPath string, content []byte) (*models.Project, []Warning, error) {
+	content = decodeManifest(content)
+
+	var warnings []Warning
 	for _, d := range r.detectors {
-		project, err := d.Detect(manifestPath, content)
+		var project *models.Project
+		var messages []string
+		var err error
+
+		if wd, ok := d.(WarningDetector); ok {
+			project, messages, err = wd.DetectWithWarnings(manifestPath, content)
+		} else {
+			project, err = d.Detect(manifestPath, content)
+		}
 		if err != nil {
-			return nil, err
+			return nil, warnings, err
 		}
+
+		for _, msg := range messages {
+			warnings = append(warnings, Warning{
+				ManifestPath: manifestPath,
+				Detector:     d.Name(),
+				Message:      msg,
+			})
+		}
+
 		if project != nil {
-			return project, nil
+			return project, warnings, nil
 		}
 	}
-	return nil, nil
+	return nil, warnings, nil
+}
+
+// xmlEncodingDecl matches the encoding attribute of a leading XML
+// declaration, such as encoding="utf-16" in Visual Studio project files.
+var xmlEncodingDecl = regexp.MustCompile(`^(\s*<\?xml\b[^>]*?)\s+encoding\s*=\s*(?:"[^"]*"|'[^']*')`)
+
+// decodeManifest strips a leading byte order mark and converts UTF-16
+// content to UTF-8. Windows tooling commonly writes manifests with a BOM,
+// which breaks the JSON, TOML, and XML parsers used by the detectors. The
+// encoding declared by converted XML is dropped, since encoding/xml cannot
+// read UTF-16 and the content is now UTF-8. Content without a BOM is
+// returned unchanged.
+func decodeManifest(content []byte) []byte {
+	if textenc.IsUTF16(content) {
+		return xmlEncodingDecl.ReplaceAll(textenc.Decode(content), []byte("$1"))
+	}
+	return textenc.Decode(content)
 }
@@ -1,6 +1,10 @@
 package detector
 
 import (
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"repoctr/pkg/models"
@@ -85,6 +89,95 @@ requires = ["setuptools>=61.0"]
 	}
 }
 
+func TestPythonDetector_PyprojectVersion(t *testing.T) {
+	root := t.TempDir()
+	writeTestFiles(t, root, map[string]string{
+		"attr/src/mypkg/__init__.py": "\"\"\"My package.\"\"\"\n\n__version__: str = \"1.4.2\"\n",
+		"file/VERSION":               "0.9.0\n",
+	})
+
+	tests := []struct {
+		name    string
+		dir     string
+		content string
+		want    string
+	}{
+		{
+			name:    "static",
+			content: "[project]\nname = \"app\"\nversion = \"2.0.0\"\n",
+			want:    "2.0.0",
+		},
+		{
+			name: "dynamic from VCS",
+			content: `[project]
+name = "app"
+dynamic = ["version"]
+
+[build-system]
+requires = ["hatchling", "hatch-vcs"]
+
+[tool.hatch.version]
+source = "vcs"
+`,
+			want: models.DynamicVersion,
+		},
+		{
+			name: "dynamic setuptools attr",
+			dir:  "attr",
+			content: `[project]
+name = "mypkg"
+dynamic = ["version", "readme"]
+
+[tool.setuptools.dynamic]
+version = {attr = "mypkg.__version__"}
+`,
+			want: "1.4.2",
+		},
+		{
+			name: "dynamic setuptools file",
+			dir:  "file",
+			content: `[project]
+name = "app"
+dynamic = ["version"]
+
+[tool.setuptools.dynamic]
+version = {file = ["VERSION"]}
+`,
+			want: "0.9.0",
+		},
+		{
+			name: "dynamic setuptools attr not found",
+			dir:  "file",
+			content: `[project]
+name = "app"
+dynamic = ["version"]
+
+[tool.setuptools.dynamic]
+version = {attr = "missing.__version__"}
+`,
+			want: models.DynamicVersion,
+		},
+		{
+			name:    "no version",
+			content: "[project]\nname = \"app\"\n",
+			want:    "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manifest := filepath.Join(root, tt.dir, "pyproject.toml")
+			project, err := NewPythonDetector().Detect(manifest, []byte(tt.content))
+			if err != nil || project == nil {
+				t.Fatalf("Detect = %+v, %v", project, err)
+			}
+			if project.Version != tt.want {
+				t.Errorf("version = %q, want %q", project.Version, tt.want)
+			}
+		})
+	}
+}
+
 func TestPythonDetector_Poetry(t *testing.T) {
 	d := NewPythonDetector()
 
@@ -108,6 +201,135 @@ python = "^3.10"
 	}
 }
 
+func TestPythonDetector_SetupCfg(t *testing.T) {
+	d := NewPythonDetector()
+
+	content := `[metadata]
+name = cfg-project
+version = 1.4.0
+
+[options]
+packages = find:
+python_requires = >=3.9
+install_requires =
+    requests>=2.0
+    click
+
+[flake8]
+max-line-length = 100
+`
+
+	project, err := d.Detect("svc/setup.cfg", []byte(content))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if project == nil {
+		t.Fatal("expected project, got nil")
+	}
+	if project.Name != "cfg-project" {
+		t.Errorf("name = %q, want %q", project.Name, "cfg-project")
+	}
+	if project.Runtime.Version != "3.9+" {
+		t.Errorf("runtime version = %q, want %q", project.Runtime.Version, "3.9+")
+	}
+	if project.Version != "1.4.0" {
+		t.Errorf("version = %q, want %q", project.Version, "1.4.0")
+	}
+	if project.DependencyCount != 2 {
+		t.Errorf("dependencies = %d, want 2", project.DependencyCount)
+	}
+
+	// A setup.cfg holding only tool settings is not a project
+	project, err = d.Detect("svc/setup.cfg", []byte("[flake8]\nmax-line-length = 100\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if project != nil {
+		t.Errorf("expected nil for tool-only setup.cfg, got %+v", project)
+	}
+}
+
+func TestPythonDetector_Pipfile(t *testing.T) {
+	d := NewPythonDetector()
+
+	content := `[[source]]
+url = "https://pypi.org/simple"
+verify_ssl = true
+name = "pypi"
+
+[packages]
+requests = "*"
+flask = {version = ">=2.0"}
+
+[dev-packages]
+pytest = "*"
+
+[requires]
+python_version = "3.11"
+`
+
+	project, err := d.Detect("api/Pipfile", []byte(content))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if project == nil {
+		t.Fatal("expected project, got nil")
+	}
+	if project.Name != "api" {
+		t.Errorf("name = %q, want %q", project.Name, "api")
+	}
+	if project.Runtime.Version != "3.11" {
+		t.Errorf("runtime version = %q, want %q", project.Runtime.Version, "3.11")
+	}
+	if project.DependencyCount != 2 {
+		t.Errorf("dependencies = %d, want 2", project.DependencyCount)
+	}
+	if project.PackageManager != "pipenv" {
+		t.Errorf("PackageManager = %q, want %q", project.PackageManager, "pipenv")
+	}
+}
+
+func TestPythonDetector_RequirementsVariants(t *testing.T) {
+	d := NewPythonDetector()
+
+	tests := []struct {
+		path         string
+		wantPath     string
+		wantManifest string
+	}{
+		{"svc/requirements-dev.txt", "svc", "requirements-dev.txt"},
+		{"svc/constraints.txt", "svc", "constraints.txt"},
+		{"svc/requirements/base.txt", "svc", "requirements/base.txt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			project, err := d.Detect(tt.path, []byte("requests\n"))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if project == nil {
+				t.Fatal("expected project, got nil")
+			}
+			if project.Path != tt.wantPath {
+				t.Errorf("path = %q, want %q", project.Path, tt.wantPath)
+			}
+			if project.ManifestFile != tt.wantManifest {
+				t.Errorf("manifest = %q, want %q", project.ManifestFile, tt.wantManifest)
+			}
+		})
+	}
+
+	// Unrelated text files are not Python manifests
+	project, err := d.Detect("svc/notes.txt", []byte("hello"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if project != nil {
+		t.Errorf("expected nil for notes.txt, got %+v", project)
+	}
+}
+
 func TestJavaScriptDetector(t *testing.T) {
 	d := NewJavaScriptDetector()
 
@@ -137,6 +359,251 @@ func TestJavaScriptDetector(t *testing.T) {
 	}
 }
 
+func TestJavaScriptDetector_PeerDepTypeScriptLibrary(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFiles(t, dir, map[string]string{
+		"src/index.ts":        "export * from './lib'",
+		"src/lib.ts":          "export const x = 1",
+		"src/react/view.tsx":  "export const View = () => null",
+		"jest.config.js":      "module.exports = {}",
+		"node_modules/a/a.js": "",
+		"node_modules/a/b.js": "",
+		"node_modules/a/c.js": "",
+		"node_modules/a/d.js": "",
+		"dist/index.js":       "",
+		"dist/index.d.ts":     "",
+		"types/globals.d.ts":  "",
+	})
+
+	content := `{
+  "name": "ts-lib",
+  "peerDependencies": {
+    "typescript": ">=5"
+  }
+}`
+
+	project, err := NewJavaScriptDetector().Detect(filepath.Join(dir, "package.json"), []byte(content))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if project == nil {
+		t.Fatal("expected project, got nil")
+	}
+	if project.Runtime.Type != models.RuntimeTypeScript {
+		t.Errorf("type = %q, want %q", project.Runtime.Type, models.RuntimeTypeScript)
+	}
+}
+
+func TestJavaScriptDetector_ReactNativeFramework(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"React Native", `{"name": "app", "dependencies": {"react-native": "0.74.1"}}`, models.FrameworkReactNative},
+		{"Capacitor", `{"name": "app", "dependencies": {"@capacitor/core": "6.0.0", "@capacitor/android": "6.0.0"}}`, ""},
+		{"Cordova", `{"name": "app", "devDependencies": {"cordova-android": "13.0.0"}}`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Every layout has native projects in android/ and ios/
+			dir := t.TempDir()
+			writeTestFiles(t, dir, map[string]string{
+				"android/build.gradle": "",
+				"ios/Podfile":          "",
+			})
+
+			project, err := NewJavaScriptDetector().Detect(filepath.Join(dir, "package.json"), []byte(tt.content))
+			if err != nil || project == nil {
+				t.Fatalf("project = %+v, %v; want a project", project, err)
+			}
+			if project.Framework != tt.want {
+				t.Errorf("framework = %q, want %q", project.Framework, tt.want)
+			}
+		})
+	}
+}
+
+func TestHasMostlyTypeScriptSources(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  bool
+	}{
+		{"ts outnumbers js", map[string]string{"src/a.ts": "", "src/b.tsx": "", "index.js": ""}, true},
+		{"js outnumbers ts", map[string]string{"src/a.js": "", "src/b.jsx": "", "index.ts": ""}, false},
+		{"tie is javascript", map[string]string{"a.ts": "", "b.js": ""}, false},
+		{"declaration files ignored", map[string]string{"index.js": "", "index.d.ts": "", "types.d.ts": ""}, false},
+		{"node_modules ignored", map[string]string{"a.ts": "", "node_modules/x/a.js": "", "node_modules/x/b.js": ""}, true},
+		{"beyond max depth ignored", map[string]string{"a.js": "", "src/a/b/c.ts": "", "src/a/b/d.ts": ""}, false},
+		{"empty", map[string]string{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeTestFiles(t, dir, tt.files)
+			if got := hasMostlyTypeScriptSources(dir); got != tt.want {
+				t.Errorf("hasMostlyTypeScriptSources() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// writeTestFiles creates files (and parent directories) under root.
+func writeTestFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for rel, content := range files {
+		full := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+			t.Fatalf("write %s: %v", rel, err)
+		}
+	}
+}
+
+func TestRegistry_BOMPrefixedPackageJSON(t *testing.T) {
+	r := NewRegistry()
+
+	content := append([]byte{0xEF, 0xBB, 0xBF}, []byte(`{
+  "name": "bom-app",
+  "engines": {
+    "node": ">=20"
+  }
+}`)...)
+
+	project, err := r.DetectProject("package.json", content)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if project == nil {
+		t.Fatal("expected project, got nil")
+	}
+	if project.Name != "bom-app" {
+		t.Errorf("name = %q, want %q", project.Name, "bom-app")
+	}
+	if project.Runtime.Version != ">=20" {
+		t.Errorf("version = %q, want %q", project.Runtime.Version, ">=20")
+	}
+}
+
+func TestRegistry_ManifestPriority(t *testing.T) {
+	r := NewRegistry()
+
+	ordered := [][]string{
+		{"CMakeLists.txt", "meson.build", "app.vcxproj", "Makefile"},
+		{"pyproject.toml", "setup.py", "setup.cfg", "Pipfile", "requirements.txt", "constraints.txt", "requirements/base.txt"},
+	}
+	for _, manifests := range ordered {
+		for i := 1; i < len(manifests); i++ {
+			if hi, lo := r.ManifestPriority(manifests[i-1]), r.ManifestPriority(manifests[i]); hi >= lo {
+				t.Errorf("priority(%s) = %d, want below priority(%s) = %d", manifests[i-1], hi, manifests[i], lo)
+			}
+		}
+	}
+
+	if got, want := r.ManifestPriority("requirements-dev.txt"), r.ManifestPriority("requirements.txt"); got != want {
+		t.Errorf("requirements variants rank %d, want %d like requirements.txt", got, want)
+	}
+}
+
+func TestRegistry_UTF16Csproj(t *testing.T) {
+	r := NewRegistry()
+
+	text := `<Project Sdk="Microsoft.NET.Sdk">
+  <PropertyGroup>
+    <TargetFramework>net6.0</TargetFramework>
+  </PropertyGroup>
+</Project>`
+
+	tests := []struct {
+		name string
+		text string
+	}{
+		{"no declaration", text},
+		// As Visual Studio writes it
+		{"utf-16 declaration", "<?xml version=\"1.0\" encoding=\"utf-16\"?>\r\n" + text},
+		{"single-quoted declaration", "<?xml version='1.0' encoding='UTF-16' standalone='yes'?>\n" + text},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Encode as UTF-16 LE with BOM
+			content := []byte{0xFF, 0xFE}
+			for _, c := range tt.text {
+				content = append(content, byte(c), 0)
+			}
+
+			project, warnings, err := r.DetectProjectWithWarnings("Legacy.csproj", content)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if project == nil {
+				t.Fatal("expected project, got nil")
+			}
+			if project.Runtime.Version != "6.0" {
+				t.Errorf("version = %q, want %q (warnings: %v)", project.Runtime.Version, "6.0", warnings)
+			}
+		})
+	}
+}
+
+func TestRegistry_MalformedCsprojWarning(t *testing.T) {
+	r := NewRegistry()
+
+	content := `<Project Sdk="Microsoft.NET.Sdk">
+  <PropertyGroup>
+    <TargetFramework>net8.0</TargetFramework>
+  </PropertyGroup>
+`
+
+	project, warnings, err := r.DetectProjectWithWarnings("src/Broken.csproj", []byte(content))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if project == nil {
+		t.Fatal("expected project, got nil")
+	}
+	if project.Runtime.Type != models.RuntimeDotNet {
+		t.Errorf("type = %q, want %q", project.Runtime.Type, models.RuntimeDotNet)
+	}
+	if project.Runtime.Version != "" {
+		t.Errorf("version = %q, want empty", project.Runtime.Version)
+	}
+
+	if len(warnings) != 1 {
+		t.Fatalf("got %d warnings, want 1: %v", len(warnings), warnings)
+	}
+	w := warnings[0]
+	if w.ManifestPath != "src/Broken.csproj" || w.Detector != "DotNet" {
+		t.Errorf("warning = %+v, want manifest src/Broken.csproj from DotNet", w)
+	}
+	if !strings.Contains(w.Message, "csproj XML parse failed") {
+		t.Errorf("message = %q, want csproj parse failure", w.Message)
+	}
+}
+
+func TestRegistry_ValidManifestNoWarnings(t *testing.T) {
+	r := NewRegistry()
+
+	content := `<Project Sdk="Microsoft.NET.Sdk">
+  <PropertyGroup>
+    <TargetFramework>net8.0</TargetFramework>
+  </PropertyGroup>
+</Project>`
+
+	_, warnings, err := r.DetectProjectWithWarnings("App.csproj", []byte(content))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("expected no warnings, got %v", warnings)
+	}
+}
+
 func TestRustDetector(t *testing.T) {
 	d := NewRustDetector()
 
@@ -212,32 +679,430 @@ func TestJavaDetector_Maven(t *testing.T) {
 	}
 }
 
-func TestDotNetDetector_SlnWithCsproj(t *testing.T) {
-	d := NewDotNetDetector()
+func TestNimDetector(t *testing.T) {
+	content := `# Package
 
-	content := `Microsoft Visual Studio Solution File, Format Version 12.00
-# Visual Studio Version 17
-Project("{FAE04EC0-301F-11D3-BF4B-00C04F79EFBC}") = "MyApp", "MyApp\MyApp.csproj", "{GUID}"
-EndProject`
+version       = "0.2.0"
+author        = "Jane Doe"
+description   = "A tiny web service"
+license       = "MIT"
+srcDir        = "src"
+bin           = @["foo"]
 
-	project, err := d.Detect("dir/MyApp.sln", []byte(content))
+# Dependencies
+
+requires "nim >= 2.0.0", "jester >= 0.6.0"
+requires "karax"
+`
+
+	project, err := NewRegistry().DetectProject(filepath.Join("services", "foo.nimble"), []byte(content))
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 	if project == nil {
 		t.Fatal("expected project, got nil")
 	}
-	if project.Runtime.Type != models.RuntimeDotNet {
-		t.Errorf("type = %q, want %q", project.Runtime.Type, models.RuntimeDotNet)
+	if project.Runtime.Type != models.RuntimeNim {
+		t.Errorf("runtime = %q, want %q", project.Runtime.Type, models.RuntimeNim)
+	}
+	if project.Name != "foo" {
+		t.Errorf("name = %q, want %q", project.Name, "foo")
+	}
+	if project.Runtime.Version != "0.2.0" {
+		t.Errorf("version = %q, want %q", project.Runtime.Version, "0.2.0")
+	}
+	if project.ManifestFile != "foo.nimble" {
+		t.Errorf("manifest = %q, want %q", project.ManifestFile, "foo.nimble")
+	}
+	if len(project.SourcePaths) != 1 || project.SourcePaths[0] != "src" {
+		t.Errorf("source paths = %v, want [src]", project.SourcePaths)
+	}
+	if project.DependencyCount != 2 {
+		t.Errorf("dependency count = %d, want 2", project.DependencyCount)
+	}
+
+	// packageName overrides the file name
+	renamed := "packageName = \"bar\"\nversion = \"1.0.0\"\n"
+	project, err = NewNimDetector().Detect("foo.nimble", []byte(renamed))
+	if err != nil || project == nil || project.Name != "bar" {
+		t.Errorf("project = %+v, %v; want name bar", project, err)
 	}
 }
 
-func TestDotNetDetector_SlnWithVcxprojOnly(t *testing.T) {
-	d := NewDotNetDetector()
+func TestRDetector(t *testing.T) {
+	content := `Package: tidyplot
+Type: Package
+Title: Tidy Plotting Helpers
+Version: 0.3.1
+Authors@R: person("Jane", "Doe", email = "jane@example.com",
+    role = c("aut", "cre"))
+Description: Helpers for plotting tidy data frames.
+    Builds on ggplot2.
+License: MIT + file LICENSE
+Depends:
+    R (>= 4.1.0),
+    ggplot2
+Imports: dplyr (>= 1.1.0), rlang,
+    tibble
+LinkingTo: Rcpp
+Suggests: testthat (>= 3.0.0)
+Encoding: UTF-8
+`
 
-	// A .sln that only references .vcxproj (C++ project) should NOT be detected as .NET
-	content := `Microsoft Visual Studio Solution File, Format Version 12.00
-# Visual Studio Version 17
+	project, err := NewRegistry().DetectProject(filepath.Join("analysis", "DESCRIPTION"), []byte(content))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if project == nil {
+		t.Fatal("expected project, got nil")
+	}
+	if project.Runtime.Type != models.RuntimeR {
+		t.Errorf("runtime = %q, want %q", project.Runtime.Type, models.RuntimeR)
+	}
+	if project.Name != "tidyplot" {
+		t.Errorf("name = %q, want %q", project.Name, "tidyplot")
+	}
+	if project.Runtime.Version != "4.1.0+" {
+		t.Errorf("version = %q, want %q", project.Runtime.Version, "4.1.0+")
+	}
+	if project.Path != "analysis" {
+		t.Errorf("path = %q, want %q", project.Path, "analysis")
+	}
+	// ggplot2, dplyr, rlang, tibble, Rcpp; Suggests are optional
+	if project.DependencyCount != 5 {
+		t.Errorf("dependency count = %d, want 5", project.DependencyCount)
+	}
+
+	// A DESCRIPTION without a Package field is not an R package
+	project, err = NewRDetector().Detect("DESCRIPTION", []byte("This directory holds the docs.\n"))
+	if err != nil || project != nil {
+		t.Errorf("project = %+v, %v; want nil", project, err)
+	}
+}
+
+func TestSwiftDetector(t *testing.T) {
+	content := `// swift-tools-version:5.9
+import PackageDescription
+
+let package = Package(
+    name: "Networking",
+    platforms: [.iOS(.v15)],
+    dependencies: [
+        .package(url: "https://github.com/apple/swift-log.git", from: "1.5.0"),
+        .package(url: "https://github.com/apple/swift-collections.git", from: "1.0.0"),
+    ],
+    targets: [
+        .target(name: "Networking", dependencies: [.product(name: "Logging", package: "swift-log")]),
+    ]
+)
+`
+
+	project, err := NewRegistry().DetectProject(filepath.Join("Packages", "Networking", "Package.swift"), []byte(content))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if project == nil {
+		t.Fatal("expected project, got nil")
+	}
+	if project.Runtime.Type != models.RuntimeSwift {
+		t.Errorf("runtime = %q, want %q", project.Runtime.Type, models.RuntimeSwift)
+	}
+	if project.Name != "Networking" {
+		t.Errorf("name = %q, want %q", project.Name, "Networking")
+	}
+	if project.Runtime.Version != "5.9" {
+		t.Errorf("version = %q, want %q", project.Runtime.Version, "5.9")
+	}
+	if project.DependencyCount != 2 {
+		t.Errorf("dependency count = %d, want 2", project.DependencyCount)
+	}
+	if project.PackageManager != "swiftpm" {
+		t.Errorf("package manager = %q, want swiftpm", project.PackageManager)
+	}
+
+	podfile := "platform :ios, '13.4'\n\ntarget 'MobileApp' do\n  pod 'Alamofire', '~> 5.8'\n  pod 'SnapKit'\n\n  target 'MobileAppTests' do\n    inherit! :search_paths\n  end\nend\n"
+	project, err = NewSwiftDetector().Detect(filepath.Join("ios", "Podfile"), []byte(podfile))
+	if err != nil || project == nil {
+		t.Fatalf("project = %+v, %v; want a CocoaPods project", project, err)
+	}
+	if project.Name != "MobileApp" || project.DependencyCount != 2 || project.PackageManager != "cocoapods" {
+		t.Errorf("project = %+v, want MobileApp with 2 pods", project)
+	}
+}
+
+func TestClojureDetector_ProjectClj(t *testing.T) {
+	content := `;; Leiningen build
+(defproject org.example/billing "0.3.0-SNAPSHOT"
+  :description "Billing service"
+  :dependencies [[org.clojure/clojure "1.11.1"]
+                 [ring/ring-core "1.10.0"]]
+  :main billing.core)
+`
+
+	project, err := NewRegistry().DetectProject(filepath.Join("services", "billing", "project.clj"), []byte(content))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if project == nil {
+		t.Fatal("expected project, got nil")
+	}
+	if project.Runtime.Type != models.RuntimeClojure {
+		t.Errorf("runtime = %q, want %q", project.Runtime.Type, models.RuntimeClojure)
+	}
+	if project.Name != "org.example/billing" {
+		t.Errorf("name = %q, want %q", project.Name, "org.example/billing")
+	}
+	if project.Version != "0.3.0-SNAPSHOT" {
+		t.Errorf("version = %q, want %q", project.Version, "0.3.0-SNAPSHOT")
+	}
+	if project.Runtime.Version != "1.11.1" {
+		t.Errorf("runtime version = %q, want %q", project.Runtime.Version, "1.11.1")
+	}
+	if project.PackageManager != "leiningen" {
+		t.Errorf("package manager = %q, want leiningen", project.PackageManager)
+	}
+
+	// A project.clj without defproject is not a Leiningen project
+	project, err = NewClojureDetector().Detect("project.clj", []byte("(println \"hello\")\n"))
+	if err != nil || project != nil {
+		t.Errorf("project = %+v, %v; want nil", project, err)
+	}
+}
+
+func TestClojureDetector_DepsEdn(t *testing.T) {
+	content := `{:paths ["src" "resources"]
+ :deps {org.clojure/clojure {:mvn/version "1.12.0"}
+        metosin/reitit {:mvn/version "0.7.2"}}}
+`
+
+	project, err := NewRegistry().DetectProject(filepath.Join("apps", "dashboard", "deps.edn"), []byte(content))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if project == nil {
+		t.Fatal("expected project, got nil")
+	}
+	if project.Runtime.Type != models.RuntimeClojure {
+		t.Errorf("runtime = %q, want %q", project.Runtime.Type, models.RuntimeClojure)
+	}
+	if project.Name != "dashboard" {
+		t.Errorf("name = %q, want the folder name %q", project.Name, "dashboard")
+	}
+	if project.Runtime.Version != "1.12.0" {
+		t.Errorf("runtime version = %q, want %q", project.Runtime.Version, "1.12.0")
+	}
+	if project.ManifestFile != "deps.edn" || project.PackageManager != "clojure-cli" {
+		t.Errorf("project = %+v, want a clojure-cli deps.edn project", project)
+	}
+}
+
+func TestOCamlDetector_DuneProject(t *testing.T) {
+	content := `(lang dune 3.11)
+(name parser_tools)
+(version 0.4.0)
+
+(generate_opam_files true)
+
+(package
+ (name parser_tools)
+ (depends
+  (ocaml (>= 4.14))
+  menhir))
+`
+
+	project, err := NewRegistry().DetectProject(filepath.Join("libs", "parser", "dune-project"), []byte(content))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if project == nil {
+		t.Fatal("expected project, got nil")
+	}
+	if project.Runtime.Type != models.RuntimeOCaml {
+		t.Errorf("runtime = %q, want %q", project.Runtime.Type, models.RuntimeOCaml)
+	}
+	if project.Name != "parser_tools" {
+		t.Errorf("name = %q, want %q", project.Name, "parser_tools")
+	}
+	if project.Version != "0.4.0" {
+		t.Errorf("version = %q, want %q", project.Version, "0.4.0")
+	}
+	if project.PackageManager != "dune" {
+		t.Errorf("package manager = %q, want dune", project.PackageManager)
+	}
+
+	// Without a name stanza the directory names the project
+	project, err = NewOCamlDetector().Detect(filepath.Join("tools", "fmt", "dune-project"), []byte("(lang dune 3.0)\n"))
+	if err != nil || project == nil || project.Name != "fmt" {
+		t.Errorf("project = %+v, %v; want one named fmt", project, err)
+	}
+}
+
+func TestOCamlDetector_Opam(t *testing.T) {
+	content := `opam-version: "2.0"
+version: "1.2.0"
+depends: [
+  "ocaml" {>= "4.14"}
+  "dune" {>= "3.0"}
+]
+`
+
+	project, err := NewRegistry().DetectProject(filepath.Join("cli", "rc_cli.opam"), []byte(content))
+	if err != nil || project == nil {
+		t.Fatalf("project = %+v, %v; want an opam project", project, err)
+	}
+	if project.Name != "rc_cli" || project.Version != "1.2.0" || project.Runtime.Version != "4.14+" {
+		t.Errorf("project = %+v, want rc_cli 1.2.0 on OCaml 4.14+", project)
+	}
+}
+
+func TestJavaDetector_KotlinGradle(t *testing.T) {
+	root := t.TempDir()
+	buildScript := `plugins {
+    kotlin("multiplatform") version "1.9.22"
+}
+
+kotlin {
+    jvm()
+    iosArm64()
+}
+`
+	writeTestFiles(t, root, map[string]string{
+		"shared/build.gradle.kts":                              buildScript,
+		"shared/src/commonMain/kotlin/com/example/Greeting.kt": "package com.example\n",
+		"shared/src/jvmMain/kotlin/com/example/Platform.kt":    "package com.example\n",
+		"shared/src/jvmMain/java/com/example/Legacy.java":      "package com.example;\n",
+		"legacy/build.gradle.kts":                              "plugins {\n    id(\"org.jetbrains.kotlin.jvm\")\n}\n",
+		"legacy/src/main/java/com/example/App.java":            "package com.example;\n",
+		"legacy/src/main/java/com/example/Util.java":           "package com.example;\n",
+		"legacy/src/main/kotlin/com/example/Extensions.kt":     "package com.example\n",
+	})
+
+	path := filepath.Join(root, "shared", "build.gradle.kts")
+	project, err := NewJavaDetector().Detect(path, []byte(buildScript))
+	if err != nil || project == nil {
+		t.Fatalf("project = %+v, %v; want a Gradle project", project, err)
+	}
+	if project.Runtime.Type != models.RuntimeKotlin {
+		t.Errorf("runtime = %q, want %q", project.Runtime.Type, models.RuntimeKotlin)
+	}
+	if project.Runtime.Version != "1.9.22" {
+		t.Errorf("version = %q, want the plugin version %q", project.Runtime.Version, "1.9.22")
+	}
+
+	// Applying the plugin is not enough when most sources are still Java
+	path = filepath.Join(root, "legacy", "build.gradle.kts")
+	content, _ := os.ReadFile(path)
+	project, err = NewJavaDetector().Detect(path, content)
+	if err != nil || project == nil {
+		t.Fatalf("project = %+v, %v; want a Gradle project", project, err)
+	}
+	if project.Runtime.Type != models.RuntimeJava {
+		t.Errorf("runtime = %q, want %q for a mostly Java project", project.Runtime.Type, models.RuntimeJava)
+	}
+}
+
+func TestDotNetDetector_GlobalJSONVersion(t *testing.T) {
+	root := t.TempDir()
+	csproj := `<Project Sdk="Microsoft.NET.Sdk">
+  <PropertyGroup>
+    <Nullable>enable</Nullable>
+  </PropertyGroup>
+</Project>`
+	writeTestFiles(t, root, map[string]string{
+		"global.json":              `{"sdk": {"version": "8.0.100", "rollForward": "latestFeature"}}`,
+		"src/Api/Api.csproj":       csproj,
+		"src/Pinned/Pinned.csproj": strings.Replace(csproj, "<Nullable>", "<TargetFramework>net6.0</TargetFramework><Nullable>", 1),
+	})
+
+	// The ancestor global.json supplies the version
+	manifest := filepath.Join(root, "src", "Api", "Api.csproj")
+	project, err := NewDotNetDetector().Detect(manifest, []byte(csproj))
+	if err != nil || project == nil {
+		t.Fatalf("project = %+v, %v; want a .NET project", project, err)
+	}
+	if project.Runtime.Version != "8.0" {
+		t.Errorf("version = %q, want 8.0 from global.json", project.Runtime.Version)
+	}
+
+	// A TargetFramework in the project takes precedence
+	manifest = filepath.Join(root, "src", "Pinned", "Pinned.csproj")
+	content, err := os.ReadFile(manifest)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	project, err = NewDotNetDetector().Detect(manifest, content)
+	if err != nil || project == nil || project.Runtime.Version != "6.0" {
+		t.Errorf("project = %+v, %v; want version 6.0", project, err)
+	}
+
+	// Editors often save global.json with a UTF-8 BOM
+	writeTestFiles(t, root, map[string]string{
+		"global.json": "\uFEFF" + `{"sdk": {"version": "9.0.100"}}`,
+	})
+	manifest = filepath.Join(root, "src", "Api", "Api.csproj")
+	project, err = NewDotNetDetector().Detect(manifest, []byte(csproj))
+	if err != nil || project == nil || project.Runtime.Version != "9.0" {
+		t.Errorf("project = %+v, %v; want version 9.0 from a BOM-prefixed global.json", project, err)
+	}
+}
+
+func TestDotNetDetector_DirectoryBuildProps(t *testing.T) {
+	root := t.TempDir()
+	csproj := `<Project Sdk="Microsoft.NET.Sdk">
+  <ItemGroup>
+    <PackageReference Include="Serilog" Version="3.1.1" />
+  </ItemGroup>
+</Project>`
+	writeTestFiles(t, root, map[string]string{
+		"global.json": `{"sdk": {"version": "9.0.100"}}`,
+		"src/Directory.Build.props": `<Project>
+  <PropertyGroup>
+    <TargetFrameworks>net8.0;netstandard2.0</TargetFrameworks>
+    <LangVersion>latest</LangVersion>
+  </PropertyGroup>
+</Project>`,
+		"src/Api/Api.csproj": csproj,
+	})
+
+	// The props file one directory up wins over global.json
+	project, err := NewDotNetDetector().Detect(filepath.Join(root, "src", "Api", "Api.csproj"), []byte(csproj))
+	if err != nil || project == nil {
+		t.Fatalf("project = %+v, %v; want a .NET project", project, err)
+	}
+	if project.Runtime.Version != "8.0" {
+		t.Errorf("version = %q, want 8.0 from Directory.Build.props", project.Runtime.Version)
+	}
+	if project.DependencyCount != 1 {
+		t.Errorf("dependency count = %d, want 1", project.DependencyCount)
+	}
+}
+
+func TestDotNetDetector_SlnWithCsproj(t *testing.T) {
+	d := NewDotNetDetector()
+
+	content := `Microsoft Visual Studio Solution File, Format Version 12.00
+# Visual Studio Version 17
+Project("{FAE04EC0-301F-11D3-BF4B-00C04F79EFBC}") = "MyApp", "MyApp\MyApp.csproj", "{GUID}"
+EndProject`
+
+	project, err := d.Detect("dir/MyApp.sln", []byte(content))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if project == nil {
+		t.Fatal("expected project, got nil")
+	}
+	if project.Runtime.Type != models.RuntimeDotNet {
+		t.Errorf("type = %q, want %q", project.Runtime.Type, models.RuntimeDotNet)
+	}
+}
+
+func TestDotNetDetector_SlnWithVcxprojOnly(t *testing.T) {
+	d := NewDotNetDetector()
+
+	// A .sln that only references .vcxproj (C++ project) should NOT be detected as .NET
+	content := `Microsoft Visual Studio Solution File, Format Version 12.00
+# Visual Studio Version 17
 Project("{8BC9CEB8-8B4A-11D0-8D11-00A0C91BC942}") = "MyCppApp", "MyCppApp\MyCppApp.vcxproj", "{GUID}"
 EndProject`
 
@@ -279,6 +1144,169 @@ func TestCppDetector_Vcxproj(t *testing.T) {
 	}
 }
 
+func TestCppDetector_Makefile(t *testing.T) {
+	tests := []struct {
+		name        string
+		files       map[string]string
+		wantNil     bool
+		wantRuntime models.RuntimeType
+		wantVersion string
+	}{
+		{
+			name: "C++ build",
+			files: map[string]string{
+				"Makefile": "CXXFLAGS = -O2 -std=c++17\n\napp: main.o\n\t$(CXX) -o app main.o\n",
+			},
+			wantRuntime: models.RuntimeCpp,
+			wantVersion: "C++17",
+		},
+		{
+			name: "C build without standard flag",
+			files: map[string]string{
+				"Makefile": "SRCS = $(wildcard src/*.c)\n\n%.o: %.c\n\t$(CC) $(CFLAGS) -c $< -o $@\n",
+			},
+			wantRuntime: models.RuntimeCpp,
+		},
+		{
+			name: "task runner next to go.mod",
+			files: map[string]string{
+				"Makefile": "CFLAGS ?= -O2\n\nbuild:\n\t$(CC) -c shim.c\n\tgo build ./...\n",
+				"go.mod":   "module example.com/app\n\ngo 1.22\n",
+			},
+			wantNil: true,
+		},
+		{
+			name: "task runner next to a .csproj",
+			files: map[string]string{
+				"Makefile":   "build:\n\tdotnet build\n",
+				"App.csproj": "<Project Sdk=\"Microsoft.NET.Sdk\"></Project>\n",
+			},
+			wantNil: true,
+		},
+		{
+			name: "task runner next to an R package",
+			files: map[string]string{
+				"Makefile":    "CFLAGS = -O2\n\nall:\n\tgcc -c src/init.c -o src/init.o\n",
+				"DESCRIPTION": "Package: tool\nVersion: 0.1.0\n",
+			},
+			wantNil: true,
+		},
+		{
+			name: "task runner next to Package.swift",
+			files: map[string]string{
+				"Makefile":      "build:\n\tswift build\n",
+				"Package.swift": "// swift-tools-version:5.9\n",
+			},
+			wantNil: true,
+		},
+		{
+			name: "task runner next to an opam file",
+			files: map[string]string{
+				"Makefile":  "build:\n\tdune build\n",
+				"tool.opam": "opam-version: \"2.0\"\n",
+			},
+			wantNil: true,
+		},
+		{
+			name: "standalone non-C Makefile",
+			files: map[string]string{
+				"Makefile": "export CFLAGS = -O2\n\nimage:\n\tdocker build -t app .\n\ndocs:\n\tmkdocs build\n",
+			},
+			wantRuntime: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := filepath.Join(t.TempDir(), "tool")
+			writeTestFiles(t, root, tt.files)
+
+			manifestPath := filepath.Join(root, "Makefile")
+			project, err := NewCppDetector().Detect(manifestPath, []byte(tt.files["Makefile"]))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantNil {
+				if project != nil {
+					t.Errorf("expected no project, got %+v", project)
+				}
+				return
+			}
+			if project == nil {
+				t.Fatal("expected project, got nil")
+			}
+			if project.Runtime.Type != tt.wantRuntime || project.Runtime.Version != tt.wantVersion {
+				t.Errorf("runtime = %+v, want %q %q", project.Runtime, tt.wantRuntime, tt.wantVersion)
+			}
+			if project.Name != "tool" || project.ManifestFile != "Makefile" {
+				t.Errorf("project = %s from %s, want tool from Makefile", project.Name, project.ManifestFile)
+			}
+		})
+	}
+}
+
+func TestCppDetector_Meson(t *testing.T) {
+	d := NewCppDetector()
+
+	tests := []struct {
+		name        string
+		content     string
+		wantNil     bool
+		wantName    string
+		wantVersion string
+	}{
+		{
+			name: "cpp_std option",
+			content: `project('engine', 'cpp',
+  version : '1.2.0',
+  default_options : ['warning_level=3', 'cpp_std=c++20'])`,
+			wantName:    "engine",
+			wantVersion: "C++20",
+		},
+		{
+			name: "language list with c_std",
+			content: `project('mixed', ['c', 'cpp'],
+  default_options : ['c_std=gnu11'])`,
+			wantName:    "mixed",
+			wantVersion: "C11",
+		},
+		{
+			name:     "no languages declared",
+			content:  `project('bare')`,
+			wantName: "bare",
+		},
+		{
+			name:    "non-C language",
+			content: `project('rusty', 'rust', default_options : ['rust_std=2021'])`,
+			wantNil: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			project, err := d.Detect("dir/meson.build", []byte(tt.content))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantNil {
+				if project != nil {
+					t.Errorf("expected nil, got project %q", project.Name)
+				}
+				return
+			}
+			if project == nil {
+				t.Fatal("expected project, got nil")
+			}
+			if project.Name != tt.wantName {
+				t.Errorf("name = %q, want %q", project.Name, tt.wantName)
+			}
+			if project.Runtime.Version != tt.wantVersion {
+				t.Errorf("version = %q, want %q", project.Runtime.Version, tt.wantVersion)
+			}
+		})
+	}
+}
+
 func TestRegistry_CppSlnNotDotNet(t *testing.T) {
 	r := NewRegistry()
 
@@ -298,6 +1326,295 @@ EndProject`
 	}
 }
 
+func TestDetectors_DependencyCount(t *testing.T) {
+	tests := []struct {
+		name     string
+		detector Detector
+		manifest string
+		content  string
+		want     int
+	}{
+		{
+			name:     "go.mod require block and single require",
+			detector: NewGoDetector(),
+			manifest: "go.mod",
+			content: `module example.com/app
+
+go 1.22
+
+require github.com/spf13/cobra v1.8.0
+
+require (
+	gopkg.in/yaml.v3 v3.0.1
+	github.com/BurntSushi/toml v1.3.2
+	github.com/inconshreveable/mousetrap v1.1.0 // indirect
+)
+`,
+			want: 3,
+		},
+		{
+			name:     "package.json dependencies and devDependencies",
+			detector: NewJavaScriptDetector(),
+			manifest: "package.json",
+			content:  `{"name": "web", "dependencies": {"react": "^18.0.0", "react-dom": "^18.0.0"}, "devDependencies": {"vite": "^5.0.0"}}`,
+			want:     3,
+		},
+		{
+			name:     "Cargo.toml dependencies table",
+			detector: NewRustDetector(),
+			manifest: "Cargo.toml",
+			content: `[package]
+name = "cli"
+
+[dependencies]
+serde = { version = "1", features = ["derive"] }
+clap = "4"
+
+[dev-dependencies]
+tempfile = "3"
+`,
+			want: 2,
+		},
+		{
+			name:     "pyproject.toml project dependencies",
+			detector: NewPythonDetector(),
+			manifest: "pyproject.toml",
+			content: `[project]
+name = "svc"
+dependencies = ["fastapi>=0.110", "uvicorn", "pydantic"]
+`,
+			want: 3,
+		},
+		{
+			name:     "pyproject.toml poetry dependencies exclude python",
+			detector: NewPythonDetector(),
+			manifest: "pyproject.toml",
+			content: `[tool.poetry]
+name = "svc"
+
+[tool.poetry.dependencies]
+python = "^3.11"
+requests = "^2.31"
+click = { version = "^8.1", optional = true }
+`,
+			want: 2,
+		},
+		{
+			name:     "requirements.txt skips comments and options",
+			detector: NewPythonDetector(),
+			manifest: "requirements.txt",
+			content: `# runtime
+-r base.txt
+requests==2.31.0
+
+flask>=3.0
+`,
+			want: 2,
+		},
+		{
+			name:     "pubspec.yaml dependencies and dev_dependencies",
+			detector: NewDartDetector(),
+			manifest: "pubspec.yaml",
+			content: `name: app
+dependencies:
+  http: ^1.1.0
+  flutter:
+    sdk: flutter
+dev_dependencies:
+  test: ^1.24.0
+`,
+			want: 3,
+		},
+		{
+			name:     "pom.xml dependencies",
+			detector: NewJavaDetector(),
+			manifest: "pom.xml",
+			content: `<project>
+  <artifactId>svc</artifactId>
+  <dependencies>
+    <dependency><groupId>org.slf4j</groupId><artifactId>slf4j-api</artifactId></dependency>
+    <dependency><groupId>junit</groupId><artifactId>junit</artifactId></dependency>
+  </dependencies>
+</project>`,
+			want: 2,
+		},
+		{
+			name:     "build.gradle.kts dependencies",
+			detector: NewJavaDetector(),
+			manifest: "build.gradle.kts",
+			content: `plugins { java }
+
+dependencies {
+    implementation("com.google.guava:guava:33.0.0-jre")
+    api("org.slf4j:slf4j-api:2.0.9")
+    testImplementation("org.junit.jupiter:junit-jupiter:5.10.0")
+}
+`,
+			want: 3,
+		},
+		{
+			name:     "csproj package references",
+			detector: NewDotNetDetector(),
+			manifest: "Api.csproj",
+			content: `<Project Sdk="Microsoft.NET.Sdk">
+  <PropertyGroup><TargetFramework>net8.0</TargetFramework></PropertyGroup>
+  <ItemGroup>
+    <PackageReference Include="Serilog" Version="3.1.1" />
+    <PackageReference Include="Dapper" Version="2.1.24" />
+  </ItemGroup>
+  <ItemGroup>
+    <PackageReference Include="xunit" Version="2.6.2" />
+  </ItemGroup>
+</Project>`,
+			want: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			project, err := tt.detector.Detect(tt.manifest, []byte(tt.content))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if project == nil {
+				t.Fatal("expected project, got nil")
+			}
+			if project.DependencyCount != tt.want {
+				t.Errorf("DependencyCount = %d, want %d", project.DependencyCount, tt.want)
+			}
+		})
+	}
+}
+
+func TestHaskellDetector_Cabal(t *testing.T) {
+	d := NewHaskellDetector()
+
+	content := `cabal-version:      3.0
+name:               parser
+version:            0.1.0.0
+tested-with:        GHC == 9.6.3, GHC == 9.4.7
+
+library
+    exposed-modules:  Parser
+    build-depends:    base >=4.17 && <5
+`
+
+	project, err := d.Detect(filepath.Join(t.TempDir(), "parser.cabal"), []byte(content))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if project == nil {
+		t.Fatal("expected project, got nil")
+	}
+	if project.Name != "parser" {
+		t.Errorf("name = %q, want %q", project.Name, "parser")
+	}
+	if project.Runtime.Version != "9.6.3" {
+		t.Errorf("version = %q, want %q", project.Runtime.Version, "9.6.3")
+	}
+	if project.Runtime.Type != models.RuntimeHaskell {
+		t.Errorf("type = %q, want %q", project.Runtime.Type, models.RuntimeHaskell)
+	}
+}
+
+func TestHaskellDetector_Hpack(t *testing.T) {
+	d := NewHaskellDetector()
+
+	content := `name: web-server
+version: 0.2.0
+
+dependencies:
+  - base >= 4.7 && < 5
+  - text
+  - warp
+
+executables:
+  web-server:
+    main: Main.hs
+    source-dirs: app
+`
+
+	project, err := d.Detect(filepath.Join(t.TempDir(), "package.yaml"), []byte(content))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if project == nil {
+		t.Fatal("expected project, got nil")
+	}
+	if project.Name != "web-server" {
+		t.Errorf("name = %q, want %q", project.Name, "web-server")
+	}
+	if project.ManifestFile != "package.yaml" {
+		t.Errorf("manifest = %q, want %q", project.ManifestFile, "package.yaml")
+	}
+	if project.DependencyCount != 3 {
+		t.Errorf("DependencyCount = %d, want 3", project.DependencyCount)
+	}
+}
+
+func TestHaskellDetector_CabalDeferredToHpack(t *testing.T) {
+	d := NewHaskellDetector()
+	root := t.TempDir()
+
+	writeTestFiles(t, root, map[string]string{
+		"package.yaml": "name: app\nlibrary:\n  source-dirs: src\n",
+	})
+
+	project, err := d.Detect(filepath.Join(root, "app.cabal"), []byte("cabal-version: 2.2\nname: app\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if project != nil {
+		t.Errorf("expected .cabal next to package.yaml to be skipped, got %+v", project)
+	}
+}
+
+func TestHaskellDetector_UnrelatedPackageYaml(t *testing.T) {
+	d := NewHaskellDetector()
+
+	project, err := d.Detect("package.yaml", []byte("name: chart\nversion: 1.0.0\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if project != nil {
+		t.Errorf("expected non-hpack package.yaml to be ignored, got %+v", project)
+	}
+}
+
+func TestPHPDetector(t *testing.T) {
+	d := NewPHPDetector()
+
+	content := `{
+  "name": "acme/shop",
+  "require": {
+    "php": "^8.2",
+    "ext-mbstring": "*",
+    "laravel/framework": "^11.0",
+    "guzzlehttp/guzzle": "^7.8"
+  },
+  "require-dev": {
+    "phpunit/phpunit": "^11.0"
+  }
+}`
+
+	project, err := d.Detect("composer.json", []byte(content))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if project == nil {
+		t.Fatal("expected project, got nil")
+	}
+	if project.Name != "shop" {
+		t.Errorf("name = %q, want %q", project.Name, "shop")
+	}
+	if project.Runtime.Version != "8.2+" {
+		t.Errorf("version = %q, want %q", project.Runtime.Version, "8.2+")
+	}
+	if project.DependencyCount != 3 {
+		t.Errorf("DependencyCount = %d, want 3", project.DependencyCount)
+	}
+}
+
 func TestRegistry(t *testing.T) {
 	r := NewRegistry()
 
@@ -307,7 +1624,7 @@ func TestRegistry(t *testing.T) {
 	}
 
 	// Check that common manifest files are included
-	expected := []string{"go.mod", "package.json", "Cargo.toml", "pyproject.toml", "pom.xml", "pubspec.yaml"}
+	expected := []string{"go.mod", "package.json", "Cargo.toml", "pyproject.toml", "pom.xml", "pubspec.yaml", "*.nimble", "DESCRIPTION", "Package.swift", "Podfile"}
 	for _, exp := range expected {
 		found := false
 		for _, p := range patterns {
@@ -321,3 +1638,199 @@ func TestRegistry(t *testing.T) {
 		}
 	}
 }
+
+func TestDetectors_PackageManager(t *testing.T) {
+	tests := []struct {
+		name     string
+		detector Detector
+		manifest string
+		files    map[string]string
+		want     string
+	}{
+		{
+			name:     "package-lock.json",
+			detector: NewJavaScriptDetector(),
+			manifest: "package.json",
+			files:    map[string]string{"package.json": `{"name": "web"}`, "package-lock.json": "{}"},
+			want:     "npm",
+		},
+		{
+			name:     "yarn.lock",
+			detector: NewJavaScriptDetector(),
+			manifest: "package.json",
+			files:    map[string]string{"package.json": `{"name": "web"}`, "yarn.lock": ""},
+			want:     "yarn",
+		},
+		{
+			name:     "pnpm-lock.yaml",
+			detector: NewJavaScriptDetector(),
+			manifest: "package.json",
+			files:    map[string]string{"package.json": `{"name": "web"}`, "pnpm-lock.yaml": "lockfileVersion: '9.0'\n"},
+			want:     "pnpm",
+		},
+		{
+			name:     "Corepack packageManager field without a lockfile",
+			detector: NewJavaScriptDetector(),
+			manifest: "package.json",
+			files:    map[string]string{"package.json": `{"name": "web", "packageManager": "yarn@4.1.0"}`},
+			want:     "yarn",
+		},
+		{
+			name:     "no lockfile",
+			detector: NewJavaScriptDetector(),
+			manifest: "package.json",
+			files:    map[string]string{"package.json": `{"name": "web"}`},
+			want:     "",
+		},
+		{
+			name:     "poetry.lock",
+			detector: NewPythonDetector(),
+			manifest: "pyproject.toml",
+			files:    map[string]string{"pyproject.toml": "[project]\nname = \"svc\"\n", "poetry.lock": ""},
+			want:     "poetry",
+		},
+		{
+			name:     "tool.poetry table",
+			detector: NewPythonDetector(),
+			manifest: "pyproject.toml",
+			files:    map[string]string{"pyproject.toml": "[tool.poetry]\nname = \"svc\"\n"},
+			want:     "poetry",
+		},
+		{
+			name:     "pdm.lock",
+			detector: NewPythonDetector(),
+			manifest: "pyproject.toml",
+			files:    map[string]string{"pyproject.toml": "[project]\nname = \"svc\"\n", "pdm.lock": ""},
+			want:     "pdm",
+		},
+		{
+			name:     "plain pyproject.toml",
+			detector: NewPythonDetector(),
+			manifest: "pyproject.toml",
+			files:    map[string]string{"pyproject.toml": "[project]\nname = \"svc\"\n"},
+			want:     "pip",
+		},
+		{
+			name:     "requirements.txt",
+			detector: NewPythonDetector(),
+			manifest: "requirements.txt",
+			files:    map[string]string{"requirements.txt": "requests==2.31.0\n"},
+			want:     "pip",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			writeTestFiles(t, root, tt.files)
+
+			manifestPath := filepath.Join(root, tt.manifest)
+			project, err := tt.detector.Detect(manifestPath, []byte(tt.files[tt.manifest]))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if project == nil {
+				t.Fatal("expected project, got nil")
+			}
+			if project.PackageManager != tt.want {
+				t.Errorf("PackageManager = %q, want %q", project.PackageManager, tt.want)
+			}
+		})
+	}
+}
+
+func TestJavaDetector_GradleSettingsModules(t *testing.T) {
+	root := t.TempDir()
+	settings := `rootProject.name = "platform"
+
+include("app")
+include(
+    ":services:auth",
+    ':services:billing',
+)
+// include 'disabled'
+includeBuild("build-logic")
+`
+	writeTestFiles(t, root, map[string]string{"settings.gradle.kts": settings})
+
+	project, err := NewJavaDetector().Detect(filepath.Join(root, "settings.gradle.kts"), []byte(settings))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if project == nil {
+		t.Fatal("expected project, got nil")
+	}
+	if project.Name != "platform" {
+		t.Errorf("name = %q, want %q", project.Name, "platform")
+	}
+
+	want := []string{"app", "services/auth", "services/billing"}
+	if len(project.Children) != len(want) {
+		t.Fatalf("got %d modules, want %d", len(project.Children), len(want))
+	}
+	for i, child := range project.Children {
+		if got := filepath.ToSlash(child.Path); got != filepath.ToSlash(filepath.Join(root, want[i])) {
+			t.Errorf("module %d path = %q, want %q", i, got, want[i])
+		}
+		if child.Runtime.Type != models.RuntimeJava {
+			t.Errorf("module %d runtime = %q, want Java", i, child.Runtime.Type)
+		}
+	}
+
+	// A build script next to the settings file is detected instead
+	writeTestFiles(t, root, map[string]string{"build.gradle.kts": "plugins { java }\n"})
+	if project, _ := NewJavaDetector().Detect(filepath.Join(root, "settings.gradle.kts"), []byte(settings)); project != nil {
+		t.Error("expected settings file to defer to the build script")
+	}
+}
+
+func TestDotNetDetector_SQLProject(t *testing.T) {
+	content := `<?xml version="1.0" encoding="utf-8"?>
+<Project DefaultTargets="Build" xmlns="http://schemas.microsoft.com/developer/msbuild/2003" ToolsVersion="4.0">
+  <PropertyGroup>
+    <Name>Inventory.Database</Name>
+    <DSP>Microsoft.Data.Tools.Schema.Sql.Sql160DatabaseSchemaProvider</DSP>
+    <TargetFrameworkVersion>v4.7.2</TargetFrameworkVersion>
+  </PropertyGroup>
+</Project>`
+
+	project, err := NewRegistry().DetectProject(filepath.Join("db", "Inventory.sqlproj"), []byte(content))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if project == nil {
+		t.Fatal("expected project, got nil")
+	}
+	if project.Runtime.Type == models.RuntimeDotNet {
+		t.Fatal("a .sqlproj must not be labeled as a .NET project")
+	}
+	if project.Runtime.Type != models.RuntimeSQL {
+		t.Errorf("runtime = %q, want %q", project.Runtime.Type, models.RuntimeSQL)
+	}
+	if project.Name != "Inventory.Database" {
+		t.Errorf("name = %q, want %q", project.Name, "Inventory.Database")
+	}
+	if project.Runtime.Version != "2022" {
+		t.Errorf("version = %q, want %q", project.Runtime.Version, "2022")
+	}
+}
+
+func TestDotNetDetector_IgnoresNonCodeProjects(t *testing.T) {
+	content := `<Project Sdk="Microsoft.NET.Sdk"><PropertyGroup><TargetFramework>net8.0</TargetFramework></PropertyGroup></Project>`
+
+	registry := NewRegistry()
+	for _, manifest := range []string{"Package.wapproj", "Client.esproj"} {
+		// Discovery never hands these to a detector
+		if registry.ManifestPriority(manifest) != math.MaxInt {
+			t.Errorf("%s matches a manifest pattern", manifest)
+		}
+
+		project, err := registry.DetectProject(manifest, []byte(content))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", manifest, err)
+		}
+		if project != nil {
+			t.Errorf("%s: expected no project, got %s", manifest, project.Runtime.Type)
+		}
+	}
+}
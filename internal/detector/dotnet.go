@@ -1,7 +1,10 @@
 package detector
 
 import (
+	"encoding/json"
 	"encoding/xml"
+	"fmt"
+	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
@@ -24,26 +27,39 @@ func (d *dotNetDetector) RuntimeType() models.RuntimeType {
 }
 
 func (d *dotNetDetector) ManifestFiles() []string {
-	return []string{"*.csproj", "*.sln", "*.fsproj", "*.vbproj"}
+	return []string{"*.csproj", "*.sln", "*.fsproj", "*.vbproj", "*.sqlproj"}
 }
 
 func (d *dotNetDetector) Detect(manifestPath string, content []byte) (*models.Project, error) {
+	project, _, err := d.DetectWithWarnings(manifestPath, content)
+	return project, err
+}
+
+func (d *dotNetDetector) DetectWithWarnings(manifestPath string, content []byte) (*models.Project, []string, error) {
 	ext := strings.ToLower(filepath.Ext(manifestPath))
 
 	switch ext {
 	case ".csproj", ".fsproj", ".vbproj":
 		return d.detectProjectFile(manifestPath, content)
 	case ".sln":
-		return d.detectSolutionFile(manifestPath, content)
+		project, err := d.detectSolutionFile(manifestPath, content)
+		return project, nil, err
+	case ".sqlproj":
+		project, err := d.detectSQLProject(manifestPath, content)
+		return project, nil, err
 	}
 
-	return nil, nil
+	// Other MSBuild projects, such as packaging (.wapproj) and JavaScript
+	// (.esproj) projects, contain no .NET code; JavaScript is detected from
+	// package.json
+	return nil, nil, nil
 }
 
 // csprojFile represents the structure of a .csproj XML file.
 type csprojFile struct {
 	XMLName        xml.Name        `xml:"Project"`
 	PropertyGroups []propertyGroup `xml:"PropertyGroup"`
+	ItemGroups     []itemGroup     `xml:"ItemGroup"`
 }
 
 type propertyGroup struct {
@@ -51,35 +67,127 @@ type propertyGroup struct {
 	TargetFrameworks string `xml:"TargetFrameworks"`
 }
 
-func (d *dotNetDetector) detectProjectFile(manifestPath string, content []byte) (*models.Project, error) {
+type itemGroup struct {
+	PackageReferences []struct {
+		Include string `xml:"Include,attr"`
+	} `xml:"PackageReference"`
+}
+
+func (d *dotNetDetector) detectProjectFile(manifestPath string, content []byte) (*models.Project, []string, error) {
 	// Check if this is a .NET project file
 	if !strings.Contains(string(content), "<Project") {
-		return nil, nil
+		return nil, nil, nil
 	}
 
 	var proj csprojFile
 	if err := xml.Unmarshal(content, &proj); err != nil {
 		// If XML parsing fails, still detect as .NET project but without version
-		return d.createProject(manifestPath, ""), nil
+		warning := fmt.Sprintf("%s XML parse failed, version unknown: %v", strings.TrimPrefix(filepath.Ext(manifestPath), "."), err)
+		return d.createProject(manifestPath, ""), []string{warning}, nil
 	}
 
-	version := ""
-	for _, pg := range proj.PropertyGroups {
+	// The framework may be shared through Directory.Build.props; failing
+	// that, fall back to the SDK pinned in global.json
+	version := targetFrameworkVersion(proj.PropertyGroups)
+	if version == "" {
+		version = buildPropsVersion(filepath.Dir(manifestPath))
+	}
+	if version == "" {
+		version = globalJSONVersion(filepath.Dir(manifestPath))
+	}
+
+	project := d.createProject(manifestPath, version)
+	for _, ig := range proj.ItemGroups {
+		project.DependencyCount += len(ig.PackageReferences)
+	}
+	return project, nil, nil
+}
+
+// targetFrameworkVersion returns the version of the first TargetFramework,
+// or the first of the TargetFrameworks, declared in groups.
+func targetFrameworkVersion(groups []propertyGroup) string {
+	for _, pg := range groups {
 		if pg.TargetFramework != "" {
-			version = extractDotNetVersion(pg.TargetFramework)
-			break
+			return extractDotNetVersion(pg.TargetFramework)
 		}
 		if pg.TargetFrameworks != "" {
 			// Multiple frameworks, take the first one
 			frameworks := strings.Split(pg.TargetFrameworks, ";")
-			if len(frameworks) > 0 {
-				version = extractDotNetVersion(frameworks[0])
-			}
-			break
+			return extractDotNetVersion(frameworks[0])
 		}
 	}
+	return ""
+}
+
+// buildPropsVersion returns the target framework version declared by the
+// Directory.Build.props nearest to dir, searching dir and its ancestors.
+// Props files without a TargetFramework are skipped, since nested props
+// usually import their parent's. It returns "" if none declares one.
+func buildPropsVersion(dir string) string {
+	for _, current := range ancestorDirs(dir) {
+		content, err := os.ReadFile(filepath.Join(current, "Directory.Build.props"))
+		if err != nil {
+			continue
+		}
+		var props csprojFile
+		if err := xml.Unmarshal(decodeManifest(content), &props); err != nil {
+			continue
+		}
+		if version := targetFrameworkVersion(props.PropertyGroups); version != "" {
+			return version
+		}
+	}
+	return ""
+}
+
+// ancestorDirs returns the absolute path of dir followed by those of its
+// ancestors, up to the filesystem root.
+func ancestorDirs(dir string) []string {
+	current, err := filepath.Abs(dir)
+	if err != nil {
+		return nil
+	}
+
+	var dirs []string
+	for {
+		dirs = append(dirs, current)
+		parent := filepath.Dir(current)
+		if parent == current {
+			return dirs
+		}
+		current = parent
+	}
+}
 
-	return d.createProject(manifestPath, version), nil
+// globalJSON represents the parts of a global.json file used for detection.
+type globalJSON struct {
+	SDK struct {
+		Version string `json:"version"`
+	} `json:"sdk"`
+}
+
+// globalJSONVersion returns the .NET version of the SDK pinned by the
+// global.json nearest to dir, which is found the way the dotnet CLI finds
+// it: in dir or its closest ancestor. SDK 8.0.100 yields "8.0". It returns
+// "" if there is no global.json or it pins no SDK version.
+func globalJSONVersion(dir string) string {
+	for _, current := range ancestorDirs(dir) {
+		content, err := os.ReadFile(filepath.Join(current, "global.json"))
+		if err != nil {
+			continue
+		}
+
+		var global globalJSON
+		if err := json.Unmarshal(decodeManifest(content), &global); err != nil || global.SDK.Version == "" {
+			return ""
+		}
+		parts := strings.SplitN(global.SDK.Version, ".", 3)
+		if len(parts) < 2 {
+			return parts[0]
+		}
+		return parts[0] + "." + parts[1]
+	}
+	return ""
 }
 
 func (d *dotNetDetector) detectSolutionFile(manifestPath string, content []byte) (*models.Project, error) {
@@ -103,6 +211,66 @@ func (d *dotNetDetector) detectSolutionFile(manifestPath string, content []byte)
 	return d.createProject(manifestPath, ""), nil
 }
 
+// sqlProjFile represents the parts of a .sqlproj file used for detection.
+type sqlProjFile struct {
+	XMLName        xml.Name `xml:"Project"`
+	PropertyGroups []struct {
+		Name string `xml:"Name"`
+		DSP  string `xml:"DSP"`
+	} `xml:"PropertyGroup"`
+}
+
+// sqlServerVersions maps database schema provider versions to SQL Server
+// releases.
+var sqlServerVersions = map[string]string{
+	"100": "2008",
+	"110": "2012",
+	"120": "2014",
+	"130": "2016",
+	"140": "2017",
+	"150": "2019",
+	"160": "2022",
+}
+
+// sqlSchemaProviderRe extracts the target from a schema provider such as
+// Microsoft.Data.Tools.Schema.Sql.Sql160DatabaseSchemaProvider.
+var sqlSchemaProviderRe = regexp.MustCompile(`\.Sql(\w+?)DatabaseSchemaProvider$`)
+
+// detectSQLProject detects a SQL Server Database Project. These are not
+// .NET code, so they get their own runtime and count .sql files.
+func (d *dotNetDetector) detectSQLProject(manifestPath string, content []byte) (*models.Project, error) {
+	if !strings.Contains(string(content), "<Project") {
+		return nil, nil
+	}
+
+	name := strings.TrimSuffix(filepath.Base(manifestPath), filepath.Ext(manifestPath))
+	version := ""
+
+	var proj sqlProjFile
+	if err := xml.Unmarshal(content, &proj); err == nil {
+		for _, pg := range proj.PropertyGroups {
+			if pg.Name != "" {
+				name = pg.Name
+			}
+			if matches := sqlSchemaProviderRe.FindStringSubmatch(pg.DSP); len(matches) > 1 && version == "" {
+				version = matches[1]
+				if release, ok := sqlServerVersions[version]; ok {
+					version = release
+				}
+			}
+		}
+	}
+
+	return &models.Project{
+		Name:           name,
+		Path:           filepath.Dir(manifestPath),
+		Runtime:        models.Runtime{Type: models.RuntimeSQL, Version: version},
+		ManifestFile:   filepath.Base(manifestPath),
+		SourcePaths:    []string{"."},
+		SrcIgnorePaths: []string{"bin", "obj"},
+	}, nil
+}
+
 func (d *dotNetDetector) createProject(manifestPath, version string) *models.Project {
 	dir := filepath.Dir(manifestPath)
 	name := filepath.Base(dir)
@@ -61,11 +61,47 @@ func (d *goDetector) Detect(manifestPath string, content []byte) (*models.Projec
 	}
 
 	return &models.Project{
-		Name:           name,
-		Path:           dir,
-		Runtime:        models.Runtime{Type: models.RuntimeGo, Version: version},
-		ManifestFile:   "go.mod",
-		SourcePaths:    []string{"."},
-		SrcIgnorePaths: []string{"vendor"},
+		Name:            name,
+		Path:            dir,
+		Runtime:         models.Runtime{Type: models.RuntimeGo, Version: version},
+		ManifestFile:    "go.mod",
+		SourcePaths:     []string{"."},
+		SrcIgnorePaths:  []string{"vendor"},
+		DependencyCount: countGoRequires(contentStr),
 	}, nil
 }
+
+// countGoRequires counts the direct requirements in a go.mod file, both in
+// single-line require directives and require blocks. Requirements marked
+// "// indirect" are not counted.
+func countGoRequires(content string) int {
+	count := 0
+	inBlock := false
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if inBlock {
+			if strings.HasPrefix(line, ")") {
+				inBlock = false
+				continue
+			}
+			if line != "" && !strings.HasPrefix(line, "//") && !strings.Contains(line, "// indirect") {
+				count++
+			}
+			continue
+		}
+
+		rest, ok := strings.CutPrefix(line, "require")
+		if !ok || (rest != "" && rest[0] != ' ' && rest[0] != '\t' && rest[0] != '(') {
+			continue
+		}
+		rest = strings.TrimSpace(rest)
+		if strings.HasPrefix(rest, "(") {
+			inBlock = true
+			continue
+		}
+		if rest != "" && !strings.Contains(rest, "// indirect") {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,160 @@
+package detector
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	"repoctr/pkg/models"
+)
+
+type haskellDetector struct{}
+
+func NewHaskellDetector() Detector {
+	return &haskellDetector{}
+}
+
+func (d *haskellDetector) Name() string {
+	return "Haskell"
+}
+
+func (d *haskellDetector) RuntimeType() models.RuntimeType {
+	return models.RuntimeHaskell
+}
+
+func (d *haskellDetector) ManifestFiles() []string {
+	return []string{"*.cabal", "package.yaml", "stack.yaml"}
+}
+
+func (d *haskellDetector) Detect(manifestPath string, content []byte) (*models.Project, error) {
+	filename := filepath.Base(manifestPath)
+
+	switch {
+	case strings.HasSuffix(filename, ".cabal"):
+		return d.detectCabal(manifestPath, content)
+	case filename == "package.yaml":
+		return d.detectHpack(manifestPath, content)
+	case filename == "stack.yaml":
+		return d.detectStack(manifestPath, content)
+	}
+
+	return nil, nil
+}
+
+var (
+	cabalNameRe       = regexp.MustCompile(`(?im)^name\s*:\s*(\S+)`)
+	cabalVersionRe    = regexp.MustCompile(`(?im)^cabal-version\s*:\s*[>=^]*\s*(\d+(?:\.\d+)*)`)
+	cabalTestedWithRe = regexp.MustCompile(`(?im)^tested-with\s*:\s*(.+)$`)
+	ghcVersionRe      = regexp.MustCompile(`(?i)GHC\s*[=<>^]*\s*(\d+(?:\.\d+)*)`)
+)
+
+func (d *haskellDetector) detectCabal(manifestPath string, content []byte) (*models.Project, error) {
+	// hpack generates the .cabal file from package.yaml; let package.yaml
+	// describe the project so it is not reported twice
+	if fileExists(filepath.Join(filepath.Dir(manifestPath), "package.yaml")) {
+		return nil, nil
+	}
+
+	contentStr := string(content)
+
+	name := ""
+	if matches := cabalNameRe.FindStringSubmatch(contentStr); len(matches) > 1 {
+		name = matches[1]
+	}
+
+	// Prefer the GHC version from tested-with, fall back to cabal-version
+	version := ""
+	if matches := cabalTestedWithRe.FindStringSubmatch(contentStr); len(matches) > 1 {
+		if ghc := ghcVersionRe.FindStringSubmatch(matches[1]); len(ghc) > 1 {
+			version = ghc[1]
+		}
+	}
+	if version == "" {
+		if matches := cabalVersionRe.FindStringSubmatch(contentStr); len(matches) > 1 {
+			version = matches[1]
+		}
+	}
+
+	if name == "" && version == "" {
+		return nil, nil
+	}
+
+	return d.createProject(manifestPath, name, version), nil
+}
+
+// hpackYaml represents the fields of an hpack package.yaml file used for
+// detection.
+type hpackYaml struct {
+	Name         string    `yaml:"name"`
+	Dependencies yaml.Node `yaml:"dependencies"`
+	Library      yaml.Node `yaml:"library"`
+	Executables  yaml.Node `yaml:"executables"`
+	Executable   yaml.Node `yaml:"executable"`
+}
+
+func (d *haskellDetector) detectHpack(manifestPath string, content []byte) (*models.Project, error) {
+	var pkg hpackYaml
+	if err := yaml.Unmarshal(content, &pkg); err != nil {
+		return nil, nil
+	}
+
+	// package.yaml is a generic name; require a name plus at least one
+	// hpack section before claiming it
+	if pkg.Name == "" {
+		return nil, nil
+	}
+	if pkg.Dependencies.IsZero() && pkg.Library.IsZero() && pkg.Executables.IsZero() && pkg.Executable.IsZero() {
+		return nil, nil
+	}
+
+	project := d.createProject(manifestPath, pkg.Name, "")
+	if pkg.Dependencies.Kind == yaml.SequenceNode || pkg.Dependencies.Kind == yaml.MappingNode {
+		project.DependencyCount = len(pkg.Dependencies.Content)
+		if pkg.Dependencies.Kind == yaml.MappingNode {
+			project.DependencyCount /= 2
+		}
+	}
+	return project, nil
+}
+
+func (d *haskellDetector) detectStack(manifestPath string, content []byte) (*models.Project, error) {
+	contentStr := string(content)
+	if !strings.Contains(contentStr, "resolver:") && !strings.Contains(contentStr, "snapshot:") {
+		return nil, nil
+	}
+
+	// A package manifest next to stack.yaml describes the project better
+	dir := filepath.Dir(manifestPath)
+	if fileExists(filepath.Join(dir, "package.yaml")) {
+		return nil, nil
+	}
+	if matches, _ := filepath.Glob(filepath.Join(dir, "*.cabal")); len(matches) > 0 {
+		return nil, nil
+	}
+
+	return d.createProject(manifestPath, "", ""), nil
+}
+
+func (d *haskellDetector) createProject(manifestPath, name, version string) *models.Project {
+	dir := filepath.Dir(manifestPath)
+	if name == "" {
+		name = filepath.Base(dir)
+	}
+
+	return &models.Project{
+		Name:           name,
+		Path:           dir,
+		Runtime:        models.Runtime{Type: models.RuntimeHaskell, Version: version},
+		ManifestFile:   filepath.Base(manifestPath),
+		SourcePaths:    []string{"."},
+		SrcIgnorePaths: []string{".stack-work", "dist-newstyle"},
+	}
+}
+
+// fileExists reports whether path exists and is a regular file.
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
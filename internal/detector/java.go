@@ -2,6 +2,8 @@ package detector
 
 import (
 	"encoding/xml"
+	"os"
+	"path"
 	"path/filepath"
 	"regexp"
 	"strings"
@@ -24,7 +26,7 @@ func (d *javaDetector) RuntimeType() models.RuntimeType {
 }
 
 func (d *javaDetector) ManifestFiles() []string {
-	return []string{"pom.xml", "build.gradle", "build.gradle.kts"}
+	return []string{"pom.xml", "build.gradle", "build.gradle.kts", "settings.gradle", "settings.gradle.kts"}
 }
 
 func (d *javaDetector) Detect(manifestPath string, content []byte) (*models.Project, error) {
@@ -35,6 +37,8 @@ func (d *javaDetector) Detect(manifestPath string, content []byte) (*models.Proj
 		return d.detectPomXml(manifestPath, content)
 	case "build.gradle", "build.gradle.kts":
 		return d.detectGradle(manifestPath, content)
+	case "settings.gradle", "settings.gradle.kts":
+		return d.detectGradleSettings(manifestPath, content)
 	}
 
 	return nil, nil
@@ -49,6 +53,10 @@ type pomXml struct {
 		JavaVersion         string `xml:"java.version"`
 		MavenCompilerSource string `xml:"maven.compiler.source"`
 	} `xml:"properties"`
+	Dependencies []struct {
+		ArtifactID string `xml:"artifactId"`
+	} `xml:"dependencies>dependency"`
+	Modules []string `xml:"modules>module"`
 }
 
 func (d *javaDetector) detectPomXml(manifestPath string, content []byte) (*models.Project, error) {
@@ -73,15 +81,60 @@ func (d *javaDetector) detectPomXml(manifestPath string, content []byte) (*model
 		version = pom.Properties.MavenCompilerSource
 	}
 
-	return d.createProject(manifestPath, name, version), nil
+	project := d.createProject(manifestPath, name, version)
+	project.DependencyCount = len(pom.Dependencies)
+	project.Children = d.declaredModules(manifestPath, pomModules(pom.Modules), version)
+	return project, nil
 }
 
+// pomModules returns the module directories listed in a POM's <modules>
+// element, relative to the POM directory. A module may also name the POM
+// file itself, e.g. <module>core/pom.xml</module>.
+func pomModules(declared []string) []string {
+	var modules []string
+	seen := make(map[string]bool)
+
+	for _, module := range declared {
+		module = strings.TrimSuffix(strings.TrimSpace(module), "/")
+		if strings.HasSuffix(module, ".xml") {
+			module = path.Dir(module)
+		}
+		module = path.Clean(module)
+		if module == "." || seen[module] {
+			continue
+		}
+		seen[module] = true
+		modules = append(modules, module)
+	}
+
+	return modules
+}
+
+// gradleDependencyRe matches dependency declarations such as
+// implementation 'g:a:v' or testImplementation("g:a:v").
+var gradleDependencyRe = regexp.MustCompile(`(?m)^\s*(implementation|api|compileOnly|runtimeOnly|testImplementation|testCompileOnly|testRuntimeOnly|annotationProcessor|compile|testCompile)\s*\(?\s*['"]`)
+
+// gradleIncludeRe matches include directives in a Gradle settings file,
+// e.g. include 'app', 'lib' or include(":services:auth", ":services:billing").
+// The first group holds the quoted module paths.
+var gradleIncludeRe = regexp.MustCompile(`(?m)^\s*include\s*\(?\s*((?:['"][^'"\n]+['"]\s*,?\s*)+)`)
+
+// gradleQuotedRe matches a single- or double-quoted string.
+var gradleQuotedRe = regexp.MustCompile(`['"]([^'"\n]+)['"]`)
+
+// gradleRootNameRe matches rootProject.name = 'name' in a settings file.
+var gradleRootNameRe = regexp.MustCompile(`rootProject\.name\s*=\s*['"]([^'"\n]+)['"]`)
+
 func (d *javaDetector) detectGradle(manifestPath string, content []byte) (*models.Project, error) {
 	contentStr := string(content)
+	dir := filepath.Dir(manifestPath)
+	settingsPath, settings := readGradleSettings(dir)
+	modules := parseGradleIncludes(settings)
 
-	// Check for common Gradle patterns
+	// Check for common Gradle patterns. Multi-module roots often only
+	// configure their subprojects, so included modules count as well.
 	if !strings.Contains(contentStr, "plugins") && !strings.Contains(contentStr, "apply plugin") &&
-		!strings.Contains(contentStr, "dependencies") {
+		!strings.Contains(contentStr, "dependencies") && len(modules) == 0 {
 		return nil, nil
 	}
 
@@ -106,7 +159,167 @@ func (d *javaDetector) detectGradle(manifestPath string, content []byte) (*model
 		version = matches[1]
 	}
 
-	return d.createProject(manifestPath, "", version), nil
+	project := d.createProject(manifestPath, gradleRootName(settings), version)
+	project.DependencyCount = len(gradleDependencyRe.FindAllString(contentStr, -1))
+	project.Children = d.declaredModules(settingsPath, modules, version)
+	classifyKotlin(project, contentStr)
+	return project, nil
+}
+
+// kotlinPluginRe matches a Gradle build script applying a Kotlin plugin:
+// id("org.jetbrains.kotlin.jvm") or kotlin("multiplatform"), each with an
+// optional version, the legacy apply plugin: 'kotlin', or a version catalog
+// alias such as alias(libs.plugins.kotlin.jvm).
+var kotlinPluginRe = regexp.MustCompile(`org\.jetbrains\.kotlin\.[a-z]+['"]\s*\)?(?:\s+version\s+['"]([^'"]+)['"])?|` +
+	`\bkotlin\s*\(\s*"[a-z]+"\s*\)(?:\s+version\s+"([^"]+)")?|` +
+	`apply\s+plugin\s*:\s*['"]kotlin[a-z-]*['"]|` +
+	`libs\.plugins\.kotlin`)
+
+// classifyKotlin turns a Gradle project into a Kotlin one when its build
+// script applies a Kotlin plugin and its sources are mostly Kotlin. The
+// runtime version is the plugin version, when the script declares it.
+func classifyKotlin(project *models.Project, buildScript string) {
+	matches := kotlinPluginRe.FindStringSubmatch(buildScript)
+	if matches == nil || !hasMostlyKotlinSources(project.Path) {
+		return
+	}
+
+	version := matches[1]
+	if version == "" {
+		version = matches[2]
+	}
+	project.Runtime = models.Runtime{Type: models.RuntimeKotlin, Version: version}
+	project.SourcePaths = []string{"src"}
+}
+
+// kotlinScanMaxFiles bounds how many source files hasMostlyKotlinSources
+// looks at, keeping detection cheap on large trees.
+const kotlinScanMaxFiles = 1000
+
+// kotlinScanSkipDirs are directories that hold build output rather than
+// the project's own sources.
+var kotlinScanSkipDirs = map[string]bool{
+	"build": true,
+	"out":   true,
+}
+
+// hasMostlyKotlinSources reports whether dir contains more .kt files than
+// .java files. Kotlin and Java sources sit deep below src/, so the whole
+// tree is scanned up to kotlinScanMaxFiles source files.
+func hasMostlyKotlinSources(dir string) bool {
+	ktFiles, javaFiles := 0, 0
+
+	filepath.WalkDir(dir, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		name := entry.Name()
+		if entry.IsDir() {
+			if path != dir && (kotlinScanSkipDirs[name] || strings.HasPrefix(name, ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		switch filepath.Ext(name) {
+		case ".kt":
+			ktFiles++
+		case ".java":
+			javaFiles++
+		}
+		if ktFiles+javaFiles >= kotlinScanMaxFiles {
+			return filepath.SkipAll
+		}
+		return nil
+	})
+
+	return ktFiles > javaFiles
+}
+
+// detectGradleSettings detects a multi-module build from a settings file
+// that has no build script next to it. When a build script exists, it is
+// detected on its own and picks up the settings file's modules.
+func (d *javaDetector) detectGradleSettings(manifestPath string, content []byte) (*models.Project, error) {
+	dir := filepath.Dir(manifestPath)
+	if fileExists(filepath.Join(dir, "build.gradle")) || fileExists(filepath.Join(dir, "build.gradle.kts")) {
+		return nil, nil
+	}
+
+	settings := string(content)
+	modules := parseGradleIncludes(settings)
+	if len(modules) == 0 {
+		return nil, nil
+	}
+
+	project := d.createProject(manifestPath, gradleRootName(settings), "")
+	project.Children = d.declaredModules(manifestPath, modules, "")
+	return project, nil
+}
+
+// readGradleSettings returns the path and content of the settings file in
+// dir, preferring settings.gradle over settings.gradle.kts. Both are empty
+// if there is none.
+func readGradleSettings(dir string) (string, string) {
+	for _, name := range []string{"settings.gradle", "settings.gradle.kts"} {
+		path := filepath.Join(dir, name)
+		if content, err := os.ReadFile(path); err == nil {
+			return path, string(content)
+		}
+	}
+	return "", ""
+}
+
+// parseGradleIncludes returns the module directories declared by include
+// directives in a settings file, relative to the settings directory.
+// Gradle project paths such as ":services:auth" map to "services/auth".
+func parseGradleIncludes(settings string) []string {
+	var modules []string
+	seen := make(map[string]bool)
+
+	for _, include := range gradleIncludeRe.FindAllStringSubmatch(settings, -1) {
+		for _, quoted := range gradleQuotedRe.FindAllStringSubmatch(include[1], -1) {
+			module := strings.ReplaceAll(strings.Trim(quoted[1], ":"), ":", "/")
+			if module == "" || seen[module] {
+				continue
+			}
+			seen[module] = true
+			modules = append(modules, module)
+		}
+	}
+
+	return modules
+}
+
+// gradleRootName returns rootProject.name from a settings file, or "".
+func gradleRootName(settings string) string {
+	if matches := gradleRootNameRe.FindStringSubmatch(settings); len(matches) > 1 {
+		return matches[1]
+	}
+	return ""
+}
+
+// declaredModules creates a child project for each module declared by the
+// Gradle settings file or parent POM at manifestPath. The module's manifest
+// file is the declaring file, relative to the module directory.
+func (d *javaDetector) declaredModules(manifestPath string, modules []string, version string) []*models.Project {
+	var children []*models.Project
+	for _, module := range modules {
+		moduleDir := filepath.Join(filepath.Dir(manifestPath), filepath.FromSlash(module))
+		child := d.createProject(filepath.Join(moduleDir, filepath.Base(manifestPath)), "", version)
+		if relPath, err := filepath.Rel(moduleDir, manifestPath); err == nil {
+			child.ManifestFile = filepath.ToSlash(relPath)
+		}
+		// Classify the module like its own build script would be, so both
+		// describe the same project
+		for _, script := range []string{"build.gradle", "build.gradle.kts"} {
+			if content, err := os.ReadFile(filepath.Join(moduleDir, script)); err == nil {
+				classifyKotlin(child, string(content))
+				break
+			}
+		}
+		children = append(children, child)
+	}
+	return children
 }
 
 func (d *javaDetector) createProject(manifestPath, name, version string) *models.Project {
@@ -2,8 +2,10 @@ package detector
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"repoctr/pkg/models"
 )
@@ -27,14 +29,20 @@ func (d *javascriptDetector) ManifestFiles() []string {
 }
 
 func (d *javascriptDetector) Detect(manifestPath string, content []byte) (*models.Project, error) {
+	project, _, err := d.DetectWithWarnings(manifestPath, content)
+	return project, err
+}
+
+func (d *javascriptDetector) DetectWithWarnings(manifestPath string, content []byte) (*models.Project, []string, error) {
 	if filepath.Base(manifestPath) != "package.json" {
-		return nil, nil
+		return nil, nil, nil
 	}
 
 	var pkg packageJSON
 	if err := json.Unmarshal(content, &pkg); err != nil {
 		// If JSON parsing fails, still detect as JS project
-		return d.createProject(manifestPath, "", "", false), nil
+		warning := fmt.Sprintf("package.json parse failed, name and version unknown: %v", err)
+		return d.createProject(manifestPath, "", "", false), []string{warning}, nil
 	}
 
 	// Determine if TypeScript
@@ -46,7 +54,42 @@ func (d *javascriptDetector) Detect(manifestPath string, content []byte) (*model
 		nodeVersion = pkg.Engines.Node
 	}
 
-	return d.createProject(manifestPath, pkg.Name, nodeVersion, isTypeScript), nil
+	project := d.createProject(manifestPath, pkg.Name, nodeVersion, isTypeScript)
+	project.DependencyCount = len(pkg.Dependencies) + len(pkg.DevDependencies)
+	project.PackageManager = jsPackageManager(project.Path, pkg.PackageManager)
+	if isReactNativeRoot(project.Path, pkg) {
+		project.Framework = models.FrameworkReactNative
+	}
+	return project, nil, nil
+}
+
+// jsLockfiles maps lockfiles to the package manager that writes them, in
+// the order they are checked.
+var jsLockfiles = []struct {
+	file    string
+	manager string
+}{
+	{"pnpm-lock.yaml", "pnpm"},
+	{"yarn.lock", "yarn"},
+	{"package-lock.json", "npm"},
+}
+
+// jsPackageManager returns the package manager of the package in dir from
+// its lockfile, falling back to the Corepack "packageManager" field of
+// package.json (e.g. "pnpm@9.1.0"). It returns "" if neither is present.
+func jsPackageManager(dir, declared string) string {
+	for _, lock := range jsLockfiles {
+		if fileExists(filepath.Join(dir, lock.file)) {
+			return lock.manager
+		}
+	}
+
+	name, _, _ := strings.Cut(declared, "@")
+	switch name {
+	case "npm", "yarn", "pnpm":
+		return name
+	}
+	return ""
 }
 
 // packageJSON represents the structure of a package.json file.
@@ -55,6 +98,7 @@ type packageJSON struct {
 	Engines         engines           `json:"engines"`
 	Dependencies    map[string]string `json:"dependencies"`
 	DevDependencies map[string]string `json:"devDependencies"`
+	PackageManager  string            `json:"packageManager"`
 }
 
 type engines struct {
@@ -77,7 +121,58 @@ func (d *javascriptDetector) isTypeScriptProject(manifestPath string, pkg packag
 		return true
 	}
 
-	return false
+	// Libraries often declare typescript only as a peer dependency
+	return hasMostlyTypeScriptSources(dir)
+}
+
+// tsScanMaxDepth bounds how deep hasMostlyTypeScriptSources looks below the
+// package directory, keeping detection cheap on large trees.
+const tsScanMaxDepth = 2
+
+// tsScanSkipDirs are directories that hold dependencies or build output
+// rather than the package's own sources.
+var tsScanSkipDirs = map[string]bool{
+	"node_modules": true,
+	"dist":         true,
+	"build":        true,
+	"out":          true,
+	"coverage":     true,
+}
+
+// hasMostlyTypeScriptSources reports whether dir contains more .ts/.tsx
+// files than .js/.jsx files within tsScanMaxDepth levels. Declaration files
+// (.d.ts) are not counted, since plain JavaScript packages ship them too.
+func hasMostlyTypeScriptSources(dir string) bool {
+	tsFiles, jsFiles := 0, 0
+
+	var scan func(current string, depth int)
+	scan = func(current string, depth int) {
+		entries, err := os.ReadDir(current)
+		if err != nil {
+			return
+		}
+
+		for _, entry := range entries {
+			name := entry.Name()
+			if entry.IsDir() {
+				if depth < tsScanMaxDepth && !tsScanSkipDirs[name] && !strings.HasPrefix(name, ".") {
+					scan(filepath.Join(current, name), depth+1)
+				}
+				continue
+			}
+
+			switch {
+			case strings.HasSuffix(name, ".d.ts"):
+			case strings.HasSuffix(name, ".ts"), strings.HasSuffix(name, ".tsx"):
+				tsFiles++
+			case strings.HasSuffix(name, ".js"), strings.HasSuffix(name, ".jsx"):
+				jsFiles++
+			}
+		}
+	}
+	scan(dir, 0)
+
+	return tsFiles > jsFiles
 }
 
 func (d *javascriptDetector) createProject(manifestPath, name, version string, isTypeScript bool) *models.Project {
@@ -100,3 +195,25 @@ func (d *javascriptDetector) createProject(manifestPath, name, version string, i
 		SrcIgnorePaths: []string{"node_modules", "dist", "build"},
 	}
 }
+
+// isReactNativeRoot reports whether dir, whose package.json is pkg, is the
+// root of a React Native app: it depends on react-native and keeps its
+// native Android and iOS projects in android/ and ios/ beside package.json.
+// Capacitor, Cordova, and NativeScript apps share that layout, hence the
+// dependency check. The Gradle and CocoaPods manifests inside them are
+// detected as projects of their own and nest below the app.
+func isReactNativeRoot(dir string, pkg packageJSON) bool {
+	_, inDeps := pkg.Dependencies["react-native"]
+	_, inDevDeps := pkg.DevDependencies["react-native"]
+	if !inDeps && !inDevDeps {
+		return false
+	}
+
+	for _, native := range []string{"android", "ios"} {
+		info, err := os.Stat(filepath.Join(dir, native))
+		if err != nil || !info.IsDir() {
+			return false
+		}
+	}
+	return true
+}
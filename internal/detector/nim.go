@@ -0,0 +1,96 @@
+package detector
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"repoctr/pkg/models"
+)
+
+type nimDetector struct{}
+
+func NewNimDetector() Detector {
+	return &nimDetector{}
+}
+
+func (d *nimDetector) Name() string {
+	return "Nim"
+}
+
+func (d *nimDetector) RuntimeType() models.RuntimeType {
+	return models.RuntimeNim
+}
+
+func (d *nimDetector) ManifestFiles() []string {
+	return []string{"*.nimble"}
+}
+
+var (
+	nimbleVersionRe     = regexp.MustCompile(`(?m)^\s*version\s*=\s*"([^"]*)"`)
+	nimblePackageNameRe = regexp.MustCompile(`(?m)^\s*packageName\s*=\s*"([^"]*)"`)
+	nimbleSrcDirRe      = regexp.MustCompile(`(?m)^\s*srcDir\s*=\s*"([^"]*)"`)
+	nimbleRequiresRe    = regexp.MustCompile(`(?m)^\s*requires\b(.*)$`)
+	nimbleQuotedRe      = regexp.MustCompile(`"([^"]+)"`)
+)
+
+func (d *nimDetector) Detect(manifestPath string, content []byte) (*models.Project, error) {
+	filename := filepath.Base(manifestPath)
+	if !strings.HasSuffix(filename, ".nimble") {
+		return nil, nil
+	}
+
+	contentStr := string(content)
+
+	// The package is named after the .nimble file unless packageName says
+	// otherwise
+	name := strings.TrimSuffix(filename, ".nimble")
+	if matches := nimblePackageNameRe.FindStringSubmatch(contentStr); len(matches) > 1 && matches[1] != "" {
+		name = matches[1]
+	}
+
+	version := ""
+	if matches := nimbleVersionRe.FindStringSubmatch(contentStr); len(matches) > 1 {
+		version = matches[1]
+	}
+
+	project := d.createProject(manifestPath, name, version)
+	if matches := nimbleSrcDirRe.FindStringSubmatch(contentStr); len(matches) > 1 && matches[1] != "" {
+		project.SourcePaths = []string{matches[1]}
+	}
+	project.DependencyCount = countNimbleRequires(contentStr)
+	return project, nil
+}
+
+// countNimbleRequires counts the packages listed by requires directives,
+// e.g. requires "nim >= 2.0.0", "jester >= 0.6". The Nim compiler itself is
+// not a dependency.
+func countNimbleRequires(content string) int {
+	count := 0
+	for _, requires := range nimbleRequiresRe.FindAllStringSubmatch(content, -1) {
+		for _, quoted := range nimbleQuotedRe.FindAllStringSubmatch(requires[1], -1) {
+			fields := strings.Fields(quoted[1])
+			if len(fields) == 0 || fields[0] == "nim" {
+				continue
+			}
+			count++
+		}
+	}
+	return count
+}
+
+func (d *nimDetector) createProject(manifestPath, name, version string) *models.Project {
+	dir := filepath.Dir(manifestPath)
+	if name == "" {
+		name = filepath.Base(dir)
+	}
+
+	return &models.Project{
+		Name:           name,
+		Path:           dir,
+		Runtime:        models.Runtime{Type: models.RuntimeNim, Version: version},
+		ManifestFile:   filepath.Base(manifestPath),
+		SourcePaths:    []string{"src", "."},
+		SrcIgnorePaths: []string{"nimcache", "nimbledeps"},
+	}
+}
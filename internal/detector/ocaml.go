@@ -0,0 +1,87 @@
+package detector
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"repoctr/pkg/models"
+)
+
+type ocamlDetector struct{}
+
+func NewOCamlDetector() Detector {
+	return &ocamlDetector{}
+}
+
+func (d *ocamlDetector) Name() string {
+	return "OCaml"
+}
+
+func (d *ocamlDetector) RuntimeType() models.RuntimeType {
+	return models.RuntimeOCaml
+}
+
+func (d *ocamlDetector) ManifestFiles() []string {
+	return []string{"dune-project", "*.opam"}
+}
+
+var (
+	duneNameRe    = regexp.MustCompile(`(?m)^\s*\(\s*name\s+([^\s()]+)\s*\)`)
+	duneVersionRe = regexp.MustCompile(`(?m)^\s*\(\s*version\s+([^\s()]+)\s*\)`)
+
+	opamVersionRe = regexp.MustCompile(`(?m)^\s*version\s*:\s*"([^"]*)"`)
+	// opamOCamlRe matches the minimum OCaml version among the depends of an
+	// opam file, e.g. "ocaml" {>= "4.14"}.
+	opamOCamlRe = regexp.MustCompile(`"ocaml"\s*\{\s*>=\s*"([^"]+)"`)
+)
+
+func (d *ocamlDetector) Detect(manifestPath string, content []byte) (*models.Project, error) {
+	filename := filepath.Base(manifestPath)
+	contentStr := string(content)
+
+	if filename == "dune-project" {
+		name := ""
+		if matches := duneNameRe.FindStringSubmatch(contentStr); len(matches) > 1 {
+			name = matches[1]
+		}
+		project := d.createProject(manifestPath, name, "")
+		if matches := duneVersionRe.FindStringSubmatch(contentStr); len(matches) > 1 {
+			project.Version = matches[1]
+		}
+		project.PackageManager = "dune"
+		return project, nil
+	}
+
+	if strings.HasSuffix(filename, ".opam") {
+		// An opam package is named after its file
+		version := ""
+		if matches := opamOCamlRe.FindStringSubmatch(contentStr); len(matches) > 1 {
+			version = matches[1] + "+"
+		}
+		project := d.createProject(manifestPath, strings.TrimSuffix(filename, ".opam"), version)
+		if matches := opamVersionRe.FindStringSubmatch(contentStr); len(matches) > 1 {
+			project.Version = matches[1]
+		}
+		project.PackageManager = "opam"
+		return project, nil
+	}
+
+	return nil, nil
+}
+
+func (d *ocamlDetector) createProject(manifestPath, name, version string) *models.Project {
+	dir := filepath.Dir(manifestPath)
+	if name == "" {
+		name = filepath.Base(dir)
+	}
+
+	return &models.Project{
+		Name:           name,
+		Path:           dir,
+		Runtime:        models.Runtime{Type: models.RuntimeOCaml, Version: version},
+		ManifestFile:   filepath.Base(manifestPath),
+		SourcePaths:    []string{"."},
+		SrcIgnorePaths: []string{"_build", "_opam"},
+	}
+}
@@ -0,0 +1,102 @@
+package detector
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"repoctr/pkg/models"
+)
+
+type phpDetector struct{}
+
+func NewPHPDetector() Detector {
+	return &phpDetector{}
+}
+
+func (d *phpDetector) Name() string {
+	return "PHP"
+}
+
+func (d *phpDetector) RuntimeType() models.RuntimeType {
+	return models.RuntimePHP
+}
+
+func (d *phpDetector) ManifestFiles() []string {
+	return []string{"composer.json"}
+}
+
+func (d *phpDetector) Detect(manifestPath string, content []byte) (*models.Project, error) {
+	if filepath.Base(manifestPath) != "composer.json" {
+		return nil, nil
+	}
+
+	var composer composerJSON
+	if err := json.Unmarshal(content, &composer); err != nil {
+		// If JSON parsing fails, still detect as PHP project
+		return d.createProject(manifestPath, "", ""), nil
+	}
+
+	// Composer names are vendor/package; use the package part
+	name := composer.Name
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+
+	version := cleanPHPVersion(composer.Require["php"])
+
+	project := d.createProject(manifestPath, name, version)
+	project.DependencyCount = countComposerPackages(composer.Require) + countComposerPackages(composer.RequireDev)
+	return project, nil
+}
+
+// composerJSON represents the structure of a composer.json file.
+type composerJSON struct {
+	Name       string            `json:"name"`
+	Require    map[string]string `json:"require"`
+	RequireDev map[string]string `json:"require-dev"`
+}
+
+// countComposerPackages counts package requirements, skipping the platform
+// requirements for PHP itself and its extensions.
+func countComposerPackages(require map[string]string) int {
+	count := 0
+	for pkg := range require {
+		if pkg == "php" || strings.HasPrefix(pkg, "ext-") || strings.HasPrefix(pkg, "lib-") {
+			continue
+		}
+		count++
+	}
+	return count
+}
+
+func (d *phpDetector) createProject(manifestPath, name, version string) *models.Project {
+	dir := filepath.Dir(manifestPath)
+	if name == "" {
+		name = filepath.Base(dir)
+	}
+
+	return &models.Project{
+		Name:           name,
+		Path:           dir,
+		Runtime:        models.Runtime{Type: models.RuntimePHP, Version: version},
+		ManifestFile:   "composer.json",
+		SourcePaths:    []string{"src", "app", "."},
+		SrcIgnorePaths: []string{"vendor", "storage", "bootstrap/cache"},
+	}
+}
+
+// cleanPHPVersion extracts version from a Composer constraint.
+// Examples: "^8.2" -> "8.2+", ">=8.1" -> "8.1+", "8.3.*" -> "8.3"
+func cleanPHPVersion(v string) string {
+	v = strings.TrimSpace(v)
+	re := regexp.MustCompile(`(\d+\.\d+)`)
+	if matches := re.FindStringSubmatch(v); len(matches) > 1 {
+		if strings.HasPrefix(v, "^") || strings.HasPrefix(v, ">=") || strings.HasPrefix(v, "~") {
+			return matches[1] + "+"
+		}
+		return matches[1]
+	}
+	return v
+}
@@ -1,8 +1,10 @@
 package detector
 
 import (
+	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/BurntSushi/toml"
@@ -24,7 +26,15 @@ func (d *pythonDetector) RuntimeType() models.RuntimeType {
 }
 
 func (d *pythonDetector) ManifestFiles() []string {
-	return []string{"pyproject.toml", "setup.py", "requirements.txt"}
+	return []string{
+		"pyproject.toml",
+		"setup.py",
+		"setup.cfg",
+		"Pipfile",
+		"requirements*.txt",
+		"constraints*.txt",
+		"requirements/*.txt",
+	}
 }
 
 func (d *pythonDetector) Detect(manifestPath string, content []byte) (*models.Project, error) {
@@ -35,24 +45,69 @@ func (d *pythonDetector) Detect(manifestPath string, content []byte) (*models.Pr
 		return d.detectPyprojectToml(manifestPath, content)
 	case "setup.py":
 		return d.detectSetupPy(manifestPath, content)
-	case "requirements.txt":
+	case "setup.cfg":
+		return d.detectSetupCfg(manifestPath, content)
+	case "Pipfile":
+		return d.detectPipfile(manifestPath, content)
+	}
+
+	if IsRequirementsFile(requirementsRelPath(manifestPath)) {
 		return d.detectRequirementsTxt(manifestPath, content)
 	}
 
 	return nil, nil
 }
 
+// IsRequirementsFile reports whether a manifest path (relative to its
+// project directory) is a pip requirements or constraints file, such as
+// "requirements.txt", "requirements-dev.txt", "constraints.txt", or
+// "requirements/base.txt".
+func IsRequirementsFile(manifest string) bool {
+	manifest = filepath.ToSlash(manifest)
+
+	if dir, file, ok := strings.Cut(manifest, "/"); ok {
+		return dir == "requirements" && !strings.Contains(file, "/") && strings.HasSuffix(file, ".txt")
+	}
+
+	for _, pattern := range []string{"requirements*.txt", "constraints*.txt"} {
+		if matched, _ := filepath.Match(pattern, manifest); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// requirementsRelPath returns the manifest path relative to the project
+// directory. Files inside a "requirements/" folder belong to the folder's parent.
+func requirementsRelPath(manifestPath string) string {
+	parent := filepath.Base(filepath.Dir(manifestPath))
+	if parent == "requirements" {
+		return "requirements/" + filepath.Base(manifestPath)
+	}
+	return filepath.Base(manifestPath)
+}
+
 // pyprojectToml represents the structure of a pyproject.toml file.
 type pyprojectToml struct {
 	Project struct {
-		Name           string `toml:"name"`
-		RequiresPython string `toml:"requires-python"`
+		Name           string   `toml:"name"`
+		Version        string   `toml:"version"`
+		Dynamic        []string `toml:"dynamic"`
+		RequiresPython string   `toml:"requires-python"`
+		Dependencies   []string `toml:"dependencies"`
 	} `toml:"project"`
 	Tool struct {
 		Poetry struct {
-			Name   string `toml:"name"`
-			Python string `toml:"python"`
+			Name         string                    `toml:"name"`
+			Version      string                    `toml:"version"`
+			Python       string                    `toml:"python"`
+			Dependencies map[string]toml.Primitive `toml:"dependencies"`
 		} `toml:"poetry"`
+		Setuptools struct {
+			Dynamic struct {
+				Version setuptoolsDynamicVersion `toml:"version"`
+			} `toml:"dynamic"`
+		} `toml:"setuptools"`
 	} `toml:"tool"`
 	BuildSystem struct {
 		Requires []string `toml:"requires"`
@@ -61,9 +116,12 @@ type pyprojectToml struct {
 
 func (d *pythonDetector) detectPyprojectToml(manifestPath string, content []byte) (*models.Project, error) {
 	var pyproj pyprojectToml
-	if _, err := toml.Decode(string(content), &pyproj); err != nil {
+	meta, err := toml.Decode(string(content), &pyproj)
+	if err != nil {
 		// If TOML parsing fails, still detect as Python project
-		return d.createProject(manifestPath, "", ""), nil
+		project := d.createProject(manifestPath, "", "")
+		project.PackageManager = pythonPackageManager(project.Path, false, false)
+		return project, nil
 	}
 
 	// Determine project name
@@ -79,7 +137,126 @@ func (d *pythonDetector) detectPyprojectToml(manifestPath string, content []byte
 	}
 	version = cleanPythonVersion(version)
 
-	return d.createProject(manifestPath, name, version), nil
+	// Poetry lists the interpreter itself among its dependencies
+	deps := len(pyproj.Project.Dependencies)
+	for dep := range pyproj.Tool.Poetry.Dependencies {
+		if dep != "python" {
+			deps++
+		}
+	}
+
+	project := d.createProject(manifestPath, name, version)
+	project.DependencyCount = deps
+	project.PackageManager = pythonPackageManager(project.Path, meta.IsDefined("tool", "poetry"), meta.IsDefined("tool", "pdm"))
+
+	// PEP 621 lets the version be computed at build time instead
+	project.Version = pyproj.Project.Version
+	if project.Version == "" {
+		project.Version = pyproj.Tool.Poetry.Version
+	}
+	if project.Version == "" && slices.Contains(pyproj.Project.Dynamic, "version") {
+		project.Version = resolveDynamicVersion(project.Path, pyproj.Tool.Setuptools.Dynamic.Version)
+	}
+	return project, nil
+}
+
+// setuptoolsDynamicVersion is [tool.setuptools.dynamic].version, which reads
+// the version from a module attribute or from a file.
+type setuptoolsDynamicVersion struct {
+	// Attr names a module attribute, e.g. "mypkg.__version__".
+	Attr string `toml:"attr"`
+	// File is a file name or a list of file names.
+	File any `toml:"file"`
+}
+
+// resolveDynamicVersion reads a dynamic version declared through setuptools
+// from the project in dir. It returns models.DynamicVersion when the version
+// comes from elsewhere (e.g. setuptools-scm or hatch-vcs) or cannot be read.
+func resolveDynamicVersion(dir string, dynamic setuptoolsDynamicVersion) string {
+	if file := firstVersionFile(dynamic.File); file != "" {
+		if data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(file))); err == nil {
+			if line, _, _ := strings.Cut(strings.TrimSpace(string(data)), "\n"); line != "" {
+				return strings.TrimSpace(line)
+			}
+		}
+	}
+
+	if version := readVersionAttr(dir, dynamic.Attr); version != "" {
+		return version
+	}
+
+	return models.DynamicVersion
+}
+
+// firstVersionFile returns the first file of a setuptools "file" directive.
+func firstVersionFile(file any) string {
+	switch f := file.(type) {
+	case string:
+		return f
+	case []any:
+		if len(f) > 0 {
+			if name, ok := f[0].(string); ok {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+// readVersionAttr reads a string attribute such as "mypkg.__version__" from
+// the module's source in dir or dir/src, without importing it.
+func readVersionAttr(dir, attr string) string {
+	module, name, ok := cutLast(attr, ".")
+	if !ok || module == "" || name == "" {
+		return ""
+	}
+
+	assignRe, err := regexp.Compile(`(?m)^` + regexp.QuoteMeta(name) + `\s*(?::[^=\n]*)?=\s*["']([^"']+)["']`)
+	if err != nil {
+		return ""
+	}
+
+	modulePath := filepath.FromSlash(strings.ReplaceAll(module, ".", "/"))
+	for _, base := range []string{dir, filepath.Join(dir, "src")} {
+		for _, candidate := range []string{
+			filepath.Join(base, modulePath, "__init__.py"),
+			filepath.Join(base, modulePath+".py"),
+		} {
+			data, err := os.ReadFile(candidate)
+			if err != nil {
+				continue
+			}
+			if matches := assignRe.FindSubmatch(data); matches != nil {
+				return string(matches[1])
+			}
+		}
+	}
+	return ""
+}
+
+// cutLast slices s around the last instance of sep.
+func cutLast(s, sep string) (before, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
+
+// pythonPackageManager returns the package manager of the Python project in
+// dir: poetry or pdm when their lockfile or pyproject.toml [tool] table is
+// present, pip otherwise. Lockfiles take precedence over tool tables.
+func pythonPackageManager(dir string, poetryTable, pdmTable bool) string {
+	switch {
+	case fileExists(filepath.Join(dir, "poetry.lock")):
+		return "poetry"
+	case fileExists(filepath.Join(dir, "pdm.lock")):
+		return "pdm"
+	case poetryTable:
+		return "poetry"
+	case pdmTable:
+		return "pdm"
+	}
+	return "pip"
 }
 
 func (d *pythonDetector) detectSetupPy(manifestPath string, content []byte) (*models.Project, error) {
@@ -104,13 +281,139 @@ func (d *pythonDetector) detectSetupPy(manifestPath string, content []byte) (*mo
 		version = cleanPythonVersion(matches[1])
 	}
 
-	return d.createProject(manifestPath, name, version), nil
+	project := d.createProject(manifestPath, name, version)
+	project.PackageManager = "pip"
+	return project, nil
+}
+
+// detectSetupCfg detects a setuptools project declared in setup.cfg. Files
+// holding only tool settings (e.g. [flake8]) are not projects.
+func (d *pythonDetector) detectSetupCfg(manifestPath string, content []byte) (*models.Project, error) {
+	sections := parseSetupCfg(content)
+	metadata, hasMetadata := sections["metadata"]
+	options, hasOptions := sections["options"]
+	if !hasMetadata && !hasOptions {
+		return nil, nil
+	}
+
+	project := d.createProject(manifestPath, metadata["name"], cleanPythonVersion(options["python_requires"]))
+	project.Version = metadata["version"]
+	project.DependencyCount = countRequirements([]byte(options["install_requires"]))
+	project.PackageManager = "pip"
+	return project, nil
+}
+
+// parseSetupCfg reads the sections of an INI-style setup.cfg into maps of
+// keys to values. Indented continuation lines are joined to their key's
+// value with newlines, as setuptools does for lists such as install_requires.
+func parseSetupCfg(content []byte) map[string]map[string]string {
+	sections := make(map[string]map[string]string)
+	var section map[string]string
+	key := ""
+
+	for _, line := range strings.Split(string(content), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]") {
+			name := strings.TrimSpace(trimmed[1 : len(trimmed)-1])
+			if sections[name] == nil {
+				sections[name] = make(map[string]string)
+			}
+			section = sections[name]
+			key = ""
+			continue
+		}
+		if section == nil {
+			continue
+		}
+
+		if line[0] == ' ' || line[0] == '\t' {
+			if key != "" {
+				section[key] = strings.TrimPrefix(section[key]+"\n"+trimmed, "\n")
+			}
+			continue
+		}
+
+		k, v, ok := strings.Cut(trimmed, "=")
+		if !ok {
+			k, v, ok = strings.Cut(trimmed, ":")
+		}
+		if !ok {
+			key = ""
+			continue
+		}
+		key = strings.TrimSpace(k)
+		section[key] = strings.TrimSpace(v)
+	}
+	return sections
+}
+
+// pipfile represents the parts of a pipenv Pipfile used for detection.
+type pipfile struct {
+	Requires struct {
+		PythonVersion     string `toml:"python_version"`
+		PythonFullVersion string `toml:"python_full_version"`
+	} `toml:"requires"`
+	Packages map[string]toml.Primitive `toml:"packages"`
+}
+
+// detectPipfile detects a pipenv project. A Pipfile names no project, so the
+// directory name is used. Like pyproject.toml, only runtime [packages] count
+// as dependencies, not [dev-packages].
+func (d *pythonDetector) detectPipfile(manifestPath string, content []byte) (*models.Project, error) {
+	var pf pipfile
+	if _, err := toml.Decode(string(content), &pf); err != nil {
+		// If TOML parsing fails, still detect as Python project
+		project := d.createProject(manifestPath, "", "")
+		project.PackageManager = "pipenv"
+		return project, nil
+	}
+
+	version := pf.Requires.PythonVersion
+	if version == "" {
+		version = pf.Requires.PythonFullVersion
+	}
+
+	project := d.createProject(manifestPath, "", cleanPythonVersion(version))
+	project.DependencyCount = len(pf.Packages)
+	project.PackageManager = "pipenv"
+	return project, nil
 }
 
 func (d *pythonDetector) detectRequirementsTxt(manifestPath string, content []byte) (*models.Project, error) {
-	// requirements.txt is a valid Python project indicator
-	// but provides no name or version info
-	return d.createProject(manifestPath, "", ""), nil
+	// requirements files are a valid Python project indicator
+	// but provide no name or version info
+	relPath := requirementsRelPath(manifestPath)
+	if strings.HasPrefix(relPath, "requirements/") {
+		// requirements/base.txt describes the project one level up
+		project := d.createProject(filepath.Dir(manifestPath), "", "")
+		project.ManifestFile = relPath
+		project.DependencyCount = countRequirements(content)
+		project.PackageManager = "pip"
+		return project, nil
+	}
+
+	project := d.createProject(manifestPath, "", "")
+	project.DependencyCount = countRequirements(content)
+	project.PackageManager = "pip"
+	return project, nil
+}
+
+// countRequirements counts the requirement lines in a pip requirements file,
+// skipping blank lines, comments, and options such as "-r base.txt".
+func countRequirements(content []byte) int {
+	count := 0
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "-") {
+			continue
+		}
+		count++
+	}
+	return count
 }
 
 func (d *pythonDetector) createProject(manifestPath, name, version string) *models.Project {
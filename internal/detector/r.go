@@ -0,0 +1,126 @@
+package detector
+
+import (
+	"bufio"
+	"bytes"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"repoctr/pkg/models"
+)
+
+type rDetector struct{}
+
+func NewRDetector() Detector {
+	return &rDetector{}
+}
+
+func (d *rDetector) Name() string {
+	return "R"
+}
+
+func (d *rDetector) RuntimeType() models.RuntimeType {
+	return models.RuntimeR
+}
+
+func (d *rDetector) ManifestFiles() []string {
+	return []string{"DESCRIPTION"}
+}
+
+// rDependsRe matches the R requirement in a Depends field,
+// e.g. "R (>= 4.1.0)".
+var rDependsRe = regexp.MustCompile(`^R\s*\(\s*(>=|>|==|<=|<)?\s*([0-9][0-9.\-]*)\s*\)$`)
+
+func (d *rDetector) Detect(manifestPath string, content []byte) (*models.Project, error) {
+	if filepath.Base(manifestPath) != "DESCRIPTION" {
+		return nil, nil
+	}
+
+	fields := parseDCF(content)
+
+	// DESCRIPTION is a common file name; only R packages declare Package
+	name, ok := fields["Package"]
+	if !ok {
+		return nil, nil
+	}
+
+	version := ""
+	dependencies := 0
+	for _, field := range []string{"Depends", "Imports", "LinkingTo"} {
+		for _, dep := range splitRPackageList(fields[field]) {
+			if matches := rDependsRe.FindStringSubmatch(dep); matches != nil {
+				version = matches[2]
+				if matches[1] == ">=" || matches[1] == ">" {
+					version += "+"
+				}
+				continue
+			}
+			if dep == "R" {
+				continue
+			}
+			dependencies++
+		}
+	}
+
+	project := d.createProject(manifestPath, name, version)
+	project.DependencyCount = dependencies
+	return project, nil
+}
+
+// parseDCF parses the Debian control file format used by DESCRIPTION:
+// "Field: value" lines, where indented lines continue the previous value.
+func parseDCF(content []byte) map[string]string {
+	fields := make(map[string]string)
+	var current string
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if line[0] == ' ' || line[0] == '\t' {
+			if current != "" {
+				fields[current] += " " + strings.TrimSpace(line)
+			}
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			current = ""
+			continue
+		}
+		current = strings.TrimSpace(key)
+		fields[current] = strings.TrimSpace(value)
+	}
+	return fields
+}
+
+// splitRPackageList splits a comma-separated package list such as
+// "R (>= 4.1.0), dplyr (>= 1.0), rlang" into its trimmed entries.
+func splitRPackageList(list string) []string {
+	var entries []string
+	for _, entry := range strings.Split(list, ",") {
+		if entry = strings.Join(strings.Fields(entry), " "); entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+func (d *rDetector) createProject(manifestPath, name, version string) *models.Project {
+	dir := filepath.Dir(manifestPath)
+	if name == "" {
+		name = filepath.Base(dir)
+	}
+
+	return &models.Project{
+		Name:           name,
+		Path:           dir,
+		Runtime:        models.Runtime{Type: models.RuntimeR, Version: version},
+		ManifestFile:   "DESCRIPTION",
+		SourcePaths:    []string{"R", "."},
+		SrcIgnorePaths: []string{"renv", "packrat", ".Rproj.user"},
+	}
+}
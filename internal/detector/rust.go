@@ -42,7 +42,9 @@ func (d *rustDetector) Detect(manifestPath string, content []byte) (*models.Proj
 		version = cargo.Package.Edition
 	}
 
-	return d.createProject(manifestPath, cargo.Package.Name, version), nil
+	project := d.createProject(manifestPath, cargo.Package.Name, version)
+	project.DependencyCount = len(cargo.Dependencies)
+	return project, nil
 }
 
 // cargoToml represents the structure of a Cargo.toml file.
@@ -55,6 +57,9 @@ type cargoToml struct {
 	Workspace struct {
 		Members []string `toml:"members"`
 	} `toml:"workspace"`
+	// Dependencies holds either version strings or inline tables, so only
+	// the keys are of interest.
+	Dependencies map[string]toml.Primitive `toml:"dependencies"`
 }
 
 func (d *rustDetector) createProject(manifestPath, name, version string) *models.Project {
@@ -0,0 +1,101 @@
+package detector
+
+import (
+	"path/filepath"
+	"regexp"
+
+	"repoctr/pkg/models"
+)
+
+type swiftDetector struct{}
+
+func NewSwiftDetector() Detector {
+	return &swiftDetector{}
+}
+
+func (d *swiftDetector) Name() string {
+	return "Swift"
+}
+
+func (d *swiftDetector) RuntimeType() models.RuntimeType {
+	return models.RuntimeSwift
+}
+
+func (d *swiftDetector) ManifestFiles() []string {
+	return []string{"Package.swift", "Podfile"}
+}
+
+var (
+	// swiftToolsVersionRe matches the tools version comment that must open
+	// every Package.swift, e.g. "// swift-tools-version:5.9".
+	swiftToolsVersionRe = regexp.MustCompile(`^//\s*swift-tools-version\s*:\s*([0-9][0-9.]*)`)
+	swiftPackageNameRe  = regexp.MustCompile(`Package\s*\(\s*name\s*:\s*"([^"]+)"`)
+	swiftDependencyRe   = regexp.MustCompile(`\.package\s*\(`)
+
+	// podfileTargetRe matches the first target of a Podfile, which names
+	// the app it builds.
+	podfileTargetRe = regexp.MustCompile(`(?m)^\s*target\s+['"]([^'"]+)['"]`)
+	podfilePodRe    = regexp.MustCompile(`(?m)^\s*pod\s+['"]`)
+)
+
+func (d *swiftDetector) Detect(manifestPath string, content []byte) (*models.Project, error) {
+	switch filepath.Base(manifestPath) {
+	case "Package.swift":
+		return d.detectPackage(manifestPath, content), nil
+	case "Podfile":
+		return d.detectPodfile(manifestPath, content), nil
+	}
+	return nil, nil
+}
+
+func (d *swiftDetector) detectPackage(manifestPath string, content []byte) *models.Project {
+	contentStr := string(content)
+
+	name := ""
+	if matches := swiftPackageNameRe.FindStringSubmatch(contentStr); len(matches) > 1 {
+		name = matches[1]
+	}
+	version := ""
+	if matches := swiftToolsVersionRe.FindStringSubmatch(contentStr); len(matches) > 1 {
+		version = matches[1]
+	}
+
+	project := d.createProject(manifestPath, "Package.swift", name, version)
+	project.SourcePaths = []string{"Sources", "."}
+	project.SrcIgnorePaths = []string{".build", ".swiftpm"}
+	project.DependencyCount = len(swiftDependencyRe.FindAllString(contentStr, -1))
+	project.PackageManager = "swiftpm"
+	return project
+}
+
+// detectPodfile detects a CocoaPods project. A Podfile does not declare a
+// Swift version, so the runtime version is left empty.
+func (d *swiftDetector) detectPodfile(manifestPath string, content []byte) *models.Project {
+	contentStr := string(content)
+
+	name := ""
+	if matches := podfileTargetRe.FindStringSubmatch(contentStr); len(matches) > 1 {
+		name = matches[1]
+	}
+
+	project := d.createProject(manifestPath, "Podfile", name, "")
+	project.SrcIgnorePaths = []string{"Pods", "build", "DerivedData"}
+	project.DependencyCount = len(podfilePodRe.FindAllString(contentStr, -1))
+	project.PackageManager = "cocoapods"
+	return project
+}
+
+func (d *swiftDetector) createProject(manifestPath, manifestFile, name, version string) *models.Project {
+	dir := filepath.Dir(manifestPath)
+	if name == "" {
+		name = filepath.Base(dir)
+	}
+
+	return &models.Project{
+		Name:         name,
+		Path:         dir,
+		Runtime:      models.Runtime{Type: models.RuntimeSwift, Version: version},
+		ManifestFile: manifestFile,
+		SourcePaths:  []string{"."},
+	}
+}
@@ -42,6 +42,11 @@ func (b *HierarchyBuilder) Build(projects []*models.Project) []*models.Project {
 	for _, project := range sorted {
 		pathMap[project.Path] = project
 
+		// Workspace packages share the lockfile at the workspace root
+		if project.PackageManager == "" {
+			project.PackageManager = b.inheritedPackageManager(project, pathMap)
+		}
+
 		// Find nearest ancestor
 		parent := b.findNearestAncestor(project.Path, pathMap)
 		if parent != nil {
@@ -76,6 +81,26 @@ func (b *HierarchyBuilder) findNearestAncestor(path string, pathMap map[string]*
 	return nil
 }
 
+// inheritedPackageManager returns the package manager of the nearest
+// ancestor in the same ecosystem that records one, or "".
+func (b *HierarchyBuilder) inheritedPackageManager(project *models.Project, pathMap map[string]*models.Project) string {
+	for ancestor := b.findNearestAncestor(project.Path, pathMap); ancestor != nil; ancestor = b.findNearestAncestor(ancestor.Path, pathMap) {
+		if ancestor.PackageManager != "" && sameEcosystem(ancestor.Runtime.Type, project.Runtime.Type) {
+			return ancestor.PackageManager
+		}
+	}
+	return ""
+}
+
+// sameEcosystem reports whether two runtimes share package tooling.
+// JavaScript and TypeScript packages live in the same workspaces.
+func sameEcosystem(a, b models.RuntimeType) bool {
+	isJS := func(rt models.RuntimeType) bool {
+		return rt == models.RuntimeJavaScript || rt == models.RuntimeTypeScript
+	}
+	return a == b || (isJS(a) && isJS(b))
+}
+
 // Flatten converts a hierarchical project tree back to a flat list.
 func (b *HierarchyBuilder) Flatten(roots []*models.Project) []*models.Project {
 	var result []*models.Project
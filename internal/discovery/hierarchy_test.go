@@ -121,3 +121,29 @@ func TestHierarchyBuilder_NoParents(t *testing.T) {
 		t.Fatalf("expected 3 roots, got %d", len(roots))
 	}
 }
+
+func TestHierarchyBuilder_InheritsWorkspacePackageManager(t *testing.T) {
+	builder := NewHierarchyBuilder()
+
+	js := models.Runtime{Type: models.RuntimeJavaScript}
+	ts := models.Runtime{Type: models.RuntimeTypeScript}
+	projects := []*models.Project{
+		{Name: "monorepo", Path: ".", Runtime: js, PackageManager: "pnpm"},
+		{Name: "ui", Path: "packages/ui", Runtime: ts},
+		{Name: "legacy", Path: "packages/legacy", Runtime: js, PackageManager: "npm"},
+		{Name: "tools", Path: "tools", Runtime: models.Runtime{Type: models.RuntimePython}},
+	}
+
+	builder.Build(projects)
+
+	want := map[string]string{
+		"ui":     "pnpm", // TypeScript package in a JavaScript workspace
+		"legacy": "npm",  // its own lockfile wins
+		"tools":  "",     // different ecosystem
+	}
+	for _, p := range projects[1:] {
+		if p.PackageManager != want[p.Name] {
+			t.Errorf("%s: PackageManager = %q, want %q", p.Name, p.PackageManager, want[p.Name])
+		}
+	}
+}
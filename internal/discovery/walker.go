@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"repoctr/internal/detector"
+	"repoctr/internal/fswalk"
 	"repoctr/internal/ignore"
 	"repoctr/pkg/models"
 )
@@ -15,6 +16,16 @@ type Walker struct {
 	registry *detector.Registry
 	matcher  *ignore.Matcher
 	rootDir  string
+	warnings []detector.Warning
+	// followSymlinks walks symbolic links to directories.
+	followSymlinks bool
+	// maxDepth limits how many directory levels below the root are walked;
+	// zero means unlimited.
+	maxDepth int
+	// filter narrows the walk to selected paths; nil selects every path.
+	filter *ignore.PathFilter
+	// logf receives diagnostic messages; nil discards them.
+	logf func(format string, args ...any)
 }
 
 // NewWalker creates a new walker for the given root directory.
@@ -36,56 +47,180 @@ func NewWalker(rootDir string, registry *detector.Registry) (*Walker, error) {
 	}, nil
 }
 
+// SetLogf sets a function that receives a message for every detected
+// manifest and skipped directory, such as log.Printf. Nil disables logging.
+func (w *Walker) SetLogf(logf func(format string, args ...any)) {
+	w.logf = logf
+}
+
+// SetFollowSymlinks makes the walk descend into symbolic links to
+// directories, walking each directory at most once.
+func (w *Walker) SetFollowSymlinks(follow bool) {
+	w.followSymlinks = follow
+}
+
+// SetMaxDepth stops the walk from descending more than depth directory
+// levels below the root, so only manifests in the root and its first depth
+// levels of subdirectories are found. Zero means unlimited.
+func (w *Walker) SetMaxDepth(depth int) {
+	w.maxDepth = depth
+}
+
+// SetPathFilter limits the walk to the directories and manifests selected
+// by filter, in addition to the ignore rules. Nil removes the filter.
+func (w *Walker) SetPathFilter(filter *ignore.PathFilter) {
+	w.filter = filter
+}
+
+// log sends a diagnostic message to the walker's logf, if any.
+func (w *Walker) log(format string, args ...any) {
+	if w.logf != nil {
+		w.logf(format, args...)
+	}
+}
+
 // Discover walks the directory tree and returns all discovered projects.
 func (w *Walker) Discover() ([]*models.Project, error) {
 	var projects []*models.Project
+	w.warnings = nil
 	manifestPatterns := w.registry.GetManifestPatterns()
 
-	err := filepath.WalkDir(w.rootDir, func(path string, d os.DirEntry, err error) error {
+	// Index of the project detected per directory and runtime. Several
+	// manifests in one directory, such as pyproject.toml and
+	// requirements.txt, describe a single project.
+	seen := make(map[projectKey]int)
+
+	// Modules declared by a parent manifest, such as the includes of a
+	// Gradle settings file
+	var modules []*models.Project
+
+	err := fswalk.WalkDir(w.rootDir, w.followSymlinks, func(path string, d os.DirEntry, err error) error {
 		if err != nil {
 			return nil // Skip inaccessible paths
 		}
 
 		// Skip ignored directories
 		if d.IsDir() {
-			if w.matcher.ShouldIgnore(path) {
+			if w.tooDeep(path) || w.shouldIgnoreDir(path) || !w.filterAllows(path, true) {
 				return filepath.SkipDir
 			}
 			return nil
 		}
 
-		// Check if this file matches any manifest pattern
-		filename := d.Name()
-		if !w.matchesManifest(filename, manifestPatterns) {
+		if !w.filterAllows(path, false) {
 			return nil
 		}
-
-		// Skip ignored files
-		if w.matcher.ShouldIgnoreFile(path) {
-			return nil
+		if project := w.detectManifest(path, manifestPatterns); project != nil {
+			projects = w.appendProject(projects, seen, project)
+			modules = append(modules, takeModules(project)...)
 		}
 
-		// Read file content
-		content, err := os.ReadFile(path)
-		if err != nil {
-			return nil // Skip unreadable files
-		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return appendModules(projects, modules), nil
+}
+
+// shouldIgnoreDir reports whether the walk should skip the directory at
+// path, which the walk already knows is a directory.
+func (w *Walker) shouldIgnoreDir(path string) bool {
+	relPath, err := filepath.Rel(w.rootDir, path)
+	if err != nil {
+		return w.matcher.ShouldIgnore(path)
+	}
+	if !w.matcher.ShouldIgnoreDir(relPath) {
+		return false
+	}
+	w.log("skipped ignored directory %s", filepath.ToSlash(relPath))
+	return true
+}
 
-		// Try to detect project
-		project, err := w.registry.DetectProject(path, content)
+// tooDeep reports whether the directory at path lies more than maxDepth
+// levels below the root.
+func (w *Walker) tooDeep(path string) bool {
+	if w.maxDepth <= 0 {
+		return false
+	}
+	relPath, err := filepath.Rel(w.rootDir, path)
+	if err != nil || relPath == "." {
+		return false
+	}
+	depth := strings.Count(filepath.ToSlash(relPath), "/") + 1
+	if depth <= w.maxDepth {
+		return false
+	}
+	w.log("skipped %s beyond the maximum depth of %d", filepath.ToSlash(relPath), w.maxDepth)
+	return true
+}
+
+// filterAllows reports whether the path filter selects the directory or
+// manifest at path. The root itself is always walked.
+func (w *Walker) filterAllows(path string, isDir bool) bool {
+	if w.filter == nil {
+		return true
+	}
+	relPath, err := filepath.Rel(w.rootDir, path)
+	if err != nil || relPath == "." {
+		return true
+	}
+	relPath = filepath.ToSlash(relPath)
+	if !isDir {
+		return w.filter.AllowsFile(relPath)
+	}
+	if !w.filter.AllowsDir(relPath) {
+		w.log("skipped %s outside the path filter", relPath)
+		return false
+	}
+	return true
+}
+
+// Manifest is a manifest file found by the walker together with the project
+// detected from it.
+type Manifest struct {
+	// Path is the manifest path relative to the walker root.
+	Path    string
+	Project *models.Project
+}
+
+// DiscoverManifests walks the directory tree like Discover but returns
+// every manifest that describes a project, including requirements variants
+// that Discover folds into a single project.
+func (w *Walker) DiscoverManifests() ([]Manifest, error) {
+	var manifests []Manifest
+	w.warnings = nil
+	manifestPatterns := w.registry.GetManifestPatterns()
+
+	err := fswalk.WalkDir(w.rootDir, w.followSymlinks, func(path string, d os.DirEntry, err error) error {
 		if err != nil {
-			return nil // Skip detection errors
+			return nil // Skip inaccessible paths
 		}
 
-		if project != nil {
-			// Make path relative to root
-			relPath, err := filepath.Rel(w.rootDir, project.Path)
-			if err == nil {
-				project.Path = relPath
+		if d.IsDir() {
+			if w.tooDeep(path) || w.shouldIgnoreDir(path) || !w.filterAllows(path, true) {
+				return filepath.SkipDir
 			}
-			projects = append(projects, project)
+			return nil
+		}
+
+		if !w.filterAllows(path, false) {
+			return nil
 		}
+		project := w.detectManifest(path, manifestPatterns)
+		if project == nil {
+			return nil
+		}
+		// Declared modules have no manifest of their own
+		takeModules(project)
 
+		relPath, err := filepath.Rel(w.rootDir, path)
+		if err != nil {
+			relPath = path
+		}
+		manifests = append(manifests, Manifest{Path: relPath, Project: project})
 		return nil
 	})
 
@@ -93,12 +228,196 @@ func (w *Walker) Discover() ([]*models.Project, error) {
 		return nil, err
 	}
 
+	return manifests, nil
+}
+
+// DiscoverDir returns the projects described by the manifests directly in
+// the root directory, without descending into subdirectories. Manifests in a
+// "requirements/" folder are included because they describe the root.
+func (w *Walker) DiscoverDir() ([]*models.Project, error) {
+	var projects []*models.Project
+	w.warnings = nil
+	manifestPatterns := w.registry.GetManifestPatterns()
+	seen := make(map[projectKey]int)
+
+	entries, err := os.ReadDir(w.rootDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			paths = append(paths, filepath.Join(w.rootDir, entry.Name()))
+		}
+	}
+	if reqEntries, err := os.ReadDir(filepath.Join(w.rootDir, "requirements")); err == nil {
+		for _, entry := range reqEntries {
+			if !entry.IsDir() {
+				paths = append(paths, filepath.Join(w.rootDir, "requirements", entry.Name()))
+			}
+		}
+	}
+
+	for _, path := range paths {
+		if project := w.detectManifest(path, manifestPatterns); project != nil {
+			// Declared modules live in subdirectories, which are not classified
+			takeModules(project)
+			projects = w.appendProject(projects, seen, project)
+		}
+	}
+
 	return projects, nil
 }
 
-// matchesManifest checks if a filename matches any manifest pattern.
-func (w *Walker) matchesManifest(filename string, patterns []string) bool {
+// detectManifest runs the registry on path if it is a manifest that is not
+// ignored. Detection warnings are collected on the walker. The returned
+// project's path is relative to the walker root.
+func (w *Walker) detectManifest(path string, manifestPatterns []string) *models.Project {
+	// Check if this file matches any manifest pattern
+	if !w.matchesManifest(path, manifestPatterns) {
+		return nil
+	}
+
+	// Skip ignored files
+	if w.matcher.ShouldIgnoreFile(path) {
+		return nil
+	}
+
+	// Read file content
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil // Skip unreadable files
+	}
+
+	// Try to detect project
+	project, warnings, err := w.registry.DetectProjectWithWarnings(path, content)
+	for _, warning := range warnings {
+		if relPath, err := filepath.Rel(w.rootDir, warning.ManifestPath); err == nil {
+			warning.ManifestPath = relPath
+		}
+		w.warnings = append(w.warnings, warning)
+	}
+	if err != nil || project == nil {
+		return nil // Skip detection errors
+	}
+
+	// Make paths relative to root
+	for _, p := range append([]*models.Project{project}, project.Children...) {
+		if relPath, err := filepath.Rel(w.rootDir, p.Path); err == nil {
+			p.Path = relPath
+		}
+	}
+
+	if relPath, err := filepath.Rel(w.rootDir, path); err == nil {
+		w.log("detected %s project %q from %s", project.Runtime.Type, project.Name, filepath.ToSlash(relPath))
+	}
+
+	return project
+}
+
+// takeModules detaches and returns the modules a detector declared as
+// children of project.
+func takeModules(project *models.Project) []*models.Project {
+	modules := project.Children
+	project.Children = nil
+	return modules
+}
+
+// appendModules adds declared modules to projects unless a manifest in the
+// module directory already produced a project of the same runtime, which
+// describes the module more accurately.
+func appendModules(projects, modules []*models.Project) []*models.Project {
+	seen := make(map[projectKey]bool, len(projects))
+	for _, p := range projects {
+		seen[projectKey{p.Path, p.Runtime.Type}] = true
+	}
+
+	for _, m := range modules {
+		k := projectKey{m.Path, m.Runtime.Type}
+		if !seen[k] {
+			seen[k] = true
+			projects = append(projects, m)
+		}
+	}
+	return projects
+}
+
+// projectKey identifies a discovered project by directory and runtime.
+type projectKey struct {
+	path    string
+	runtime models.RuntimeType
+}
+
+// appendProject adds project to projects, keeping a single project per
+// directory and runtime when several manifests describe it, such as a
+// pyproject.toml next to a requirements.txt. seen indexes the kept projects.
+func (w *Walker) appendProject(projects []*models.Project, seen map[projectKey]int, project *models.Project) []*models.Project {
+	k := projectKey{project.Path, project.Runtime.Type}
+	idx, ok := seen[k]
+	if !ok {
+		seen[k] = len(projects)
+		return append(projects, project)
+	}
+
+	if w.preferManifest(project, projects[idx]) {
+		projects[idx] = project
+	}
+	return projects
+}
+
+// preferManifest reports whether candidate describes its project better
+// than current. The manifest with the higher registry priority wins (e.g.
+// CMakeLists.txt over a Makefile, pyproject.toml over requirements.txt);
+// between equally ranked manifests, the one that yielded more of a name, a
+// version, and a runtime version wins, then the canonical requirements.txt
+// over its variants. On a full tie the manifest found first is kept.
+func (w *Walker) preferManifest(candidate, current *models.Project) bool {
+	if c, k := w.registry.ManifestPriority(candidate.ManifestFile), w.registry.ManifestPriority(current.ManifestFile); c != k {
+		return c < k
+	}
+	if c, k := manifestRichness(candidate), manifestRichness(current); c != k {
+		return c > k
+	}
+	return candidate.ManifestFile == "requirements.txt" && current.ManifestFile != "requirements.txt"
+}
+
+// manifestRichness scores how much a project's manifest told about it.
+func manifestRichness(project *models.Project) int {
+	score := 0
+	if project.Name != "" && project.Name != filepath.Base(project.Path) {
+		score++
+	}
+	if project.Version != "" {
+		score++
+	}
+	if project.Runtime.Version != "" {
+		score++
+	}
+	return score
+}
+
+// Warnings returns the non-fatal detection warnings collected by the last
+// call to Discover, with manifest paths relative to the walker root.
+func (w *Walker) Warnings() []detector.Warning {
+	return w.warnings
+}
+
+// matchesManifest checks if a file matches any manifest pattern.
+// Patterns containing a "/" (e.g. "requirements/*.txt") are matched against
+// the file's parent directory name and filename.
+func (w *Walker) matchesManifest(path string, patterns []string) bool {
+	filename := filepath.Base(path)
+	parentAndName := filepath.Base(filepath.Dir(path)) + "/" + filename
+
 	for _, pattern := range patterns {
+		if strings.Contains(pattern, "/") {
+			if matched, err := filepath.Match(pattern, parentAndName); err == nil && matched {
+				return true
+			}
+			continue
+		}
+
 		// Check for exact match
 		if pattern == filename {
 			return true
@@ -0,0 +1,579 @@
+package discovery
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"repoctr/internal/detector"
+	"repoctr/internal/ignore"
+	"repoctr/pkg/models"
+)
+
+// writeFile creates a file (and its parent directories) under root.
+func writeFile(t *testing.T, root, rel, content string) {
+	t.Helper()
+	full := filepath.Join(root, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+		t.Fatalf("write %s: %v", rel, err)
+	}
+}
+
+func TestWalker_RequirementsDirectory(t *testing.T) {
+	root := t.TempDir()
+
+	writeFile(t, root, "requirements/base.txt", "requests\n")
+	writeFile(t, root, "requirements/dev.txt", "-r base.txt\npytest\n")
+
+	walker, err := NewWalker(root, detector.NewRegistry())
+	if err != nil {
+		t.Fatalf("NewWalker: %v", err)
+	}
+
+	projects, err := walker.Discover()
+	if err != nil {
+		t.Fatalf("Discover: %v", err)
+	}
+
+	if len(projects) != 1 {
+		t.Fatalf("expected 1 project, got %d", len(projects))
+	}
+	if projects[0].Path != "." {
+		t.Errorf("path = %q, want %q", projects[0].Path, ".")
+	}
+	if projects[0].ManifestFile != "requirements/base.txt" {
+		t.Errorf("manifest = %q, want %q", projects[0].ManifestFile, "requirements/base.txt")
+	}
+}
+
+func TestWalker_RequirementsVariantsDeduplicated(t *testing.T) {
+	root := t.TempDir()
+
+	writeFile(t, root, "svc/requirements-dev.txt", "pytest\n")
+	writeFile(t, root, "svc/requirements.txt", "flask\n")
+	writeFile(t, root, "svc/constraints.txt", "flask<3\n")
+	writeFile(t, root, "tools/requirements-dev.txt", "black\n")
+
+	walker, err := NewWalker(root, detector.NewRegistry())
+	if err != nil {
+		t.Fatalf("NewWalker: %v", err)
+	}
+
+	projects, err := walker.Discover()
+	if err != nil {
+		t.Fatalf("Discover: %v", err)
+	}
+
+	if len(projects) != 2 {
+		t.Fatalf("expected 2 projects, got %d", len(projects))
+	}
+
+	manifests := map[string]string{}
+	for _, p := range projects {
+		manifests[filepath.ToSlash(p.Path)] = p.ManifestFile
+	}
+	if manifests["svc"] != "requirements.txt" {
+		t.Errorf("svc manifest = %q, want %q", manifests["svc"], "requirements.txt")
+	}
+	if manifests["tools"] != "requirements-dev.txt" {
+		t.Errorf("tools manifest = %q, want %q", manifests["tools"], "requirements-dev.txt")
+	}
+}
+
+func TestWalker_MultipleManifestsDeduplicated(t *testing.T) {
+	root := t.TempDir()
+
+	writeFile(t, root, "svc/pyproject.toml", "[project]\nname = \"billing\"\nrequires-python = \">=3.11\"\n")
+	writeFile(t, root, "svc/requirements.txt", "flask\n")
+	// setup.py is walked after requirements.txt but still wins
+	writeFile(t, root, "app/requirements.txt", "django\n")
+	writeFile(t, root, "app/setup.py", "from setuptools import setup\n\nsetup(name=\"portal\", python_requires=\">=3.9\")\n")
+
+	walker, err := NewWalker(root, detector.NewRegistry())
+	if err != nil {
+		t.Fatalf("NewWalker: %v", err)
+	}
+
+	projects, err := walker.Discover()
+	if err != nil {
+		t.Fatalf("Discover: %v", err)
+	}
+
+	if len(projects) != 2 {
+		t.Fatalf("expected 2 projects, got %d", len(projects))
+	}
+
+	names := map[string]string{}
+	for _, p := range projects {
+		names[p.Path] = p.Name + " (" + p.ManifestFile + ")"
+	}
+	if names["svc"] != "billing (pyproject.toml)" {
+		t.Errorf("svc = %q, want %q", names["svc"], "billing (pyproject.toml)")
+	}
+	if names["app"] != "portal (setup.py)" {
+		t.Errorf("app = %q, want %q", names["app"], "portal (setup.py)")
+	}
+}
+
+func TestWalker_ManifestPriority(t *testing.T) {
+	root := t.TempDir()
+
+	writeFile(t, root, "engine/CMakeLists.txt", "cmake_minimum_required(VERSION 3.20)\nproject(engine)\nset(CMAKE_CXX_STANDARD 20)\n")
+	writeFile(t, root, "engine/Makefile", "CXXFLAGS = -std=c++17\n\nall: main.o\n\t$(CXX) -o engine main.o\n")
+	// Pipfile is walked first and declares a Python version, but
+	// pyproject.toml ranks higher
+	writeFile(t, root, "api/Pipfile", "[packages]\nflask = \"*\"\n\n[requires]\npython_version = \"3.11\"\n")
+	writeFile(t, root, "api/pyproject.toml", "[build-system]\nrequires = [\"setuptools\"]\n")
+
+	walker, err := NewWalker(root, detector.NewRegistry())
+	if err != nil {
+		t.Fatalf("NewWalker: %v", err)
+	}
+
+	projects, err := walker.Discover()
+	if err != nil {
+		t.Fatalf("Discover: %v", err)
+	}
+
+	if len(projects) != 2 {
+		t.Fatalf("expected 2 projects, got %d", len(projects))
+	}
+
+	manifests := map[string]string{}
+	for _, p := range projects {
+		manifests[p.Path] = p.ManifestFile
+	}
+	if manifests["engine"] != "CMakeLists.txt" {
+		t.Errorf("engine manifest = %q, want CMakeLists.txt", manifests["engine"])
+	}
+	if manifests["api"] != "pyproject.toml" {
+		t.Errorf("api manifest = %q, want pyproject.toml", manifests["api"])
+	}
+}
+
+func TestWalker_CollectsDetectionWarnings(t *testing.T) {
+	root := t.TempDir()
+
+	writeFile(t, root, "app/App.csproj", "<Project Sdk=\"Microsoft.NET.Sdk\">\n")
+	writeFile(t, root, "lib/go.mod", "module example.com/lib\n\ngo 1.22\n")
+
+	walker, err := NewWalker(root, detector.NewRegistry())
+	if err != nil {
+		t.Fatalf("NewWalker: %v", err)
+	}
+
+	projects, err := walker.Discover()
+	if err != nil {
+		t.Fatalf("Discover: %v", err)
+	}
+	if len(projects) != 2 {
+		t.Fatalf("expected 2 projects, got %d", len(projects))
+	}
+
+	warnings := walker.Warnings()
+	if len(warnings) != 1 {
+		t.Fatalf("expected 1 warning, got %v", warnings)
+	}
+	if want := filepath.Join("app", "App.csproj"); warnings[0].ManifestPath != want {
+		t.Errorf("manifest = %q, want %q", warnings[0].ManifestPath, want)
+	}
+}
+
+func TestWalker_HaskellCabalGlob(t *testing.T) {
+	root := t.TempDir()
+
+	writeFile(t, root, "parser/parser.cabal", "cabal-version: 3.0\nname: parser\nversion: 0.1.0\n")
+	writeFile(t, root, "parser/stack.yaml", "resolver: lts-22.7\n")
+	writeFile(t, root, "parser/src/Parser.hs", "module Parser where\n")
+
+	walker, err := NewWalker(root, detector.NewRegistry())
+	if err != nil {
+		t.Fatalf("NewWalker: %v", err)
+	}
+
+	projects, err := walker.Discover()
+	if err != nil {
+		t.Fatalf("Discover: %v", err)
+	}
+
+	if len(projects) != 1 {
+		t.Fatalf("expected 1 project, got %d", len(projects))
+	}
+	if projects[0].Name != "parser" {
+		t.Errorf("name = %q, want %q", projects[0].Name, "parser")
+	}
+	if projects[0].ManifestFile != "parser.cabal" {
+		t.Errorf("manifest = %q, want %q", projects[0].ManifestFile, "parser.cabal")
+	}
+}
+
+func TestWalker_ComposerAndPackageJSONCoexist(t *testing.T) {
+	root := t.TempDir()
+
+	writeFile(t, root, "composer.json", `{"name": "acme/shop", "require": {"php": "^8.2", "laravel/framework": "^11.0"}}`)
+	writeFile(t, root, "package.json", `{"name": "shop-assets", "devDependencies": {"vite": "^5.0.0"}}`)
+	writeFile(t, root, "packages/ui/package.json", `{"name": "ui"}`)
+
+	walker, err := NewWalker(root, detector.NewRegistry())
+	if err != nil {
+		t.Fatalf("NewWalker: %v", err)
+	}
+
+	projects, err := walker.Discover()
+	if err != nil {
+		t.Fatalf("Discover: %v", err)
+	}
+
+	roots := NewHierarchyBuilder().Build(projects)
+	runtimes := make(map[models.RuntimeType]string)
+	for _, p := range roots {
+		if p.Path != "." {
+			t.Errorf("unexpected root project %q at %q", p.Name, p.Path)
+		}
+		runtimes[p.Runtime.Type] = p.Name
+	}
+	if runtimes[models.RuntimePHP] != "shop" {
+		t.Errorf("PHP project = %q, want %q (roots: %v)", runtimes[models.RuntimePHP], "shop", runtimes)
+	}
+	if runtimes[models.RuntimeJavaScript] != "shop-assets" {
+		t.Errorf("JavaScript project = %q, want %q (roots: %v)", runtimes[models.RuntimeJavaScript], "shop-assets", runtimes)
+	}
+
+	if flat := NewHierarchyBuilder().Flatten(roots); len(flat) != 3 {
+		t.Errorf("expected 3 projects after building the hierarchy, got %d", len(flat))
+	}
+}
+
+func TestWalker_GradleSettingsModules(t *testing.T) {
+	root := t.TempDir()
+
+	writeFile(t, root, "settings.gradle", "rootProject.name = 'shop'\ninclude 'app', \"lib\"\ninclude ':services:auth'\n")
+	writeFile(t, root, "build.gradle", "subprojects {\n    apply plugin: 'java'\n}\n")
+	// lib has its own build script, which takes precedence over the declaration
+	writeFile(t, root, "lib/build.gradle", "plugins { id 'java-library' }\njava { toolchain { languageVersion = JavaLanguageVersion.of(21) } }\n")
+
+	walker, err := NewWalker(root, detector.NewRegistry())
+	if err != nil {
+		t.Fatalf("NewWalker: %v", err)
+	}
+
+	projects, err := walker.Discover()
+	if err != nil {
+		t.Fatalf("Discover: %v", err)
+	}
+
+	byPath := make(map[string]*models.Project)
+	for _, p := range projects {
+		if _, dup := byPath[p.Path]; dup {
+			t.Errorf("duplicate project at %q", p.Path)
+		}
+		byPath[filepath.ToSlash(p.Path)] = p
+	}
+
+	if len(projects) != 4 {
+		t.Fatalf("expected 4 projects, got %d: %v", len(projects), byPath)
+	}
+	if p := byPath["."]; p == nil || p.Name != "shop" || p.ManifestFile != "build.gradle" {
+		t.Errorf("root project = %+v, want shop from build.gradle", p)
+	}
+	if p := byPath["app"]; p == nil || p.ManifestFile != "../settings.gradle" {
+		t.Errorf("app project = %+v, want a module declared by ../settings.gradle", p)
+	}
+	if p := byPath["lib"]; p == nil || p.ManifestFile != "build.gradle" || p.Runtime.Version != "21" {
+		t.Errorf("lib project = %+v, want lib/build.gradle with Java 21", p)
+	}
+	if p := byPath["services/auth"]; p == nil || p.Name != "auth" {
+		t.Errorf("services/auth project = %+v, want module auth", p)
+	}
+
+	roots := NewHierarchyBuilder().Build(projects)
+	if len(roots) != 1 || len(roots[0].Children) != 3 {
+		t.Errorf("expected one root with 3 module children")
+	}
+}
+
+func TestWalker_NimbleManifest(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "tools/foo.nimble", "version = \"0.2.0\"\n")
+	writeFile(t, root, "tools/src/foo.nim", "echo \"hi\"\n")
+
+	walker, err := NewWalker(root, detector.NewRegistry())
+	if err != nil {
+		t.Fatalf("NewWalker: %v", err)
+	}
+
+	projects, err := walker.Discover()
+	if err != nil {
+		t.Fatalf("Discover: %v", err)
+	}
+
+	if len(projects) != 1 {
+		t.Fatalf("expected 1 project, got %d", len(projects))
+	}
+	p := projects[0]
+	if p.Runtime.Type != models.RuntimeNim || p.Name != "foo" || filepath.ToSlash(p.Path) != "tools" {
+		t.Errorf("project = %+v, want Nim project foo at tools", p)
+	}
+}
+
+func TestWalker_MavenModules(t *testing.T) {
+	root := t.TempDir()
+
+	writeFile(t, root, "pom.xml", `<project>
+  <artifactId>shop-parent</artifactId>
+  <packaging>pom</packaging>
+  <properties>
+    <maven.compiler.source>17</maven.compiler.source>
+  </properties>
+  <modules>
+    <module>core</module>
+    <module>web/pom.xml</module>
+  </modules>
+</project>
+`)
+	// web has its own POM, which takes precedence over the declaration
+	writeFile(t, root, "web/pom.xml", "<project>\n  <artifactId>shop-web</artifactId>\n</project>\n")
+
+	walker, err := NewWalker(root, detector.NewRegistry())
+	if err != nil {
+		t.Fatalf("NewWalker: %v", err)
+	}
+
+	projects, err := walker.Discover()
+	if err != nil {
+		t.Fatalf("Discover: %v", err)
+	}
+
+	byPath := make(map[string]*models.Project)
+	for _, p := range projects {
+		if _, dup := byPath[p.Path]; dup {
+			t.Errorf("duplicate project at %q", p.Path)
+		}
+		byPath[filepath.ToSlash(p.Path)] = p
+	}
+
+	if len(projects) != 3 {
+		t.Fatalf("expected 3 projects, got %d: %v", len(projects), byPath)
+	}
+	if p := byPath["."]; p == nil || p.Name != "shop-parent" {
+		t.Errorf("root project = %+v, want shop-parent", p)
+	}
+	if p := byPath["core"]; p == nil || p.ManifestFile != "../pom.xml" || p.Runtime.Version != "17" {
+		t.Errorf("core project = %+v, want a Java 17 module declared by ../pom.xml", p)
+	}
+	if p := byPath["web"]; p == nil || p.Name != "shop-web" || p.ManifestFile != "pom.xml" {
+		t.Errorf("web project = %+v, want shop-web from web/pom.xml", p)
+	}
+
+	roots := NewHierarchyBuilder().Build(projects)
+	if len(roots) != 1 || len(roots[0].Children) != 2 {
+		t.Errorf("expected one root with 2 module children")
+	}
+}
+
+func TestWalker_ReactNativeApp(t *testing.T) {
+	root := t.TempDir()
+
+	writeFile(t, root, "package.json", `{"name": "MobileApp", "dependencies": {"react": "18.2.0", "react-native": "0.74.1"}}`)
+	writeFile(t, root, "App.tsx", "export default function App() { return null }\n")
+	writeFile(t, root, "android/settings.gradle", "rootProject.name = 'MobileApp'\ninclude ':app'\n")
+	writeFile(t, root, "android/build.gradle", "buildscript {\n    repositories { google() }\n}\n")
+	writeFile(t, root, "android/app/build.gradle", "apply plugin: \"com.android.application\"\ndependencies {\n    implementation(\"com.facebook.react:react-android\")\n}\n")
+	writeFile(t, root, "ios/Podfile", "platform :ios, '13.4'\n\ntarget 'MobileApp' do\n  config = use_native_modules!\n  pod 'FirebaseAnalytics'\nend\n")
+
+	walker, err := NewWalker(root, detector.NewRegistry())
+	if err != nil {
+		t.Fatalf("NewWalker: %v", err)
+	}
+
+	projects, err := walker.Discover()
+	if err != nil {
+		t.Fatalf("Discover: %v", err)
+	}
+
+	roots := NewHierarchyBuilder().Build(projects)
+	if len(roots) != 1 {
+		t.Fatalf("expected the app as the only root, got %d roots", len(roots))
+	}
+	app := roots[0]
+	if app.Path != "." || app.Runtime.Type != models.RuntimeTypeScript || app.Framework != models.FrameworkReactNative {
+		t.Errorf("root = %+v, want a React Native TypeScript app at .", app)
+	}
+
+	children := make(map[string]*models.Project)
+	for _, c := range app.Children {
+		children[filepath.ToSlash(c.Path)] = c
+	}
+	if len(children) != 2 {
+		t.Fatalf("expected android and ios children, got %v", children)
+	}
+	if p := children["android"]; p == nil || p.Runtime.Type != models.RuntimeJava || p.Framework != "" {
+		t.Errorf("android = %+v, want a Gradle project", p)
+	} else if len(p.Children) != 1 || filepath.ToSlash(p.Children[0].Path) != "android/app" {
+		t.Errorf("android children = %v, want the app module", p.Children)
+	}
+	if p := children["ios"]; p == nil || p.Runtime.Type != models.RuntimeSwift || p.Name != "MobileApp" || p.PackageManager != "cocoapods" {
+		t.Errorf("ios = %+v, want the MobileApp CocoaPods project", p)
+	}
+}
+
+func TestWalker_Logf(t *testing.T) {
+	root := t.TempDir()
+
+	writeFile(t, root, "svc/go.mod", "module example.com/svc\n\ngo 1.22\n")
+	writeFile(t, root, "node_modules/dep/package.json", `{"name": "dep"}`)
+
+	walker, err := NewWalker(root, detector.NewRegistry())
+	if err != nil {
+		t.Fatalf("NewWalker: %v", err)
+	}
+	var logged []string
+	walker.SetLogf(func(format string, args ...any) {
+		logged = append(logged, fmt.Sprintf(format, args...))
+	})
+
+	if _, err := walker.Discover(); err != nil {
+		t.Fatalf("Discover: %v", err)
+	}
+
+	want := []string{
+		"skipped ignored directory node_modules",
+		`detected Go project "svc" from svc/go.mod`,
+	}
+	if !slices.Equal(logged, want) {
+		t.Errorf("logged = %q, want %q", logged, want)
+	}
+}
+
+func TestWalker_FollowSymlinks(t *testing.T) {
+	root := t.TempDir()
+	shared := t.TempDir()
+
+	writeFile(t, shared, "go.mod", "module example.com/shared\n\ngo 1.22\n")
+	if err := os.Symlink(shared, filepath.Join(root, "shared")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+	if err := os.Symlink(root, filepath.Join(root, "loop")); err != nil {
+		t.Fatalf("symlink: %v", err)
+	}
+
+	discover := func(follow bool) []string {
+		t.Helper()
+		walker, err := NewWalker(root, detector.NewRegistry())
+		if err != nil {
+			t.Fatalf("NewWalker: %v", err)
+		}
+		walker.SetFollowSymlinks(follow)
+		projects, err := walker.Discover()
+		if err != nil {
+			t.Fatalf("Discover: %v", err)
+		}
+		var paths []string
+		for _, p := range projects {
+			paths = append(paths, p.Path)
+		}
+		return paths
+	}
+
+	if paths := discover(false); len(paths) != 0 {
+		t.Errorf("without following symlinks found %q, want none", paths)
+	}
+	if paths := discover(true); !slices.Equal(paths, []string{"shared"}) {
+		t.Errorf("following symlinks found %q, want [shared]", paths)
+	}
+}
+
+func TestWalker_MaxDepth(t *testing.T) {
+	root := t.TempDir()
+
+	writeFile(t, root, "go.mod", "module example.com/root\n\ngo 1.22\n")
+	writeFile(t, root, "services/api/go.mod", "module example.com/api\n\ngo 1.22\n")
+	writeFile(t, root, "services/api/internal/tools/gen/go.mod", "module example.com/gen\n\ngo 1.22\n")
+
+	discover := func(depth int) []string {
+		t.Helper()
+		walker, err := NewWalker(root, detector.NewRegistry())
+		if err != nil {
+			t.Fatalf("NewWalker: %v", err)
+		}
+		walker.SetMaxDepth(depth)
+		projects, err := walker.Discover()
+		if err != nil {
+			t.Fatalf("Discover: %v", err)
+		}
+		var paths []string
+		for _, p := range projects {
+			paths = append(paths, filepath.ToSlash(p.Path))
+		}
+		slices.Sort(paths)
+		return paths
+	}
+
+	if got, want := discover(2), []string{".", "services/api"}; !slices.Equal(got, want) {
+		t.Errorf("max depth 2 found %q, want %q", got, want)
+	}
+	if got, want := discover(1), []string{"."}; !slices.Equal(got, want) {
+		t.Errorf("max depth 1 found %q, want %q", got, want)
+	}
+	if got, want := discover(0), []string{".", "services/api", "services/api/internal/tools/gen"}; !slices.Equal(got, want) {
+		t.Errorf("unlimited depth found %q, want %q", got, want)
+	}
+}
+
+func TestWalker_PathFilter(t *testing.T) {
+	root := t.TempDir()
+
+	writeFile(t, root, "go.mod", "module example.com/root\n\ngo 1.22\n")
+	writeFile(t, root, "services/api/go.mod", "module example.com/api\n\ngo 1.22\n")
+	writeFile(t, root, "services/api/testdata/fixture/go.mod", "module example.com/fixture\n\ngo 1.22\n")
+	writeFile(t, root, "services/web/package.json", `{"name": "web"}`)
+	writeFile(t, root, "tools/lint/go.mod", "module example.com/lint\n\ngo 1.22\n")
+
+	filter, err := ignore.NewPathFilter([]string{"services/**"}, []string{"**/testdata/**"})
+	if err != nil {
+		t.Fatalf("NewPathFilter: %v", err)
+	}
+	walker, err := NewWalker(root, detector.NewRegistry())
+	if err != nil {
+		t.Fatalf("NewWalker: %v", err)
+	}
+	walker.SetPathFilter(filter)
+	var logged []string
+	walker.SetLogf(func(format string, args ...any) {
+		logged = append(logged, fmt.Sprintf(format, args...))
+	})
+
+	projects, err := walker.Discover()
+	if err != nil {
+		t.Fatalf("Discover: %v", err)
+	}
+	var paths []string
+	for _, p := range projects {
+		paths = append(paths, filepath.ToSlash(p.Path))
+	}
+	slices.Sort(paths)
+	if want := []string{"services/api", "services/web"}; !slices.Equal(paths, want) {
+		t.Errorf("discovered %q, want %q", paths, want)
+	}
+
+	// Subtrees outside the filter are never entered
+	var skipped []string
+	for _, msg := range logged {
+		if strings.HasSuffix(msg, "outside the path filter") {
+			skipped = append(skipped, msg)
+		}
+	}
+	want := []string{
+		"skipped services/api/testdata outside the path filter",
+		"skipped tools outside the path filter",
+	}
+	if !slices.Equal(skipped, want) {
+		t.Errorf("skipped %q, want %q", skipped, want)
+	}
+}
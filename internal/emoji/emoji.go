@@ -23,6 +23,24 @@ func Map(rt models.RuntimeType) string {
 		return "🦀"
 	case models.RuntimeCpp:
 		return "⚙️"
+	case models.RuntimeHaskell:
+		return "λ"
+	case models.RuntimePHP:
+		return "🐘"
+	case models.RuntimeSQL:
+		return "🗄️"
+	case models.RuntimeNim:
+		return "👑"
+	case models.RuntimeR:
+		return "📐"
+	case models.RuntimeSwift:
+		return "🐦"
+	case models.RuntimeClojure:
+		return "🌀"
+	case models.RuntimeOCaml:
+		return "🐫"
+	case models.RuntimeKotlin:
+		return "🟠"
 	default:
 		return "📦"
 	}
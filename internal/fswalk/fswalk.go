@@ -0,0 +1,105 @@
+// Package fswalk walks directory trees, optionally following symbolic links
+// to directories.
+package fswalk
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// WalkDir walks the file tree rooted at root like filepath.WalkDir.
+//
+// When followSymlinks is set, symbolic links to directories outside root are
+// walked as well, with paths reported below the link rather than its target.
+// Links to directories inside root are not followed, since the real
+// directory is walked under its own path anyway. Each directory is walked at
+// most once, identified by its resolved path, so a link that forms a cycle or
+// leads to a directory already walked is not followed either. Links that are
+// not followed, to files, or dangling are reported as filepath.WalkDir
+// reports them.
+func WalkDir(root string, followSymlinks bool, fn fs.WalkDirFunc) error {
+	if !followSymlinks {
+		return filepath.WalkDir(root, fn)
+	}
+
+	realRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return filepath.WalkDir(root, fn)
+	}
+
+	w := &walker{fn: fn, realRoot: realRoot, visited: make(map[string]bool)}
+	if err := w.walk(root, realRoot); err != nil && err != filepath.SkipDir && err != filepath.SkipAll {
+		return err
+	}
+	return nil
+}
+
+// walker holds the state of a walk that follows symbolic links.
+type walker struct {
+	fn fs.WalkDirFunc
+	// realRoot is the resolved root of the walk.
+	realRoot string
+	// visited holds the resolved path of every directory walked so far.
+	visited map[string]bool
+	// stopped is set once fn returns filepath.SkipAll, which a nested walk
+	// would otherwise swallow.
+	stopped bool
+}
+
+// walk walks the resolved directory realDir, reporting its entries below
+// path.
+func (w *walker) walk(path, realDir string) error {
+	return filepath.WalkDir(realDir, func(realPath string, d fs.DirEntry, err error) error {
+		reported := path
+		if rel, relErr := filepath.Rel(realDir, realPath); relErr == nil && rel != "." {
+			reported = filepath.Join(path, rel)
+		}
+
+		if err == nil && d.IsDir() {
+			if w.visited[realPath] {
+				return filepath.SkipDir
+			}
+			w.visited[realPath] = true
+		}
+
+		if err == nil && d.Type()&fs.ModeSymlink != 0 {
+			if target, ok := symlinkedDir(realPath); ok && !w.visited[target] && !isWithin(w.realRoot, target) {
+				if err := w.walk(reported, target); err != nil {
+					return err
+				}
+				if w.stopped {
+					return filepath.SkipAll
+				}
+				return nil
+			}
+		}
+
+		result := w.fn(reported, d, err)
+		if result == filepath.SkipAll {
+			w.stopped = true
+		}
+		return result
+	})
+}
+
+// symlinkedDir returns the resolved path of the symbolic link at path
+// and whether it leads to a directory.
+func symlinkedDir(path string) (string, bool) {
+	target, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", false
+	}
+	info, err := os.Stat(target)
+	if err != nil || !info.IsDir() {
+		return "", false
+	}
+	return target, true
+}
+
+// isWithin reports whether path is dir or lies below it.
+func isWithin(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
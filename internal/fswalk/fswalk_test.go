@@ -0,0 +1,67 @@
+package fswalk
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestWalkDir_FollowSymlinks(t *testing.T) {
+	root := t.TempDir()
+	target := t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(root, "a.txt"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(target, "b.txt"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(target, filepath.Join(root, "link")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+	// A real directory inside the root and a link to it, which sorts first
+	if err := os.Mkdir(filepath.Join(root, "zsvc"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "zsvc", "c.txt"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(root, "zsvc"), filepath.Join(root, "alias")); err != nil {
+		t.Fatal(err)
+	}
+	// Neither a second link to the same directory nor a link back to the
+	// root is followed
+	if err := os.Symlink(target, filepath.Join(root, "link2")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(root, filepath.Join(target, "up")); err != nil {
+		t.Fatal(err)
+	}
+
+	walk := func(follow bool) []string {
+		t.Helper()
+		var paths []string
+		err := WalkDir(root, follow, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			rel, _ := filepath.Rel(root, path)
+			paths = append(paths, filepath.ToSlash(rel))
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("WalkDir: %v", err)
+		}
+		return paths
+	}
+
+	if got, want := walk(false), []string{".", "a.txt", "alias", "link", "link2", "zsvc", "zsvc/c.txt"}; !slices.Equal(got, want) {
+		t.Errorf("without following = %q, want %q", got, want)
+	}
+	// The real zsvc is walked under its own path, not through alias
+	if got, want := walk(true), []string{".", "a.txt", "alias", "link", "link/b.txt", "link/up", "link2", "zsvc", "zsvc/c.txt"}; !slices.Equal(got, want) {
+		t.Errorf("following = %q, want %q", got, want)
+	}
+}
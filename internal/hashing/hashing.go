@@ -0,0 +1,60 @@
+package hashing
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"fmt"
+	"hash"
+	"strings"
+)
+
+// Algorithm names a content hash algorithm.
+type Algorithm string
+
+const (
+	SHA256 Algorithm = "sha256"
+	SHA1   Algorithm = "sha1"
+	// XXHash is 64-bit xxHash (XXH64, seed 0). It is much faster than the
+	// cryptographic algorithms but offers no collision resistance against
+	// deliberate tampering.
+	XXHash Algorithm = "xxhash"
+)
+
+// Default is the algorithm used when none is configured.
+const Default = SHA256
+
+// Algorithms lists the supported algorithms.
+var Algorithms = []Algorithm{SHA256, SHA1, XXHash}
+
+// Parse returns the algorithm named s (case-insensitive). An empty name
+// selects Default.
+func Parse(s string) (Algorithm, error) {
+	if s == "" {
+		return Default, nil
+	}
+
+	algo := Algorithm(strings.ToLower(s))
+	for _, known := range Algorithms {
+		if algo == known {
+			return algo, nil
+		}
+	}
+
+	names := make([]string, len(Algorithms))
+	for i, known := range Algorithms {
+		names[i] = string(known)
+	}
+	return "", fmt.Errorf("unknown hash algorithm %q (supported: %s)", s, strings.Join(names, ", "))
+}
+
+// New returns a new hash.Hash for algo. The zero Algorithm selects Default.
+func New(algo Algorithm) hash.Hash {
+	switch algo {
+	case SHA1:
+		return sha1.New()
+	case XXHash:
+		return newXXH64()
+	default:
+		return sha256.New()
+	}
+}
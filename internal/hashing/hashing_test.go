@@ -0,0 +1,88 @@
+package hashing
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func sum(algo Algorithm, data string) string {
+	h := New(algo)
+	h.Write([]byte(data))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func TestNew_KnownDigests(t *testing.T) {
+	tests := []struct {
+		algo Algorithm
+		data string
+		want string
+	}{
+		{SHA256, "abc", "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+		{SHA1, "abc", "a9993e364706816aba3e25717850c26c9cd0d89d"},
+		{XXHash, "", "ef46db3751d8e999"},
+		{XXHash, "a", "d24ec4f1a98c6e5b"},
+		{XXHash, "abc", "44bc2cf5ad770999"},
+		{XXHash, "Nobody inspects the spammish repetition", "fbcea83c8a378bf1"},
+	}
+
+	for _, tt := range tests {
+		if got := sum(tt.algo, tt.data); got != tt.want {
+			t.Errorf("%s(%q) = %s, want %s", tt.algo, tt.data, got, tt.want)
+		}
+	}
+}
+
+func TestNew_ConsistentAcrossWrites(t *testing.T) {
+	// Long enough to cover xxhash's 32-byte stripes and every tail path
+	content := strings.Repeat("package main\n\nfunc main() {}\n", 7) + "tail!"
+
+	for _, algo := range Algorithms {
+		want := sum(algo, content)
+		if again := sum(algo, content); again != want {
+			t.Errorf("%s: digest changed between runs: %s != %s", algo, again, want)
+		}
+
+		// Feeding the same content in uneven chunks must not change the digest
+		h := New(algo)
+		for rest := content; rest != ""; {
+			n := min(len(rest), 5)
+			h.Write([]byte(rest[:n]))
+			rest = rest[n:]
+		}
+		if chunked := hex.EncodeToString(h.Sum(nil)); chunked != want {
+			t.Errorf("%s: chunked digest %s, want %s", algo, chunked, want)
+		}
+
+		h.Reset()
+		h.Write([]byte(content))
+		if reset := hex.EncodeToString(h.Sum(nil)); reset != want {
+			t.Errorf("%s: digest after Reset %s, want %s", algo, reset, want)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Algorithm
+		wantErr bool
+	}{
+		{"", SHA256, false},
+		{"sha256", SHA256, false},
+		{"SHA1", SHA1, false},
+		{"xxhash", XXHash, false},
+		{"md5", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := Parse(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Parse(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Parse(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package hashing
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// XXH64 primes from the xxHash specification. They are variables so the
+// seed setup in Reset can rely on wrapping arithmetic.
+var (
+	prime64_1 uint64 = 11400714785074694791
+	prime64_2 uint64 = 14029467366897019727
+	prime64_3 uint64 = 1609587929392839161
+	prime64_4 uint64 = 9650029242287828579
+	prime64_5 uint64 = 2870177450012600261
+)
+
+// xxh64 is a streaming implementation of XXH64 with seed 0. It implements
+// hash.Hash64; Sum appends the digest in big-endian order, matching the
+// canonical hex representation.
+type xxh64 struct {
+	v1, v2, v3, v4 uint64
+	total          uint64
+	buf            [32]byte
+	n              int
+}
+
+func newXXH64() *xxh64 {
+	d := &xxh64{}
+	d.Reset()
+	return d
+}
+
+func (d *xxh64) Reset() {
+	d.v1 = prime64_1 + prime64_2
+	d.v2 = prime64_2
+	d.v3 = 0
+	d.v4 = -prime64_1
+	d.total = 0
+	d.n = 0
+}
+
+func (d *xxh64) Size() int { return 8 }
+
+func (d *xxh64) BlockSize() int { return 32 }
+
+func (d *xxh64) Write(p []byte) (int, error) {
+	n := len(p)
+	d.total += uint64(n)
+
+	// Fill the buffered stripe first
+	if d.n > 0 {
+		c := copy(d.buf[d.n:], p)
+		d.n += c
+		p = p[c:]
+		if d.n < 32 {
+			return n, nil
+		}
+		d.stripe(d.buf[:])
+		d.n = 0
+	}
+
+	for len(p) >= 32 {
+		d.stripe(p[:32])
+		p = p[32:]
+	}
+
+	d.n = copy(d.buf[:], p)
+	return n, nil
+}
+
+// stripe consumes one 32-byte stripe into the accumulators.
+func (d *xxh64) stripe(b []byte) {
+	d.v1 = round(d.v1, binary.LittleEndian.Uint64(b[0:8]))
+	d.v2 = round(d.v2, binary.LittleEndian.Uint64(b[8:16]))
+	d.v3 = round(d.v3, binary.LittleEndian.Uint64(b[16:24]))
+	d.v4 = round(d.v4, binary.LittleEndian.Uint64(b[24:32]))
+}
+
+func (d *xxh64) Sum64() uint64 {
+	var h uint64
+	if d.total >= 32 {
+		h = bits.RotateLeft64(d.v1, 1) + bits.RotateLeft64(d.v2, 7) +
+			bits.RotateLeft64(d.v3, 12) + bits.RotateLeft64(d.v4, 18)
+		h = mergeRound(h, d.v1)
+		h = mergeRound(h, d.v2)
+		h = mergeRound(h, d.v3)
+		h = mergeRound(h, d.v4)
+	} else {
+		h = prime64_5
+	}
+	h += d.total
+
+	b := d.buf[:d.n]
+	for ; len(b) >= 8; b = b[8:] {
+		h ^= round(0, binary.LittleEndian.Uint64(b[:8]))
+		h = bits.RotateLeft64(h, 27)*prime64_1 + prime64_4
+	}
+	if len(b) >= 4 {
+		h ^= uint64(binary.LittleEndian.Uint32(b[:4])) * prime64_1
+		h = bits.RotateLeft64(h, 23)*prime64_2 + prime64_3
+		b = b[4:]
+	}
+	for _, c := range b {
+		h ^= uint64(c) * prime64_5
+		h = bits.RotateLeft64(h, 11) * prime64_1
+	}
+
+	h ^= h >> 33
+	h *= prime64_2
+	h ^= h >> 29
+	h *= prime64_3
+	h ^= h >> 32
+	return h
+}
+
+func (d *xxh64) Sum(b []byte) []byte {
+	return binary.BigEndian.AppendUint64(b, d.Sum64())
+}
+
+func round(acc, input uint64) uint64 {
+	acc += input * prime64_2
+	acc = bits.RotateLeft64(acc, 31)
+	return acc * prime64_1
+}
+
+func mergeRound(acc, val uint64) uint64 {
+	val = round(0, val)
+	acc ^= val
+	return acc*prime64_1 + prime64_4
+}
@@ -0,0 +1,129 @@
+// Package history analyzes how a repository grew over time from its git
+// log, as opposed to the current-state statistics of package stats.
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// commitPrefix starts the header line printed for each commit, followed by
+// the author date in strict ISO 8601 format.
+const commitPrefix = "commit "
+
+// Year holds the line changes of all commits authored in a calendar year.
+type Year struct {
+	Year    int
+	Commits int
+	Added   int
+	Deleted int
+	// Total is the running sum of net added lines up to and including
+	// this year.
+	Total int
+}
+
+// Net returns the lines added minus the lines deleted in the year.
+func (y Year) Net() int {
+	return y.Added - y.Deleted
+}
+
+// GitLog runs git log in dir and returns its output. It is a variable so
+// tests can inject log output.
+var GitLog = func(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", append([]string{"log"}, args...)...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git log failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+// ByYear attributes the lines added and deleted by every commit reachable
+// from HEAD in dir to the calendar year it was authored, limited to paths
+// (relative to dir) when any are given. Years are in ascending order.
+func ByYear(dir string, paths []string) ([]Year, error) {
+	args := []string{"--numstat", "--no-renames", "--format=" + commitPrefix + "%aI"}
+	if len(paths) > 0 {
+		args = append(append(args, "--"), paths...)
+	}
+
+	out, err := GitLog(dir, args...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseNumstat(bytes.NewReader(out))
+}
+
+// ParseNumstat aggregates 'git log --numstat' output per year. Each commit
+// must start with a "commit <author date>" header line, followed by
+// "added<TAB>deleted<TAB>path" lines. Binary files, reported as "-", are
+// skipped. Years without commits are omitted.
+func ParseNumstat(r io.Reader) ([]Year, error) {
+	byYear := make(map[int]*Year)
+	var current *Year
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+
+		if date, ok := strings.CutPrefix(line, commitPrefix); ok {
+			authored, err := time.Parse(time.RFC3339, strings.TrimSpace(date))
+			if err != nil {
+				return nil, fmt.Errorf("invalid commit date %q: %w", date, err)
+			}
+			// The year as the author saw it, in their own time zone
+			year := authored.Year()
+			current = byYear[year]
+			if current == nil {
+				current = &Year{Year: year}
+				byYear[year] = current
+			}
+			current.Commits++
+			continue
+		}
+
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) != 3 || current == nil {
+			continue
+		}
+		added, errAdded := strconv.Atoi(fields[0])
+		deleted, errDeleted := strconv.Atoi(fields[1])
+		if errAdded != nil || errDeleted != nil {
+			continue // Binary file
+		}
+		current.Added += added
+		current.Deleted += deleted
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	years := make([]Year, 0, len(byYear))
+	for _, y := range byYear {
+		years = append(years, *y)
+	}
+	sort.Slice(years, func(i, j int) bool {
+		return years[i].Year < years[j].Year
+	})
+
+	total := 0
+	for i := range years {
+		total += years[i].Net()
+		years[i].Total = total
+	}
+	return years, nil
+}
@@ -0,0 +1,78 @@
+package history
+
+import (
+	"strings"
+	"testing"
+)
+
+const testLog = `commit 2023-12-31T23:30:00-05:00
+10	2	main.go
+-	-	logo.png
+
+commit 2022-06-01T10:00:00+02:00
+100	0	main.go
+20	0	README.md
+
+commit 2022-01-15T09:00:00Z
+5	15	util.go
+commit 2021-03-03T12:00:00Z
+`
+
+func TestParseNumstat(t *testing.T) {
+	years, err := ParseNumstat(strings.NewReader(testLog))
+	if err != nil {
+		t.Fatalf("ParseNumstat: %v", err)
+	}
+
+	// A commit made late on New Year's Eve counts towards the author's year,
+	// and commits without changes (e.g. merges) still count as commits
+	want := []Year{
+		{Year: 2021, Commits: 1, Added: 0, Deleted: 0, Total: 0},
+		{Year: 2022, Commits: 2, Added: 125, Deleted: 15, Total: 110},
+		{Year: 2023, Commits: 1, Added: 10, Deleted: 2, Total: 118},
+	}
+	if len(years) != len(want) {
+		t.Fatalf("got %d years, want %d: %+v", len(years), len(want), years)
+	}
+	for i, y := range years {
+		if y != want[i] {
+			t.Errorf("year %d = %+v, want %+v", i, y, want[i])
+		}
+	}
+	if net := years[1].Net(); net != 110 {
+		t.Errorf("2022 net = %d, want 110", net)
+	}
+}
+
+func TestParseNumstat_InvalidDate(t *testing.T) {
+	if _, err := ParseNumstat(strings.NewReader("commit yesterday\n1\t0\ta.go\n")); err == nil {
+		t.Error("expected an error for an unparseable commit date")
+	}
+}
+
+func TestByYear_PassesPaths(t *testing.T) {
+	restore := GitLog
+	defer func() { GitLog = restore }()
+
+	var gotDir string
+	var gotArgs []string
+	GitLog = func(dir string, args ...string) ([]byte, error) {
+		gotDir, gotArgs = dir, args
+		return []byte(testLog), nil
+	}
+
+	years, err := ByYear("/repo", []string{"services/api"})
+	if err != nil {
+		t.Fatalf("ByYear: %v", err)
+	}
+	if len(years) != 3 {
+		t.Errorf("got %d years, want 3", len(years))
+	}
+
+	if gotDir != "/repo" {
+		t.Errorf("dir = %q, want /repo", gotDir)
+	}
+	if n := len(gotArgs); n < 2 || gotArgs[n-2] != "--" || gotArgs[n-1] != "services/api" {
+		t.Errorf("args = %v, want a trailing -- services/api pathspec", gotArgs)
+	}
+}
@@ -0,0 +1,93 @@
+package ignore
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PathFilter narrows a walk to the paths selected by gitignore-style include
+// and exclude patterns, on top of the ignore rules of a Matcher. Paths are
+// slash-separated and relative to the walk root. A nil PathFilter allows
+// every path.
+type PathFilter struct {
+	include []gitignoreRule
+	exclude []gitignoreRule
+}
+
+// NewPathFilter compiles include and exclude patterns. With no include
+// patterns every path not excluded is selected; otherwise a path must match
+// an include pattern and no exclude pattern. Patterns that can never match
+// a path, as reported by ValidatePattern, are an error.
+func NewPathFilter(include, exclude []string) (*PathFilter, error) {
+	filter := &PathFilter{}
+	for _, pattern := range include {
+		if err := ValidatePattern(pattern); err != nil {
+			return nil, fmt.Errorf("include pattern %q %v", pattern, err)
+		}
+		filter.include = append(filter.include, parseRule(pattern))
+	}
+	for _, pattern := range exclude {
+		if err := ValidatePattern(pattern); err != nil {
+			return nil, fmt.Errorf("exclude pattern %q %v", pattern, err)
+		}
+		filter.exclude = append(filter.exclude, parseRule(pattern))
+	}
+	return filter, nil
+}
+
+// AllowsDir reports whether a walk should descend into the directory at
+// relPath: it is not excluded and, when there are include patterns, it or
+// some path below it can match one.
+func (f *PathFilter) AllowsDir(relPath string) bool {
+	if f == nil {
+		return true
+	}
+	if anyRuleMatches(f.exclude, relPath, true) {
+		return false
+	}
+	if len(f.include) == 0 {
+		return true
+	}
+	for _, rule := range f.include {
+		if rule.matches(relPath, true) || rule.mayMatchBelow(relPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsFile reports whether the file at relPath is selected: it matches no
+// exclude pattern and, when there are include patterns, matches one of them.
+func (f *PathFilter) AllowsFile(relPath string) bool {
+	if f == nil {
+		return true
+	}
+	if anyRuleMatches(f.exclude, relPath, false) {
+		return false
+	}
+	return len(f.include) == 0 || anyRuleMatches(f.include, relPath, false)
+}
+
+// mayMatchBelow reports whether the rule could match a path inside the
+// directory at relPath. Unanchored rules match at any depth.
+func (rule gitignoreRule) mayMatchBelow(relPath string) bool {
+	if !rule.anchored {
+		return true
+	}
+	segments := strings.Split(relPath, "/")
+	for _, p := range expandBraces(rule.pattern) {
+		if matchSegmentsBelow(strings.Split(p, "/"), segments) {
+			return true
+		}
+	}
+	return false
+}
+
+func anyRuleMatches(rules []gitignoreRule, relPath string, isDir bool) bool {
+	for _, rule := range rules {
+		if rule.matches(relPath, isDir) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+package ignore
+
+import "testing"
+
+func TestPathFilter(t *testing.T) {
+	filter, err := NewPathFilter([]string{"services/**", "*.csproj"}, []string{"**/testdata/**", "legacy/"})
+	if err != nil {
+		t.Fatalf("NewPathFilter: %v", err)
+	}
+
+	dirs := map[string]bool{
+		"services":                  true,
+		"services/api":              true,
+		"services/api/testdata":     false,
+		"tools":                     true, // may hold a *.csproj
+		"services/legacy":           false,
+		"services/api/testdata/sub": false,
+	}
+	for dir, want := range dirs {
+		if got := filter.AllowsDir(dir); got != want {
+			t.Errorf("AllowsDir(%q) = %v, want %v", dir, got, want)
+		}
+	}
+
+	files := map[string]bool{
+		"go.mod":                           false,
+		"services/api/go.mod":              true,
+		"services/api/testdata/app/go.mod": false,
+		"tools/build/Build.csproj":         true,
+		"tools/build/go.mod":               false,
+	}
+	for file, want := range files {
+		if got := filter.AllowsFile(file); got != want {
+			t.Errorf("AllowsFile(%q) = %v, want %v", file, got, want)
+		}
+	}
+
+	// Anchored include patterns rule out unrelated subtrees
+	anchored, err := NewPathFilter([]string{"services/api/**"}, nil)
+	if err != nil {
+		t.Fatalf("NewPathFilter: %v", err)
+	}
+	for dir, want := range map[string]bool{"services": true, "services/api": true, "services/web": false, "tools": false} {
+		if got := anchored.AllowsDir(dir); got != want {
+			t.Errorf("AllowsDir(%q) with anchored include = %v, want %v", dir, got, want)
+		}
+	}
+
+	if _, err := NewPathFilter([]string{`services\api`}, nil); err == nil {
+		t.Error("NewPathFilter accepted a pattern with backslashes")
+	}
+
+	var none *PathFilter
+	if !none.AllowsDir("any") || !none.AllowsFile("any/go.mod") {
+		t.Error("nil PathFilter should allow every path")
+	}
+}
@@ -0,0 +1,60 @@
+package ignore
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+)
+
+// ValidatePattern reports why a custom pattern can never match a path, or
+// nil if it can. Patterns are compiled the way AddPatterns compiles them
+// and matched against slash-separated paths relative to the root, so
+// Windows backslashes, drive letters, and home directories never match,
+// nor do globs that path.Match rejects.
+func ValidatePattern(pattern string) error {
+	if strings.Contains(pattern, `\`) {
+		return fmt.Errorf("uses Windows backslashes, which never match; use %q", strings.ReplaceAll(pattern, `\`, "/"))
+	}
+
+	rule := parseRule(pattern)
+	if len(rule.pattern) >= 2 && rule.pattern[1] == ':' && isASCIILetter(rule.pattern[0]) {
+		return fmt.Errorf("is an absolute Windows path; use a path relative to the repository root")
+	}
+	if strings.HasPrefix(rule.pattern, "~") {
+		return fmt.Errorf("refers to a home directory; use a path relative to the repository root")
+	}
+	if rule.pattern == "" {
+		return fmt.Errorf("is empty after removing its slashes and matches nothing")
+	}
+
+	for _, p := range expandBraces(rule.pattern) {
+		for _, segment := range strings.Split(p, "/") {
+			if segment == "**" {
+				continue
+			}
+			if _, err := path.Match(segment, ""); err != nil {
+				return fmt.Errorf("is not a valid glob: %v", err)
+			}
+		}
+	}
+	return nil
+}
+
+// IsDefaultPattern reports whether pattern only repeats one of
+// DefaultIgnorePatterns, e.g. "node_modules", "node_modules/", or
+// "**/node_modules/**". Anchored or negated patterns are never duplicates.
+func IsDefaultPattern(pattern string) bool {
+	if strings.HasPrefix(pattern, "/") || strings.HasPrefix(pattern, "!") {
+		return false
+	}
+
+	name := strings.TrimPrefix(pattern, "**/")
+	name = strings.TrimSuffix(name, "/**")
+	name = strings.TrimSuffix(name, "/")
+	return slices.Contains(DefaultIgnorePatterns, name)
+}
+
+func isASCIILetter(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
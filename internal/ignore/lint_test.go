@@ -0,0 +1,46 @@
+package ignore
+
+import "testing"
+
+func TestValidatePattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		valid   bool
+	}{
+		{"**/*.snap", true},
+		{"/apps/legacy", true},
+		{"build/{a,b}/*.js", true},
+		{"!dist/keep.js", true},
+		{`build\out`, false},
+		{"C:/src/build", false},
+		{"~/cache", false},
+		{"src/[a-", false},
+		{"/", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			err := ValidatePattern(tt.pattern)
+			if (err == nil) != tt.valid {
+				t.Errorf("ValidatePattern(%q) = %v, want valid %v", tt.pattern, err, tt.valid)
+			}
+		})
+	}
+}
+
+func TestIsDefaultPattern(t *testing.T) {
+	for pattern, want := range map[string]bool{
+		"node_modules":      true,
+		"node_modules/":     true,
+		"**/vendor/**":      true,
+		"*.egg-info":        true,
+		"/node_modules":     false,
+		"!vendor":           false,
+		"apps/node_modules": false,
+		"coverage":          false,
+	} {
+		if got := IsDefaultPattern(pattern); got != want {
+			t.Errorf("IsDefaultPattern(%q) = %v, want %v", pattern, got, want)
+		}
+	}
+}
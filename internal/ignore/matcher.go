@@ -2,18 +2,52 @@ package ignore
 
 import (
 	"bufio"
+	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
+	"sync"
 )
 
 // Matcher handles gitignore patterns and custom ignore rules.
+//
+// Rules are layered with increasing precedence: the built-in defaults,
+// .gitignore files, the root .repoctrignore file, and custom patterns
+// (configured global excludes, then project excludes). When a later layer
+// matches a path it overrides the earlier ones, so a negated pattern
+// re-includes a path excluded by a lower layer. Directories excluded by
+// the defaults are only entered to reach paths re-included by anchored
+// negated custom patterns.
 type Matcher struct {
-	rootDir         string
-	defaultIgnores  map[string]bool
-	gitignoreRules  []gitignoreRule
-	customPatterns  []gitignoreRule
-	projectRootDir  string
+	rootDir        string
+	defaultIgnores map[string]bool
+	gitignores     *gitignoreSet
+	virtualEnvs    *virtualEnvSet
+	repoctrIgnores []gitignoreRule
+	customPatterns []gitignoreRule
+	projectRootDir string
+}
+
+// RepoctrIgnoreFile is the name of the repo-ctr specific ignore file, read
+// from the matcher's root. It uses gitignore syntax.
+const RepoctrIgnoreFile = ".repoctrignore"
+
+// gitignoreSet lazily loads and caches the .gitignore rules of each directory
+// under the root. Rules are keyed by the directory's slash-separated path
+// relative to the root ("." for the root itself).
+type gitignoreSet struct {
+	rootDir string
+	mu      sync.Mutex
+	rules   map[string][]gitignoreRule
+}
+
+// virtualEnvSet caches which directories are Python virtual environments,
+// keyed by the directory's path, so a directory is checked for pyvenv.cfg
+// once however many walks visit it.
+type virtualEnvSet struct {
+	mu   sync.Mutex
+	dirs map[string]bool
 }
 
 type gitignoreRule struct {
@@ -21,8 +55,28 @@ type gitignoreRule struct {
 	negate   bool
 	dirOnly  bool
 	anchored bool
+	// text is the rule as written and source where it is defined, for
+	// Explain.
+	text   string
+	source string
+}
+
+// Rule is an ignore rule that excludes a path, as reported by Explain.
+type Rule struct {
+	// Pattern is the rule as written, e.g. "dist/" or "*.pyc".
+	Pattern string
+	// Source is where the rule is defined: "default ignores", a .gitignore
+	// or .repoctrignore file and line relative to the root (e.g.
+	// "web/.gitignore:3"), or the source given to AddPatternsFrom.
+	Source string
+	// Path is the slash-separated path the rule matched, relative to the
+	// root: the explained path itself or an excluded directory above it.
+	Path string
 }
 
+// defaultSource is the Source of the built-in ignore rules.
+const defaultSource = "default ignores"
+
 // DefaultIgnorePatterns contains patterns that should always be ignored.
 var DefaultIgnorePatterns = []string{
 	// Version control
@@ -67,6 +121,19 @@ var DefaultIgnorePatterns = []string{
 	"Thumbs.db",
 }
 
+// GeneratedDirPatterns contains gitignore-style patterns for conventional
+// generated-code locations. They are opt-in (stats --exclude-generated-dirs)
+// and layered on top of the defaults as custom patterns.
+var GeneratedDirPatterns = []string{
+	"gen/",
+	"generated/",
+	"__generated__/",
+	"migrations/",
+	// Protocol buffer outputs
+	"*.pb.go",
+	"*.pb.gw.go",
+}
+
 // DefaultIgnoreExtensions contains file extensions to ignore.
 var DefaultIgnoreExtensions = []string{
 	".pyc",
@@ -83,6 +150,8 @@ func NewMatcher(rootDir string) (*Matcher, error) {
 	m := &Matcher{
 		rootDir:        rootDir,
 		defaultIgnores: make(map[string]bool),
+		gitignores:     newGitignoreSet(rootDir),
+		virtualEnvs:    &virtualEnvSet{dirs: make(map[string]bool)},
 	}
 
 	// Build default ignore set
@@ -90,17 +159,57 @@ func NewMatcher(rootDir string) (*Matcher, error) {
 		m.defaultIgnores[pattern] = true
 	}
 
-	// Load .gitignore if it exists
-	gitignorePath := filepath.Join(rootDir, ".gitignore")
-	if rules, err := parseGitignore(gitignorePath); err == nil {
-		m.gitignoreRules = rules
-	}
+	// Load the root .gitignore eagerly; nested ones are loaded on demand
+	m.gitignores.load(".")
+
+	// A missing .repoctrignore simply adds no rules
+	m.repoctrIgnores, _ = parseGitignore(filepath.Join(rootDir, RepoctrIgnoreFile), RepoctrIgnoreFile)
 
 	return m, nil
 }
 
-// parseGitignore reads and parses a .gitignore file.
-func parseGitignore(path string) ([]gitignoreRule, error) {
+func newGitignoreSet(rootDir string) *gitignoreSet {
+	return &gitignoreSet{
+		rootDir: rootDir,
+		rules:   make(map[string][]gitignoreRule),
+	}
+}
+
+// load returns the rules of the .gitignore in dir (relative to the root),
+// reading and caching the file on first use. Missing files yield no rules.
+func (s *gitignoreSet) load(dir string) []gitignoreRule {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if rules, ok := s.rules[dir]; ok {
+		return rules
+	}
+
+	rules, err := parseGitignore(filepath.Join(s.rootDir, filepath.FromSlash(dir), ".gitignore"), path.Join(dir, ".gitignore"))
+	if err != nil {
+		rules = nil
+	}
+	s.rules[dir] = rules
+	return rules
+}
+
+// contains reports whether dir is a Python virtual environment, checking
+// the filesystem only the first time dir is asked about.
+func (s *virtualEnvSet) contains(dir string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	isVenv, ok := s.dirs[dir]
+	if !ok {
+		isVenv = IsVirtualEnv(dir)
+		s.dirs[dir] = isVenv
+	}
+	return isVenv
+}
+
+// parseGitignore reads and parses a .gitignore file. name is the file's
+// path as shown by Explain.
+func parseGitignore(path, name string) ([]gitignoreRule, error) {
 	file, err := os.Open(path)
 	if err != nil {
 		return nil, err
@@ -110,7 +219,7 @@ func parseGitignore(path string) ([]gitignoreRule, error) {
 	var rules []gitignoreRule
 	scanner := bufio.NewScanner(file)
 
-	for scanner.Scan() {
+	for lineNum := 1; scanner.Scan(); lineNum++ {
 		line := strings.TrimSpace(scanner.Text())
 
 		// Skip empty lines and comments
@@ -118,33 +227,66 @@ func parseGitignore(path string) ([]gitignoreRule, error) {
 			continue
 		}
 
-		rule := gitignoreRule{}
+		rule := parseRule(line)
+		rule.source = fmt.Sprintf("%s:%d", name, lineNum)
+		rules = append(rules, rule)
+	}
 
-		// Check for negation
-		if strings.HasPrefix(line, "!") {
-			rule.negate = true
-			line = line[1:]
-		}
+	return rules, scanner.Err()
+}
 
-		// Check for directory only
-		if strings.HasSuffix(line, "/") {
-			rule.dirOnly = true
-			line = strings.TrimSuffix(line, "/")
-		}
+// parseRule parses a single gitignore-style pattern. Patterns containing a
+// "/" other than a trailing one are anchored: they match from the base
+// directory (the .gitignore's directory, or the root for custom patterns)
+// rather than against any path component. A leading "/" anchors a pattern
+// without other slashes, e.g. "/vendored" matches only at the top level.
+func parseRule(pattern string) gitignoreRule {
+	rule := gitignoreRule{text: pattern}
+
+	// Check for negation
+	if strings.HasPrefix(pattern, "!") {
+		rule.negate = true
+		pattern = pattern[1:]
+	}
 
-		// Check if anchored (contains / not at end)
-		if strings.Contains(line, "/") {
-			rule.anchored = true
-		}
+	// Check for directory only
+	if strings.HasSuffix(pattern, "/") {
+		rule.dirOnly = true
+		pattern = strings.TrimSuffix(pattern, "/")
+	}
 
-		rule.pattern = line
-		rules = append(rules, rule)
+	// Check if anchored (contains / not at end)
+	if strings.Contains(pattern, "/") {
+		rule.anchored = true
 	}
 
-	return rules, scanner.Err()
+	// The leading slash itself is not part of the pattern
+	rule.pattern = strings.TrimPrefix(pattern, "/")
+	return rule
+}
+
+// matches reports whether the rule matches relPath, which is relative to the
+// rule's base directory.
+func (rule gitignoreRule) matches(relPath string, isDir bool) bool {
+	// Skip directory-only rules for files
+	if rule.dirOnly && !isDir {
+		return false
+	}
+
+	if rule.anchored {
+		// Anchored patterns match from the base directory
+		return matchPathPattern(rule.pattern, relPath)
+	}
+
+	// Non-anchored patterns match any path component
+	return matchPattern(rule.pattern, relPath) ||
+		matchPattern(rule.pattern, filepath.Base(relPath))
 }
 
 // ShouldIgnore checks if a path should be ignored.
+// An excluded directory is still entered when an anchored negated custom
+// pattern (e.g. "!dist/keep.js") re-includes a path below it; the other
+// files in it remain ignored (see ShouldIgnoreFile).
 func (m *Matcher) ShouldIgnore(path string) bool {
 	// Get relative path from root
 	relPath, err := filepath.Rel(m.rootDir, path)
@@ -159,10 +301,47 @@ func (m *Matcher) ShouldIgnore(path string) bool {
 	info, err := os.Stat(path)
 	isDir := err == nil && info.IsDir()
 
+	if !m.excludes(path, relPath, isDir) {
+		return false
+	}
+
+	return !(isDir && m.reincludesBelow(relPath))
+}
+
+// ShouldIgnoreDir is ShouldIgnore for a path known to be a directory, such
+// as one reported by a DirEntry during a walk. It skips the stat that
+// ShouldIgnore needs to tell directories from files; the pyvenv.cfg check
+// for virtual environments is made once per directory and shared with
+// clones. relPath is relative to the matcher's root.
+func (m *Matcher) ShouldIgnoreDir(relPath string) bool {
+	relPath = filepath.ToSlash(filepath.Clean(relPath))
+	path := filepath.Join(m.rootDir, filepath.FromSlash(relPath))
+
+	if !m.excludes(path, relPath, true) {
+		return false
+	}
+
+	return !m.reincludesBelow(relPath)
+}
+
+// excludes applies the default, gitignore, and custom rules to a path.
+func (m *Matcher) excludes(path, relPath string, isDir bool) bool {
+	_, excluded := m.excludingRule(path, relPath, isDir)
+	return excluded
+}
+
+// excludingRule returns the rule that excludes a path, if any, by the
+// precedence excludes applies.
+func (m *Matcher) excludingRule(path, relPath string, isDir bool) (Rule, bool) {
 	// Check basename against default patterns
 	base := filepath.Base(path)
 	if m.defaultIgnores[base] {
-		return true
+		return Rule{Pattern: base, Source: defaultSource, Path: relPath}, true
+	}
+
+	// Skip Python virtual environments whatever they are named
+	if isDir && m.virtualEnvs.contains(path) {
+		return Rule{Pattern: "pyvenv.cfg (virtual environment)", Source: defaultSource, Path: relPath}, true
 	}
 
 	// Check file extensions
@@ -170,22 +349,17 @@ func (m *Matcher) ShouldIgnore(path string) bool {
 		ext := strings.ToLower(filepath.Ext(path))
 		for _, ignoreExt := range DefaultIgnoreExtensions {
 			if ext == ignoreExt {
-				return true
+				return Rule{Pattern: "*" + ignoreExt, Source: defaultSource, Path: relPath}, true
 			}
 		}
 	}
 
-	// Check gitignore rules
-	if m.matchGitignore(relPath, isDir) {
-		return true
-	}
-
-	// Check custom patterns
-	if m.matchCustomPatterns(relPath, isDir) {
-		return true
+	// Check gitignore, .repoctrignore, and custom rules; later layers
+	// override earlier ones
+	if rule, _ := m.decidingRule(relPath, isDir); rule != nil && !rule.negate {
+		return Rule{Pattern: rule.text, Source: rule.source, Path: relPath}, true
 	}
-
-	return false
+	return Rule{}, false
 }
 
 // ShouldIgnoreFile checks if a file path should be ignored (not directory check).
@@ -212,90 +386,219 @@ func (m *Matcher) ShouldIgnoreFile(path string) bool {
 		}
 	}
 
-	// Check gitignore rules
-	if m.matchGitignore(relPath, false) {
+	// Check gitignore, .repoctrignore, and custom rules
+	ignored, reincluded := m.matchLayers(relPath, false)
+	if ignored {
 		return true
 	}
-
-	// Check custom patterns
-	if m.matchCustomPatterns(relPath, false) {
-		return true
+	if reincluded {
+		// Explicitly re-included by a negated pattern
+		return false
 	}
 
-	return false
+	// Directories are only entered despite being excluded to reach
+	// re-included paths; everything else inside them stays ignored
+	return m.hasReincludes() && m.insideExcludedDir(relPath)
 }
 
-// matchGitignore checks if a path matches any gitignore rule.
-func (m *Matcher) matchGitignore(relPath string, isDir bool) bool {
-	ignored := false
+// Explain returns the rule that excludes path, or false if path is not
+// ignored. A path inside an excluded directory is explained by the rule
+// excluding the directory, which is reported in Rule.Path.
+func (m *Matcher) Explain(path string) (Rule, bool) {
+	relPath, err := filepath.Rel(m.rootDir, path)
+	if err != nil {
+		relPath = path
+	}
+	relPath = filepath.ToSlash(relPath)
 
-	for _, rule := range m.gitignoreRules {
-		// Skip directory-only rules for files
-		if rule.dirOnly && !isDir {
-			continue
+	// Directories above the path are skipped before it is ever reached
+	parts := strings.Split(relPath, "/")
+	for i := 1; i < len(parts); i++ {
+		dir := strings.Join(parts[:i], "/")
+		if m.ShouldIgnoreDir(dir) {
+			return m.excludingRule(filepath.Join(m.rootDir, filepath.FromSlash(dir)), dir, true)
 		}
+	}
 
-		matched := false
+	info, err := os.Stat(path)
+	if err == nil && info.IsDir() {
+		if !m.ShouldIgnoreDir(relPath) {
+			return Rule{}, false
+		}
+		return m.excludingRule(path, relPath, true)
+	}
+
+	if !m.ShouldIgnoreFile(path) {
+		return Rule{}, false
+	}
+	if rule, ok := m.excludingRule(path, relPath, false); ok {
+		return rule, true
+	}
+
+	// Otherwise the file is inside a directory that is only entered to
+	// reach paths re-included below it
+	for i := 1; i < len(parts); i++ {
+		dir := strings.Join(parts[:i], "/")
+		if rule, ok := m.excludingRule(filepath.Join(m.rootDir, filepath.FromSlash(dir)), dir, true); ok {
+			return rule, true
+		}
+	}
+	return Rule{}, false
+}
+
+// IsVirtualEnv reports whether dir is a Python virtual environment, which
+// 'python -m venv' and virtualenv mark with a pyvenv.cfg file at the top.
+func IsVirtualEnv(dir string) bool {
+	info, err := os.Stat(filepath.Join(dir, "pyvenv.cfg"))
+	return err == nil && !info.IsDir()
+}
 
-		if rule.anchored {
-			// Anchored patterns match from root
-			matched = matchPattern(rule.pattern, relPath)
-		} else {
-			// Non-anchored patterns match any path component
-			matched = matchPattern(rule.pattern, relPath) ||
-				matchPattern(rule.pattern, filepath.Base(relPath))
+// matchGitignore returns the last gitignore rule matching a path, or nil.
+// Every .gitignore from the root down to the path's parent directory applies,
+// with each file's patterns evaluated relative to the directory it lives in.
+// Deeper files are evaluated last, so their rules take precedence.
+func (m *Matcher) matchGitignore(relPath string, isDir bool) *gitignoreRule {
+	var matched *gitignoreRule
+
+	for _, dir := range gitignoreDirs(relPath) {
+		subPath := relPath
+		if dir != "." {
+			subPath = strings.TrimPrefix(relPath, dir+"/")
 		}
 
-		if matched {
-			ignored = !rule.negate
+		// Patterns are evaluated relative to the .gitignore's directory
+		rules := m.gitignores.load(dir)
+		for i := range rules {
+			if rules[i].matches(subPath, isDir) {
+				matched = &rules[i]
+			}
 		}
 	}
 
-	return ignored
+	return matched
 }
 
-// matchCustomPatterns checks if a path matches any custom pattern.
-func (m *Matcher) matchCustomPatterns(relPath string, isDir bool) bool {
-	ignored := false
+// gitignoreDirs returns the directories whose .gitignore files apply to
+// relPath, ordered from the root down to the path's parent directory.
+func gitignoreDirs(relPath string) []string {
+	dirs := []string{"."}
 
-	for _, rule := range m.customPatterns {
-		// Skip directory-only rules for files
-		if rule.dirOnly && !isDir {
-			continue
+	// Paths outside the root are only subject to the root .gitignore
+	if relPath == "." || relPath == ".." || strings.HasPrefix(relPath, "../") {
+		return dirs
+	}
+
+	parent := path.Dir(relPath)
+	if parent == "." {
+		return dirs
+	}
+
+	parts := strings.Split(parent, "/")
+	for i := range parts {
+		dirs = append(dirs, strings.Join(parts[:i+1], "/"))
+	}
+
+	return dirs
+}
+
+// matchLayers applies the .gitignore, .repoctrignore, and custom rules to
+// relPath in order of precedence. reincluded reports whether a negated
+// .repoctrignore or custom pattern decided that the path is not ignored.
+func (m *Matcher) matchLayers(relPath string, isDir bool) (ignored, reincluded bool) {
+	rule, custom := m.decidingRule(relPath, isDir)
+	if rule == nil {
+		return false, false
+	}
+	return !rule.negate, custom && rule.negate
+}
+
+// decidingRule returns the rule that decides whether relPath is ignored:
+// the last matching rule of the highest layer with a match, or nil. custom
+// reports whether it is a .repoctrignore or custom rule.
+func (m *Matcher) decidingRule(relPath string, isDir bool) (rule *gitignoreRule, custom bool) {
+	rule = m.matchGitignore(relPath, isDir)
+
+	for _, layer := range [][]gitignoreRule{m.repoctrIgnores, m.customPatterns} {
+		if matched := matchRules(layer, relPath, isDir); matched != nil {
+			rule, custom = matched, true
+		}
+	}
+
+	return rule, custom
+}
+
+// matchRules applies .repoctrignore or custom rules to relPath. They are
+// evaluated relative to the root, so anchored patterns such as
+// "/apps/legacy/vendored" match only from the repository root. The last
+// matching rule, including a negated one, is returned, or nil.
+func matchRules(rules []gitignoreRule, relPath string, isDir bool) *gitignoreRule {
+	var matched *gitignoreRule
+	for i := range rules {
+		if rules[i].matches(relPath, isDir) {
+			matched = &rules[i]
 		}
+	}
 
-		matched := false
+	return matched
+}
 
-		if rule.anchored {
-			// Anchored patterns match from root
-			matched = matchPattern(rule.pattern, relPath)
-		} else {
-			// Non-anchored patterns match any path component
-			matched = matchPattern(rule.pattern, relPath) ||
-				matchPattern(rule.pattern, filepath.Base(relPath))
+// hasReincludes reports whether any anchored negated custom pattern exists.
+func (m *Matcher) hasReincludes() bool {
+	for _, rule := range m.customPatterns {
+		if rule.negate && rule.anchored {
+			return true
 		}
+	}
+	return false
+}
 
-		if matched {
-			ignored = !rule.negate
+// reincludesBelow reports whether an anchored negated custom pattern could
+// match a path inside the directory relDir.
+func (m *Matcher) reincludesBelow(relDir string) bool {
+	segments := strings.Split(relDir, "/")
+	for _, rule := range m.customPatterns {
+		if !rule.negate || !rule.anchored {
+			continue
+		}
+		for _, p := range expandBraces(rule.pattern) {
+			if matchSegmentsBelow(strings.Split(p, "/"), segments) {
+				return true
+			}
 		}
 	}
+	return false
+}
 
-	return ignored
+// insideExcludedDir reports whether any ancestor directory of relPath is
+// excluded by the default, gitignore, or custom rules.
+func (m *Matcher) insideExcludedDir(relPath string) bool {
+	parts := strings.Split(relPath, "/")
+	for i := 1; i < len(parts); i++ {
+		dir := strings.Join(parts[:i], "/")
+		if m.excludes(filepath.Join(m.rootDir, filepath.FromSlash(dir)), dir, true) {
+			return true
+		}
+	}
+	return false
 }
 
 // Clone creates a copy of the matcher for project-specific layering.
-// The clone shares the same default ignores but has independent gitignore
-// and custom pattern rules that can be extended.
+// The clone has its own default ignores and custom pattern rules, so
+// extending it never affects the original. The cache of loaded .gitignore
+// files is shared: it is safe for concurrent use and its rules never change.
 func (m *Matcher) Clone() *Matcher {
 	cloned := &Matcher{
 		rootDir:        m.rootDir,
-		defaultIgnores: m.defaultIgnores,
+		defaultIgnores: make(map[string]bool, len(m.defaultIgnores)),
+		gitignores:     m.gitignores,
+		virtualEnvs:    m.virtualEnvs,
+		repoctrIgnores: m.repoctrIgnores,
 		projectRootDir: m.rootDir,
 	}
 
-	// Deep copy gitignore rules
-	cloned.gitignoreRules = make([]gitignoreRule, len(m.gitignoreRules))
-	copy(cloned.gitignoreRules, m.gitignoreRules)
+	for pattern := range m.defaultIgnores {
+		cloned.defaultIgnores[pattern] = true
+	}
 
 	// Deep copy custom patterns
 	cloned.customPatterns = make([]gitignoreRule, len(m.customPatterns))
@@ -305,80 +608,147 @@ func (m *Matcher) Clone() *Matcher {
 }
 
 // AddPatterns adds custom patterns to the matcher (like gitignore patterns).
-// Patterns should be in gitignore format.
+// Patterns should be in gitignore format; a leading "/" anchors a pattern to
+// the root instead of matching the name at any depth.
 func (m *Matcher) AddPatterns(patterns []string) error {
+	return m.AddPatternsFrom("custom exclude", patterns)
+}
+
+// AddPatternsFrom is AddPatterns for patterns defined in source, e.g.
+// "--exclude", which Explain reports as the Rule.Source of a match.
+func (m *Matcher) AddPatternsFrom(source string, patterns []string) error {
 	for _, pattern := range patterns {
 		if pattern == "" {
 			continue
 		}
+		rule := parseRule(pattern)
+		rule.source = source
+		m.customPatterns = append(m.customPatterns, rule)
+	}
 
-		rule := gitignoreRule{}
+	return nil
+}
 
-		// Check for negation
-		if strings.HasPrefix(pattern, "!") {
-			rule.negate = true
-			pattern = pattern[1:]
-		}
+// matchPattern performs glob matching against the full path or its basename.
+func matchPattern(pattern, relPath string) bool {
+	base := path.Base(relPath)
 
-		// Check for directory only
-		if strings.HasSuffix(pattern, "/") {
-			rule.dirOnly = true
-			pattern = strings.TrimSuffix(pattern, "/")
+	for _, p := range expandBraces(pattern) {
+		if matchSegments(strings.Split(p, "/"), strings.Split(relPath, "/")) {
+			return true
 		}
 
-		// Check if anchored (contains / not at end)
-		if strings.Contains(pattern, "/") {
-			rule.anchored = true
+		// Try matching against basename
+		if matched, err := path.Match(p, base); err == nil && matched {
+			return true
 		}
-
-		rule.pattern = pattern
-		m.customPatterns = append(m.customPatterns, rule)
 	}
 
-	return nil
+	return false
 }
 
-// matchPattern performs simple glob matching.
-func matchPattern(pattern, path string) bool {
-	// Handle ** for recursive matching
-	if strings.Contains(pattern, "**") {
-		parts := strings.Split(pattern, "**")
-		if len(parts) == 2 {
-			prefix := strings.TrimSuffix(parts[0], "/")
-			suffix := strings.TrimPrefix(parts[1], "/")
+// matchPathPattern performs glob matching against the full path only.
+// A pattern also matches any path below a directory it matches.
+func matchPathPattern(pattern, relPath string) bool {
+	for _, p := range expandBraces(pattern) {
+		if matchSegments(strings.Split(p, "/"), strings.Split(relPath, "/")) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchSegments matches slash-separated pattern segments against path
+// segments. Each segment is matched with path.Match, so "*", "?", and
+// character ranges never cross a "/"; a "**" segment matches zero or more
+// whole path segments. Once the pattern is exhausted the remaining path
+// segments are treated as contents of a matched directory.
+func matchSegments(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return true
+	}
 
-			if prefix == "" && suffix == "" {
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchSegments(pattern[1:], segments[i:]) {
 				return true
 			}
-			if prefix != "" && !strings.HasPrefix(path, prefix) {
-				return false
-			}
-			if suffix != "" && !strings.HasSuffix(path, suffix) {
-				return false
-			}
-			return true
 		}
+		return false
 	}
 
-	// Try exact match
-	if pattern == path {
-		return true
+	if len(segments) == 0 {
+		return false
 	}
 
-	// Try filepath.Match for glob patterns
-	if matched, err := filepath.Match(pattern, path); err == nil && matched {
-		return true
+	matched, err := path.Match(pattern[0], segments[0])
+	if err != nil || !matched {
+		return false
 	}
 
-	// Try matching against basename
-	if matched, err := filepath.Match(pattern, filepath.Base(path)); err == nil && matched {
+	return matchSegments(pattern[1:], segments[1:])
+}
+
+// matchSegmentsBelow reports whether pattern could match a path strictly
+// inside the directory described by segments: the directory must match a
+// prefix of the pattern with at least one pattern segment left over.
+func matchSegmentsBelow(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return false
+	}
+	if pattern[0] == "**" {
 		return true
 	}
-
-	// Try prefix match for directory patterns
-	if strings.HasPrefix(path, pattern+"/") {
+	if len(segments) == 0 {
 		return true
 	}
 
-	return false
+	matched, err := path.Match(pattern[0], segments[0])
+	if err != nil || !matched {
+		return false
+	}
+
+	return matchSegmentsBelow(pattern[1:], segments[1:])
+}
+
+// expandBraces expands brace groups into the set of patterns they describe.
+// Example: "build/{a,b}/*.{js,ts}" -> "build/a/*.js", "build/a/*.ts", ...
+// Patterns without a complete brace group are returned unchanged.
+func expandBraces(pattern string) []string {
+	start := strings.IndexByte(pattern, '{')
+	if start < 0 {
+		return []string{pattern}
+	}
+
+	// Find the matching close brace, splitting alternatives on top-level commas
+	var alternatives []string
+	depth, last, end := 0, start+1, -1
+	for i := start; i < len(pattern) && end < 0; i++ {
+		switch pattern[i] {
+		case '{':
+			depth++
+		case ',':
+			if depth == 1 {
+				alternatives = append(alternatives, pattern[last:i])
+				last = i + 1
+			}
+		case '}':
+			depth--
+			if depth == 0 {
+				alternatives = append(alternatives, pattern[last:i])
+				end = i
+			}
+		}
+	}
+
+	if end < 0 {
+		return []string{pattern}
+	}
+
+	prefix, suffix := pattern[:start], pattern[end+1:]
+	var result []string
+	for _, alt := range alternatives {
+		result = append(result, expandBraces(prefix+alt+suffix)...)
+	}
+	return result
 }
@@ -0,0 +1,560 @@
+package ignore
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeFile creates a file (and its parent directories) under root.
+func writeFile(t *testing.T, root, rel, content string) {
+	t.Helper()
+	full := filepath.Join(root, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+		t.Fatalf("write %s: %v", rel, err)
+	}
+}
+
+func TestMatcher_NestedGitignore(t *testing.T) {
+	root := t.TempDir()
+
+	writeFile(t, root, ".gitignore", "logs/\n/coverage\n")
+	writeFile(t, root, "frontend/.gitignore", "generated/\n*.map\n/cache\n")
+	writeFile(t, root, "frontend/generated/api.js", "")
+	writeFile(t, root, "frontend/cache/x.js", "")
+	writeFile(t, root, "frontend/src/cache/y.js", "")
+	writeFile(t, root, "frontend/app.js.map", "")
+	writeFile(t, root, "frontend/logs/out.txt", "")
+	writeFile(t, root, "backend/generated/api.go", "")
+	writeFile(t, root, "backend/app.js.map", "")
+	writeFile(t, root, "backend/coverage/c.out", "")
+	writeFile(t, root, "coverage/c.out", "")
+	writeFile(t, root, "logs/out.txt", "")
+
+	m, err := NewMatcher(root)
+	if err != nil {
+		t.Fatalf("NewMatcher: %v", err)
+	}
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		// Root .gitignore rules
+		{"logs", true},
+		{"frontend/logs", true},
+		{"coverage", true},
+		{"backend/coverage", false},
+		// Nested .gitignore rules are scoped to frontend/
+		{"frontend/generated", true},
+		{"frontend/cache", true},
+		{"frontend/src/cache", false},
+		{"frontend/app.js.map", true},
+		{"backend/generated", false},
+		{"backend/app.js.map", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got := m.ShouldIgnore(filepath.Join(root, filepath.FromSlash(tt.path)))
+			if got != tt.want {
+				t.Errorf("ShouldIgnore(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatcher_NestedGitignoreNegation(t *testing.T) {
+	root := t.TempDir()
+
+	writeFile(t, root, ".gitignore", "*.gen.go\n")
+	writeFile(t, root, "keep/.gitignore", "!*.gen.go\n")
+	writeFile(t, root, "a.gen.go", "")
+	writeFile(t, root, "keep/b.gen.go", "")
+
+	m, err := NewMatcher(root)
+	if err != nil {
+		t.Fatalf("NewMatcher: %v", err)
+	}
+
+	if !m.ShouldIgnoreFile(filepath.Join(root, "a.gen.go")) {
+		t.Error("expected a.gen.go to be ignored by root .gitignore")
+	}
+	if m.ShouldIgnoreFile(filepath.Join(root, "keep", "b.gen.go")) {
+		t.Error("expected keep/b.gen.go to be re-included by nested .gitignore")
+	}
+}
+
+func TestMatcher_CloneSharesGitignores(t *testing.T) {
+	root := t.TempDir()
+
+	writeFile(t, root, "pkg/.gitignore", "out/\n")
+	writeFile(t, root, "pkg/out/x.txt", "")
+
+	m, err := NewMatcher(root)
+	if err != nil {
+		t.Fatalf("NewMatcher: %v", err)
+	}
+
+	clone := m.Clone()
+	if !clone.ShouldIgnore(filepath.Join(root, "pkg", "out")) {
+		t.Error("expected clone to honor nested .gitignore")
+	}
+}
+
+func TestMatcher_AnchoredCustomPatterns(t *testing.T) {
+	root := t.TempDir()
+
+	for _, dir := range []string{
+		"vendored",
+		"apps/vendored",
+		"apps/legacy/vendored",
+		"libs/apps/legacy/vendored",
+	} {
+		writeFile(t, root, dir+"/lib.go", "")
+	}
+
+	tests := []struct {
+		pattern string
+		want    map[string]bool
+	}{
+		{
+			pattern: "vendored",
+			want: map[string]bool{
+				"vendored":                  true,
+				"apps/vendored":             true,
+				"apps/legacy/vendored":      true,
+				"libs/apps/legacy/vendored": true,
+			},
+		},
+		{
+			pattern: "/vendored",
+			want: map[string]bool{
+				"vendored":                  true,
+				"apps/vendored":             false,
+				"apps/legacy/vendored":      false,
+				"libs/apps/legacy/vendored": false,
+			},
+		},
+		{
+			pattern: "/apps/legacy/vendored",
+			want: map[string]bool{
+				"vendored":                  false,
+				"apps/vendored":             false,
+				"apps/legacy/vendored":      true,
+				"libs/apps/legacy/vendored": false,
+			},
+		},
+		{
+			pattern: "/apps/legacy/vendored/",
+			want: map[string]bool{
+				"vendored":                  false,
+				"apps/vendored":             false,
+				"apps/legacy/vendored":      true,
+				"libs/apps/legacy/vendored": false,
+			},
+		},
+		{
+			pattern: "**/legacy/vendored",
+			want: map[string]bool{
+				"vendored":                  false,
+				"apps/vendored":             false,
+				"apps/legacy/vendored":      true,
+				"libs/apps/legacy/vendored": true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			m, err := NewMatcher(root)
+			if err != nil {
+				t.Fatalf("NewMatcher: %v", err)
+			}
+			m.AddPatterns([]string{tt.pattern})
+
+			for dir, want := range tt.want {
+				full := filepath.Join(root, filepath.FromSlash(dir))
+				if got := m.ShouldIgnore(full); got != want {
+					t.Errorf("ShouldIgnore(%q) = %v, want %v", dir, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestMatcher_NegatedCustomPatternReincludes(t *testing.T) {
+	root := t.TempDir()
+
+	writeFile(t, root, "assets/app.js", "")
+	writeFile(t, root, "assets/keep.js", "")
+	writeFile(t, root, "dist/bundle.js", "")
+	writeFile(t, root, "dist/keep.js", "")
+
+	m, err := NewMatcher(root)
+	if err != nil {
+		t.Fatalf("NewMatcher: %v", err)
+	}
+	m.AddPatterns([]string{"assets/**", "!assets/keep.js", "!dist/keep.js"})
+
+	tests := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		// Excluded directories are entered to reach re-included files
+		{"assets", true, false},
+		{"dist", true, false},
+		{"assets/app.js", false, true},
+		{"assets/keep.js", false, false},
+		// dist/ is a default ignore; only the re-included file surfaces
+		{"dist/bundle.js", false, true},
+		{"dist/keep.js", false, false},
+	}
+
+	for _, tt := range tests {
+		full := filepath.Join(root, filepath.FromSlash(tt.path))
+		var got bool
+		if tt.isDir {
+			got = m.ShouldIgnore(full)
+		} else {
+			got = m.ShouldIgnoreFile(full)
+		}
+		if got != tt.want {
+			t.Errorf("ignore %q = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestMatcher_CloneIsIndependent(t *testing.T) {
+	root := t.TempDir()
+
+	m, err := NewMatcher(root)
+	if err != nil {
+		t.Fatalf("NewMatcher: %v", err)
+	}
+	m.AddPatterns([]string{"*.tmp"})
+
+	clone := m.Clone()
+	clone.AddPatterns([]string{"!keep.tmp", "*.log"})
+	clone.defaultIgnores["fixtures"] = true
+
+	if !m.ShouldIgnoreFile(filepath.Join(root, "keep.tmp")) {
+		t.Error("negation added to the clone leaked into the original")
+	}
+	if m.ShouldIgnoreFile(filepath.Join(root, "app.log")) {
+		t.Error("pattern added to the clone leaked into the original")
+	}
+	if m.defaultIgnores["fixtures"] {
+		t.Error("clone shares default ignores with the original")
+	}
+	if clone.ShouldIgnoreFile(filepath.Join(root, "keep.tmp")) {
+		t.Error("expected clone to re-include keep.tmp")
+	}
+}
+
+func TestMatchPattern(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		path    string
+		want    bool
+	}{
+		// Brace groups
+		{"brace extension js", "*.{js,ts}", "src/app.js", true},
+		{"brace extension ts", "*.{js,ts}", "src/app.ts", true},
+		{"brace extension miss", "*.{js,ts}", "src/app.go", false},
+		{"brace dir a", "build/{a,b}/**", "build/a/out.txt", true},
+		{"brace dir b", "build/{a,b}/**", "build/b/deep/out.txt", true},
+		{"brace dir miss", "build/{a,b}/**", "build/c/out.txt", false},
+		{"nested braces", "{src,lib/{x,y}}/gen", "lib/y/gen", true},
+		{"unclosed brace is literal", "foo{bar", "foo{bar", true},
+
+		// Single-character and range matching
+		{"question mark basename", "?.log", "logs/a.log", true},
+		{"question mark too long", "?.log", "logs/ab.log", false},
+		{"question mark full path", "src/?/main.go", "src/a/main.go", true},
+		{"question mark not slash", "src?main.go", "src/main.go", false},
+		{"char range", "file[0-9].txt", "file7.txt", true},
+		{"char range miss", "file[0-9].txt", "filex.txt", false},
+
+		// ** segments
+		{"leading double star", "**/*.test.js", "a/b/c.test.js", true},
+		{"leading double star root", "**/*.test.js", "c.test.js", true},
+		{"trailing double star", "docs/**", "docs/api/index.md", true},
+		{"trailing double star prefix only", "docs/**", "docsite/index.md", false},
+		{"nested double star", "a/**/b/**/*.go", "a/x/y/b/z/main.go", true},
+		{"nested double star zero segments", "a/**/b/**/*.go", "a/b/main.go", true},
+		{"nested double star miss", "a/**/b/**/*.go", "a/x/c/main.go", false},
+		{"double star only", "**", "anything/at/all", true},
+
+		// Directory prefix
+		{"directory prefix", "src/generated", "src/generated/api.go", true},
+		{"directory prefix partial name", "src/gen", "src/generated/api.go", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchPattern(tt.pattern, tt.path); got != tt.want {
+				t.Errorf("matchPattern(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpandBraces(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    []string
+	}{
+		{"*.go", []string{"*.go"}},
+		{"*.{js,ts}", []string{"*.js", "*.ts"}},
+		{"{a,b}/{c,d}", []string{"a/c", "a/d", "b/c", "b/d"}},
+		{"x{a,{b,c}}", []string{"xa", "xb", "xc"}},
+		{"x{a,b", []string{"x{a,b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			got := expandBraces(tt.pattern)
+			if len(got) != len(tt.want) {
+				t.Fatalf("expandBraces(%q) = %v, want %v", tt.pattern, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("expandBraces(%q)[%d] = %q, want %q", tt.pattern, i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestMatcher_CustomNamedVirtualEnv(t *testing.T) {
+	root := t.TempDir()
+
+	writeFile(t, root, ".myenv/pyvenv.cfg", "home = /usr/bin\nversion = 3.12.1\n")
+	writeFile(t, root, ".myenv/lib/python3.12/site-packages/six.py", "")
+	writeFile(t, root, "src/pyvenv_notes/readme.py", "")
+
+	m, err := NewMatcher(root)
+	if err != nil {
+		t.Fatalf("NewMatcher: %v", err)
+	}
+
+	if !m.ShouldIgnore(filepath.Join(root, ".myenv")) {
+		t.Error("expected directory containing pyvenv.cfg to be ignored")
+	}
+	if m.ShouldIgnore(filepath.Join(root, "src", "pyvenv_notes")) {
+		t.Error("expected regular directory to be kept")
+	}
+}
+
+func TestMatcher_ShouldIgnoreDirMatchesStat(t *testing.T) {
+	root := t.TempDir()
+
+	writeFile(t, root, ".gitignore", "logs/\n/coverage\n*.tmp\n")
+	writeFile(t, root, "frontend/.gitignore", "generated/\n/cache\n")
+	writeFile(t, root, "frontend/generated/api.js", "")
+	writeFile(t, root, "frontend/cache/x.js", "")
+	writeFile(t, root, "frontend/src/cache/y.js", "")
+	writeFile(t, root, "frontend/node_modules/left-pad/index.js", "")
+	writeFile(t, root, "backend/coverage/c.out", "")
+	writeFile(t, root, "coverage/c.out", "")
+	writeFile(t, root, "logs/out.txt", "")
+	writeFile(t, root, "scratch.tmp/notes.txt", "")
+	writeFile(t, root, "tools/py/pyvenv.cfg", "home = /usr/bin\n")
+	writeFile(t, root, "dist/keep.js", "")
+	writeFile(t, root, "dist/drop.js", "")
+	writeFile(t, root, "apps/legacy/vendored/lib.go", "")
+	writeFile(t, root, "apps/current/main.go", "")
+
+	m, err := NewMatcher(root)
+	if err != nil {
+		t.Fatalf("NewMatcher: %v", err)
+	}
+	m.AddPatterns([]string{"/apps/legacy/vendored", "dist/", "!dist/keep.js"})
+
+	dirs := 0
+	err = filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return err
+		}
+		dirs++
+		relPath, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		if got, want := m.ShouldIgnoreDir(relPath), m.ShouldIgnore(path); got != want {
+			t.Errorf("ShouldIgnoreDir(%q) = %v, ShouldIgnore = %v", relPath, got, want)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("walk: %v", err)
+	}
+	if dirs < 20 {
+		t.Fatalf("walked %d directories, expected the whole tree", dirs)
+	}
+}
+
+// BenchmarkMatcher_WalkDirs compares pruning a large tree with the stat-based
+// ShouldIgnore and ShouldIgnoreDir, which checks each directory for
+// pyvenv.cfg once. Like stats, every walk uses a fresh clone of the matcher.
+func BenchmarkMatcher_WalkDirs(b *testing.B) {
+	root := b.TempDir()
+	for i := 0; i < 50; i++ {
+		for j := 0; j < 40; j++ {
+			dir := filepath.Join(root, fmt.Sprintf("pkg%d", i), fmt.Sprintf("sub%d", j))
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				b.Fatalf("mkdir: %v", err)
+			}
+		}
+	}
+	if err := os.WriteFile(filepath.Join(root, ".gitignore"), []byte("logs/\n/coverage\n*.tmp\n"), 0644); err != nil {
+		b.Fatalf("write .gitignore: %v", err)
+	}
+	for i := 0; i < 50; i += 10 {
+		venv := filepath.Join(root, fmt.Sprintf("pkg%d", i), "env")
+		if err := os.MkdirAll(filepath.Join(venv, "lib"), 0755); err != nil {
+			b.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(venv, "pyvenv.cfg"), []byte("home = /usr/bin\n"), 0644); err != nil {
+			b.Fatalf("write pyvenv.cfg: %v", err)
+		}
+	}
+
+	m, err := NewMatcher(root)
+	if err != nil {
+		b.Fatalf("NewMatcher: %v", err)
+	}
+
+	walk := func(b *testing.B, ignored func(m *Matcher, path string) bool) {
+		for i := 0; i < b.N; i++ {
+			clone := m.Clone()
+			pruned := 0
+			filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+				if err == nil && d.IsDir() && ignored(clone, path) {
+					pruned++
+					return filepath.SkipDir
+				}
+				return nil
+			})
+			if pruned != 5 {
+				b.Fatalf("pruned %d directories, want the 5 virtual environments", pruned)
+			}
+		}
+	}
+
+	b.Run("ShouldIgnore", func(b *testing.B) {
+		walk(b, (*Matcher).ShouldIgnore)
+	})
+	b.Run("ShouldIgnoreDir", func(b *testing.B) {
+		walk(b, func(m *Matcher, path string) bool {
+			relPath, _ := filepath.Rel(root, path)
+			return m.ShouldIgnoreDir(relPath)
+		})
+	})
+}
+
+func TestMatcher_RepoctrIgnore(t *testing.T) {
+	root := t.TempDir()
+
+	writeFile(t, root, ".gitignore", "*.log\n")
+	writeFile(t, root, RepoctrIgnoreFile, "# Only repo-ctr skips these\nfixtures/\n*.snap\n!keep.log\n")
+	writeFile(t, root, "fixtures/big.go", "")
+	writeFile(t, root, "ui/button.snap", "")
+	writeFile(t, root, "ui/golden.snap", "")
+	writeFile(t, root, "ui/button.go", "")
+	writeFile(t, root, "debug.log", "")
+	writeFile(t, root, "keep.log", "")
+
+	m, err := NewMatcher(root)
+	if err != nil {
+		t.Fatalf("NewMatcher: %v", err)
+	}
+	// Config excludes take precedence over .repoctrignore
+	m.AddPatterns([]string{"!golden.snap"})
+
+	tests := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		// Patterns only in .repoctrignore
+		{"fixtures", true, true},
+		{"ui/button.snap", false, true},
+		{"ui/button.go", false, false},
+		// .gitignore still applies...
+		{"debug.log", false, true},
+		// ...but .repoctrignore negations override it
+		{"keep.log", false, false},
+		// and custom negations override .repoctrignore
+		{"ui/golden.snap", false, false},
+	}
+
+	for _, tt := range tests {
+		full := filepath.Join(root, filepath.FromSlash(tt.path))
+		var got bool
+		if tt.isDir {
+			got = m.ShouldIgnore(full)
+		} else {
+			got = m.ShouldIgnoreFile(full)
+		}
+		if got != tt.want {
+			t.Errorf("ignore %q = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+
+	// Project matchers cloned from the root matcher keep the rules
+	if !m.Clone().ShouldIgnoreFile(filepath.Join(root, "ui", "button.snap")) {
+		t.Error("clone lost the .repoctrignore rules")
+	}
+}
+
+func TestMatcher_Explain(t *testing.T) {
+	root := t.TempDir()
+
+	writeFile(t, root, ".gitignore", "# logs\n*.log\n")
+	writeFile(t, root, "web/.gitignore", "\ncoverage/\n")
+	writeFile(t, root, "web/coverage/index.js", "")
+	writeFile(t, root, "web/node_modules/dep/index.js", "")
+	writeFile(t, root, "web/out/app.js", "")
+	writeFile(t, root, "web/out/keep.js", "")
+	writeFile(t, root, "web/debug.log", "")
+	writeFile(t, root, "web/snap.test.js", "")
+	writeFile(t, root, "web/app.js", "")
+
+	m, err := NewMatcher(root)
+	if err != nil {
+		t.Fatalf("NewMatcher: %v", err)
+	}
+	m.AddPatternsFrom("--exclude", []string{"*.test.js", "web/out/", "!web/out/keep.js"})
+
+	tests := []struct {
+		path string
+		want Rule
+		ok   bool
+	}{
+		{"web/debug.log", Rule{Pattern: "*.log", Source: ".gitignore:2", Path: "web/debug.log"}, true},
+		{"web/coverage/index.js", Rule{Pattern: "coverage/", Source: "web/.gitignore:2", Path: "web/coverage"}, true},
+		{"web/node_modules/dep/index.js", Rule{Pattern: "node_modules", Source: "default ignores", Path: "web/node_modules"}, true},
+		{"web/snap.test.js", Rule{Pattern: "*.test.js", Source: "--exclude", Path: "web/snap.test.js"}, true},
+		// out is entered to reach keep.js, but its other files stay ignored
+		{"web/out/app.js", Rule{Pattern: "web/out/", Source: "--exclude", Path: "web/out"}, true},
+		{"web/out/keep.js", Rule{}, false},
+		{"web/app.js", Rule{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, ok := m.Explain(filepath.Join(root, filepath.FromSlash(tt.path)))
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("Explain = %+v, %v; want %+v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
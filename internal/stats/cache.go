@@ -0,0 +1,276 @@
+package stats
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"repoctr/pkg/models"
+)
+
+// cacheVersion is bumped whenever the meaning of cached counts changes, so
+// caches written by older versions are discarded instead of trusted.
+const cacheVersion = 3
+
+// BlobHashSource returns the git blob SHA of each file under root whose
+// working-tree content is known to match it, keyed by absolute path.
+type BlobHashSource func(root string) (map[string]string, error)
+
+// Cache stores per-file line counts between runs so unchanged files are not
+// read again. Files are keyed by their git blob SHA when available, which
+// survives CI checkouts that reset modification times, and by size and
+// modification time otherwise.
+//
+// A Cache is safe for concurrent use by the counter's workers.
+type Cache struct {
+	path string
+
+	mu       sync.Mutex
+	root     string
+	settings string
+	blobs    map[string]string
+	previous map[string]cacheEntry
+	current  map[string]cacheEntry
+	hits     int
+	misses   int
+}
+
+// cacheFile is the on-disk representation of a Cache.
+type cacheFile struct {
+	Version  int                   `json:"version"`
+	Settings string                `json:"settings"`
+	Files    map[string]cacheEntry `json:"files"`
+}
+
+// cacheEntry holds the counts of a single file, keyed by content identity.
+type cacheEntry struct {
+	Key             string `json:"key"`
+	Lines           int    `json:"lines"`
+	BlankLines      int    `json:"blank_lines"`
+	CodeLines       int    `json:"code_lines"`
+	StructuralLines int    `json:"structural_lines,omitempty"`
+	DataLines       int    `json:"data_lines,omitempty"`
+	NotebookLines   int    `json:"notebook_lines,omitempty"`
+	LongLines       int    `json:"long_lines,omitempty"`
+	BlankRuns       int    `json:"blank_runs,omitempty"`
+	Generated       bool   `json:"generated,omitempty"`
+	Binary          bool   `json:"binary,omitempty"`
+}
+
+// LoadCache reads the cache stored at path. A missing, unreadable, or
+// outdated cache file yields an empty cache rather than an error, since the
+// cache only ever saves work.
+func LoadCache(path string) *Cache {
+	cache := &Cache{
+		path:     path,
+		previous: make(map[string]cacheEntry),
+		current:  make(map[string]cacheEntry),
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cache
+	}
+
+	var file cacheFile
+	if err := json.Unmarshal(data, &file); err != nil || file.Version != cacheVersion {
+		return cache
+	}
+
+	cache.settings = file.Settings
+	if file.Files != nil {
+		cache.previous = file.Files
+	}
+	return cache
+}
+
+// Save writes the entries used since the cache was loaded back to its file.
+// Entries for files that were not counted in this run are dropped.
+func (c *Cache) Save() error {
+	c.mu.Lock()
+	file := cacheFile{
+		Version:  cacheVersion,
+		Settings: c.settings,
+		Files:    c.current,
+	}
+	data, err := json.Marshal(file)
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	// Write through a temporary file so an interrupted run never leaves a
+	// truncated cache behind
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write cache %s: %w", c.path, err)
+	}
+	if err := os.Rename(tmp, c.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write cache %s: %w", c.path, err)
+	}
+	return nil
+}
+
+// Stats returns the number of files served from the cache and the number
+// that had to be counted since the cache was loaded.
+func (c *Cache) Stats() (hits, misses int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hits, c.misses
+}
+
+// prepare binds the cache to a counter root and its counting settings.
+// Cached counts recorded under different settings are discarded. Blob
+// hashes come from source; if it fails, files are keyed by size and
+// modification time only.
+func (c *Cache) prepare(root, settings string, source BlobHashSource) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.root = root
+	if c.settings != settings {
+		c.previous = make(map[string]cacheEntry)
+		c.settings = settings
+	}
+
+	if source == nil {
+		source = GitBlobHashes
+	}
+	blobs, err := source(root)
+	if err != nil {
+		blobs = nil
+	}
+	c.blobs = blobs
+}
+
+// key returns the content identity of the file at path.
+func (c *Cache) key(path string, info os.FileInfo) string {
+	if blob, ok := c.blobs[path]; ok {
+		return "blob:" + blob
+	}
+	return fmt.Sprintf("stat:%d:%d", info.Size(), info.ModTime().UnixNano())
+}
+
+// entryName returns the name a file is stored under: its path relative to
+// the counter root with forward slashes, so caches restored on another
+// machine or checkout directory still match.
+func (c *Cache) entryName(path string) string {
+	relPath, err := filepath.Rel(c.root, path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(relPath)
+}
+
+// lookup fills stats from the cache if the file at path is unchanged since
+// it was last counted and reports whether it did.
+func (c *Cache) lookup(path string, info os.FileInfo, stats *models.FileStats) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	name := c.entryName(path)
+	entry, ok := c.previous[name]
+	if !ok || entry.Key != c.key(path, info) {
+		c.misses++
+		return false
+	}
+
+	c.hits++
+	c.current[name] = entry
+	stats.Lines = entry.Lines
+	stats.BlankLines = entry.BlankLines
+	stats.CodeLines = entry.CodeLines
+	stats.StructuralLines = entry.StructuralLines
+	stats.DataLines = entry.DataLines
+	stats.NotebookLines = entry.NotebookLines
+	stats.LongLines = entry.LongLines
+	stats.BlankRuns = entry.BlankRuns
+	stats.Generated = entry.Generated
+	stats.Binary = entry.Binary
+	return true
+}
+
+// store records the counts of the file at path.
+func (c *Cache) store(path string, info os.FileInfo, stats *models.FileStats) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.current[c.entryName(path)] = cacheEntry{
+		Key:             c.key(path, info),
+		Lines:           stats.Lines,
+		BlankLines:      stats.BlankLines,
+		CodeLines:       stats.CodeLines,
+		StructuralLines: stats.StructuralLines,
+		DataLines:       stats.DataLines,
+		NotebookLines:   stats.NotebookLines,
+		LongLines:       stats.LongLines,
+		BlankRuns:       stats.BlankRuns,
+		Generated:       stats.Generated,
+		Binary:          stats.Binary,
+	}
+}
+
+// GitBlobHashes returns the blob SHA of every regular file tracked by git
+// under root, keyed by absolute path, using 'git ls-files -s'. Files with
+// unstaged modifications are left out because their index entry no longer
+// describes the working tree.
+func GitBlobHashes(root string) (map[string]string, error) {
+	staged, err := gitLsFiles(root, "-s")
+	if err != nil {
+		return nil, err
+	}
+	modified, err := gitLsFiles(root, "-m")
+	if err != nil {
+		return nil, err
+	}
+
+	skip := make(map[string]bool, len(modified))
+	for _, name := range modified {
+		skip[name] = true
+	}
+
+	blobs := make(map[string]string, len(staged))
+	for _, line := range staged {
+		// "<mode> <sha> <stage>\t<path>"
+		meta, name, ok := strings.Cut(line, "\t")
+		if !ok || skip[name] {
+			continue
+		}
+		fields := strings.Fields(meta)
+		// Only regular files at stage 0; symlinks, submodules, and
+		// conflicted entries are keyed by size and modification time
+		if len(fields) != 3 || !strings.HasPrefix(fields[0], "100") || fields[2] != "0" {
+			continue
+		}
+		blobs[filepath.Join(root, filepath.FromSlash(name))] = fields[1]
+	}
+
+	return blobs, nil
+}
+
+// gitLsFiles runs 'git ls-files -z' with args in dir and returns its
+// NUL-separated entries. Paths are relative to dir.
+func gitLsFiles(dir string, args ...string) ([]string, error) {
+	cmd := exec.Command("git", append([]string{"ls-files", "-z"}, args...)...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git ls-files failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	var entries []string
+	for _, entry := range strings.Split(string(out), "\x00") {
+		if entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
@@ -0,0 +1,185 @@
+package stats
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"repoctr/pkg/models"
+)
+
+// countWithCache counts goProject under root using the cache file at
+// cachePath and blobs as the blob-hash source, then saves the cache.
+func countWithCache(t *testing.T, root, cachePath string, blobs BlobHashSource) (*models.ProjectStats, *Cache) {
+	t.Helper()
+
+	cache := LoadCache(cachePath)
+	counter, err := NewCounterWithOptions(root, Options{Cache: cache, BlobHashes: blobs})
+	if err != nil {
+		t.Fatalf("NewCounterWithOptions: %v", err)
+	}
+
+	stats, err := counter.CountProject(goProject())
+	if err != nil {
+		t.Fatalf("CountProject: %v", err)
+	}
+	if err := cache.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	return stats, cache
+}
+
+// staticBlobs returns a blob-hash source reporting sha for the file rel.
+func staticBlobs(root, rel, sha string) BlobHashSource {
+	return func(string) (map[string]string, error) {
+		return map[string]string{filepath.Join(root, rel): sha}, nil
+	}
+}
+
+func touch(t *testing.T, path string, mtime time.Time) {
+	t.Helper()
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+}
+
+func TestCache_BlobHashSurvivesNewMtime(t *testing.T) {
+	root := t.TempDir()
+	cachePath := filepath.Join(t.TempDir(), "cache.json")
+	writeFile(t, root, "main.go", "package main\n\nfunc main() {}\n")
+
+	blobs := staticBlobs(root, "main.go", "1111111111111111111111111111111111111111")
+
+	first, cache := countWithCache(t, root, cachePath, blobs)
+	if hits, misses := cache.Stats(); hits != 0 || misses != 1 {
+		t.Fatalf("first run: hits=%d misses=%d, want 0/1", hits, misses)
+	}
+
+	// A fresh CI checkout resets the modification time but not the blob
+	touch(t, filepath.Join(root, "main.go"), time.Now().Add(time.Hour))
+
+	second, cache := countWithCache(t, root, cachePath, blobs)
+	if hits, misses := cache.Stats(); hits != 1 || misses != 0 {
+		t.Errorf("unchanged blob: hits=%d misses=%d, want 1/0", hits, misses)
+	}
+	if second.TotalLines != first.TotalLines || second.CodeLines != first.CodeLines {
+		t.Errorf("cached counts %d/%d differ from counted %d/%d",
+			second.TotalLines, second.CodeLines, first.TotalLines, first.CodeLines)
+	}
+
+	// A different blob is a miss even though nothing else changed
+	changed := staticBlobs(root, "main.go", "2222222222222222222222222222222222222222")
+	_, cache = countWithCache(t, root, cachePath, changed)
+	if hits, misses := cache.Stats(); hits != 0 || misses != 1 {
+		t.Errorf("changed blob: hits=%d misses=%d, want 0/1", hits, misses)
+	}
+}
+
+func TestCache_FallsBackToSizeAndMtime(t *testing.T) {
+	root := t.TempDir()
+	cachePath := filepath.Join(t.TempDir(), "cache.json")
+	writeFile(t, root, "main.go", "package main\n")
+	mainPath := filepath.Join(root, "main.go")
+
+	noBlobs := func(string) (map[string]string, error) { return nil, nil }
+	mtime := time.Now().Add(-time.Hour).Truncate(time.Second)
+	touch(t, mainPath, mtime)
+
+	countWithCache(t, root, cachePath, noBlobs)
+
+	_, cache := countWithCache(t, root, cachePath, noBlobs)
+	if hits, _ := cache.Stats(); hits != 1 {
+		t.Errorf("unchanged file: hits=%d, want 1", hits)
+	}
+
+	// Outside git a new modification time means the file is recounted
+	touch(t, mainPath, mtime.Add(time.Minute))
+	_, cache = countWithCache(t, root, cachePath, noBlobs)
+	if hits, misses := cache.Stats(); hits != 0 || misses != 1 {
+		t.Errorf("new mtime: hits=%d misses=%d, want 0/1", hits, misses)
+	}
+}
+
+func TestCache_DiscardedWhenSettingsChange(t *testing.T) {
+	root := t.TempDir()
+	cachePath := filepath.Join(t.TempDir(), "cache.json")
+	writeFile(t, root, "main.go", "package main\n\nfunc main() {\n}\n")
+
+	blobs := staticBlobs(root, "main.go", "3333333333333333333333333333333333333333")
+	countWithCache(t, root, cachePath, blobs)
+
+	// Structural lines are only tallied when requested, so cached counts
+	// from a run without the option must not be reused
+	cache := LoadCache(cachePath)
+	counter, err := NewCounterWithOptions(root, Options{
+		Cache:                   cache,
+		BlobHashes:              blobs,
+		SeparateStructuralLines: true,
+	})
+	if err != nil {
+		t.Fatalf("NewCounterWithOptions: %v", err)
+	}
+	stats, err := counter.CountProject(goProject())
+	if err != nil {
+		t.Fatalf("CountProject: %v", err)
+	}
+
+	if hits, _ := cache.Stats(); hits != 0 {
+		t.Errorf("hits = %d, want 0 after settings changed", hits)
+	}
+	if stats.StructuralLines != 1 {
+		t.Errorf("StructuralLines = %d, want 1", stats.StructuralLines)
+	}
+}
+
+func TestLoadCache_CorruptFileIsEmpty(t *testing.T) {
+	cachePath := filepath.Join(t.TempDir(), "cache.json")
+	if err := os.WriteFile(cachePath, []byte("{not json"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	root := t.TempDir()
+	writeFile(t, root, "main.go", "package main\n")
+
+	stats, cache := countWithCache(t, root, cachePath, nil)
+	if stats.TotalLines != 1 {
+		t.Errorf("TotalLines = %d, want 1", stats.TotalLines)
+	}
+	if hits, misses := cache.Stats(); hits != 0 || misses != 1 {
+		t.Errorf("hits=%d misses=%d, want 0/1", hits, misses)
+	}
+}
+
+func TestGitBlobHashes(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	root := t.TempDir()
+	writeFile(t, root, "clean.go", "package main\n")
+	writeFile(t, root, "edited.go", "package main\n")
+	for _, args := range [][]string{{"init", "-q"}, {"add", "."}} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = root
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+	writeFile(t, root, "edited.go", "package main\n\n// edited\n")
+
+	blobs, err := GitBlobHashes(root)
+	if err != nil {
+		t.Fatalf("GitBlobHashes: %v", err)
+	}
+
+	// The blob SHA of "package main\n", as printed by git hash-object
+	const want = "06ab7d0f9a35a7d1070711496d6ca1cb892a258f"
+	if got := blobs[filepath.Join(root, "clean.go")]; got != want {
+		t.Errorf("clean.go blob = %q, want %q", got, want)
+	}
+	if _, ok := blobs[filepath.Join(root, "edited.go")]; ok {
+		t.Error("files with unstaged edits must not be keyed by their index blob")
+	}
+}
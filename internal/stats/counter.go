@@ -2,13 +2,23 @@ package stats
 
 import (
 	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"runtime"
 	"sort"
 	"strings"
+	"sync"
+	"unicode/utf8"
 
 	"repoctr/internal/config"
+	"repoctr/internal/fswalk"
+	"repoctr/internal/hashing"
 	"repoctr/internal/ignore"
+	"repoctr/internal/textenc"
 	"repoctr/pkg/models"
 )
 
@@ -17,10 +27,119 @@ type Counter struct {
 	rootDir string
 	matcher *ignore.Matcher
 	config  *models.RepoCtrConfig
+	options Options
+
+	// callbackMu serializes calls to options.OnFile and options.Logf
+	callbackMu sync.Mutex
+}
+
+// Options controls optional counting behavior.
+type Options struct {
+	// CountTestDirsSeparately counts test directories (and everything below
+	// them) in TestFolders instead of TotalFolders.
+	CountTestDirsSeparately bool
+
+	// Workers is the number of files counted concurrently.
+	// Zero means runtime.NumCPU().
+	Workers int
+
+	// ExcludeGeneratedDirs skips conventional generated-code directories
+	// (see ignore.GeneratedDirPatterns).
+	ExcludeGeneratedDirs bool
+
+	// Excludes are ad-hoc gitignore-style patterns applied to every project
+	// in addition to the configured global excludes.
+	Excludes []string
+
+	// IncludeExtensions are file extensions (e.g. ".tmpl") counted in every
+	// project in addition to the extensions of its runtime and the
+	// project's own ExtraExtensions.
+	IncludeExtensions []string
+
+	// ExcludeGenerated omits files with a generated-code header from the
+	// totals. They are still reported in GeneratedFiles and GeneratedLines.
+	ExcludeGenerated bool
+
+	// SeparateStructuralLines counts lines consisting only of structural
+	// characters ({, }, (, ), ;) in StructuralLines instead of CodeLines,
+	// for a closer approximation of logical lines of code.
+	SeparateStructuralLines bool
+
+	// SeparateDataLines counts the lines inside multi-line string literals
+	// (Go raw strings, Python triple-quoted strings) in DataLines instead
+	// of CodeLines, so embedded JSON, SQL, or templates do not inflate code.
+	SeparateDataLines bool
+
+	// IncludeNotebooks counts the code cells of Jupyter notebooks (.ipynb)
+	// in Python projects, in NotebookLines. Markdown and raw cells and cell
+	// outputs are ignored.
+	IncludeNotebooks bool
+
+	// MaxLineLength, when positive, counts lines longer than this many
+	// runes in LongLines.
+	MaxLineLength int
+
+	// CountBlankRuns counts runs of two or more consecutive blank lines in
+	// BlankRuns, a sign of sloppy formatting.
+	CountBlankRuns bool
+
+	// MaxFileSize, when positive, skips files larger than this many bytes
+	// (e.g. minified bundles) and lists them in SkippedFiles instead.
+	MaxFileSize int64
+
+	// HashAlgorithm selects the content hash used by Fingerprint.
+	// The zero value means hashing.Default (SHA-256).
+	HashAlgorithm hashing.Algorithm
+
+	// Cache, when set, serves counts of unchanged files from a previous run
+	// and records the counts of every file counted in this one.
+	Cache *Cache
+
+	// BlobHashes supplies the git blob SHAs used as cache keys.
+	// Nil means GitBlobHashes.
+	BlobHashes BlobHashSource
+
+	// OnFile, when set, is called once for every file counted, for
+	// progress reporting. Calls are serialized, so the callback need not be
+	// safe for concurrent use, but it should return quickly.
+	OnFile func(*models.FileStats)
+
+	// FollowSymlinks descends into symbolic links to directories below
+	// each source path. Each directory is walked at most once, so links
+	// that form cycles are skipped.
+	FollowSymlinks bool
+
+	// Logf, when set, receives diagnostic messages such as the directories
+	// and oversized files skipped while counting. Like OnFile, calls are
+	// serialized.
+	Logf func(format string, args ...any)
 }
 
+// testDirNames contains directory names that hold tests.
+var testDirNames = map[string]bool{
+	"test":      true,
+	"tests":     true,
+	"__tests__": true,
+	"spec":      true,
+}
+
+// generatedMarkers identify generated files when found in the first
+// generatedHeaderLines lines, e.g. Go's "// Code generated ... DO NOT EDIT."
+var generatedMarkers = []string{"Code generated", "@generated", "DO NOT EDIT"}
+
+const generatedHeaderLines = 10
+
+// binarySniffSize is how much of a file is searched for a NUL byte to tell
+// binary files from text.
+const binarySniffSize = 8 * 1024
+
 // NewCounter creates a new stats counter.
 func NewCounter(rootDir string) (*Counter, error) {
+	return NewCounterWithOptions(rootDir, Options{})
+}
+
+// NewCounterWithOptions creates a new stats counter with options.
+func NewCounterWithOptions(rootDir string, options Options) (*Counter, error) {
 	absRoot, err := filepath.Abs(rootDir)
 	if err != nil {
 		return nil, err
@@ -37,41 +156,56 @@ func NewCounter(rootDir string) (*Counter, error) {
 		cfg = &models.RepoCtrConfig{}
 	}
 
+	if options.Cache != nil {
+		options.Cache.prepare(absRoot, cacheSettings(options), options.BlobHashes)
+	}
+
 	return &Counter{
 		rootDir: absRoot,
 		matcher: matcher,
 		config:  cfg,
+		options: options,
 	}, nil
 }
 
+// HierarchyOptions controls how CountHierarchy walks a project hierarchy.
+type HierarchyOptions struct {
+	// Concurrency is the number of sibling projects counted at once.
+	// Zero or less means one at a time. Files within each project are
+	// counted on their own pool of Options.Workers goroutines.
+	Concurrency int
+
+	// OnProject, when set, is called after a project and its children have
+	// been counted. Calls are serialized, so the callback need not be safe
+	// for concurrent use.
+	OnProject func(*models.ProjectStats)
+}
+
 // CountProject calculates statistics for a single project.
 func (c *Counter) CountProject(project *models.Project) (*models.ProjectStats, error) {
-	stats := &models.ProjectStats{
-		Project:      project,
-		LargestFiles: make([]models.FileStats, 0, 5),
-	}
+	return c.countProject(context.Background(), project)
+}
+
+// countProject counts a project, returning ctx.Err() without statistics if
+// ctx is canceled before counting finishes.
+func (c *Counter) countProject(ctx context.Context, project *models.Project) (*models.ProjectStats, error) {
+	stats := &models.ProjectStats{Project: project}
 
 	// Build the full project path
 	projectPath := filepath.Join(c.rootDir, project.Path)
 
-	// Create a project-specific matcher by cloning the base matcher
-	projectMatcher := c.matcher.Clone()
-
-	// Apply global excludes from config
-	if c.config != nil && len(c.config.GlobalExcludes) > 0 {
-		projectMatcher.AddPatterns(c.config.GlobalExcludes)
-	}
-
-	// Apply project-specific exclude patterns
-	if len(project.ExcludePatterns) > 0 {
-		projectMatcher.AddPatterns(project.ExcludePatterns)
-	}
+	projectMatcher := c.projectMatcher(project)
 
-	// Track all file stats for finding largest, and seen files to avoid duplicates
-	var allFiles []models.FileStats
+	// Collect files to count, tracking seen files to avoid duplicates.
+	// The walk runs on this goroutine only, so seenFiles needs no locking;
+	// counting happens afterwards on a worker pool.
+	var filePaths []string
 	folderSet := make(map[string]bool)
+	testFolderSet := make(map[string]bool)
 	seenFiles := make(map[string]bool)
 
+	extraExts := extensionSet(c.options.IncludeExtensions, project.ExtraExtensions)
+
 	// Process each source path
 	for _, srcPath := range project.SourcePaths {
 		fullPath := filepath.Join(projectPath, srcPath)
@@ -84,20 +218,19 @@ func (c *Counter) CountProject(project *models.Project) (*models.ProjectStats, e
 
 		if !info.IsDir() {
 			// Single file
-			fileStats, err := c.countFile(fullPath)
-			if err == nil {
-				absPath, _ := filepath.Abs(fullPath)
-				if !seenFiles[absPath] {
-					seenFiles[absPath] = true
-					c.addFileStats(stats, fileStats)
-					allFiles = append(allFiles, *fileStats)
-				}
+			absPath, _ := filepath.Abs(fullPath)
+			if !seenFiles[absPath] {
+				seenFiles[absPath] = true
+				filePaths = append(filePaths, fullPath)
 			}
 			continue
 		}
 
 		// Walk directory
-		err = filepath.WalkDir(fullPath, func(path string, d os.DirEntry, err error) error {
+		err = fswalk.WalkDir(fullPath, c.options.FollowSymlinks, func(path string, d os.DirEntry, err error) error {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
 			if err != nil {
 				return nil
 			}
@@ -110,20 +243,27 @@ func (c *Counter) CountProject(project *models.Project) (*models.ProjectStats, e
 				// Check against project-specific src-ignore-paths (legacy, simple prefix matching)
 				for _, ignorePath := range project.SrcIgnorePaths {
 					if relPath == ignorePath || strings.HasPrefix(relPath, ignorePath+string(filepath.Separator)) {
+						c.log("%s: skipped src-ignore-path %s", project.Name, filepath.ToSlash(relPath))
 						return filepath.SkipDir
 					}
 				}
 
 				// Use project matcher (includes global excludes + project exclude patterns)
-				if projectMatcher.ShouldIgnore(path) {
+				if shouldIgnoreDir(projectMatcher, c.rootDir, path) {
+					c.log("%s: skipped ignored directory %s", project.Name, filepath.ToSlash(relPath))
 					return filepath.SkipDir
 				}
-				folderSet[path] = true
+				if c.options.CountTestDirsSeparately && isTestDir(relPath) {
+					testFolderSet[path] = true
+				} else {
+					folderSet[path] = true
+				}
 				return nil
 			}
 
 			// Skip non-source files (only count files for this project's runtime)
-			if !isSourceFile(path, project.Runtime.Type) {
+			if !isSourceFile(path, project.Runtime.Type) && !extraExts[strings.ToLower(filepath.Ext(path))] &&
+				!(c.options.IncludeNotebooks && project.Runtime.Type == models.RuntimePython && isNotebook(path)) {
 				return nil
 			}
 
@@ -138,25 +278,132 @@ func (c *Counter) CountProject(project *models.Project) (*models.ProjectStats, e
 				return nil
 			}
 			seenFiles[absPath] = true
-
-			fileStats, err := c.countFile(path)
-			if err == nil {
-				c.addFileStats(stats, fileStats)
-				allFiles = append(allFiles, *fileStats)
-			}
+			filePaths = append(filePaths, path)
 
 			return nil
 		})
 		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, ctxErr
+			}
+			continue
+		}
+	}
+
+	stats.TotalFolders = len(folderSet)
+	stats.TestFolders = len(testFolderSet)
+
+	// Count files concurrently and aggregate in walk order
+	counted, err := c.countFiles(ctx, filePaths)
+	if err != nil {
+		return nil, err
+	}
+	var allFiles []models.FileStats
+	for _, fileStats := range counted {
+		if fileStats == nil {
+			continue // Skip unreadable files
+		}
+		if !c.addFileStats(stats, fileStats) {
+			continue
+		}
+		allFiles = append(allFiles, *fileStats)
+	}
+
+	stats.SubLanguages = splitSubLanguages(allFiles, project.Runtime.Type)
+	setFiles(stats, allFiles)
+
+	return stats, nil
+}
+
+// projectMatcher returns the matcher for a project's files: the base
+// matcher layered with the global, generated-directory, ad-hoc, and project
+// excludes.
+func (c *Counter) projectMatcher(project *models.Project) *ignore.Matcher {
+	// Create a project-specific matcher by cloning the base matcher
+	projectMatcher := c.matcher.Clone()
+
+	// Apply global excludes from config
+	if c.config != nil && len(c.config.GlobalExcludes) > 0 {
+		projectMatcher.AddPatternsFrom("global-excludes in "+filepath.Base(config.ConfigPath(c.rootDir)), c.config.GlobalExcludes)
+	}
+
+	// Apply the curated generated-directory set
+	if c.options.ExcludeGeneratedDirs {
+		projectMatcher.AddPatternsFrom("--exclude-generated-dirs", ignore.GeneratedDirPatterns)
+	}
+
+	// Apply ad-hoc excludes (e.g. from --exclude)
+	if len(c.options.Excludes) > 0 {
+		projectMatcher.AddPatternsFrom("--exclude", c.options.Excludes)
+	}
+
+	// Apply project-specific exclude patterns
+	if len(project.ExcludePatterns) > 0 {
+		projectMatcher.AddPatternsFrom("exclude-patterns of project "+project.Name, project.ExcludePatterns)
+	}
+
+	return projectMatcher
+}
+
+// shouldIgnoreDir reports whether matcher, rooted at rootDir, ignores the
+// directory at path. Walks already know path is a directory, so this avoids
+// the stat in Matcher.ShouldIgnore.
+func shouldIgnoreDir(matcher *ignore.Matcher, rootDir, path string) bool {
+	relPath, err := filepath.Rel(rootDir, path)
+	if err != nil {
+		return matcher.ShouldIgnore(path)
+	}
+	return matcher.ShouldIgnoreDir(relPath)
+}
+
+// CountPaths counts exactly the given files, bypassing discovery, ignore
+// rules, and runtime filtering. The files are grouped under project, which
+// is typically synthetic. Duplicate paths are counted once; files that
+// cannot be read are skipped.
+func (c *Counter) CountPaths(project *models.Project, paths []string) *models.ProjectStats {
+	stats := &models.ProjectStats{Project: project}
+
+	var filePaths []string
+	seenFiles := make(map[string]bool)
+	folderSet := make(map[string]bool)
+
+	for _, p := range paths {
+		absPath, err := filepath.Abs(p)
+		if err != nil || seenFiles[absPath] {
 			continue
 		}
+		seenFiles[absPath] = true
+		filePaths = append(filePaths, p)
+	}
+
+	// A background context is never canceled, so there is no error
+	counted, _ := c.countFiles(context.Background(), filePaths)
+	var allFiles []models.FileStats
+	for _, fileStats := range counted {
+		if fileStats == nil {
+			continue
+		}
+		if !c.addFileStats(stats, fileStats) {
+			continue
+		}
+		allFiles = append(allFiles, *fileStats)
+		folderSet[filepath.Dir(fileStats.Path)] = true
 	}
 
 	stats.TotalFolders = len(folderSet)
+	setFiles(stats, allFiles)
 
-	// Sort files by lines (descending)
+	return stats
+}
+
+// setFiles sorts files by lines (descending, then path for a stable order)
+// and stores them as AllFiles and the top 5 as LargestFiles.
+func setFiles(stats *models.ProjectStats, allFiles []models.FileStats) {
 	sort.Slice(allFiles, func(i, j int) bool {
-		return allFiles[i].Lines > allFiles[j].Lines
+		if allFiles[i].Lines != allFiles[j].Lines {
+			return allFiles[i].Lines > allFiles[j].Lines
+		}
+		return allFiles[i].Path < allFiles[j].Path
 	})
 
 	// Store all files
@@ -168,32 +415,151 @@ func (c *Counter) CountProject(project *models.Project) (*models.ProjectStats, e
 		limit = len(allFiles)
 	}
 	stats.LargestFiles = allFiles[:limit]
+}
 
-	return stats, nil
+// CountHierarchy calculates statistics for a project hierarchy, counting up
+// to opts.Concurrency sibling projects at once. Results keep the order of
+// projects.
+//
+// If ctx is canceled, CountHierarchy stops starting new projects, abandons
+// the ones in progress, and returns the projects that were fully counted
+// together with ctx.Err(). A project whose children were cut short is
+// returned with the children that completed.
+func (c *Counter) CountHierarchy(ctx context.Context, projects []*models.Project, opts HierarchyOptions) ([]*models.ProjectStats, error) {
+	var mu sync.Mutex
+	onProject := opts.OnProject
+	if onProject != nil {
+		opts.OnProject = func(stats *models.ProjectStats) {
+			mu.Lock()
+			defer mu.Unlock()
+			onProject(stats)
+		}
+	}
+	return c.countHierarchy(ctx, projects, opts)
 }
 
-// CountHierarchy calculates statistics for a project hierarchy.
-func (c *Counter) CountHierarchy(projects []*models.Project) ([]*models.ProjectStats, error) {
-	var results []*models.ProjectStats
+func (c *Counter) countHierarchy(ctx context.Context, projects []*models.Project, opts HierarchyOptions) ([]*models.ProjectStats, error) {
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
 
-	for _, project := range projects {
-		stats, err := c.CountProject(project)
-		if err != nil {
-			continue
+	// Each slot is written by exactly one goroutine
+	slots := make([]*models.ProjectStats, len(projects))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, project := range projects {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
 		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			stats, err := c.countProject(ctx, project)
+			if err != nil {
+				return
+			}
+
+			// Recursively count children, keeping those that completed
+			if len(project.Children) > 0 {
+				stats.Children, _ = c.countHierarchy(ctx, project.Children, opts)
+			}
+
+			slots[i] = stats
+			if opts.OnProject != nil {
+				opts.OnProject(stats)
+			}
+		}()
+	}
+	wg.Wait()
+
+	results := make([]*models.ProjectStats, 0, len(projects))
+	for _, stats := range slots {
+		if stats != nil {
+			results = append(results, stats)
+		}
+	}
 
-		// Recursively count children
-		if len(project.Children) > 0 {
-			childStats, err := c.CountHierarchy(project.Children)
-			if err == nil {
-				stats.Children = childStats
+	return results, ctx.Err()
+}
+
+// countFiles counts the given files on a bounded worker pool. The result at
+// index i belongs to paths[i] and is nil if the file could not be counted.
+// If ctx is canceled, it stops handing out files and returns ctx.Err().
+func (c *Counter) countFiles(ctx context.Context, paths []string) ([]*models.FileStats, error) {
+	results := make([]*models.FileStats, len(paths))
+
+	workers := c.options.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > len(paths) {
+		workers = len(paths)
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				fileStats, err := c.countFile(paths[i])
+				if err == nil {
+					results[i] = fileStats
+					c.fileCounted(fileStats)
+				}
 			}
+		}()
+	}
+
+dispatch:
+	for i := range paths {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break dispatch
 		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results, ctx.Err()
+}
+
+// fileCounted reports a counted file to options.OnFile.
+func (c *Counter) fileCounted(fileStats *models.FileStats) {
+	if c.options.OnFile == nil {
+		return
+	}
+	c.callbackMu.Lock()
+	defer c.callbackMu.Unlock()
+	c.options.OnFile(fileStats)
+}
 
-		results = append(results, stats)
+// log sends a diagnostic message to options.Logf, if set.
+func (c *Counter) log(format string, args ...any) {
+	if c.options.Logf == nil {
+		return
 	}
+	c.callbackMu.Lock()
+	defer c.callbackMu.Unlock()
+	c.options.Logf(format, args...)
+}
 
-	return results, nil
+// CountFile counts the lines of a single file regardless of its type or
+// ignore rules.
+func (c *Counter) CountFile(path string) (*models.FileStats, error) {
+	return c.countFile(path)
 }
 
 func (c *Counter) countFile(path string) (*models.FileStats, error) {
@@ -213,32 +579,183 @@ func (c *Counter) countFile(path string) (*models.FileStats, error) {
 		Size: info.Size(),
 	}
 
-	scanner := bufio.NewScanner(file)
+	if c.options.MaxFileSize > 0 && stats.Size > c.options.MaxFileSize {
+		stats.Skipped = true
+		return stats, nil
+	}
+
+	if c.options.Cache != nil && c.options.Cache.lookup(path, info, stats) {
+		return stats, nil
+	}
+
+	binary, err := isBinary(file)
+	if err != nil {
+		return nil, err
+	}
+	if binary {
+		stats.Binary = true
+		if c.options.Cache != nil {
+			c.options.Cache.store(path, info, stats)
+		}
+		return stats, nil
+	}
+
+	if c.options.IncludeNotebooks && isNotebook(path) {
+		if err := countNotebook(file, stats); err != nil {
+			return nil, err
+		}
+		if c.options.Cache != nil {
+			c.options.Cache.store(path, info, stats)
+		}
+		return stats, nil
+	}
+
+	scanner := bufio.NewScanner(textenc.NewReader(file))
 	// Handle long lines
 	buf := make([]byte, 0, 64*1024)
 	scanner.Buffer(buf, 1024*1024)
 
+	var strs *stringBlockScanner
+	if c.options.SeparateDataLines {
+		strs = newStringBlockScanner(path)
+	}
+
+	// blankRun is the number of blank lines just read in a row
+	blankRun := 0
 	for scanner.Scan() {
 		line := scanner.Text()
 		stats.Lines++
 
+		if stats.Lines <= generatedHeaderLines && !stats.Generated && isGeneratedMarker(line) {
+			stats.Generated = true
+		}
+
+		if c.options.MaxLineLength > 0 && utf8.RuneCountInString(line) > c.options.MaxLineLength {
+			stats.LongLines++
+		}
+
 		trimmed := strings.TrimSpace(line)
 		if trimmed == "" {
 			stats.BlankLines++
+			blankRun++
+			if c.options.CountBlankRuns && blankRun == 2 {
+				stats.BlankRuns++
+			}
+			continue
+		}
+
+		blankRun = 0
+		if strs != nil && strs.interior(line) {
+			stats.DataLines++
+		} else if c.options.SeparateStructuralLines && isStructuralLine(trimmed) {
+			stats.StructuralLines++
 		} else {
 			stats.CodeLines++
 		}
 	}
 
-	return stats, scanner.Err()
+	if err := scanner.Err(); err != nil {
+		return stats, err
+	}
+	if c.options.Cache != nil {
+		c.options.Cache.store(path, info, stats)
+	}
+	return stats, nil
+}
+
+// isBinary reports whether file looks binary: its first binarySniffSize
+// bytes contain a NUL byte and it does not start with a UTF-16 byte order
+// mark, since UTF-16 text is full of NUL bytes. The file is rewound
+// afterwards.
+func isBinary(file *os.File) (bool, error) {
+	buf := make([]byte, binarySniffSize)
+	n, err := io.ReadFull(file, buf)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return false, err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return false, err
+	}
+
+	head := buf[:n]
+	if textenc.IsUTF16(head) {
+		return false, nil
+	}
+	return bytes.IndexByte(head, 0) >= 0, nil
+}
+
+// cacheSettings describes the options that affect per-file counts, so a
+// cache written under different settings is not reused.
+func cacheSettings(options Options) string {
+	return fmt.Sprintf("max-line-length=%d,separate-structural=%t,separate-data=%t,notebooks=%t,blank-runs=%t",
+		options.MaxLineLength, options.SeparateStructuralLines, options.SeparateDataLines, options.IncludeNotebooks, options.CountBlankRuns)
+}
+
+// isStructuralLine reports whether a trimmed, non-empty line consists only
+// of braces, parentheses, semicolons, and whitespace.
+func isStructuralLine(trimmed string) bool {
+	return strings.Trim(trimmed, "{}(); \t") == ""
+}
+
+// isGeneratedMarker reports whether line contains a generated-code marker.
+func isGeneratedMarker(line string) bool {
+	for _, marker := range generatedMarkers {
+		if strings.Contains(line, marker) {
+			return true
+		}
+	}
+	return false
 }
 
-func (c *Counter) addFileStats(projectStats *models.ProjectStats, fileStats *models.FileStats) {
+// addFileStats adds a file to the project totals. It returns false if the
+// file was left out because it is too large, looks binary, or generated files
+// are excluded.
+func (c *Counter) addFileStats(projectStats *models.ProjectStats, fileStats *models.FileStats) bool {
+	if fileStats.Skipped {
+		c.log("%s: skipped %s (%d bytes, over the maximum file size)", projectStats.Project.Name, fileStats.Path, fileStats.Size)
+		projectStats.SkippedFiles = append(projectStats.SkippedFiles, models.SkippedFile{
+			Path: fileStats.Path,
+			Size: fileStats.Size,
+		})
+		return false
+	}
+
+	if fileStats.Binary {
+		c.log("%s: skipped %s (binary file)", projectStats.Project.Name, fileStats.Path)
+		projectStats.BinaryFiles++
+		return false
+	}
+
+	if fileStats.Generated {
+		projectStats.GeneratedFiles++
+		projectStats.GeneratedLines += fileStats.Lines
+		if c.options.ExcludeGenerated {
+			return false
+		}
+	}
+
 	projectStats.TotalFiles++
 	projectStats.TotalLines += fileStats.Lines
 	projectStats.BlankLines += fileStats.BlankLines
 	projectStats.CodeLines += fileStats.CodeLines
+	projectStats.StructuralLines += fileStats.StructuralLines
+	projectStats.DataLines += fileStats.DataLines
+	projectStats.NotebookLines += fileStats.NotebookLines
+	projectStats.LongLines += fileStats.LongLines
+	projectStats.BlankRuns += fileStats.BlankRuns
 	projectStats.TotalSize += fileStats.Size
+	return true
+}
+
+// isTestDir reports whether a directory (relative to the project root) is a
+// test directory or lies inside one.
+func isTestDir(relPath string) bool {
+	for _, part := range strings.Split(filepath.ToSlash(relPath), "/") {
+		if testDirNames[part] {
+			return true
+		}
+	}
+	return false
 }
 
 // sourceExtensionsByRuntime maps each RuntimeType to its language-specific source file extensions.
@@ -256,8 +773,13 @@ var sourceExtensionsByRuntime = map[models.RuntimeType]map[string]bool{
 	models.RuntimeTypeScript: {
 		".ts": true, ".tsx": true, ".js": true, ".jsx": true, ".mjs": true, ".cjs": true,
 	},
+	// Gradle modules often mix Java and Kotlin; a mostly Java module keeps
+	// its Kotlin sources, reported as a separate language
 	models.RuntimeJava: {
-		".java": true, ".kt": true, ".kts": true, ".scala": true,
+		".java": true, ".scala": true, ".kt": true, ".kts": true,
+	},
+	models.RuntimeKotlin: {
+		".kt": true, ".kts": true,
 	},
 	models.RuntimeDotNet: {
 		".cs": true, ".fs": true, ".vb": true,
@@ -268,12 +790,120 @@ var sourceExtensionsByRuntime = map[models.RuntimeType]map[string]bool{
 	models.RuntimeDart: {
 		".dart": true,
 	},
+	models.RuntimeHaskell: {
+		".hs": true, ".lhs": true,
+	},
+	models.RuntimePHP: {
+		".php": true,
+	},
+	models.RuntimeSQL: {
+		".sql": true,
+	},
+	models.RuntimeNim: {
+		".nim": true, ".nims": true,
+	},
+	models.RuntimeR: {
+		".r": true, ".rmd": true,
+	},
+	models.RuntimeSwift: {
+		".swift": true, ".m": true, ".mm": true, ".h": true,
+	},
+	models.RuntimeClojure: {
+		".clj": true, ".cljs": true, ".cljc": true, ".edn": true,
+	},
+	models.RuntimeOCaml: {
+		".ml": true, ".mli": true,
+	},
 	models.RuntimeCpp: {
 		".c": true, ".h": true, ".cpp": true, ".cc": true, ".cxx": true,
 		".hpp": true, ".hh": true, ".hxx": true,
 	},
 }
 
+// subLanguagesByRuntime maps source extensions to individual languages for
+// runtimes that span more than one language.
+var subLanguagesByRuntime = map[models.RuntimeType]map[string]string{
+	models.RuntimeCpp: {
+		".c": "C", ".h": "C",
+		".cpp": "C++", ".cc": "C++", ".cxx": "C++",
+		".hpp": "C++", ".hh": "C++", ".hxx": "C++",
+	},
+	models.RuntimeSwift: {
+		".swift": "Swift", ".m": "Objective-C", ".mm": "Objective-C", ".h": "Objective-C",
+	},
+	models.RuntimeJava: {
+		".java": "Java", ".scala": "Scala", ".kt": "Kotlin", ".kts": "Kotlin",
+	},
+}
+
+// splitSubLanguages groups file statistics by language for runtimes listed in
+// subLanguagesByRuntime. Returns nil for single-language runtimes.
+func splitSubLanguages(files []models.FileStats, runtimeType models.RuntimeType) []models.SubLanguageStats {
+	languages, ok := subLanguagesByRuntime[runtimeType]
+	if !ok {
+		return nil
+	}
+
+	byLanguage := make(map[string]*models.SubLanguageStats)
+	for _, f := range files {
+		lang, ok := languages[strings.ToLower(filepath.Ext(f.Path))]
+		if !ok {
+			continue
+		}
+		bucket, ok := byLanguage[lang]
+		if !ok {
+			bucket = &models.SubLanguageStats{Language: lang}
+			byLanguage[lang] = bucket
+		}
+		bucket.TotalFiles++
+		bucket.TotalLines += f.Lines
+		bucket.BlankLines += f.BlankLines
+		bucket.CodeLines += f.CodeLines
+	}
+
+	result := make([]models.SubLanguageStats, 0, len(byLanguage))
+	for _, bucket := range byLanguage {
+		result = append(result, *bucket)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Language < result[j].Language
+	})
+
+	return result
+}
+
+// extensionSet returns the lowercase extensions of lists as a set, adding a
+// leading dot where it is missing, so "tmpl", ".tmpl", and ".TMPL" are the
+// same extension.
+func extensionSet(lists ...[]string) map[string]bool {
+	set := make(map[string]bool)
+	for _, list := range lists {
+		for _, ext := range list {
+			ext = strings.ToLower(strings.TrimSpace(ext))
+			if ext == "" {
+				continue
+			}
+			if !strings.HasPrefix(ext, ".") {
+				ext = "." + ext
+			}
+			set[ext] = true
+		}
+	}
+	return set
+}
+
+// IsSourceExtension reports whether some runtime counts files with the
+// extension ext (e.g. ".go"), compared case-insensitively.
+func IsSourceExtension(ext string) bool {
+	ext = strings.ToLower(ext)
+	for _, exts := range sourceExtensionsByRuntime {
+		if exts[ext] {
+			return true
+		}
+	}
+	return false
+}
+
 // isSourceFile checks if a file is a source code file for the given runtime type.
 func isSourceFile(path string, runtimeType models.RuntimeType) bool {
 	ext := strings.ToLower(filepath.Ext(path))
@@ -0,0 +1,1058 @@
+package stats
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf16"
+
+	"repoctr/pkg/models"
+)
+
+// writeFile creates a file (and its parent directories) under root.
+func writeFile(t *testing.T, root, rel, content string) {
+	t.Helper()
+	full := filepath.Join(root, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+		t.Fatalf("write %s: %v", rel, err)
+	}
+}
+
+func goProject() *models.Project {
+	return &models.Project{
+		Name:        "app",
+		Path:        ".",
+		Runtime:     models.Runtime{Type: models.RuntimeGo},
+		SourcePaths: []string{"."},
+	}
+}
+
+func TestCounter_CountTestDirsSeparately(t *testing.T) {
+	root := t.TempDir()
+
+	writeFile(t, root, "main.go", "package main\n")
+	writeFile(t, root, "internal/app.go", "package internal\n")
+	writeFile(t, root, "tests/app_test.go", "package tests\n")
+	writeFile(t, root, "tests/fixtures/data.go", "package fixtures\n")
+
+	counter, err := NewCounterWithOptions(root, Options{CountTestDirsSeparately: true})
+	if err != nil {
+		t.Fatalf("NewCounterWithOptions: %v", err)
+	}
+
+	stats, err := counter.CountProject(goProject())
+	if err != nil {
+		t.Fatalf("CountProject: %v", err)
+	}
+
+	// ".", "internal" are regular folders; "tests", "tests/fixtures" are test folders
+	if stats.TotalFolders != 2 {
+		t.Errorf("TotalFolders = %d, want 2", stats.TotalFolders)
+	}
+	if stats.TestFolders != 2 {
+		t.Errorf("TestFolders = %d, want 2", stats.TestFolders)
+	}
+	if stats.TotalFiles != 4 {
+		t.Errorf("TotalFiles = %d, want 4", stats.TotalFiles)
+	}
+}
+
+func TestCounter_TestDirsCountedAsFoldersByDefault(t *testing.T) {
+	root := t.TempDir()
+
+	writeFile(t, root, "main.go", "package main\n")
+	writeFile(t, root, "tests/app_test.go", "package tests\n")
+
+	counter, err := NewCounter(root)
+	if err != nil {
+		t.Fatalf("NewCounter: %v", err)
+	}
+
+	stats, err := counter.CountProject(goProject())
+	if err != nil {
+		t.Fatalf("CountProject: %v", err)
+	}
+
+	if stats.TotalFolders != 2 {
+		t.Errorf("TotalFolders = %d, want 2", stats.TotalFolders)
+	}
+	if stats.TestFolders != 0 {
+		t.Errorf("TestFolders = %d, want 0", stats.TestFolders)
+	}
+}
+
+func TestCounter_AdHocExcludes(t *testing.T) {
+	root := t.TempDir()
+
+	writeFile(t, root, "main.go", "package main\n")
+	writeFile(t, root, "api.gen.go", "package main\n")
+	writeFile(t, root, "pkg/testdata/sample.go", "package testdata\n")
+	writeFile(t, root, "pkg/util.go", "package pkg\n")
+	writeFile(t, root, "legacy/old.go", "package legacy\n")
+	writeFile(t, root, ".repoctrconfig.yaml", "global-excludes:\n  - legacy/**\n")
+
+	counter, err := NewCounterWithOptions(root, Options{
+		Excludes: []string{"**/testdata/**", "*.gen.go"},
+	})
+	if err != nil {
+		t.Fatalf("NewCounterWithOptions: %v", err)
+	}
+
+	stats, err := counter.CountProject(goProject())
+	if err != nil {
+		t.Fatalf("CountProject: %v", err)
+	}
+
+	// Only main.go and pkg/util.go survive both ad-hoc and config excludes
+	if stats.TotalFiles != 2 {
+		var names []string
+		for _, f := range stats.AllFiles {
+			names = append(names, f.Path)
+		}
+		t.Errorf("TotalFiles = %d, want 2 (got %v)", stats.TotalFiles, names)
+	}
+}
+
+// writeSampleTree creates a Go source tree with varied file sizes.
+func writeSampleTree(t testing.TB, root string, files int) {
+	t.Helper()
+	for i := 0; i < files; i++ {
+		var content strings.Builder
+		content.WriteString("package sample\n")
+		for j := 0; j < i%17; j++ {
+			content.WriteString("\nfunc f() {}\n")
+		}
+		rel := filepath.Join(fmt.Sprintf("pkg%d", i%7), fmt.Sprintf("file%d.go", i))
+		full := filepath.Join(root, rel)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(full, []byte(content.String()), 0644); err != nil {
+			t.Fatalf("write %s: %v", rel, err)
+		}
+	}
+}
+
+func TestCounter_ConcurrentMatchesSerial(t *testing.T) {
+	root := t.TempDir()
+	writeSampleTree(t, root, 200)
+
+	count := func(workers int) *models.ProjectStats {
+		counter, err := NewCounterWithOptions(root, Options{Workers: workers})
+		if err != nil {
+			t.Fatalf("NewCounterWithOptions: %v", err)
+		}
+		stats, err := counter.CountProject(goProject())
+		if err != nil {
+			t.Fatalf("CountProject: %v", err)
+		}
+		return stats
+	}
+
+	serial := count(1)
+	parallel := count(8)
+
+	if serial.TotalFiles != 200 {
+		t.Fatalf("serial TotalFiles = %d, want 200", serial.TotalFiles)
+	}
+	if serial.TotalFiles != parallel.TotalFiles || serial.TotalLines != parallel.TotalLines ||
+		serial.CodeLines != parallel.CodeLines || serial.BlankLines != parallel.BlankLines ||
+		serial.TotalSize != parallel.TotalSize || serial.TotalFolders != parallel.TotalFolders {
+		t.Errorf("totals differ: serial %+v, parallel %+v", serial, parallel)
+	}
+
+	if len(serial.AllFiles) != len(parallel.AllFiles) {
+		t.Fatalf("AllFiles length differs: %d vs %d", len(serial.AllFiles), len(parallel.AllFiles))
+	}
+	for i := range serial.AllFiles {
+		if serial.AllFiles[i] != parallel.AllFiles[i] {
+			t.Errorf("AllFiles[%d] differs: %+v vs %+v", i, serial.AllFiles[i], parallel.AllFiles[i])
+		}
+	}
+}
+
+func BenchmarkCounter_CountProject(b *testing.B) {
+	root := b.TempDir()
+	writeSampleTree(b, root, 2000)
+
+	for _, workers := range []int{1, 0} {
+		name := "serial"
+		if workers == 0 {
+			name = "parallel"
+		}
+		b.Run(name, func(b *testing.B) {
+			counter, err := NewCounterWithOptions(root, Options{Workers: workers})
+			if err != nil {
+				b.Fatalf("NewCounterWithOptions: %v", err)
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := counter.CountProject(goProject()); err != nil {
+					b.Fatalf("CountProject: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestCounter_CppSubLanguages(t *testing.T) {
+	root := t.TempDir()
+
+	writeFile(t, root, "src/util.c", "int add(int a, int b) {\n\n    return a + b;\n}\n")
+	writeFile(t, root, "include/util.h", "int add(int a, int b);\n")
+	writeFile(t, root, "src/main.cpp", "#include <iostream>\n\nint main() {\n    return 0;\n}\n")
+	writeFile(t, root, "include/app.hpp", "#pragma once\nclass App {};\n")
+
+	counter, err := NewCounter(root)
+	if err != nil {
+		t.Fatalf("NewCounter: %v", err)
+	}
+
+	stats, err := counter.CountProject(&models.Project{
+		Name:        "mixed",
+		Path:        ".",
+		Runtime:     models.Runtime{Type: models.RuntimeCpp},
+		SourcePaths: []string{"."},
+	})
+	if err != nil {
+		t.Fatalf("CountProject: %v", err)
+	}
+
+	if len(stats.SubLanguages) != 2 {
+		t.Fatalf("expected 2 sub-languages, got %+v", stats.SubLanguages)
+	}
+
+	c, cpp := stats.SubLanguages[0], stats.SubLanguages[1]
+	if c.Language != "C" || c.TotalFiles != 2 || c.TotalLines != 5 || c.CodeLines != 4 || c.BlankLines != 1 {
+		t.Errorf("unexpected C bucket: %+v", c)
+	}
+	if cpp.Language != "C++" || cpp.TotalFiles != 2 || cpp.TotalLines != 7 || cpp.CodeLines != 6 || cpp.BlankLines != 1 {
+		t.Errorf("unexpected C++ bucket: %+v", cpp)
+	}
+}
+
+func TestCounter_CountPaths(t *testing.T) {
+	root := t.TempDir()
+
+	writeFile(t, root, "a.go", "package a\n\nfunc A() {}\n")
+	writeFile(t, root, "docs/readme.md", "# Title\n\ntext\nmore\n")
+	writeFile(t, root, "vendor/skip.go", "package skip\n")
+
+	counter, err := NewCounter(root)
+	if err != nil {
+		t.Fatalf("NewCounter: %v", err)
+	}
+
+	paths := []string{
+		filepath.Join(root, "a.go"),
+		filepath.Join(root, "docs", "readme.md"),
+		filepath.Join(root, "vendor", "skip.go"),
+		filepath.Join(root, "a.go"),               // duplicate
+		filepath.Join(root, "missing", "gone.go"), // unreadable
+	}
+
+	stats := counter.CountPaths(&models.Project{Name: "paths-from", Path: "."}, paths)
+
+	// Listed files are counted regardless of runtime or default ignores
+	if stats.TotalFiles != 3 {
+		t.Errorf("TotalFiles = %d, want 3", stats.TotalFiles)
+	}
+	if stats.TotalLines != 3+4+1 {
+		t.Errorf("TotalLines = %d, want %d", stats.TotalLines, 3+4+1)
+	}
+	if stats.BlankLines != 2 {
+		t.Errorf("BlankLines = %d, want 2", stats.BlankLines)
+	}
+	if stats.TotalFolders != 3 {
+		t.Errorf("TotalFolders = %d, want 3", stats.TotalFolders)
+	}
+}
+
+func TestCounter_ExcludeGeneratedDirs(t *testing.T) {
+	root := t.TempDir()
+
+	writeFile(t, root, "main.go", "package main\n")
+	writeFile(t, root, "generated/models.go", "package generated\n")
+	writeFile(t, root, "api/service.pb.go", "package api\n")
+	writeFile(t, root, "db/migrations/001_init.go", "package migrations\n")
+
+	count := func(exclude bool) int {
+		counter, err := NewCounterWithOptions(root, Options{ExcludeGeneratedDirs: exclude})
+		if err != nil {
+			t.Fatalf("NewCounterWithOptions: %v", err)
+		}
+		stats, err := counter.CountProject(goProject())
+		if err != nil {
+			t.Fatalf("CountProject: %v", err)
+		}
+		return stats.TotalFiles
+	}
+
+	if got := count(false); got != 4 {
+		t.Errorf("without flag TotalFiles = %d, want 4", got)
+	}
+	if got := count(true); got != 1 {
+		t.Errorf("with flag TotalFiles = %d, want 1", got)
+	}
+}
+
+func TestCounter_IncludeExtensions(t *testing.T) {
+	root := t.TempDir()
+
+	writeFile(t, root, "main.go", "package main\n")
+	writeFile(t, root, "templates/page.tmpl", "<html>\n{{ .Title }}\n</html>\n")
+	writeFile(t, root, "queries/users.SQL", "SELECT 1;\n")
+
+	tests := []struct {
+		name      string
+		options   Options
+		extra     []string
+		wantFiles int
+	}{
+		{name: "runtime extensions only", wantFiles: 1},
+		{name: "flag", options: Options{IncludeExtensions: []string{".tmpl"}}, wantFiles: 2},
+		{name: "project extra-extensions without dot", extra: []string{"tmpl", "sql"}, wantFiles: 3},
+		{name: "flag and project", options: Options{IncludeExtensions: []string{".TMPL"}}, extra: []string{".sql"}, wantFiles: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counter, err := NewCounterWithOptions(root, tt.options)
+			if err != nil {
+				t.Fatalf("NewCounterWithOptions: %v", err)
+			}
+			project := goProject()
+			project.ExtraExtensions = tt.extra
+			stats, err := counter.CountProject(project)
+			if err != nil {
+				t.Fatalf("CountProject: %v", err)
+			}
+			if stats.TotalFiles != tt.wantFiles {
+				t.Errorf("TotalFiles = %d, want %d", stats.TotalFiles, tt.wantFiles)
+			}
+		})
+	}
+}
+
+func TestCounter_GeneratedFiles(t *testing.T) {
+	root := t.TempDir()
+
+	writeFile(t, root, "main.go", "package main\n\nfunc main() {}\n")
+	writeFile(t, root, "api.pb.go", "// Code generated by protoc-gen-go. DO NOT EDIT.\n// source: api.proto\n\npackage main\n\ntype Req struct{}\n")
+	// A marker below the header window is not treated as generated
+	late := strings.Repeat("// comment\n", 20) + "// Code generated by nothing. DO NOT EDIT.\npackage main\n"
+	writeFile(t, root, "late.go", late)
+
+	tests := []struct {
+		name      string
+		options   Options
+		wantFiles int
+		wantCode  int
+	}{
+		{"counted by default", Options{}, 3, 2 + 4 + 22},
+		{"excluded", Options{ExcludeGenerated: true}, 2, 2 + 22},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counter, err := NewCounterWithOptions(root, tt.options)
+			if err != nil {
+				t.Fatalf("NewCounterWithOptions: %v", err)
+			}
+
+			stats, err := counter.CountProject(goProject())
+			if err != nil {
+				t.Fatalf("CountProject: %v", err)
+			}
+
+			if stats.GeneratedFiles != 1 {
+				t.Errorf("GeneratedFiles = %d, want 1", stats.GeneratedFiles)
+			}
+			if stats.GeneratedLines != 6 {
+				t.Errorf("GeneratedLines = %d, want 6", stats.GeneratedLines)
+			}
+			if stats.TotalFiles != tt.wantFiles {
+				t.Errorf("TotalFiles = %d, want %d", stats.TotalFiles, tt.wantFiles)
+			}
+			if stats.CodeLines != tt.wantCode {
+				t.Errorf("CodeLines = %d, want %d", stats.CodeLines, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestCounter_SeparateDataLines(t *testing.T) {
+	goRoot := t.TempDir()
+	writeFile(t, goRoot, "schema.go", "package main\n"+
+		"\n"+
+		"// A ` in a comment does not open a string\n"+
+		"var sep = \"`\"\n"+
+		"var r = '`'\n"+
+		"\n"+
+		"const schema = `{\n"+
+		"  \"type\": \"object\",\n"+
+		"  \"properties\": {\n"+
+		"    \"id\": {\"type\": \"integer\"},\n"+
+		"\n"+
+		"    \"name\": {\"type\": \"string\"}\n"+
+		"  }\n"+
+		"}`\n"+
+		"var inline = `one line`\n"+
+		"func main() {}\n")
+
+	pyRoot := t.TempDir()
+	writeFile(t, pyRoot, "query.py", `def query():
+    """Return the query."""
+    return """
+SELECT id,
+       name
+FROM users
+"""
+x = "'''"  # not a string
+`)
+	pyProject := &models.Project{Name: "q", Path: ".", Runtime: models.Runtime{Type: models.RuntimePython}, SourcePaths: []string{"."}}
+
+	tests := []struct {
+		name     string
+		root     string
+		project  *models.Project
+		separate bool
+		wantCode int
+		wantData int
+	}{
+		{"go strings counted as code by default", goRoot, goProject(), false, 13, 0},
+		{"go raw string interior as data", goRoot, goProject(), true, 8, 5},
+		{"python triple-quoted interior as data", pyRoot, pyProject, true, 5, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counter, err := NewCounterWithOptions(tt.root, Options{SeparateDataLines: tt.separate})
+			if err != nil {
+				t.Fatalf("NewCounterWithOptions: %v", err)
+			}
+
+			stats, err := counter.CountProject(tt.project)
+			if err != nil {
+				t.Fatalf("CountProject: %v", err)
+			}
+
+			if stats.CodeLines != tt.wantCode {
+				t.Errorf("CodeLines = %d, want %d", stats.CodeLines, tt.wantCode)
+			}
+			if stats.DataLines != tt.wantData {
+				t.Errorf("DataLines = %d, want %d", stats.DataLines, tt.wantData)
+			}
+			if got := stats.CodeLines + stats.BlankLines + stats.DataLines; got != stats.TotalLines {
+				t.Errorf("code+blank+data = %d, want TotalLines %d", got, stats.TotalLines)
+			}
+		})
+	}
+}
+
+func TestCounter_IncludeNotebooks(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "main.py", "print('hi')\n")
+	writeFile(t, root, "analysis.ipynb", `{
+ "cells": [
+  {"cell_type": "markdown", "metadata": {}, "source": ["# Analysis\n", "\n", "Some notes.\n"]},
+  {"cell_type": "code", "execution_count": 1, "metadata": {},
+   "outputs": [{"output_type": "stream", "name": "stdout", "text": ["3\n", "4\n"]}],
+   "source": ["import pandas as pd\n", "\n", "df = pd.read_csv('data.csv')\n", "print(len(df))"]},
+  {"cell_type": "raw", "metadata": {}, "source": "raw text\nmore"},
+  {"cell_type": "code", "execution_count": 2, "metadata": {}, "outputs": [], "source": "df.head()\n"}
+ ],
+ "metadata": {},
+ "nbformat": 4,
+ "nbformat_minor": 5
+}
+`)
+	project := &models.Project{Name: "nb", Path: ".", Runtime: models.Runtime{Type: models.RuntimePython}, SourcePaths: []string{"."}}
+
+	counter, err := NewCounterWithOptions(root, Options{})
+	if err != nil {
+		t.Fatalf("NewCounterWithOptions: %v", err)
+	}
+	stats, err := counter.CountProject(project)
+	if err != nil {
+		t.Fatalf("CountProject: %v", err)
+	}
+	if stats.TotalFiles != 1 || stats.NotebookLines != 0 {
+		t.Errorf("without notebooks: files = %d, notebook lines = %d, want 1 and 0", stats.TotalFiles, stats.NotebookLines)
+	}
+
+	counter, err = NewCounterWithOptions(root, Options{IncludeNotebooks: true})
+	if err != nil {
+		t.Fatalf("NewCounterWithOptions: %v", err)
+	}
+	stats, err = counter.CountProject(project)
+	if err != nil {
+		t.Fatalf("CountProject: %v", err)
+	}
+
+	// Only the two code cells count: 4 non-blank lines and 1 blank line
+	if stats.TotalFiles != 2 {
+		t.Errorf("TotalFiles = %d, want 2", stats.TotalFiles)
+	}
+	if stats.NotebookLines != 4 {
+		t.Errorf("NotebookLines = %d, want 4", stats.NotebookLines)
+	}
+	if stats.CodeLines != 1 {
+		t.Errorf("CodeLines = %d, want 1 (main.py only)", stats.CodeLines)
+	}
+	if stats.BlankLines != 1 {
+		t.Errorf("BlankLines = %d, want 1", stats.BlankLines)
+	}
+	if stats.TotalLines != 6 {
+		t.Errorf("TotalLines = %d, want 6", stats.TotalLines)
+	}
+}
+
+func TestCounter_OnFile(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "main.go", "package main\n")
+	writeFile(t, root, "util/util.go", "package util\n")
+	writeFile(t, root, "util/strings.go", "package util\n")
+	writeFile(t, root, "README.md", "# not counted\n")
+
+	var paths []string
+	counter, err := NewCounterWithOptions(root, Options{
+		Workers: 2,
+		OnFile:  func(f *models.FileStats) { paths = append(paths, f.Path) },
+	})
+	if err != nil {
+		t.Fatalf("NewCounterWithOptions: %v", err)
+	}
+
+	stats, err := counter.CountProject(goProject())
+	if err != nil {
+		t.Fatalf("CountProject: %v", err)
+	}
+
+	if len(paths) != stats.TotalFiles || len(paths) != 3 {
+		t.Fatalf("OnFile called %d times (%v), want once per counted file (%d)", len(paths), paths, stats.TotalFiles)
+	}
+	seen := make(map[string]bool)
+	for _, p := range paths {
+		if seen[p] {
+			t.Errorf("OnFile called twice for %s", p)
+		}
+		seen[p] = true
+	}
+}
+
+func TestCounter_SeparateStructuralLines(t *testing.T) {
+	root := t.TempDir()
+
+	content := "package main\n\nfunc main() {\n\tif true {\n\t\tprintln(\"x\");\n\t}\n}\n(\n);\n  }  \n})\n"
+	writeFile(t, root, "main.go", content)
+
+	tests := []struct {
+		name           string
+		options        Options
+		wantCode       int
+		wantStructural int
+	}{
+		{"counted as code by default", Options{}, 10, 0},
+		{"separated", Options{SeparateStructuralLines: true}, 4, 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counter, err := NewCounterWithOptions(root, tt.options)
+			if err != nil {
+				t.Fatalf("NewCounterWithOptions: %v", err)
+			}
+
+			stats, err := counter.CountProject(goProject())
+			if err != nil {
+				t.Fatalf("CountProject: %v", err)
+			}
+
+			if stats.CodeLines != tt.wantCode {
+				t.Errorf("CodeLines = %d, want %d", stats.CodeLines, tt.wantCode)
+			}
+			if stats.StructuralLines != tt.wantStructural {
+				t.Errorf("StructuralLines = %d, want %d", stats.StructuralLines, tt.wantStructural)
+			}
+			if stats.BlankLines != 1 {
+				t.Errorf("BlankLines = %d, want 1", stats.BlankLines)
+			}
+			if got := stats.CodeLines + stats.BlankLines + stats.StructuralLines; got != stats.TotalLines {
+				t.Errorf("code+blank+structural = %d, want TotalLines %d", got, stats.TotalLines)
+			}
+		})
+	}
+}
+
+func TestCounter_LongLines(t *testing.T) {
+	root := t.TempDir()
+
+	long := "// " + strings.Repeat("x", 30)
+	// 20 runes but 40 bytes: only rune length counts
+	multiByte := "// " + strings.Repeat("é", 17)
+	writeFile(t, root, "a.go", strings.Join([]string{"package a", long, long, multiByte, ""}, "\n"))
+	writeFile(t, root, "b.go", strings.Join([]string{"package b", long, ""}, "\n"))
+
+	tests := []struct {
+		name    string
+		options Options
+		want    map[string]int
+	}{
+		{"disabled by default", Options{}, map[string]int{"a.go": 0, "b.go": 0}},
+		{"width 20", Options{MaxLineLength: 20}, map[string]int{"a.go": 2, "b.go": 1}},
+		{"width 19", Options{MaxLineLength: 19}, map[string]int{"a.go": 3, "b.go": 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counter, err := NewCounterWithOptions(root, tt.options)
+			if err != nil {
+				t.Fatalf("NewCounterWithOptions: %v", err)
+			}
+
+			stats, err := counter.CountProject(goProject())
+			if err != nil {
+				t.Fatalf("CountProject: %v", err)
+			}
+
+			total := 0
+			for _, f := range stats.AllFiles {
+				name := filepath.Base(f.Path)
+				if f.LongLines != tt.want[name] {
+					t.Errorf("%s LongLines = %d, want %d", name, f.LongLines, tt.want[name])
+				}
+				total += tt.want[name]
+			}
+			if stats.LongLines != total {
+				t.Errorf("project LongLines = %d, want %d", stats.LongLines, total)
+			}
+		})
+	}
+}
+
+func TestCounter_BlankRuns(t *testing.T) {
+	root := t.TempDir()
+	// Runs of 2, 3, and 2 blank lines (the last one at the end of the
+	// file); single blank lines do not count
+	writeFile(t, root, "gaps.go", "package main\n\n\nimport \"fmt\"\n\nfunc a() {}\n\n\n\nfunc b() {\n\tfmt.Println()\n}\n\n\n")
+	writeFile(t, root, "tidy.go", "package main\n\nfunc c() {}\n")
+
+	tests := []struct {
+		name    string
+		options Options
+		want    map[string]int
+	}{
+		{"disabled by default", Options{}, map[string]int{"gaps.go": 0, "tidy.go": 0}},
+		{"enabled", Options{CountBlankRuns: true}, map[string]int{"gaps.go": 3, "tidy.go": 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counter, err := NewCounterWithOptions(root, tt.options)
+			if err != nil {
+				t.Fatalf("NewCounterWithOptions: %v", err)
+			}
+
+			stats, err := counter.CountProject(goProject())
+			if err != nil {
+				t.Fatalf("CountProject: %v", err)
+			}
+
+			total := 0
+			for _, f := range stats.AllFiles {
+				name := filepath.Base(f.Path)
+				if f.BlankRuns != tt.want[name] {
+					t.Errorf("%s BlankRuns = %d, want %d", name, f.BlankRuns, tt.want[name])
+				}
+				if name == "gaps.go" && f.BlankLines != 8 {
+					t.Errorf("gaps.go BlankLines = %d, want 8", f.BlankLines)
+				}
+				total += tt.want[name]
+			}
+			if stats.BlankRuns != total {
+				t.Errorf("project BlankRuns = %d, want %d", stats.BlankRuns, total)
+			}
+		})
+	}
+}
+
+func TestCounter_ConfigNegationReincludes(t *testing.T) {
+	root := t.TempDir()
+
+	writeFile(t, root, ".repoctrconfig.yaml", "global-excludes:\n  - \"dist/**\"\n  - \"!dist/keep.js\"\n")
+	writeFile(t, root, "src/app.js", "console.log(1)\n")
+	writeFile(t, root, "dist/bundle.js", "console.log(2)\n")
+	writeFile(t, root, "dist/keep.js", "console.log(3)\n")
+
+	counter, err := NewCounter(root)
+	if err != nil {
+		t.Fatalf("NewCounter: %v", err)
+	}
+
+	stats, err := counter.CountProject(&models.Project{
+		Name:        "web",
+		Path:        ".",
+		Runtime:     models.Runtime{Type: models.RuntimeJavaScript},
+		SourcePaths: []string{"."},
+	})
+	if err != nil {
+		t.Fatalf("CountProject: %v", err)
+	}
+
+	var counted []string
+	for _, f := range stats.AllFiles {
+		rel, _ := filepath.Rel(root, f.Path)
+		counted = append(counted, filepath.ToSlash(rel))
+	}
+	sort.Strings(counted)
+
+	want := []string{"dist/keep.js", "src/app.js"}
+	if strings.Join(counted, ",") != strings.Join(want, ",") {
+		t.Errorf("counted files = %v, want %v", counted, want)
+	}
+}
+
+func TestCounter_MaxFileSize(t *testing.T) {
+	root := t.TempDir()
+
+	const limit = 100
+	writeFile(t, root, "under.go", strings.Repeat("x", limit-1)+"\n") // 100 bytes
+	writeFile(t, root, "over.go", strings.Repeat("x", limit)+"\n")    // 101 bytes
+	writeFile(t, root, "small.go", "package main\n")
+
+	counter, err := NewCounterWithOptions(root, Options{MaxFileSize: limit})
+	if err != nil {
+		t.Fatalf("NewCounterWithOptions: %v", err)
+	}
+
+	stats, err := counter.CountProject(goProject())
+	if err != nil {
+		t.Fatalf("CountProject: %v", err)
+	}
+
+	if stats.TotalFiles != 2 {
+		t.Errorf("TotalFiles = %d, want 2", stats.TotalFiles)
+	}
+	if stats.TotalLines != 2 {
+		t.Errorf("TotalLines = %d, want 2", stats.TotalLines)
+	}
+	if len(stats.SkippedFiles) != 1 {
+		t.Fatalf("SkippedFiles = %+v, want 1 entry", stats.SkippedFiles)
+	}
+	if skipped := stats.SkippedFiles[0]; filepath.Base(skipped.Path) != "over.go" || skipped.Size != limit+1 {
+		t.Errorf("skipped = %+v, want over.go with size %d", skipped, limit+1)
+	}
+
+	unlimited, err := NewCounter(root)
+	if err != nil {
+		t.Fatalf("NewCounter: %v", err)
+	}
+	stats, err = unlimited.CountProject(goProject())
+	if err != nil {
+		t.Fatalf("CountProject: %v", err)
+	}
+	if stats.TotalFiles != 3 || len(stats.SkippedFiles) != 0 {
+		t.Errorf("without a limit: TotalFiles = %d, SkippedFiles = %d; want 3, 0", stats.TotalFiles, len(stats.SkippedFiles))
+	}
+}
+
+func TestCounter_SkipsCustomNamedVirtualEnv(t *testing.T) {
+	root := t.TempDir()
+
+	writeFile(t, root, "app.py", "print('hi')\n")
+	writeFile(t, root, "tools-env/pyvenv.cfg", "home = /usr/bin\n")
+	writeFile(t, root, "tools-env/lib/python3.12/site-packages/requests/api.py", "def get():\n    pass\n")
+	writeFile(t, root, "tools-env/lib/python3.12/os.py", "import sys\n")
+
+	counter, err := NewCounter(root)
+	if err != nil {
+		t.Fatalf("NewCounter: %v", err)
+	}
+
+	project := &models.Project{
+		Name:        "app",
+		Path:        ".",
+		Runtime:     models.Runtime{Type: models.RuntimePython},
+		SourcePaths: []string{"."},
+	}
+	stats, err := counter.CountProject(project)
+	if err != nil {
+		t.Fatalf("CountProject: %v", err)
+	}
+
+	if stats.TotalFiles != 1 {
+		t.Errorf("TotalFiles = %d, want 1 (virtualenv files should be skipped)", stats.TotalFiles)
+	}
+	if stats.TotalFolders != 1 {
+		t.Errorf("TotalFolders = %d, want 1", stats.TotalFolders)
+	}
+}
+
+// hierarchyProjects creates n single-file Go projects p0..pn-1 under root.
+func hierarchyProjects(t *testing.T, root string, n int) []*models.Project {
+	t.Helper()
+	var projects []*models.Project
+	for i := 0; i < n; i++ {
+		name := fmt.Sprintf("p%d", i)
+		writeFile(t, root, name+"/main.go", strings.Repeat("package main\n", i+1))
+		projects = append(projects, &models.Project{
+			Name:        name,
+			Path:        name,
+			Runtime:     models.Runtime{Type: models.RuntimeGo},
+			SourcePaths: []string{"."},
+		})
+	}
+	return projects
+}
+
+func TestCounter_CountHierarchyConcurrentKeepsOrder(t *testing.T) {
+	root := t.TempDir()
+	projects := hierarchyProjects(t, root, 8)
+	projects[0].Children = hierarchyProjects(t, filepath.Join(root, "libs"), 2)
+	for _, child := range projects[0].Children {
+		child.Path = filepath.Join("libs", child.Path)
+	}
+
+	counter, err := NewCounter(root)
+	if err != nil {
+		t.Fatalf("NewCounter: %v", err)
+	}
+
+	var counted int
+	stats, err := counter.CountHierarchy(context.Background(), projects, HierarchyOptions{
+		Concurrency: 4,
+		OnProject:   func(*models.ProjectStats) { counted++ },
+	})
+	if err != nil {
+		t.Fatalf("CountHierarchy: %v", err)
+	}
+
+	if len(stats) != len(projects) {
+		t.Fatalf("got %d projects, want %d", len(stats), len(projects))
+	}
+	for i, s := range stats {
+		if s.Project != projects[i] || s.TotalLines != i+1 {
+			t.Errorf("stats[%d] = %s with %d lines, want %s with %d", i, s.Project.Name, s.TotalLines, projects[i].Name, i+1)
+		}
+	}
+	if len(stats[0].Children) != 2 {
+		t.Errorf("p0 has %d children, want 2", len(stats[0].Children))
+	}
+	if counted != 10 {
+		t.Errorf("OnProject called %d times, want 10", counted)
+	}
+}
+
+func TestCounter_CountHierarchyCanceled(t *testing.T) {
+	root := t.TempDir()
+	projects := hierarchyProjects(t, root, 5)
+
+	counter, err := NewCounter(root)
+	if err != nil {
+		t.Fatalf("NewCounter: %v", err)
+	}
+
+	// Cancel once two projects are done, like a Ctrl-C partway through
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := 0
+	stats, err := counter.CountHierarchy(ctx, projects, HierarchyOptions{
+		OnProject: func(*models.ProjectStats) {
+			if done++; done == 2 {
+				cancel()
+			}
+		},
+	})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if len(stats) != 2 {
+		t.Fatalf("got %d projects, want the 2 completed ones", len(stats))
+	}
+	for i, s := range stats {
+		if s.Project != projects[i] || s.TotalLines != i+1 {
+			t.Errorf("stats[%d] = %s with %d lines, want completed %s", i, s.Project.Name, s.TotalLines, projects[i].Name)
+		}
+	}
+}
+
+func TestCounter_ByteOrderMarks(t *testing.T) {
+	root := t.TempDir()
+
+	source := "// Generated\r\nnamespace App\r\n{\r\n\r\n    class Ünïcode {} // 😀\r\n}\r\n"
+
+	// UTF-16LE with a byte order mark, as Visual Studio writes it
+	utf16LE := []byte{0xFF, 0xFE}
+	for _, u := range utf16.Encode([]rune(source)) {
+		utf16LE = binary.LittleEndian.AppendUint16(utf16LE, u)
+	}
+	writeFile(t, root, "Utf16.cs", string(utf16LE))
+	writeFile(t, root, "Utf8Bom.cs", "\uFEFF"+source)
+	writeFile(t, root, "Plain.cs", source)
+
+	counter, err := NewCounter(root)
+	if err != nil {
+		t.Fatalf("NewCounter: %v", err)
+	}
+
+	for _, name := range []string{"Utf16.cs", "Utf8Bom.cs", "Plain.cs"} {
+		stats, err := counter.CountFile(filepath.Join(root, name))
+		if err != nil {
+			t.Fatalf("CountFile(%s): %v", name, err)
+		}
+		if stats.Lines != 6 || stats.CodeLines != 5 || stats.BlankLines != 1 {
+			t.Errorf("%s: Lines = %d, CodeLines = %d, BlankLines = %d; want 6, 5, 1",
+				name, stats.Lines, stats.CodeLines, stats.BlankLines)
+		}
+	}
+}
+
+func TestCounter_FollowSymlinks(t *testing.T) {
+	root := t.TempDir()
+	shared := t.TempDir()
+
+	writeFile(t, root, "main.go", "package main\n")
+	writeFile(t, shared, "lib.go", "package lib\n\nfunc Lib() {}\n")
+	if err := os.Symlink(shared, filepath.Join(root, "lib")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+	// A link back to the project root must not make the walk loop forever
+	if err := os.Symlink(root, filepath.Join(root, "loop")); err != nil {
+		t.Fatalf("symlink: %v", err)
+	}
+
+	count := func(follow bool) *models.ProjectStats {
+		t.Helper()
+		counter, err := NewCounterWithOptions(root, Options{FollowSymlinks: follow})
+		if err != nil {
+			t.Fatalf("NewCounterWithOptions: %v", err)
+		}
+
+		done := make(chan *models.ProjectStats)
+		go func() {
+			stats, err := counter.CountProject(goProject())
+			if err != nil {
+				t.Errorf("CountProject: %v", err)
+			}
+			done <- stats
+		}()
+		select {
+		case stats := <-done:
+			return stats
+		case <-time.After(10 * time.Second):
+			t.Fatalf("CountProject(FollowSymlinks: %v) did not finish", follow)
+			return nil
+		}
+	}
+
+	if stats := count(false); stats.TotalFiles != 1 {
+		t.Errorf("without FollowSymlinks TotalFiles = %d, want 1", stats.TotalFiles)
+	}
+
+	stats := count(true)
+	if stats.TotalFiles != 2 {
+		t.Errorf("with FollowSymlinks TotalFiles = %d, want 2", stats.TotalFiles)
+	}
+	if stats.TotalLines != 4 {
+		t.Errorf("with FollowSymlinks TotalLines = %d, want 4", stats.TotalLines)
+	}
+}
+
+func TestCounter_BinaryFiles(t *testing.T) {
+	root := t.TempDir()
+
+	writeFile(t, root, "main.go", "package main\n\nfunc main() {}\n")
+	// A binary blob that happens to carry a source extension
+	writeFile(t, root, "blob.go", "\x7fELF\x02\x01\x01\x00\x00\x00\nline\nline\n")
+	// UTF-16 text is full of NUL bytes but is not binary
+	utf16Text := []byte{0xFF, 0xFE}
+	for _, r := range "package util\n" {
+		utf16Text = binary.LittleEndian.AppendUint16(utf16Text, uint16(r))
+	}
+	writeFile(t, root, "util.go", string(utf16Text))
+
+	counter, err := NewCounter(root)
+	if err != nil {
+		t.Fatalf("NewCounter: %v", err)
+	}
+	stats, err := counter.CountProject(goProject())
+	if err != nil {
+		t.Fatalf("CountProject: %v", err)
+	}
+
+	if stats.BinaryFiles != 1 {
+		t.Errorf("BinaryFiles = %d, want 1", stats.BinaryFiles)
+	}
+	if stats.TotalFiles != 2 {
+		t.Errorf("TotalFiles = %d, want 2 (binary file not counted)", stats.TotalFiles)
+	}
+	if stats.TotalLines != 4 {
+		t.Errorf("TotalLines = %d, want 4", stats.TotalLines)
+	}
+	for _, f := range stats.AllFiles {
+		if filepath.Base(f.Path) == "blob.go" {
+			t.Errorf("binary file listed in AllFiles: %+v", f)
+		}
+	}
+}
+
+func TestCounter_MixedJavaKotlin(t *testing.T) {
+	root := t.TempDir()
+
+	writeFile(t, root, "src/main/java/App.java", "class App {}\n")
+	writeFile(t, root, "src/main/java/Util.java", "class Util {}\n")
+	writeFile(t, root, "src/main/kotlin/Extensions.kt", "fun String.shout() = uppercase()\n")
+	writeFile(t, root, "build.gradle.kts", "plugins {\n    kotlin(\"jvm\")\n}\n")
+
+	counter, err := NewCounter(root)
+	if err != nil {
+		t.Fatalf("NewCounter: %v", err)
+	}
+
+	count := func(runtime models.RuntimeType) *models.ProjectStats {
+		t.Helper()
+		stats, err := counter.CountProject(&models.Project{
+			Name:        "app",
+			Path:        ".",
+			Runtime:     models.Runtime{Type: runtime},
+			SourcePaths: []string{"src", "build.gradle.kts"},
+		})
+		if err != nil {
+			t.Fatalf("CountProject: %v", err)
+		}
+		return stats
+	}
+
+	// A mostly Java module that applies the Kotlin plugin stays Java and
+	// reports its Kotlin sources as a separate language
+	java := count(models.RuntimeJava)
+	want := []models.SubLanguageStats{
+		{Language: "Java", TotalFiles: 2, TotalLines: 2, CodeLines: 2},
+		{Language: "Kotlin", TotalFiles: 2, TotalLines: 4, CodeLines: 4},
+	}
+	if !reflect.DeepEqual(java.SubLanguages, want) {
+		t.Errorf("Java project SubLanguages = %+v, want %+v", java.SubLanguages, want)
+	}
+
+	// A Kotlin project counts only Kotlin sources
+	kotlin := count(models.RuntimeKotlin)
+	if kotlin.TotalFiles != 2 {
+		t.Errorf("Kotlin project TotalFiles = %d, want 2 (.kt and .kts)", kotlin.TotalFiles)
+	}
+	if len(kotlin.SubLanguages) != 0 {
+		t.Errorf("Kotlin project SubLanguages = %+v, want none", kotlin.SubLanguages)
+	}
+}
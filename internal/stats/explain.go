@@ -0,0 +1,111 @@
+package stats
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"repoctr/internal/ignore"
+	"repoctr/pkg/models"
+)
+
+// Explanation tells whether a file or directory is counted for a project
+// and, if not, why.
+type Explanation struct {
+	// IsDir reports whether the path is a directory.
+	IsDir bool
+	// InSourcePaths reports whether the path is inside one of the project's
+	// source paths.
+	InSourcePaths bool
+	// SourceExtension reports whether the file's extension is counted for
+	// the project's runtime, including extra extensions. It is always true
+	// for directories.
+	SourceExtension bool
+	// SrcIgnorePath is the project's src-ignore-paths entry that skips the
+	// path, or "".
+	SrcIgnorePath string
+	// IgnoredBy is the ignore rule that excludes the path, or nil.
+	IgnoredBy *ignore.Rule
+	// OverMaxFileSize reports whether the file is larger than the counter's
+	// MaxFileSize and so skipped.
+	OverMaxFileSize bool
+	// Binary reports whether the file looks binary and so is skipped. It is
+	// only checked for files within MaxFileSize.
+	Binary bool
+}
+
+// Counted reports whether the file is counted, or the directory walked.
+func (e *Explanation) Counted() bool {
+	return e.InSourcePaths && e.SourceExtension && e.SrcIgnorePath == "" && e.IgnoredBy == nil &&
+		!e.OverMaxFileSize && !e.Binary
+}
+
+// Explain checks path, a file or directory, against the rules CountProject
+// applies to project's files.
+func (c *Counter) Explain(project *models.Project, path string) (*Explanation, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(absPath)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &Explanation{IsDir: info.IsDir(), SourceExtension: true}
+
+	projectPath := filepath.Join(c.rootDir, project.Path)
+	for _, srcPath := range project.SourcePaths {
+		if isWithin(filepath.Join(projectPath, srcPath), absPath) {
+			e.InSourcePaths = true
+			break
+		}
+	}
+
+	if !e.IsDir {
+		ext := strings.ToLower(filepath.Ext(absPath))
+		extraExts := extensionSet(c.options.IncludeExtensions, project.ExtraExtensions)
+		e.SourceExtension = isSourceFile(absPath, project.Runtime.Type) || extraExts[ext] ||
+			(c.options.IncludeNotebooks && project.Runtime.Type == models.RuntimePython && isNotebook(absPath))
+	}
+
+	if relPath, err := filepath.Rel(projectPath, absPath); err == nil {
+		for _, ignorePath := range project.SrcIgnorePaths {
+			if (e.IsDir && relPath == ignorePath) || strings.HasPrefix(relPath, ignorePath+string(filepath.Separator)) {
+				e.SrcIgnorePath = ignorePath
+				break
+			}
+		}
+	}
+
+	if rule, ok := c.projectMatcher(project).Explain(absPath); ok {
+		e.IgnoredBy = &rule
+	}
+
+	if !e.IsDir {
+		if c.options.MaxFileSize > 0 && info.Size() > c.options.MaxFileSize {
+			e.OverMaxFileSize = true
+		} else if e.Binary, err = isBinaryFile(absPath); err != nil {
+			return nil, err
+		}
+	}
+
+	return e, nil
+}
+
+// isBinaryFile reports whether the file at path looks binary, as countFile
+// decides it.
+func isBinaryFile(path string) (bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+	return isBinary(file)
+}
+
+// isWithin reports whether path is dir or inside it.
+func isWithin(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
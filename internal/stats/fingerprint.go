@@ -0,0 +1,112 @@
+package stats
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"repoctr/internal/hashing"
+	"repoctr/pkg/models"
+)
+
+// Fingerprint computes a reproducible digest over the files counted in stats.
+// Each file contributes its path (relative to the counter root, with forward
+// slashes) and a hash of its content (SHA-256 unless Options.HashAlgorithm
+// selects another algorithm), so the digest only changes when
+// counted code is added, removed, renamed, or edited. The overall digest
+// covers each file once even when nested projects overlap. Files deleted
+// since they were counted are left out.
+func (c *Counter) Fingerprint(stats []*models.ProjectStats) (*models.Fingerprint, error) {
+	fileHashes := make(map[string]string)
+
+	projects, err := c.fingerprintProjects(stats, fileHashes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.Fingerprint{
+		Digest:   digestFiles(c.options.HashAlgorithm, fileHashes),
+		Files:    len(fileHashes),
+		Projects: projects,
+	}, nil
+}
+
+func (c *Counter) fingerprintProjects(stats []*models.ProjectStats, fileHashes map[string]string) ([]*models.ProjectFingerprint, error) {
+	var result []*models.ProjectFingerprint
+
+	for _, s := range stats {
+		projectHashes := make(map[string]string, len(s.AllFiles))
+
+		for _, f := range s.AllFiles {
+			relPath, err := filepath.Rel(c.rootDir, f.Path)
+			if err != nil {
+				relPath = f.Path
+			}
+			relPath = filepath.ToSlash(relPath)
+
+			hash, ok := fileHashes[relPath]
+			if !ok {
+				hash, err = hashFile(c.options.HashAlgorithm, f.Path)
+				if errors.Is(err, fs.ErrNotExist) {
+					// Deleted since it was counted, e.g. by an atomic save
+					continue
+				}
+				if err != nil {
+					return nil, fmt.Errorf("failed to hash %s: %w", relPath, err)
+				}
+				fileHashes[relPath] = hash
+			}
+			projectHashes[relPath] = hash
+		}
+
+		children, err := c.fingerprintProjects(s.Children, fileHashes)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, &models.ProjectFingerprint{
+			Project:  s.Project,
+			Digest:   digestFiles(c.options.HashAlgorithm, projectHashes),
+			Files:    len(projectHashes),
+			Children: children,
+		})
+	}
+
+	return result, nil
+}
+
+// hashFile returns the hex-encoded hash of a file's content.
+func hashFile(algo hashing.Algorithm, path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := hashing.New(algo)
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// digestFiles combines path/content-hash pairs into a single digest.
+// Pairs are sorted by path so enumeration order does not matter.
+func digestFiles(algo hashing.Algorithm, fileHashes map[string]string) string {
+	paths := make([]string, 0, len(fileHashes))
+	for p := range fileHashes {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	hash := hashing.New(algo)
+	for _, p := range paths {
+		fmt.Fprintf(hash, "%s\x00%s\n", p, fileHashes[p])
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
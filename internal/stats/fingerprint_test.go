@@ -0,0 +1,180 @@
+package stats
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"repoctr/internal/hashing"
+	"repoctr/pkg/models"
+)
+
+func fingerprint(t *testing.T, root string, project *models.Project) *models.Fingerprint {
+	t.Helper()
+
+	counter, err := NewCounter(root)
+	if err != nil {
+		t.Fatalf("NewCounter: %v", err)
+	}
+
+	stats, err := counter.CountHierarchy(context.Background(), []*models.Project{project}, HierarchyOptions{})
+	if err != nil {
+		t.Fatalf("CountHierarchy: %v", err)
+	}
+
+	fp, err := counter.Fingerprint(stats)
+	if err != nil {
+		t.Fatalf("Fingerprint: %v", err)
+	}
+	return fp
+}
+
+func TestCounter_Fingerprint(t *testing.T) {
+	root := t.TempDir()
+
+	writeFile(t, root, "main.go", "package main\n")
+	writeFile(t, root, "lib/lib.go", "package lib\n")
+	writeFile(t, root, "README.md", "not counted\n")
+
+	first := fingerprint(t, root, goProject())
+	second := fingerprint(t, root, goProject())
+
+	if first.Digest == "" {
+		t.Fatal("expected non-empty digest")
+	}
+	if first.Digest != second.Digest {
+		t.Errorf("digest not stable across runs: %s != %s", first.Digest, second.Digest)
+	}
+	if first.Files != 2 {
+		t.Errorf("Files = %d, want 2", first.Files)
+	}
+
+	// Non-counted files do not affect the digest
+	writeFile(t, root, "README.md", "still not counted\n")
+	if fp := fingerprint(t, root, goProject()); fp.Digest != first.Digest {
+		t.Error("digest changed after editing a non-counted file")
+	}
+
+	// Editing counted code changes the digest
+	writeFile(t, root, "lib/lib.go", "package lib\n\nfunc F() {}\n")
+	edited := fingerprint(t, root, goProject())
+	if edited.Digest == first.Digest {
+		t.Error("digest did not change after editing a counted file")
+	}
+
+	// Renaming counted code changes the digest
+	writeFile(t, root, "lib/lib.go", "package lib\n")
+	if err := os.Rename(filepath.Join(root, "lib", "lib.go"), filepath.Join(root, "lib", "renamed.go")); err != nil {
+		t.Fatalf("rename: %v", err)
+	}
+	renamed := fingerprint(t, root, goProject())
+	if renamed.Digest == first.Digest {
+		t.Error("digest did not change after renaming a counted file")
+	}
+	if renamed.Files != 2 {
+		t.Errorf("Files after rename = %d, want 2", renamed.Files)
+	}
+}
+
+func TestCounter_FingerprintPerProject(t *testing.T) {
+	root := t.TempDir()
+
+	writeFile(t, root, "main.go", "package main\n")
+	writeFile(t, root, "svc/svc.go", "package svc\n")
+
+	project := goProject()
+	project.Children = []*models.Project{{
+		Name:        "svc",
+		Path:        "svc",
+		Runtime:     models.Runtime{Type: models.RuntimeGo},
+		SourcePaths: []string{"."},
+	}}
+
+	fp := fingerprint(t, root, project)
+
+	// The root project also walks svc/, but the repo digest counts each file once
+	if fp.Files != 2 {
+		t.Errorf("Files = %d, want 2", fp.Files)
+	}
+	if len(fp.Projects) != 1 || len(fp.Projects[0].Children) != 1 {
+		t.Fatalf("expected root project with one child fingerprint")
+	}
+
+	child := fp.Projects[0].Children[0]
+	if child.Files != 1 {
+		t.Errorf("child Files = %d, want 1", child.Files)
+	}
+	if child.Digest == fp.Projects[0].Digest {
+		t.Error("expected child digest to differ from parent digest")
+	}
+}
+
+func TestCounter_FingerprintHashAlgorithms(t *testing.T) {
+	root := t.TempDir()
+
+	writeFile(t, root, "main.go", "package main\n")
+	writeFile(t, root, "lib/lib.go", "package lib\n")
+
+	fingerprintWith := func(algo hashing.Algorithm) string {
+		counter, err := NewCounterWithOptions(root, Options{HashAlgorithm: algo})
+		if err != nil {
+			t.Fatalf("NewCounterWithOptions: %v", err)
+		}
+		stats, err := counter.CountHierarchy(context.Background(), []*models.Project{goProject()}, HierarchyOptions{})
+		if err != nil {
+			t.Fatalf("CountHierarchy: %v", err)
+		}
+		fp, err := counter.Fingerprint(stats)
+		if err != nil {
+			t.Fatalf("Fingerprint: %v", err)
+		}
+		return fp.Digest
+	}
+
+	// The zero value keeps the historical SHA-256 digests
+	if got, want := fingerprintWith(""), fingerprintWith(hashing.SHA256); got != want {
+		t.Errorf("default digest %s, want sha256 digest %s", got, want)
+	}
+
+	digests := make(map[string]hashing.Algorithm)
+	for _, algo := range hashing.Algorithms {
+		digest := fingerprintWith(algo)
+		if again := fingerprintWith(algo); again != digest {
+			t.Errorf("%s: digest not stable across runs: %s != %s", algo, again, digest)
+		}
+		if other, ok := digests[digest]; ok {
+			t.Errorf("%s and %s produced the same digest", algo, other)
+		}
+		digests[digest] = algo
+	}
+}
+
+func TestCounter_FingerprintSkipsVanishedFiles(t *testing.T) {
+	root := t.TempDir()
+
+	writeFile(t, root, "main.go", "package main\n")
+	writeFile(t, root, "tmp.go", "package main\n")
+
+	counter, err := NewCounter(root)
+	if err != nil {
+		t.Fatalf("NewCounter: %v", err)
+	}
+	stats, err := counter.CountHierarchy(context.Background(), []*models.Project{goProject()}, HierarchyOptions{})
+	if err != nil {
+		t.Fatalf("CountHierarchy: %v", err)
+	}
+
+	// Deleted between counting and hashing, as by an editor's atomic save
+	if err := os.Remove(filepath.Join(root, "tmp.go")); err != nil {
+		t.Fatal(err)
+	}
+
+	fp, err := counter.Fingerprint(stats)
+	if err != nil {
+		t.Fatalf("Fingerprint: %v, want the vanished file skipped", err)
+	}
+	if fp.Files != 1 {
+		t.Errorf("Files = %d, want 1", fp.Files)
+	}
+}
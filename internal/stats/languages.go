@@ -0,0 +1,50 @@
+package stats
+
+import (
+	"sort"
+
+	"repoctr/pkg/models"
+)
+
+// AggregateByLanguage sums statistics per runtime type across the whole
+// project hierarchy. Results are ordered by code lines (descending), then
+// by runtime name.
+func AggregateByLanguage(stats []*models.ProjectStats) []*models.LanguageStats {
+	byRuntime := make(map[models.RuntimeType]*models.LanguageStats)
+
+	var aggregate func([]*models.ProjectStats)
+	aggregate = func(list []*models.ProjectStats) {
+		for _, s := range list {
+			rt := s.Project.Runtime.Type
+			lang, ok := byRuntime[rt]
+			if !ok {
+				lang = &models.LanguageStats{Runtime: rt}
+				byRuntime[rt] = lang
+			}
+
+			lang.Projects++
+			lang.TotalFiles += s.TotalFiles
+			lang.TotalLines += s.TotalLines
+			lang.BlankLines += s.BlankLines
+			lang.CodeLines += s.CodeLines
+			lang.TotalSize += s.TotalSize
+			aggregate(s.Children)
+		}
+	}
+
+	aggregate(stats)
+
+	result := make([]*models.LanguageStats, 0, len(byRuntime))
+	for _, lang := range byRuntime {
+		result = append(result, lang)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].CodeLines != result[j].CodeLines {
+			return result[i].CodeLines > result[j].CodeLines
+		}
+		return result[i].Runtime < result[j].Runtime
+	})
+
+	return result
+}
@@ -0,0 +1,53 @@
+package stats
+
+import (
+	"testing"
+
+	"repoctr/pkg/models"
+)
+
+func TestAggregateByLanguage(t *testing.T) {
+	goRuntime := models.Runtime{Type: models.RuntimeGo}
+	rustRuntime := models.Runtime{Type: models.RuntimeRust}
+
+	stats := []*models.ProjectStats{
+		{
+			Project:    &models.Project{Name: "api", Runtime: goRuntime},
+			TotalFiles: 10, TotalLines: 1000, CodeLines: 800, BlankLines: 200, TotalSize: 4000,
+			Children: []*models.ProjectStats{
+				{
+					Project:    &models.Project{Name: "engine", Runtime: rustRuntime},
+					TotalFiles: 4, TotalLines: 500, CodeLines: 450, BlankLines: 50, TotalSize: 2000,
+				},
+				{
+					Project:    &models.Project{Name: "worker", Runtime: goRuntime},
+					TotalFiles: 3, TotalLines: 300, CodeLines: 250, BlankLines: 50, TotalSize: 1200,
+				},
+			},
+		},
+		{
+			Project:    &models.Project{Name: "cli", Runtime: rustRuntime},
+			TotalFiles: 2, TotalLines: 100, CodeLines: 90, BlankLines: 10, TotalSize: 500,
+		},
+	}
+
+	langs := AggregateByLanguage(stats)
+	if len(langs) != 2 {
+		t.Fatalf("expected 2 languages, got %d", len(langs))
+	}
+
+	goStats, rustStats := langs[0], langs[1]
+	if goStats.Runtime != models.RuntimeGo {
+		t.Fatalf("expected Go first (most code lines), got %q", goStats.Runtime)
+	}
+
+	if goStats.Projects != 2 || goStats.TotalFiles != 13 || goStats.TotalLines != 1300 ||
+		goStats.CodeLines != 1050 || goStats.BlankLines != 250 || goStats.TotalSize != 5200 {
+		t.Errorf("unexpected Go totals: %+v", *goStats)
+	}
+
+	if rustStats.Projects != 2 || rustStats.TotalFiles != 6 || rustStats.TotalLines != 600 ||
+		rustStats.CodeLines != 540 || rustStats.BlankLines != 60 || rustStats.TotalSize != 2500 {
+		t.Errorf("unexpected Rust totals: %+v", *rustStats)
+	}
+}
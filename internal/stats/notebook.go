@@ -0,0 +1,75 @@
+package stats
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"repoctr/pkg/models"
+)
+
+// notebook holds the parts of a Jupyter notebook (nbformat 4) used for
+// counting.
+type notebook struct {
+	Cells []struct {
+		CellType string `json:"cell_type"`
+		// Source is a string or a list of lines, each ending in "\n".
+		Source any `json:"source"`
+	} `json:"cells"`
+}
+
+// isNotebook reports whether path is a Jupyter notebook.
+func isNotebook(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".ipynb")
+}
+
+// countNotebook counts the source lines of the code cells of the notebook
+// read from r. Lines are the code-cell lines rather than the lines of the
+// JSON file; non-blank ones go to NotebookLines. Markdown and raw cells and
+// outputs are ignored.
+func countNotebook(r io.Reader, stats *models.FileStats) error {
+	var nb notebook
+	if err := json.NewDecoder(r).Decode(&nb); err != nil {
+		return fmt.Errorf("invalid notebook: %w", err)
+	}
+
+	for _, cell := range nb.Cells {
+		if cell.CellType != "code" {
+			continue
+		}
+		for _, line := range cellLines(cell.Source) {
+			stats.Lines++
+			if strings.TrimSpace(line) == "" {
+				stats.BlankLines++
+			} else {
+				stats.NotebookLines++
+			}
+		}
+	}
+	return nil
+}
+
+// cellLines splits the source of a notebook cell into lines.
+func cellLines(source any) []string {
+	var text string
+	switch s := source.(type) {
+	case string:
+		text = s
+	case []any:
+		var b strings.Builder
+		for _, part := range s {
+			if line, ok := part.(string); ok {
+				b.WriteString(line)
+			}
+		}
+		text = b.String()
+	}
+
+	text = strings.TrimSuffix(text, "\n")
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
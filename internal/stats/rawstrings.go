@@ -0,0 +1,127 @@
+package stats
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// stringSyntax describes the literals of a language well enough to follow
+// multi-line strings line by line. It is a heuristic, not a parser: block
+// comments and nested interpolation are not tracked.
+type stringSyntax struct {
+	// lineComment starts a comment running to the end of the line.
+	lineComment string
+	// blockDelims open and close string literals that may span lines.
+	blockDelims []string
+	// quotes delimit single-line strings, whose contents are skipped.
+	quotes string
+}
+
+var (
+	goStringSyntax = &stringSyntax{
+		lineComment: "//",
+		blockDelims: []string{"`"},
+		quotes:      `"'`,
+	}
+	pythonStringSyntax = &stringSyntax{
+		lineComment: "#",
+		blockDelims: []string{`"""`, `'''`},
+		quotes:      `"'`,
+	}
+)
+
+// stringSyntaxByExtension maps source extensions to the syntax used to find
+// their multi-line string literals.
+var stringSyntaxByExtension = map[string]*stringSyntax{
+	".go":  goStringSyntax,
+	".py":  pythonStringSyntax,
+	".pyw": pythonStringSyntax,
+	".pyi": pythonStringSyntax,
+}
+
+// stringBlockScanner follows a file line by line and reports the lines that
+// lie entirely inside a multi-line string literal, such as embedded JSON in
+// a Go raw string. The lines opening and closing the literal are code.
+type stringBlockScanner struct {
+	syntax *stringSyntax
+	// open is the delimiter of the string literal the scanner is in, or ""
+	// outside of one.
+	open string
+}
+
+// newStringBlockScanner returns a scanner for the file at path, or nil if
+// its language has no supported multi-line strings.
+func newStringBlockScanner(path string) *stringBlockScanner {
+	syntax, ok := stringSyntaxByExtension[strings.ToLower(filepath.Ext(path))]
+	if !ok {
+		return nil
+	}
+	return &stringBlockScanner{syntax: syntax}
+}
+
+// interior consumes the next line and reports whether it lies entirely
+// inside a multi-line string literal.
+func (s *stringBlockScanner) interior(line string) bool {
+	if s.open != "" && !strings.Contains(line, s.open) {
+		return true
+	}
+	s.scan(line)
+	return false
+}
+
+// scan updates the scanner state with the literals found in line.
+func (s *stringBlockScanner) scan(line string) {
+	for i := 0; i < len(line); {
+		if s.open != "" {
+			end := strings.Index(line[i:], s.open)
+			if end < 0 {
+				return
+			}
+			i += end + len(s.open)
+			s.open = ""
+			continue
+		}
+
+		rest := line[i:]
+		if strings.HasPrefix(rest, s.syntax.lineComment) {
+			return
+		}
+		if delim := s.blockDelimAt(rest); delim != "" {
+			s.open = delim
+			i += len(delim)
+			continue
+		}
+		if strings.IndexByte(s.syntax.quotes, line[i]) >= 0 {
+			i = skipQuoted(line, i)
+			continue
+		}
+		i++
+	}
+}
+
+// blockDelimAt returns the multi-line string delimiter that rest starts
+// with, or "".
+func (s *stringBlockScanner) blockDelimAt(rest string) string {
+	for _, delim := range s.syntax.blockDelims {
+		if strings.HasPrefix(rest, delim) {
+			return delim
+		}
+	}
+	return ""
+}
+
+// skipQuoted returns the index just past the single-line string or rune
+// literal starting at line[start], honoring backslash escapes. An
+// unterminated literal runs to the end of the line.
+func skipQuoted(line string, start int) int {
+	quote := line[start]
+	for i := start + 1; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case quote:
+			return i + 1
+		}
+	}
+	return len(line)
+}
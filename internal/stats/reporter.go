@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"repoctr/internal/emoji"
@@ -12,7 +13,9 @@ import (
 
 // Reporter formats and outputs project statistics.
 type Reporter struct {
-	writer io.Writer
+	writer         io.Writer
+	normalizePaths bool
+	groupThreshold int
 }
 
 // NewReporter creates a new stats reporter.
@@ -20,6 +23,27 @@ func NewReporter(w io.Writer) *Reporter {
 	return &Reporter{writer: w}
 }
 
+// SetNormalizePaths makes the reporter print paths with forward slashes on
+// every platform.
+func (r *Reporter) SetNormalizePaths(normalize bool) {
+	r.normalizePaths = normalize
+}
+
+// SetGroupThreshold makes the reporter collapse sibling projects with fewer
+// than threshold code lines into a single aggregate entry. Projects with
+// children are always shown. Zero disables grouping.
+func (r *Reporter) SetGroupThreshold(threshold int) {
+	r.groupThreshold = threshold
+}
+
+// displayPath returns path as it should be printed.
+func (r *Reporter) displayPath(path string) string {
+	if r.normalizePaths {
+		return NormalizePath(path)
+	}
+	return path
+}
+
 // Report outputs statistics for a list of project stats.
 func (r *Reporter) Report(stats []*models.ProjectStats) {
 	r.ReportWithOptions(stats, false)
@@ -27,7 +51,7 @@ func (r *Reporter) Report(stats []*models.ProjectStats) {
 
 // ReportWithOptions outputs statistics for a list of project stats with options.
 func (r *Reporter) ReportWithOptions(stats []*models.ProjectStats, allFiles bool) {
-	for _, s := range stats {
+	for _, s := range groupSmallProjects(stats, r.groupThreshold) {
 		r.reportProjectWithOptions(s, 0, allFiles)
 	}
 
@@ -39,10 +63,50 @@ func (r *Reporter) ReportWithOptions(stats []*models.ProjectStats, allFiles bool
 		r.printSeparator()
 		fmt.Fprintf(r.writer, "   Files:      %d\n", totals.TotalFiles)
 		fmt.Fprintf(r.writer, "   Folders:    %d\n", totals.TotalFolders)
+		if totals.TestFolders > 0 {
+			fmt.Fprintf(r.writer, "   Test Dirs:  %d\n", totals.TestFolders)
+		}
 		fmt.Fprintf(r.writer, "   Lines:      %d\n", totals.TotalLines)
 		fmt.Fprintf(r.writer, "   Code:       %d\n", totals.CodeLines)
 		fmt.Fprintf(r.writer, "   Blank:      %d\n", totals.BlankLines)
-		fmt.Fprintf(r.writer, "   Size:       %s\n", formatSize(totals.TotalSize))
+		if totals.StructuralLines > 0 {
+			fmt.Fprintf(r.writer, "   Structural: %d\n", totals.StructuralLines)
+		}
+		if totals.DataLines > 0 {
+			fmt.Fprintf(r.writer, "   Data:       %d\n", totals.DataLines)
+		}
+		if totals.NotebookLines > 0 {
+			fmt.Fprintf(r.writer, "   Notebooks:  %d\n", totals.NotebookLines)
+		}
+		if totals.LongLines > 0 {
+			fmt.Fprintf(r.writer, "   Long Lines: %d\n", totals.LongLines)
+		}
+		if totals.BlankRuns > 0 {
+			fmt.Fprintf(r.writer, "   Blank Runs: %d\n", totals.BlankRuns)
+		}
+		fmt.Fprintf(r.writer, "   Size:       %s\n", FormatSize(totals.TotalSize))
+		if totals.GeneratedFiles > 0 {
+			fmt.Fprintf(r.writer, "   Generated:  %d files, %d lines\n", totals.GeneratedFiles, totals.GeneratedLines)
+		}
+		if len(totals.SkippedFiles) > 0 {
+			fmt.Fprintf(r.writer, "   Skipped:    %d files over the size limit\n", len(totals.SkippedFiles))
+		}
+		if totals.BinaryFiles > 0 {
+			fmt.Fprintf(r.writer, "   Binary:     %d files not counted\n", totals.BinaryFiles)
+		}
+
+		r.ReportLanguages(AggregateByLanguage(stats))
+	}
+}
+
+// ReportLanguages prints totals grouped by runtime type.
+func (r *Reporter) ReportLanguages(langs []*models.LanguageStats) {
+	r.printSeparator()
+	fmt.Fprintf(r.writer, "\n🌐 BY LANGUAGE\n")
+	r.printSeparator()
+	for _, l := range langs {
+		fmt.Fprintf(r.writer, "   %s %-12s %6d files %9d lines %9d code %10s\n",
+			emoji.Map(l.Runtime), l.Runtime, l.TotalFiles, l.TotalLines, l.CodeLines, FormatSize(l.TotalSize))
 	}
 }
 
@@ -53,21 +117,64 @@ func (r *Reporter) reportProjectWithOptions(stats *models.ProjectStats, depth in
 	// Project header
 	r.printSeparator()
 	techEmoji := emoji.Map(project.Runtime.Type)
-	fmt.Fprintf(r.writer, "\n%s📁 %s %s (%s", indent, project.Name, techEmoji, project.Runtime.Type)
-	if project.Runtime.Version != "" {
-		fmt.Fprintf(r.writer, " %s", project.Runtime.Version)
+	fmt.Fprintf(r.writer, "\n%s📁 %s %s", indent, project.Name, techEmoji)
+	if project.Runtime.Type != "" {
+		fmt.Fprintf(r.writer, " (%s", project.Runtime.Type)
+		if project.Runtime.Version != "" {
+			fmt.Fprintf(r.writer, " %s", project.Runtime.Version)
+		}
+		if project.PackageManager != "" {
+			fmt.Fprintf(r.writer, ", %s", project.PackageManager)
+		}
+		fmt.Fprintf(r.writer, ")")
+	}
+	fmt.Fprintf(r.writer, "\n")
+	if project.Path != "" {
+		fmt.Fprintf(r.writer, "%s   Path: %s\n", indent, r.displayPath(project.Path))
 	}
-	fmt.Fprintf(r.writer, ")\n")
-	fmt.Fprintf(r.writer, "%s   Path: %s\n", indent, project.Path)
 	r.printSeparator()
 
 	// Statistics table
 	fmt.Fprintf(r.writer, "%s   %-12s %s\n", indent, "Files:", fmt.Sprintf("%d", stats.TotalFiles))
 	fmt.Fprintf(r.writer, "%s   %-12s %s\n", indent, "Folders:", fmt.Sprintf("%d", stats.TotalFolders))
+	if stats.TestFolders > 0 {
+		fmt.Fprintf(r.writer, "%s   %-12s %s\n", indent, "Test Dirs:", fmt.Sprintf("%d", stats.TestFolders))
+	}
 	fmt.Fprintf(r.writer, "%s   %-12s %s\n", indent, "Total Lines:", fmt.Sprintf("%d", stats.TotalLines))
 	fmt.Fprintf(r.writer, "%s   %-12s %s\n", indent, "Code Lines:", fmt.Sprintf("%d", stats.CodeLines))
 	fmt.Fprintf(r.writer, "%s   %-12s %s\n", indent, "Blank Lines:", fmt.Sprintf("%d", stats.BlankLines))
-	fmt.Fprintf(r.writer, "%s   %-12s %s\n", indent, "Total Size:", formatSize(stats.TotalSize))
+	if stats.StructuralLines > 0 {
+		fmt.Fprintf(r.writer, "%s   %-12s %s\n", indent, "Structural:", fmt.Sprintf("%d", stats.StructuralLines))
+	}
+	if stats.DataLines > 0 {
+		fmt.Fprintf(r.writer, "%s   %-12s %s\n", indent, "Data Lines:", fmt.Sprintf("%d", stats.DataLines))
+	}
+	if stats.NotebookLines > 0 {
+		fmt.Fprintf(r.writer, "%s   %-12s %s\n", indent, "Notebooks:", fmt.Sprintf("%d", stats.NotebookLines))
+	}
+	if stats.LongLines > 0 {
+		fmt.Fprintf(r.writer, "%s   %-12s %s\n", indent, "Long Lines:", fmt.Sprintf("%d", stats.LongLines))
+	}
+	if stats.BlankRuns > 0 {
+		fmt.Fprintf(r.writer, "%s   %-12s %s\n", indent, "Blank Runs:", fmt.Sprintf("%d", stats.BlankRuns))
+	}
+	fmt.Fprintf(r.writer, "%s   %-12s %s\n", indent, "Total Size:", FormatSize(stats.TotalSize))
+	if stats.GeneratedFiles > 0 {
+		fmt.Fprintf(r.writer, "%s   %-12s %d files, %d lines\n", indent, "Generated:", stats.GeneratedFiles, stats.GeneratedLines)
+	}
+	if len(stats.SkippedFiles) > 0 {
+		fmt.Fprintf(r.writer, "%s   %-12s %d files over the size limit\n", indent, "Skipped:", len(stats.SkippedFiles))
+	}
+	if stats.BinaryFiles > 0 {
+		fmt.Fprintf(r.writer, "%s   %-12s %d files not counted\n", indent, "Binary:", stats.BinaryFiles)
+	}
+	if len(stats.SubLanguages) > 1 {
+		parts := make([]string, 0, len(stats.SubLanguages))
+		for _, l := range stats.SubLanguages {
+			parts = append(parts, fmt.Sprintf("%s: %d", l.Language, l.CodeLines))
+		}
+		fmt.Fprintf(r.writer, "%s   %-12s %s (code lines)\n", indent, "Languages:", strings.Join(parts, ", "))
+	}
 
 	// Files listing
 	var filesToShow []models.FileStats
@@ -87,17 +194,112 @@ func (r *Reporter) reportProjectWithOptions(stats *models.ProjectStats, depth in
 			if relPath == "" {
 				relPath = filepath.Base(f.Path)
 			}
-			fmt.Fprintf(r.writer, "%s     %d. %s (%d lines)\n", indent, i+1, relPath, f.Lines)
+			fmt.Fprintf(r.writer, "%s     %d. %s (%d lines)\n", indent, i+1, r.displayPath(relPath), f.Lines)
 		}
 	}
 
 	// Report children
-	for _, child := range stats.Children {
+	for _, child := range groupSmallProjects(stats.Children, r.groupThreshold) {
 		fmt.Fprintln(r.writer)
 		r.reportProjectWithOptions(child, depth+1, allFiles)
 	}
 }
 
+// ReportLongLines lists the files with the most lines longer than
+// maxLength across the hierarchy, at most limit files. Paths are shown
+// relative to rootDir.
+func (r *Reporter) ReportLongLines(stats []*models.ProjectStats, rootDir string, maxLength, limit int) {
+	type longLineFile struct {
+		path      string
+		longLines int
+	}
+
+	var files []longLineFile
+	var collect func([]*models.ProjectStats)
+	collect = func(list []*models.ProjectStats) {
+		for _, s := range list {
+			for _, f := range s.AllFiles {
+				if f.LongLines > 0 {
+					files = append(files, longLineFile{path: f.Path, longLines: f.LongLines})
+				}
+			}
+			collect(s.Children)
+		}
+	}
+	collect(stats)
+
+	sort.Slice(files, func(i, j int) bool {
+		if files[i].longLines != files[j].longLines {
+			return files[i].longLines > files[j].longLines
+		}
+		return files[i].path < files[j].path
+	})
+	if len(files) > limit {
+		files = files[:limit]
+	}
+
+	r.printSeparator()
+	fmt.Fprintf(r.writer, "\n📏 LONG LINES (> %d characters)\n", maxLength)
+	r.printSeparator()
+	if len(files) == 0 {
+		fmt.Fprintf(r.writer, "   No lines longer than %d characters\n", maxLength)
+		return
+	}
+	for i, f := range files {
+		path := f.path
+		if relPath, err := filepath.Rel(rootDir, f.path); err == nil {
+			path = relPath
+		}
+		fmt.Fprintf(r.writer, "   %d. %s (%d long)\n", i+1, r.displayPath(path), f.longLines)
+	}
+}
+
+// groupSmallProjects returns stats with the projects that have fewer than
+// threshold code lines and no children replaced by one aggregate project,
+// named "(N small projects)", that holds their combined totals. The
+// aggregate comes after the remaining projects. Nothing is grouped unless
+// at least two projects are small.
+func groupSmallProjects(stats []*models.ProjectStats, threshold int) []*models.ProjectStats {
+	if threshold <= 0 {
+		return stats
+	}
+
+	var large, small []*models.ProjectStats
+	for _, s := range stats {
+		if s.CodeLines < threshold && len(s.Children) == 0 {
+			small = append(small, s)
+		} else {
+			large = append(large, s)
+		}
+	}
+	if len(small) < 2 {
+		return stats
+	}
+
+	aggregate := &models.ProjectStats{
+		Project: &models.Project{Name: fmt.Sprintf("(%d small projects)", len(small))},
+	}
+	for _, s := range small {
+		aggregate.TotalFiles += s.TotalFiles
+		aggregate.TotalFolders += s.TotalFolders
+		aggregate.TestFolders += s.TestFolders
+		aggregate.TotalLines += s.TotalLines
+		aggregate.CodeLines += s.CodeLines
+		aggregate.BlankLines += s.BlankLines
+		aggregate.StructuralLines += s.StructuralLines
+		aggregate.DataLines += s.DataLines
+		aggregate.NotebookLines += s.NotebookLines
+		aggregate.LongLines += s.LongLines
+		aggregate.BlankRuns += s.BlankRuns
+		aggregate.TotalSize += s.TotalSize
+		aggregate.GeneratedFiles += s.GeneratedFiles
+		aggregate.GeneratedLines += s.GeneratedLines
+		aggregate.SkippedFiles = append(aggregate.SkippedFiles, s.SkippedFiles...)
+		aggregate.BinaryFiles += s.BinaryFiles
+	}
+	return append(large, aggregate)
+}
+
 func (r *Reporter) printSeparator() {
 	fmt.Fprintf(r.writer, "%s\n", strings.Repeat("─", 60))
 }
@@ -110,10 +312,20 @@ func (r *Reporter) calculateTotals(stats []*models.ProjectStats) *models.Project
 		for _, s := range list {
 			totals.TotalFiles += s.TotalFiles
 			totals.TotalFolders += s.TotalFolders
+			totals.TestFolders += s.TestFolders
 			totals.TotalLines += s.TotalLines
 			totals.BlankLines += s.BlankLines
 			totals.CodeLines += s.CodeLines
+			totals.StructuralLines += s.StructuralLines
+			totals.DataLines += s.DataLines
+			totals.NotebookLines += s.NotebookLines
+			totals.LongLines += s.LongLines
+			totals.BlankRuns += s.BlankRuns
 			totals.TotalSize += s.TotalSize
+			totals.GeneratedFiles += s.GeneratedFiles
+			totals.GeneratedLines += s.GeneratedLines
+			totals.SkippedFiles = append(totals.SkippedFiles, s.SkippedFiles...)
+			totals.BinaryFiles += s.BinaryFiles
 			aggregate(s.Children)
 		}
 	}
@@ -122,8 +334,15 @@ func (r *Reporter) calculateTotals(stats []*models.ProjectStats) *models.Project
 	return totals
 }
 
-// formatSize formats bytes into human-readable format.
-func formatSize(bytes int64) string {
+// NormalizePath returns path with forward slashes. Backslashes are treated
+// as separators on every platform, so paths produced on Windows print the
+// same as on Unix.
+func NormalizePath(path string) string {
+	return strings.ReplaceAll(path, `\`, "/")
+}
+
+// FormatSize formats bytes into human-readable format.
+func FormatSize(bytes int64) string {
 	const unit = 1024
 	if bytes < unit {
 		return fmt.Sprintf("%d B", bytes)
@@ -0,0 +1,74 @@
+package stats
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"repoctr/pkg/models"
+)
+
+func TestGroupSmallProjects(t *testing.T) {
+	project := func(name string, files, code, blank int, size int64) *models.ProjectStats {
+		return &models.ProjectStats{
+			Project:    &models.Project{Name: name, Path: "packages/" + name},
+			TotalFiles: files,
+			CodeLines:  code,
+			BlankLines: blank,
+			TotalLines: code + blank,
+			TotalSize:  size,
+		}
+	}
+	stats := []*models.ProjectStats{
+		project("tiny-a", 2, 40, 5, 1000),
+		project("core", 80, 12000, 900, 400000),
+		project("tiny-b", 1, 10, 2, 300),
+		project("tiny-c", 3, 99, 11, 2500),
+	}
+
+	grouped := groupSmallProjects(stats, 100)
+	if len(grouped) != 2 {
+		t.Fatalf("got %d entries, want core plus one aggregate", len(grouped))
+	}
+	if grouped[0].Project.Name != "core" {
+		t.Errorf("first entry = %q, want core", grouped[0].Project.Name)
+	}
+
+	aggregate := grouped[1]
+	if aggregate.Project.Name != "(3 small projects)" {
+		t.Errorf("aggregate name = %q, want %q", aggregate.Project.Name, "(3 small projects)")
+	}
+	if aggregate.TotalFiles != 6 || aggregate.CodeLines != 149 || aggregate.BlankLines != 18 ||
+		aggregate.TotalLines != 167 || aggregate.TotalSize != 3800 {
+		t.Errorf("aggregate = %+v, want 6 files, 149 code, 18 blank, 167 lines, 3800 bytes", aggregate)
+	}
+
+	// A single small project is not worth an aggregate
+	if got := groupSmallProjects(stats, 20); len(got) != len(stats) {
+		t.Errorf("got %d entries with one small project, want %d", len(got), len(stats))
+	}
+}
+
+func TestReporter_GroupThresholdKeepsGrandTotals(t *testing.T) {
+	stats := []*models.ProjectStats{
+		{Project: &models.Project{Name: "app", Path: "app", Runtime: models.Runtime{Type: models.RuntimeGo}}, TotalFiles: 10, CodeLines: 5000, TotalLines: 5000},
+		{Project: &models.Project{Name: "a", Path: "a", Runtime: models.Runtime{Type: models.RuntimeGo}}, TotalFiles: 1, CodeLines: 20, TotalLines: 20},
+		{Project: &models.Project{Name: "b", Path: "b", Runtime: models.Runtime{Type: models.RuntimeGo}}, TotalFiles: 1, CodeLines: 30, TotalLines: 30},
+	}
+
+	var out bytes.Buffer
+	reporter := NewReporter(&out)
+	reporter.SetGroupThreshold(100)
+	reporter.Report(stats)
+
+	report := out.String()
+	if !strings.Contains(report, "(2 small projects)") {
+		t.Errorf("report has no aggregate entry:\n%s", report)
+	}
+	if strings.Contains(report, "Path: a\n") {
+		t.Errorf("small project a is still listed:\n%s", report)
+	}
+	if !strings.Contains(report, "   Code:       5050\n") {
+		t.Errorf("grand totals do not include the small projects:\n%s", report)
+	}
+}
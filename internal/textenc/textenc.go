@@ -0,0 +1,113 @@
+// Package textenc reads text that Windows tools write with a byte order
+// mark, as UTF-8.
+package textenc
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+var (
+	utf8BOM    = []byte{0xEF, 0xBB, 0xBF}
+	utf16LEBOM = []byte{0xFF, 0xFE}
+	utf16BEBOM = []byte{0xFE, 0xFF}
+)
+
+// IsUTF16 reports whether content starts with a UTF-16 byte order mark.
+func IsUTF16(content []byte) bool {
+	return bytes.HasPrefix(content, utf16LEBOM) || bytes.HasPrefix(content, utf16BEBOM)
+}
+
+// Decode returns content as UTF-8, the way NewReader reads it. Content
+// without a byte order mark is returned unchanged.
+func Decode(content []byte) []byte {
+	switch {
+	case bytes.HasPrefix(content, utf8BOM):
+		return content[len(utf8BOM):]
+	case IsUTF16(content):
+		// Reading from memory cannot fail
+		text, _ := io.ReadAll(NewReader(bytes.NewReader(content)))
+		return text
+	}
+	return content
+}
+
+// NewReader returns a reader of the UTF-8 text of r, so lines can be
+// scanned whatever the encoding. A UTF-8 byte order mark is stripped and
+// UTF-16 text with a byte order mark, as some Windows tools generate, is
+// transcoded to UTF-8. Anything else is read unchanged.
+func NewReader(r io.Reader) io.Reader {
+	br := bufio.NewReader(r)
+	bom, _ := br.Peek(len(utf8BOM))
+
+	switch {
+	case bytes.HasPrefix(bom, utf8BOM):
+		br.Discard(len(utf8BOM))
+	case bytes.HasPrefix(bom, utf16LEBOM):
+		br.Discard(len(utf16LEBOM))
+		return &utf16Reader{r: br, order: binary.LittleEndian}
+	case bytes.HasPrefix(bom, utf16BEBOM):
+		br.Discard(len(utf16BEBOM))
+		return &utf16Reader{r: br, order: binary.BigEndian}
+	}
+	return br
+}
+
+// utf16Reader transcodes UTF-16 text to UTF-8. Unpaired surrogates and a
+// trailing odd byte become utf8.RuneError.
+type utf16Reader struct {
+	r     *bufio.Reader
+	order binary.ByteOrder
+	// buf holds decoded text; pending is the part not yet read.
+	buf     []byte
+	pending []byte
+	err     error
+}
+
+func (u *utf16Reader) Read(p []byte) (int, error) {
+	for len(u.pending) == 0 {
+		if u.err != nil {
+			return 0, u.err
+		}
+		u.fill()
+	}
+	n := copy(p, u.pending)
+	u.pending = u.pending[n:]
+	return n, nil
+}
+
+// fill decodes the next few KiB of input into pending, recording the read
+// error, if any, once the input is exhausted.
+func (u *utf16Reader) fill() {
+	u.buf = u.buf[:0]
+	var unit [2]byte
+	for len(u.buf) < 4096 {
+		if _, err := io.ReadFull(u.r, unit[:]); err != nil {
+			if err == io.ErrUnexpectedEOF {
+				u.buf = utf8.AppendRune(u.buf, utf8.RuneError)
+				err = io.EOF
+			}
+			u.err = err
+			break
+		}
+
+		r := rune(u.order.Uint16(unit[:]))
+		if utf16.IsSurrogate(r) {
+			low, err := u.r.Peek(2)
+			if err != nil {
+				r = utf8.RuneError
+			} else if pair := utf16.DecodeRune(r, rune(u.order.Uint16(low))); pair != utf8.RuneError {
+				u.r.Discard(2)
+				r = pair
+			} else {
+				r = utf8.RuneError
+			}
+		}
+		u.buf = utf8.AppendRune(u.buf, r)
+	}
+	u.pending = u.buf
+}
@@ -0,0 +1,45 @@
+package textenc
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+var decodeTests = []struct {
+	name  string
+	input []byte
+	want  string
+}{
+	{"utf-8", []byte("a\nb"), "a\nb"},
+	{"utf-8 bom", []byte("\xEF\xBB\xBFa\nb"), "a\nb"},
+	{"utf-16le", []byte{0xFF, 0xFE, 'a', 0, '\n', 0, 0x3D, 0xD8, 0x00, 0xDE}, "a\n😀"},
+	{"utf-16be", []byte{0xFE, 0xFF, 0, 'a', 0, '\n', 0, 'b'}, "a\nb"},
+	{"utf-16 unpaired surrogate", []byte{0xFF, 0xFE, 0x3D, 0xD8, 'a', 0}, "\uFFFDa"},
+	{"utf-16 odd length", []byte{0xFF, 0xFE, 'a', 0, 'b'}, "a\uFFFD"},
+	{"empty", nil, ""},
+}
+
+func TestNewReader(t *testing.T) {
+	for _, tt := range decodeTests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := io.ReadAll(NewReader(bytes.NewReader(tt.input)))
+			if err != nil {
+				t.Fatalf("ReadAll: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecode(t *testing.T) {
+	for _, tt := range decodeTests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Decode(tt.input); string(got) != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -12,4 +12,15 @@ var (
 
 	// GitHubRepo is the GitHub repository name
 	GitHubRepo = "repoctr"
+
+	// ReleaseProvider selects the release API used by 'repo-ctr update':
+	// "github" (default), "gitlab", or "gitea". Overridden at runtime by
+	// REPOCTR_RELEASE_PROVIDER.
+	ReleaseProvider = "github"
+
+	// ReleaseBaseURL is the base URL of a self-hosted GitLab or Gitea
+	// instance, e.g. https://gitlab.example.com. The owner and repository
+	// name above identify the project there. Overridden at runtime by
+	// REPOCTR_RELEASE_URL.
+	ReleaseBaseURL = ""
 )
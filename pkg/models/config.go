@@ -11,4 +11,5 @@ type ProjectOverride struct {
 	ExcludePatterns []string `yaml:"exclude-patterns,omitempty"`
 	SrcIgnorePaths  []string `yaml:"src-ignore-paths,omitempty"`
 	SourcePaths     []string `yaml:"source-paths,omitempty"`
+	ExtraExtensions []string `yaml:"extra-extensions,omitempty"`
 }
@@ -1,5 +1,10 @@
 package models
 
+import (
+	"fmt"
+	"strings"
+)
+
 // RuntimeType represents the programming language/runtime of a project.
 type RuntimeType string
 
@@ -13,27 +18,104 @@ const (
 	RuntimeDart       RuntimeType = "Dart"
 	RuntimeCpp        RuntimeType = "C/C++"
 	RuntimeRust       RuntimeType = "Rust"
+	RuntimeHaskell    RuntimeType = "Haskell"
+	RuntimePHP        RuntimeType = "PHP"
+	RuntimeSQL        RuntimeType = "SQL"
+	RuntimeNim        RuntimeType = "Nim"
+	RuntimeR          RuntimeType = "R"
+	RuntimeSwift      RuntimeType = "Swift"
+	RuntimeClojure    RuntimeType = "Clojure"
+	RuntimeOCaml      RuntimeType = "OCaml"
+	RuntimeKotlin     RuntimeType = "Kotlin"
 )
 
+// AllRuntimeTypes lists every runtime type repo-ctr detects.
+var AllRuntimeTypes = []RuntimeType{
+	RuntimeDotNet, RuntimePython, RuntimeGo, RuntimeJava, RuntimeTypeScript,
+	RuntimeJavaScript, RuntimeDart, RuntimeCpp, RuntimeRust, RuntimeHaskell,
+	RuntimePHP, RuntimeSQL, RuntimeNim, RuntimeR, RuntimeSwift, RuntimeClojure,
+	RuntimeOCaml, RuntimeKotlin,
+}
+
+// runtimeAliases maps lowercase alternative names to runtime types.
+var runtimeAliases = map[string]RuntimeType{
+	"node":   RuntimeJavaScript,
+	"nodejs": RuntimeJavaScript,
+	"js":     RuntimeJavaScript,
+	"ts":     RuntimeTypeScript,
+	"py":     RuntimePython,
+	"golang": RuntimeGo,
+	"cs":     RuntimeDotNet,
+	"csharp": RuntimeDotNet,
+	"c#":     RuntimeDotNet,
+	"dotnet": RuntimeDotNet,
+	"c":      RuntimeCpp,
+	"cpp":    RuntimeCpp,
+	"c++":    RuntimeCpp,
+	"rs":     RuntimeRust,
+	"hs":     RuntimeHaskell,
+	"clj":    RuntimeClojure,
+	"kt":     RuntimeKotlin,
+	"mssql":  RuntimeSQL,
+	"tsql":   RuntimeSQL,
+}
+
+// ParseRuntimeType resolves a user-supplied runtime name, such as a command
+// line flag value, to a RuntimeType. Names are case-insensitive and may be
+// a canonical name ("Go", ".NET") or a common alias ("golang", "csharp",
+// "node", "c++").
+func ParseRuntimeType(name string) (RuntimeType, error) {
+	key := strings.ToLower(strings.TrimSpace(name))
+	for _, rt := range AllRuntimeTypes {
+		if strings.ToLower(string(rt)) == key {
+			return rt, nil
+		}
+	}
+	if rt, ok := runtimeAliases[key]; ok {
+		return rt, nil
+	}
+
+	names := make([]string, len(AllRuntimeTypes))
+	for i, rt := range AllRuntimeTypes {
+		names[i] = string(rt)
+	}
+	return "", fmt.Errorf("unknown runtime %q (expected one of %s)", name, strings.Join(names, ", "))
+}
+
+// DynamicVersion is the Project.Version of a project whose version is
+// computed at build time, e.g. from VCS tags, and could not be resolved
+// from its sources.
+const DynamicVersion = "dynamic"
+
+// FrameworkReactNative is the Project.Framework of a React Native app: a
+// JavaScript or TypeScript package that depends on react-native, with native
+// android/ and ios/ projects beside its package.json.
+const FrameworkReactNative = "React Native"
+
 // Runtime describes the language runtime and version for a project.
 type Runtime struct {
-	Type    RuntimeType `yaml:"type"`
-	Version string      `yaml:"version,omitempty"`
+	Type    RuntimeType `yaml:"type" json:"type"`
+	Version string      `yaml:"version,omitempty" json:"version,omitempty"`
 }
 
 // Project represents a discovered project in the repository.
 type Project struct {
-	Name            string     `yaml:"name"`
-	Path            string     `yaml:"path"`
-	Runtime         Runtime    `yaml:"runtime"`
-	ManifestFile    string     `yaml:"manifest-file"`
-	SourcePaths     []string   `yaml:"source-paths"`
-	SrcIgnorePaths  []string   `yaml:"src-ignore-paths,omitempty"`
-	ExcludePatterns []string   `yaml:"exclude-patterns,omitempty"`
-	Children        []*Project `yaml:"children,omitempty"`
+	Name            string     `yaml:"name" json:"name"`
+	Path            string     `yaml:"path" json:"path"`
+	Runtime         Runtime    `yaml:"runtime" json:"runtime"`
+	ManifestFile    string     `yaml:"manifest-file" json:"manifest-file"`
+	SourcePaths     []string   `yaml:"source-paths" json:"source-paths"`
+	SrcIgnorePaths  []string   `yaml:"src-ignore-paths,omitempty" json:"src-ignore-paths,omitempty"`
+	ExcludePatterns []string   `yaml:"exclude-patterns,omitempty" json:"exclude-patterns,omitempty"`
+	ExtraExtensions []string   `yaml:"extra-extensions,omitempty" json:"extra-extensions,omitempty"`
+	DependencyCount int        `yaml:"dependency-count,omitempty" json:"dependency-count,omitempty"`
+	PackageManager  string     `yaml:"package-manager,omitempty" json:"package-manager,omitempty"`
+	Version         string     `yaml:"version,omitempty" json:"version,omitempty"`
+	Framework       string     `yaml:"framework,omitempty" json:"framework,omitempty"`
+	Children        []*Project `yaml:"children,omitempty" json:"children,omitempty"`
 }
 
 // ProjectsConfig is the root structure for projects.yaml.
 type ProjectsConfig struct {
-	Projects []*Project `yaml:"projects"`
+	Projects []*Project `yaml:"projects" json:"projects"`
 }
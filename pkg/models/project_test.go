@@ -0,0 +1,54 @@
+package models
+
+import "testing"
+
+func TestParseRuntimeType(t *testing.T) {
+	tests := []struct {
+		name string
+		want RuntimeType
+	}{
+		// Canonical names, in any case
+		{"Go", RuntimeGo},
+		{"python", RuntimePython},
+		{".net", RuntimeDotNet},
+		{"C/C++", RuntimeCpp},
+		{" TypeScript ", RuntimeTypeScript},
+
+		// Aliases
+		{"node", RuntimeJavaScript},
+		{"nodejs", RuntimeJavaScript},
+		{"NodeJS", RuntimeJavaScript},
+		{"js", RuntimeJavaScript},
+		{"ts", RuntimeTypeScript},
+		{"py", RuntimePython},
+		{"golang", RuntimeGo},
+		{"cs", RuntimeDotNet},
+		{"csharp", RuntimeDotNet},
+		{"c#", RuntimeDotNet},
+		{"dotnet", RuntimeDotNet},
+		{"cpp", RuntimeCpp},
+		{"c++", RuntimeCpp},
+		{"c", RuntimeCpp},
+		{"rs", RuntimeRust},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRuntimeType(tt.name)
+			if err != nil {
+				t.Fatalf("ParseRuntimeType(%q): %v", tt.name, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseRuntimeType(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRuntimeType_Unknown(t *testing.T) {
+	for _, name := range []string{"", "cobol", "java script"} {
+		if rt, err := ParseRuntimeType(name); err == nil {
+			t.Errorf("ParseRuntimeType(%q) = %q, want an error", name, rt)
+		}
+	}
+}
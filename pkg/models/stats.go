@@ -6,7 +6,35 @@ type FileStats struct {
 	Lines      int
 	BlankLines int
 	CodeLines  int
-	Size       int64
+	// StructuralLines holds lines made only of braces, parentheses, and
+	// semicolons when they are counted separately from code.
+	StructuralLines int
+	// DataLines holds the interior lines of multi-line string literals when
+	// they are counted separately from code.
+	DataLines int
+	// NotebookLines holds the non-blank code-cell lines of a Jupyter
+	// notebook when notebooks are counted.
+	NotebookLines int
+	// LongLines counts lines longer than the configured maximum length.
+	LongLines int
+	// BlankRuns counts runs of two or more consecutive blank lines when
+	// blank runs are tracked.
+	BlankRuns int
+	Size      int64
+	Generated bool
+	// Skipped is set when the file exceeded the maximum file size and was
+	// not read; only Path and Size are filled in.
+	Skipped bool
+	// Binary is set when the file looks binary, having a NUL byte near its
+	// start, and was not line-counted; only Path and Size are filled in.
+	Binary bool
+}
+
+// SkippedFile is a file left out of the counts for exceeding the maximum
+// file size.
+type SkippedFile struct {
+	Path string
+	Size int64
 }
 
 // ProjectStats holds aggregated statistics for a project.
@@ -14,11 +42,76 @@ type ProjectStats struct {
 	Project      *Project
 	TotalFiles   int
 	TotalFolders int
+	TestFolders  int
 	TotalLines   int
 	BlankLines   int
 	CodeLines    int
-	TotalSize    int64
+	// StructuralLines is non-zero only when structural lines are counted
+	// separately; such lines are then excluded from CodeLines.
+	StructuralLines int
+	// DataLines is non-zero only when the interior lines of multi-line
+	// string literals are counted separately; they are then excluded from
+	// CodeLines.
+	DataLines int
+	// NotebookLines is non-zero only when Jupyter notebooks are counted. It
+	// holds their non-blank code-cell lines, which are not in CodeLines.
+	NotebookLines int
+	// LongLines counts lines over the maximum line length; zero when long
+	// lines are not tracked.
+	LongLines int
+	// BlankRuns counts runs of two or more consecutive blank lines; zero
+	// when blank runs are not tracked.
+	BlankRuns int
+	TotalSize int64
+	// GeneratedFiles and GeneratedLines count files carrying a generated-code
+	// header. They are included in the totals above unless generated files
+	// are excluded.
+	GeneratedFiles int
+	GeneratedLines int
+	// SkippedFiles lists files over the maximum file size, which are not
+	// included in any of the totals above.
+	SkippedFiles []SkippedFile
+	// BinaryFiles counts files that looked binary and were not
+	// line-counted. They are not included in any of the totals above.
+	BinaryFiles  int
 	LargestFiles []FileStats
 	AllFiles     []FileStats
+	SubLanguages []SubLanguageStats
 	Children     []*ProjectStats
 }
+
+// SubLanguageStats holds statistics for one language within a runtime that
+// covers several languages (e.g. C and C++ within a C/C++ project).
+type SubLanguageStats struct {
+	Language   string
+	TotalFiles int
+	TotalLines int
+	BlankLines int
+	CodeLines  int
+}
+
+// LanguageStats holds statistics aggregated across all projects of one runtime.
+type LanguageStats struct {
+	Runtime    RuntimeType
+	Projects   int
+	TotalFiles int
+	TotalLines int
+	BlankLines int
+	CodeLines  int
+	TotalSize  int64
+}
+
+// Fingerprint is a content digest over a set of counted files.
+type Fingerprint struct {
+	Digest   string
+	Files    int
+	Projects []*ProjectFingerprint
+}
+
+// ProjectFingerprint is the content digest over a single project's counted files.
+type ProjectFingerprint struct {
+	Project  *Project
+	Digest   string
+	Files    int
+	Children []*ProjectFingerprint
+}
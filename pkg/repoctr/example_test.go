@@ -0,0 +1,59 @@
+package repoctr_test
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"repoctr/pkg/repoctr"
+)
+
+func Example() {
+	root, err := os.MkdirTemp("", "repoctr-example")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	files := map[string]string{
+		"go.mod":            "module example.com/app\n\ngo 1.22\n",
+		"main.go":           "package main\n\nfunc main() {}\n",
+		"web/package.json":  `{"name": "web"}`,
+		"web/src/index.js":  "console.log('hello')\n",
+		"web/src/format.js": "export const format = (s) => s.trim()\n",
+	}
+	for rel, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			log.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	projects, err := repoctr.Discover(root)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	projectStats, err := repoctr.ComputeStats(root, projects)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var printTree func(list []*repoctr.ProjectStats, indent string)
+	printTree = func(list []*repoctr.ProjectStats, indent string) {
+		for _, s := range list {
+			fmt.Printf("%s%s (%s): %d files, %d code lines\n",
+				indent, s.Project.Name, s.Project.Runtime.Type, s.TotalFiles, s.CodeLines)
+			printTree(s.Children, indent+"  ")
+		}
+	}
+	printTree(projectStats, "")
+
+	// Output:
+	// app (Go): 1 files, 2 code lines
+	//   web (JavaScript): 2 files, 2 code lines
+}
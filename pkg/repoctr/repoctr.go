@@ -0,0 +1,63 @@
+// Package repoctr discovers the projects in a repository and computes their
+// lines-of-code statistics, as the repo-ctr CLI does with identify and
+// stats. It is a thin wrapper over repo-ctr's internal walker, detectors,
+// and counter; use repoctr/pkg/stats directly for totals, counting options,
+// and cancellation.
+package repoctr
+
+import (
+	"fmt"
+
+	"repoctr/internal/config"
+	"repoctr/internal/detector"
+	"repoctr/internal/discovery"
+	"repoctr/pkg/models"
+	"repoctr/pkg/stats"
+)
+
+// Re-exported model types, so callers need not import repoctr/pkg/models.
+type (
+	Project      = models.Project
+	Runtime      = models.Runtime
+	RuntimeType  = models.RuntimeType
+	ProjectStats = models.ProjectStats
+	FileStats    = models.FileStats
+)
+
+// Discover finds the projects under root and nests them by path, like
+// 'repo-ctr identify'. Project paths are relative to root, and the
+// project-overrides of root's .repoctrconfig.yaml are applied. Detection
+// warnings and ambiguous-override warnings are not reported.
+func Discover(root string) ([]*models.Project, error) {
+	walker, err := discovery.NewWalker(root, detector.NewRegistry())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create walker for %s: %w", root, err)
+	}
+
+	projects, err := walker.Discover()
+	if err != nil {
+		return nil, fmt.Errorf("discovery failed for %s: %w", root, err)
+	}
+	if len(projects) == 0 {
+		return nil, nil
+	}
+
+	cfg, err := config.LoadConfig(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	merged, _ := config.MergeProjects(discovery.NewHierarchyBuilder().Build(projects), nil, cfg)
+	return merged, nil
+}
+
+// ComputeStats counts the projects, whose paths are relative to root, with
+// the default options of 'repo-ctr stats'. The result mirrors the project
+// hierarchy.
+func ComputeStats(root string, projects []*models.Project) ([]*models.ProjectStats, error) {
+	result, err := stats.Compute(root, projects, stats.Options{})
+	if err != nil {
+		return nil, err
+	}
+	return result.Projects, nil
+}
@@ -0,0 +1,207 @@
+// Package stats computes lines-of-code statistics for a set of projects.
+// It is the stable entry point for Go programs embedding repo-ctr.
+package stats
+
+import (
+	"context"
+	"fmt"
+
+	internalstats "repoctr/internal/stats"
+	"repoctr/pkg/models"
+)
+
+// Options controls how statistics are computed.
+type Options struct {
+	// Jobs is the number of files counted concurrently.
+	// Zero means runtime.NumCPU().
+	Jobs int
+
+	// ProjectJobs is the number of sibling projects counted concurrently,
+	// each with its own pool of Jobs file workers. Zero means one at a time.
+	ProjectJobs int
+
+	// Excludes are gitignore-style patterns applied to every project in
+	// addition to the global excludes in .repoctrconfig.yaml.
+	Excludes []string
+
+	// IncludeExtensions are file extensions, such as ".tmpl", counted in
+	// every project in addition to those of its runtime.
+	IncludeExtensions []string
+
+	// ExcludeGeneratedDirs skips conventional generated-code directories
+	// such as gen/, generated/, and migrations/.
+	ExcludeGeneratedDirs bool
+
+	// ExcludeGenerated omits files with a generated-code header from totals.
+	ExcludeGenerated bool
+
+	// CountTestDirsSeparately reports test directories in TestFolders
+	// instead of Folders.
+	CountTestDirsSeparately bool
+
+	// SeparateStructuralLines counts lines of only braces, parentheses,
+	// and semicolons as structural lines instead of code.
+	SeparateStructuralLines bool
+
+	// SeparateDataLines counts the interior lines of multi-line string
+	// literals (Go raw strings, Python triple-quoted strings) as data lines
+	// instead of code.
+	SeparateDataLines bool
+
+	// IncludeNotebooks counts the code cells of Jupyter notebooks in
+	// Python projects as notebook lines.
+	IncludeNotebooks bool
+
+	// MaxLineLength, when positive, counts lines longer than this many
+	// runes in LongLines.
+	MaxLineLength int
+
+	// CountBlankRuns counts runs of two or more consecutive blank lines in
+	// BlankRuns.
+	CountBlankRuns bool
+
+	// MaxFileSize, when positive, skips files larger than this many bytes.
+	// Skipped files are listed in ProjectStats.SkippedFiles.
+	MaxFileSize int64
+
+	// FollowSymlinks counts files below symbolic links to directories.
+	// Each directory is walked at most once, so cyclic links are skipped.
+	FollowSymlinks bool
+
+	// CacheFile, when set, names a file holding per-file counts from
+	// previous runs. Files whose git blob SHA (or, outside git, size and
+	// modification time) is unchanged are not read again, and the file is
+	// rewritten with the counts of this run.
+	CacheFile string
+
+	// OnFile, when set, is called once for every file counted, e.g. to
+	// report progress. Calls are serialized but come from counting
+	// goroutines, so the callback should return quickly.
+	OnFile func(*models.FileStats)
+
+	// Logf, when set, receives diagnostic messages such as the directories
+	// and oversized files skipped while counting.
+	Logf func(format string, args ...any)
+}
+
+// Totals holds grand totals across a project hierarchy.
+type Totals struct {
+	Projects        int
+	Files           int
+	Folders         int
+	TestFolders     int
+	TotalLines      int
+	CodeLines       int
+	BlankLines      int
+	StructuralLines int
+	DataLines       int
+	NotebookLines   int
+	LongLines       int
+	BlankRuns       int
+	Size            int64
+	GeneratedFiles  int
+	GeneratedLines  int
+	SkippedFiles    int
+	BinaryFiles     int
+}
+
+// Result is the outcome of Compute.
+type Result struct {
+	// Projects mirrors the input hierarchy with per-project statistics.
+	Projects []*models.ProjectStats
+	// Totals sums every project in the hierarchy.
+	Totals Totals
+	// ByLanguage aggregates totals per runtime, largest first.
+	ByLanguage []*models.LanguageStats
+}
+
+// Compute counts the projects, whose paths are relative to root, and
+// returns per-project statistics with grand totals.
+func Compute(root string, projects []*models.Project, opts Options) (Result, error) {
+	return ComputeContext(context.Background(), root, projects, opts)
+}
+
+// ComputeContext is like Compute but stops when ctx is canceled. It then
+// returns the statistics of the projects that were fully counted, with
+// totals over those projects, together with an error wrapping ctx.Err().
+func ComputeContext(ctx context.Context, root string, projects []*models.Project, opts Options) (Result, error) {
+	var cache *internalstats.Cache
+	if opts.CacheFile != "" {
+		cache = internalstats.LoadCache(opts.CacheFile)
+	}
+
+	counter, err := internalstats.NewCounterWithOptions(root, internalstats.Options{
+		Workers:                 opts.Jobs,
+		Excludes:                opts.Excludes,
+		IncludeExtensions:       opts.IncludeExtensions,
+		ExcludeGeneratedDirs:    opts.ExcludeGeneratedDirs,
+		ExcludeGenerated:        opts.ExcludeGenerated,
+		CountTestDirsSeparately: opts.CountTestDirsSeparately,
+		SeparateStructuralLines: opts.SeparateStructuralLines,
+		SeparateDataLines:       opts.SeparateDataLines,
+		IncludeNotebooks:        opts.IncludeNotebooks,
+		MaxLineLength:           opts.MaxLineLength,
+		CountBlankRuns:          opts.CountBlankRuns,
+		MaxFileSize:             opts.MaxFileSize,
+		FollowSymlinks:          opts.FollowSymlinks,
+		Cache:                   cache,
+		OnFile:                  opts.OnFile,
+		Logf:                    opts.Logf,
+	})
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to create stats counter: %w", err)
+	}
+
+	// CountHierarchy only fails when ctx is canceled, and then still
+	// returns the projects that completed
+	projectStats, countErr := counter.CountHierarchy(ctx, projects, internalstats.HierarchyOptions{
+		Concurrency: opts.ProjectJobs,
+	})
+	if countErr != nil {
+		countErr = fmt.Errorf("statistics incomplete: %w", countErr)
+	}
+
+	if cache != nil {
+		if err := cache.Save(); err != nil {
+			return Result{}, err
+		}
+	}
+
+	return Result{
+		Projects:   projectStats,
+		Totals:     Sum(projectStats),
+		ByLanguage: internalstats.AggregateByLanguage(projectStats),
+	}, countErr
+}
+
+// Sum returns the totals of a project hierarchy, including children.
+func Sum(projectStats []*models.ProjectStats) Totals {
+	var totals Totals
+
+	var aggregate func([]*models.ProjectStats)
+	aggregate = func(list []*models.ProjectStats) {
+		for _, s := range list {
+			totals.Projects++
+			totals.Files += s.TotalFiles
+			totals.Folders += s.TotalFolders
+			totals.TestFolders += s.TestFolders
+			totals.TotalLines += s.TotalLines
+			totals.CodeLines += s.CodeLines
+			totals.BlankLines += s.BlankLines
+			totals.StructuralLines += s.StructuralLines
+			totals.DataLines += s.DataLines
+			totals.NotebookLines += s.NotebookLines
+			totals.LongLines += s.LongLines
+			totals.BlankRuns += s.BlankRuns
+			totals.Size += s.TotalSize
+			totals.GeneratedFiles += s.GeneratedFiles
+			totals.GeneratedLines += s.GeneratedLines
+			totals.SkippedFiles += len(s.SkippedFiles)
+			totals.BinaryFiles += s.BinaryFiles
+			aggregate(s.Children)
+		}
+	}
+
+	aggregate(projectStats)
+	return totals
+}
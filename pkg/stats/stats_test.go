@@ -0,0 +1,81 @@
+package stats
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"repoctr/pkg/models"
+)
+
+// writeFile creates a file (and its parent directories) under root.
+func writeFile(t *testing.T, root, rel, content string) {
+	t.Helper()
+	full := filepath.Join(root, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+		t.Fatalf("write %s: %v", rel, err)
+	}
+}
+
+func TestCompute(t *testing.T) {
+	root := t.TempDir()
+
+	writeFile(t, root, "main.go", "package main\n\nfunc main() {}\n")
+	writeFile(t, root, "gen/api.go", "package gen\n")
+	writeFile(t, root, "web/index.ts", "export {}\n\nconst a = 1\n")
+
+	projects := []*models.Project{
+		{
+			Name:        "app",
+			Path:        ".",
+			Runtime:     models.Runtime{Type: models.RuntimeGo},
+			SourcePaths: []string{"."},
+			Children: []*models.Project{
+				{
+					Name:        "web",
+					Path:        "web",
+					Runtime:     models.Runtime{Type: models.RuntimeTypeScript},
+					SourcePaths: []string{"."},
+				},
+			},
+		},
+	}
+
+	result, err := Compute(root, projects, Options{Jobs: 2})
+	if err != nil {
+		t.Fatalf("Compute: %v", err)
+	}
+
+	if len(result.Projects) != 1 || len(result.Projects[0].Children) != 1 {
+		t.Fatalf("expected hierarchy of app > web, got %+v", result.Projects)
+	}
+
+	want := Totals{
+		Projects:   2,
+		Files:      3,
+		TotalLines: 7,
+		CodeLines:  5,
+		BlankLines: 2,
+	}
+	got := result.Totals
+	got.Folders, got.Size = 0, 0 // depend on the filesystem layout
+	if got != want {
+		t.Errorf("Totals = %+v, want %+v", got, want)
+	}
+
+	if len(result.ByLanguage) != 2 {
+		t.Errorf("ByLanguage has %d entries, want 2", len(result.ByLanguage))
+	}
+
+	// Options are applied
+	result, err = Compute(root, projects, Options{ExcludeGeneratedDirs: true})
+	if err != nil {
+		t.Fatalf("Compute: %v", err)
+	}
+	if result.Totals.Files != 2 {
+		t.Errorf("Files with ExcludeGeneratedDirs = %d, want 2", result.Totals.Files)
+	}
+}